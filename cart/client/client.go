@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"log"
+
+	"github.com/abhiii71/orderStream/cart/models"
+	"github.com/abhiii71/orderStream/cart/proto/pb"
+	orderModels "github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Client struct {
+	conn    *grpc.ClientConn
+	service pb.CartServiceClient
+}
+
+func NewClient(url string) (*Client, error) {
+	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	c := pb.NewCartServiceClient(conn)
+	return &Client{conn, c}, nil
+}
+
+func (c *Client) Close() {
+	err := c.conn.Close()
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (c *Client) GetCart(ctx context.Context, owner models.Owner) (*models.Cart, error) {
+	r, err := c.service.GetCart(ctx, &pb.GetCartRequest{Owner: ownerToProto(owner)})
+	if err != nil {
+		return nil, err
+	}
+	return cartFromProto(r), nil
+}
+
+func (c *Client) AddItem(ctx context.Context, owner models.Owner, productId string, quantity int) (*models.Cart, error) {
+	r, err := c.service.AddItem(ctx, &pb.AddItemRequest{
+		Owner:     ownerToProto(owner),
+		ProductId: productId,
+		Quantity:  int32(quantity),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cartFromProto(r), nil
+}
+
+func (c *Client) UpdateItem(ctx context.Context, owner models.Owner, productId string, quantity int) (*models.Cart, error) {
+	r, err := c.service.UpdateItem(ctx, &pb.UpdateItemRequest{
+		Owner:     ownerToProto(owner),
+		ProductId: productId,
+		Quantity:  int32(quantity),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cartFromProto(r), nil
+}
+
+func (c *Client) RemoveItem(ctx context.Context, owner models.Owner, productId string) (*models.Cart, error) {
+	r, err := c.service.RemoveItem(ctx, &pb.RemoveItemRequest{
+		Owner:     ownerToProto(owner),
+		ProductId: productId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cartFromProto(r), nil
+}
+
+func (c *Client) MergeCarts(ctx context.Context, sessionId string, accountId uint64) (*models.Cart, error) {
+	r, err := c.service.MergeCarts(ctx, &pb.MergeCartsRequest{
+		SessionId: sessionId,
+		AccountId: accountId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cartFromProto(r), nil
+}
+
+// CheckoutCart places an order for the cart of the account and returns the
+// id of the order. The order is shipped to shipTo by shippingMethod, and
+// couponCode, if not empty, is redeemed on it.
+func (c *Client) CheckoutCart(ctx context.Context, accountId uint64, shipTo orderModels.Address, shippingMethod, couponCode string) (uint64, error) {
+	r, err := c.service.CheckoutCart(ctx, &pb.CheckoutCartRequest{
+		AccountId:  accountId,
+		CouponCode: couponCode,
+		ShipTo: &pb.Address{
+			Name:       shipTo.Name,
+			Line1:      shipTo.Line1,
+			Line2:      shipTo.Line2,
+			City:       shipTo.City,
+			PostalCode: shipTo.PostalCode,
+			Country:    shipTo.Country,
+			Region:     shipTo.Region,
+		},
+		ShippingMethod: shippingMethod,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return r.OrderId, nil
+}
+
+func ownerToProto(o models.Owner) *pb.Owner {
+	return &pb.Owner{AccountId: o.AccountId, SessionId: o.SessionId}
+}
+
+func cartFromProto(r *pb.Cart) *models.Cart {
+	c := &models.Cart{
+		Owner: models.Owner{AccountId: r.GetOwner().GetAccountId(), SessionId: r.GetOwner().GetSessionId()},
+		Items: []models.PricedItem{},
+	}
+	if r.Subtotal != nil {
+		subtotal := money.FromProto(r.Subtotal)
+		c.Subtotal = &subtotal
+	}
+	for _, item := range r.Items {
+		pricedItem := models.PricedItem{
+			ProductId: item.ProductId,
+			Name:      item.Name,
+			Quantity:  int(item.Quantity),
+			Available: item.Available,
+		}
+		if item.Available {
+			pricedItem.UnitPrice = money.FromProto(item.UnitPrice)
+			pricedItem.LineTotal = money.FromProto(item.LineTotal)
+		}
+		c.Items = append(c.Items, pricedItem)
+	}
+	return c
+}
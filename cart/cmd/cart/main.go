@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/abhiii71/orderStream/cart"
+	"github.com/abhiii71/orderStream/cart/config"
+	"github.com/abhiii71/orderStream/cart/internal"
+	order "github.com/abhiii71/orderStream/order/client"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	product "github.com/abhiii71/orderStream/product/client"
+	"github.com/joho/godotenv"
+	"github.com/tinrab/retry"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver
+)
+
+// prunable is an idempotency store that can delete expired keys.
+type prunable interface {
+	idempotency.Store
+	Prune(ctx context.Context) (int64, error)
+}
+
+func main() {
+	err := godotenv.Load(".env") // relative to project root
+	if err != nil {
+		log.Println(".env file not found!")
+	}
+
+	var (
+		repository internal.CartRepository
+		keys       prunable
+	)
+
+	switch config.Store {
+	case "memory":
+		log.Println("Keeping carts in memory, they are lost on restart")
+		repository = internal.NewMemoryRepository()
+		keys = idempotency.NewMemoryStore(config.IdempotencyKeyTTL)
+	case "postgres":
+		if config.DatabaseURL == "" {
+			log.Fatal("DATABASE_URL not set")
+		}
+
+		var db *sql.DB
+		// Retry connecting to DB
+		retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+			db, err = sql.Open("pgx", config.DatabaseURL)
+			if err != nil {
+				log.Println("DB connection error:", err)
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := db.PingContext(ctx); err != nil {
+				log.Println("DB ping error:", err)
+				return err
+			}
+
+			repository = internal.NewPostgresRepository(db)
+			return nil
+		})
+		keys = idempotency.NewPostgresStore(db, config.IdempotencyKeyTTL)
+	default:
+		log.Fatalf("Unknown CART_STORE %q", config.Store)
+	}
+	defer repository.Close()
+
+	productClient, err := product.NewClient(config.ProductURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer productClient.Close()
+
+	orderClient, err := order.NewClient(config.OrderURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer orderClient.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service := internal.NewCartService(repository, productClient, orderClient)
+	go deleteAbandonedCarts(ctx, service)
+	go pruneIdempotencyKeys(ctx, keys)
+
+	port := cart.Port
+	log.Printf("Listening on port %d...", port)
+	log.Fatal(internal.ListenGRPC(service, keys, port))
+}
+
+// deleteAbandonedCarts deletes session carts that were not changed within
+// the session cart TTL.
+func deleteAbandonedCarts(ctx context.Context, service internal.Service) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := service.DeleteAbandonedCarts(ctx)
+		if err != nil {
+			log.Printf("Failed to delete abandoned carts: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Deleted %d abandoned session carts", deleted)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pruneIdempotencyKeys deletes idempotency keys once their responses are no
+// longer replayed.
+func pruneIdempotencyKeys(ctx context.Context, keys prunable) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := keys.Prune(ctx)
+		if err != nil {
+			log.Printf("Failed to prune idempotency keys: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d expired idempotency keys", pruned)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+)
+
+var (
+	DatabaseURL string
+	// Store selects the cart repository: "postgres" (default) or "memory",
+	// which loses carts on restart.
+	Store      string
+	ProductURL string
+	OrderURL   string
+	// SessionCartTTL is how long anonymous carts are kept after their last
+	// change.
+	SessionCartTTL time.Duration
+	// IdempotencyKeyTTL is how long the response to a checkout sent with an
+	// idempotency key is replayed.
+	IdempotencyKeyTTL time.Duration
+)
+
+func init() {
+	DatabaseURL = os.Getenv("DATABASE_URL")
+	Store = os.Getenv("CART_STORE")
+	if Store == "" {
+		Store = "postgres"
+	}
+	ProductURL = os.Getenv("PRODUCT_URL")
+	OrderURL = os.Getenv("ORDER_URL")
+	SessionCartTTL = 30 * 24 * time.Hour
+	if ttl, err := time.ParseDuration(os.Getenv("SESSION_CART_TTL")); err == nil {
+		SessionCartTTL = ttl
+	}
+	IdempotencyKeyTTL = idempotency.DefaultTTL
+	if ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL")); err == nil {
+		IdempotencyKeyTTL = ttl
+	}
+}
@@ -0,0 +1,18 @@
+package cart
+
+import "errors"
+
+const Port = 8080
+
+// MaxQuantity is the most units of one product a cart may hold.
+const MaxQuantity = 99
+
+var (
+	ErrNoOwner            = errors.New("cart needs an account or a session")
+	ErrInvalidQuantity    = errors.New("quantity must be between 1 and 99")
+	ErrItemNotFound       = errors.New("product is not in the cart")
+	ErrProductUnavailable = errors.New("product is not available")
+	ErrMixedCurrencies    = errors.New("all products in a cart must be priced in the same currency")
+	ErrEmptyCart          = errors.New("cart is empty")
+	ErrUnavailableItems   = errors.New("cart contains products that are no longer available")
+)
@@ -0,0 +1,12 @@
+-- owner is "account:<id>" for signed in customers and "session:<id>" before
+-- they sign in
+CREATE TABLE IF NOT EXISTS cart_items (
+    owner VARCHAR(255) NOT NULL,
+    product_id VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (owner, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cart_items_updated_at ON cart_items (updated_at);
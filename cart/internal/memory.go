@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abhiii71/orderStream/cart"
+	"github.com/abhiii71/orderStream/cart/models"
+)
+
+type memoryItem struct {
+	models.Item
+	updatedAt time.Time
+}
+
+// memoryRepository keeps carts in memory. Carts are lost on restart, so it
+// is meant for development and tests.
+type memoryRepository struct {
+	mu    sync.Mutex
+	carts map[string][]memoryItem
+}
+
+func NewMemoryRepository() CartRepository {
+	return &memoryRepository{carts: make(map[string][]memoryItem)}
+}
+
+func (r *memoryRepository) Close() {}
+
+func (r *memoryRepository) GetItems(_ context.Context, owner string) ([]models.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := []models.Item{}
+	for _, item := range r.carts[owner] {
+		items = append(items, item.Item)
+	}
+	return items, nil
+}
+
+func (r *memoryRepository) SetItem(_ context.Context, owner, productId string, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	for i := range r.carts[owner] {
+		if r.carts[owner][i].ProductId == productId {
+			r.carts[owner][i].Quantity = quantity
+			r.carts[owner][i].updatedAt = now
+			return nil
+		}
+	}
+	r.carts[owner] = append(r.carts[owner], memoryItem{
+		Item:      models.Item{ProductId: productId, Quantity: quantity, AddedAt: now},
+		updatedAt: now,
+	})
+	return nil
+}
+
+func (r *memoryRepository) RemoveItem(_ context.Context, owner, productId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.carts[owner] = slices.DeleteFunc(r.carts[owner], func(item memoryItem) bool {
+		return item.ProductId == productId
+	})
+	if len(r.carts[owner]) == 0 {
+		delete(r.carts, owner)
+	}
+	return nil
+}
+
+func (r *memoryRepository) MergeCarts(_ context.Context, from, to string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, item := range r.carts[from] {
+		i := slices.IndexFunc(r.carts[to], func(existing memoryItem) bool {
+			return existing.ProductId == item.ProductId
+		})
+		if i < 0 {
+			item.Quantity = min(item.Quantity, cart.MaxQuantity)
+			item.updatedAt = now
+			r.carts[to] = append(r.carts[to], item)
+			continue
+		}
+		r.carts[to][i].Quantity = min(r.carts[to][i].Quantity+item.Quantity, cart.MaxQuantity)
+		r.carts[to][i].updatedAt = now
+	}
+	delete(r.carts, from)
+	return nil
+}
+
+func (r *memoryRepository) ClearCart(_ context.Context, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.carts, owner)
+	return nil
+}
+
+func (r *memoryRepository) DeleteSessionCarts(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for owner, items := range r.carts {
+		if !strings.HasPrefix(owner, "session:") {
+			continue
+		}
+		stale := !slices.ContainsFunc(items, func(item memoryItem) bool {
+			return !item.updatedAt.Before(before)
+		})
+		if stale {
+			deleted += int64(len(items))
+			delete(r.carts, owner)
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/abhiii71/orderStream/cart"
+	"github.com/abhiii71/orderStream/cart/models"
+)
+
+// CartRepository stores the items of carts by owner key (see
+// models.Owner.Key). A cart without items does not exist.
+type CartRepository interface {
+	Close()
+	GetItems(ctx context.Context, owner string) ([]models.Item, error)
+	// SetItem sets the quantity of a product, adding it if it is not in the
+	// cart yet.
+	SetItem(ctx context.Context, owner, productId string, quantity int) error
+	RemoveItem(ctx context.Context, owner, productId string) error
+	// MergeCarts moves the items of one cart into another. Quantities of
+	// products in both carts are added up to at most cart.MaxQuantity.
+	MergeCarts(ctx context.Context, from, to string) error
+	ClearCart(ctx context.Context, owner string) error
+	// DeleteSessionCarts deletes anonymous carts whose items were all last
+	// changed before before.
+	DeleteSessionCarts(ctx context.Context, before time.Time) (int64, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(db *sql.DB) CartRepository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Close() {
+	if err := r.db.Close(); err != nil {
+		log.Println("Error closing DB:", err)
+	}
+}
+
+func (r *postgresRepository) GetItems(ctx context.Context, owner string) ([]models.Item, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT product_id, quantity, added_at FROM cart_items
+		WHERE owner = $1 ORDER BY added_at, product_id`, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.Item{}
+	for rows.Next() {
+		var item models.Item
+		if err := rows.Scan(&item.ProductId, &item.Quantity, &item.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *postgresRepository) SetItem(ctx context.Context, owner, productId string, quantity int) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO cart_items (owner, product_id, quantity, added_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (owner, product_id) DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = EXCLUDED.updated_at`,
+		owner, productId, quantity, time.Now().UTC())
+	return err
+}
+
+func (r *postgresRepository) RemoveItem(ctx context.Context, owner, productId string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cart_items WHERE owner = $1 AND product_id = $2`, owner, productId)
+	return err
+}
+
+func (r *postgresRepository) MergeCarts(ctx context.Context, from, to string) error {
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	now := time.Now().UTC()
+	_, err = txn.ExecContext(ctx, `INSERT INTO cart_items (owner, product_id, quantity, added_at, updated_at)
+		SELECT $2, product_id, LEAST(quantity, $3), added_at, $4 FROM cart_items WHERE owner = $1
+		ON CONFLICT (owner, product_id) DO UPDATE
+		SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $3), updated_at = EXCLUDED.updated_at`,
+		from, to, cart.MaxQuantity, now)
+	if err != nil {
+		return err
+	}
+	if _, err := txn.ExecContext(ctx, `DELETE FROM cart_items WHERE owner = $1`, from); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+func (r *postgresRepository) ClearCart(ctx context.Context, owner string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cart_items WHERE owner = $1`, owner)
+	return err
+}
+
+func (r *postgresRepository) DeleteSessionCarts(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cart_items WHERE owner IN (
+		SELECT owner FROM cart_items WHERE owner LIKE 'session:%'
+		GROUP BY owner HAVING MAX(updated_at) < $1)`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+
+	"github.com/abhiii71/orderStream/cart"
+	"github.com/abhiii71/orderStream/cart/models"
+	"github.com/abhiii71/orderStream/cart/proto/pb"
+	orderModels "github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type grpcServer struct {
+	pb.UnimplementedCartServiceServer
+	service Service
+	keys    idempotency.Store
+}
+
+func ListenGRPC(service Service, keys idempotency.Store, port int) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	serv := grpc.NewServer()
+	pb.RegisterCartServiceServer(serv, &grpcServer{
+		pb.UnimplementedCartServiceServer{},
+		service,
+		keys,
+	})
+	reflection.Register(serv)
+
+	return serv.Serve(lis)
+}
+
+func (s *grpcServer) GetCart(ctx context.Context, request *pb.GetCartRequest) (*pb.Cart, error) {
+	c, err := s.service.GetCart(ctx, ownerFromProto(request.GetOwner()))
+	if err != nil {
+		log.Println(err)
+		return nil, cartError(err)
+	}
+	return cartToProto(c), nil
+}
+
+func (s *grpcServer) AddItem(ctx context.Context, request *pb.AddItemRequest) (*pb.Cart, error) {
+	c, err := s.service.AddItem(ctx, ownerFromProto(request.GetOwner()), request.GetProductId(), int(request.GetQuantity()))
+	if err != nil {
+		log.Println(err)
+		return nil, cartError(err)
+	}
+	return cartToProto(c), nil
+}
+
+func (s *grpcServer) UpdateItem(ctx context.Context, request *pb.UpdateItemRequest) (*pb.Cart, error) {
+	c, err := s.service.UpdateItem(ctx, ownerFromProto(request.GetOwner()), request.GetProductId(), int(request.GetQuantity()))
+	if err != nil {
+		log.Println(err)
+		return nil, cartError(err)
+	}
+	return cartToProto(c), nil
+}
+
+func (s *grpcServer) RemoveItem(ctx context.Context, request *pb.RemoveItemRequest) (*pb.Cart, error) {
+	c, err := s.service.RemoveItem(ctx, ownerFromProto(request.GetOwner()), request.GetProductId())
+	if err != nil {
+		log.Println(err)
+		return nil, cartError(err)
+	}
+	return cartToProto(c), nil
+}
+
+func (s *grpcServer) MergeCarts(ctx context.Context, request *pb.MergeCartsRequest) (*pb.Cart, error) {
+	c, err := s.service.MergeCarts(ctx, request.GetSessionId(), request.GetAccountId())
+	if err != nil {
+		log.Println(err)
+		return nil, cartError(err)
+	}
+	return cartToProto(c), nil
+}
+
+// CheckoutCart places an order for the cart of an account. Retries of a
+// request sent with an idempotency key get the order placed by the first
+// one, even though the cart was emptied in the meantime.
+func (s *grpcServer) CheckoutCart(ctx context.Context, request *pb.CheckoutCartRequest) (*pb.CheckoutCartResponse, error) {
+	key := idempotency.IncomingKey(ctx)
+	scope := "CheckoutCart/account:" + strconv.FormatUint(request.GetAccountId(), 10)
+	response, err := idempotency.Do(ctx, s.keys, scope, key, request, func(ctx context.Context) (*pb.CheckoutCartResponse, error) {
+		// the order service gets the key too
+		shipTo := request.GetShipTo()
+		order, err := s.service.Checkout(idempotency.WithKey(ctx, key), request.GetAccountId(), orderModels.Address{
+			Name:       shipTo.GetName(),
+			Line1:      shipTo.GetLine1(),
+			Line2:      shipTo.GetLine2(),
+			City:       shipTo.GetCity(),
+			PostalCode: shipTo.GetPostalCode(),
+			Country:    shipTo.GetCountry(),
+			Region:     shipTo.GetRegion(),
+		}, request.GetShippingMethod(), request.GetCouponCode())
+		if err != nil {
+			return nil, err
+		}
+		return &pb.CheckoutCartResponse{OrderId: uint64(order.ID)}, nil
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, cartError(err)
+	}
+	return response, nil
+}
+
+func ownerFromProto(o *pb.Owner) models.Owner {
+	return models.Owner{AccountId: o.GetAccountId(), SessionId: o.GetSessionId()}
+}
+
+func cartToProto(c *models.Cart) *pb.Cart {
+	cartProto := &pb.Cart{
+		Owner: &pb.Owner{AccountId: c.Owner.AccountId, SessionId: c.Owner.SessionId},
+		Items: []*pb.CartItem{},
+	}
+	if c.Subtotal != nil {
+		cartProto.Subtotal = money.ToProto(*c.Subtotal)
+	}
+	for _, item := range c.Items {
+		itemProto := &pb.CartItem{
+			ProductId: item.ProductId,
+			Name:      item.Name,
+			Quantity:  int32(item.Quantity),
+			Available: item.Available,
+		}
+		if item.Available {
+			itemProto.UnitPrice = money.ToProto(item.UnitPrice)
+			itemProto.LineTotal = money.ToProto(item.LineTotal)
+		}
+		cartProto.Items = append(cartProto.Items, itemProto)
+	}
+	return cartProto
+}
+
+// cartError maps cart errors to gRPC status codes. Errors of the product and
+// order services keep their codes.
+func cartError(err error) error {
+	switch {
+	case errors.Is(err, cart.ErrNoOwner), errors.Is(err, cart.ErrInvalidQuantity),
+		errors.Is(err, idempotency.ErrInvalidKey), errors.Is(err, idempotency.ErrKeyReused):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, cart.ErrItemNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, cart.ErrProductUnavailable), errors.Is(err, cart.ErrMixedCurrencies),
+		errors.Is(err, cart.ErrEmptyCart), errors.Is(err, cart.ErrUnavailableItems):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, idempotency.ErrInProgress):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return err
+}
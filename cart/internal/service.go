@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/abhiii71/orderStream/cart"
+	"github.com/abhiii71/orderStream/cart/config"
+	"github.com/abhiii71/orderStream/cart/models"
+	orderModels "github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/pricing"
+	productModels "github.com/abhiii71/orderStream/product/models"
+	"github.com/google/uuid"
+)
+
+// Catalog looks up products in the product service.
+type Catalog interface {
+	GetProducts(ctx context.Context, skip, take uint64, ids []string, query string, filter productModels.ProductFilter) ([]productModels.Product, error)
+}
+
+// OrderPlacer places orders in the order service.
+type OrderPlacer interface {
+	PostOrder(ctx context.Context, accountId uint64, products []*orderModels.OrderedProduct, shipTo orderModels.Address, shippingMethod, couponCode string) (*orderModels.Order, error)
+}
+
+type Service interface {
+	GetCart(ctx context.Context, owner models.Owner) (*models.Cart, error)
+	AddItem(ctx context.Context, owner models.Owner, productId string, quantity int) (*models.Cart, error)
+	UpdateItem(ctx context.Context, owner models.Owner, productId string, quantity int) (*models.Cart, error)
+	RemoveItem(ctx context.Context, owner models.Owner, productId string) (*models.Cart, error)
+	MergeCarts(ctx context.Context, sessionId string, accountId uint64) (*models.Cart, error)
+	Checkout(ctx context.Context, accountId uint64, shipTo orderModels.Address, shippingMethod, couponCode string) (*orderModels.Order, error)
+	DeleteAbandonedCarts(ctx context.Context) (int64, error)
+}
+
+type cartService struct {
+	repo    CartRepository
+	catalog Catalog
+	orders  OrderPlacer
+}
+
+func NewCartService(repository CartRepository, catalog Catalog, orders OrderPlacer) Service {
+	return &cartService{repository, catalog, orders}
+}
+
+// GetCart returns the cart of owner priced at the current prices.
+func (s *cartService) GetCart(ctx context.Context, owner models.Owner) (*models.Cart, error) {
+	if owner.IsZero() {
+		return nil, cart.ErrNoOwner
+	}
+	items, err := s.repo.GetItems(ctx, owner.Key())
+	if err != nil {
+		return nil, err
+	}
+	return s.price(ctx, owner, items)
+}
+
+// AddItem adds quantity units of a product to the cart. Without an owner a
+// new session cart is started; its id is returned in the cart's owner.
+func (s *cartService) AddItem(ctx context.Context, owner models.Owner, productId string, quantity int) (*models.Cart, error) {
+	if quantity < 1 || quantity > cart.MaxQuantity {
+		return nil, cart.ErrInvalidQuantity
+	}
+	if owner.IsZero() {
+		owner.SessionId = uuid.NewString()
+	}
+
+	items, err := s.repo.GetItems(ctx, owner.Key())
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.price(ctx, owner, items)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.catalog.GetProducts(ctx, 0, 0, []string{productId}, "", productModels.ProductFilter{IncludeHidden: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 || !orderable(&products[0]) {
+		return nil, cart.ErrProductUnavailable
+	}
+	for _, item := range current.Items {
+		if item.Available && item.UnitPrice.Currency != products[0].Price.Currency {
+			return nil, cart.ErrMixedCurrencies
+		}
+	}
+
+	for _, item := range items {
+		if item.ProductId == productId {
+			quantity += item.Quantity
+		}
+	}
+	if quantity > cart.MaxQuantity {
+		return nil, cart.ErrInvalidQuantity
+	}
+	if err := s.repo.SetItem(ctx, owner.Key(), productId, quantity); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, owner)
+}
+
+// UpdateItem changes the quantity of a product in the cart. A quantity of 0
+// removes it.
+func (s *cartService) UpdateItem(ctx context.Context, owner models.Owner, productId string, quantity int) (*models.Cart, error) {
+	if quantity == 0 {
+		return s.RemoveItem(ctx, owner, productId)
+	}
+	if quantity < 0 || quantity > cart.MaxQuantity {
+		return nil, cart.ErrInvalidQuantity
+	}
+	if owner.IsZero() {
+		return nil, cart.ErrNoOwner
+	}
+
+	items, err := s.repo.GetItems(ctx, owner.Key())
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(items, func(item models.Item) bool { return item.ProductId == productId }) {
+		return nil, cart.ErrItemNotFound
+	}
+	if err := s.repo.SetItem(ctx, owner.Key(), productId, quantity); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, owner)
+}
+
+func (s *cartService) RemoveItem(ctx context.Context, owner models.Owner, productId string) (*models.Cart, error) {
+	if owner.IsZero() {
+		return nil, cart.ErrNoOwner
+	}
+	if err := s.repo.RemoveItem(ctx, owner.Key(), productId); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, owner)
+}
+
+// MergeCarts moves the items of an anonymous session cart into the cart of
+// the account that just signed in.
+func (s *cartService) MergeCarts(ctx context.Context, sessionId string, accountId uint64) (*models.Cart, error) {
+	if sessionId == "" || accountId == 0 {
+		return nil, cart.ErrNoOwner
+	}
+
+	account := models.Owner{AccountId: accountId}
+	err := s.repo.MergeCarts(ctx, models.Owner{SessionId: sessionId}.Key(), account.Key())
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, account)
+}
+
+// Checkout places an order for the cart of an account at the current prices
+// and empties the cart. The order is shipped to shipTo by shippingMethod,
+// and couponCode, if not empty, is redeemed on it.
+func (s *cartService) Checkout(ctx context.Context, accountId uint64, shipTo orderModels.Address, shippingMethod, couponCode string) (*orderModels.Order, error) {
+	c, err := s.GetCart(ctx, models.Owner{AccountId: accountId})
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Items) == 0 {
+		return nil, cart.ErrEmptyCart
+	}
+
+	products := make([]*orderModels.OrderedProduct, len(c.Items))
+	for i, item := range c.Items {
+		if !item.Available {
+			return nil, cart.ErrUnavailableItems
+		}
+		products[i] = &orderModels.OrderedProduct{ID: item.ProductId, Quantity: uint32(item.Quantity)}
+	}
+	if c.Subtotal == nil {
+		return nil, cart.ErrMixedCurrencies
+	}
+
+	order, err := s.orders.PostOrder(idempotency.OutgoingContext(ctx), accountId, products, shipTo, shippingMethod, couponCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ClearCart(context.WithoutCancel(ctx), c.Owner.Key()); err != nil {
+		log.Printf("Failed to clear cart after placing order %d: %v", order.ID, err)
+	}
+	return order, nil
+}
+
+// DeleteAbandonedCarts deletes session carts nobody touched for
+// config.SessionCartTTL and returns how many items they held.
+func (s *cartService) DeleteAbandonedCarts(ctx context.Context) (int64, error) {
+	return s.repo.DeleteSessionCarts(ctx, time.Now().UTC().Add(-config.SessionCartTTL))
+}
+
+// price looks up the current details of the products in a cart.
+func (s *cartService) price(ctx context.Context, owner models.Owner, items []models.Item) (*models.Cart, error) {
+	c := &models.Cart{Owner: owner, Items: []models.PricedItem{}}
+	if len(items) == 0 {
+		return c, nil
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductId
+	}
+	products, err := s.catalog.GetProducts(ctx, 0, 0, ids, "", productModels.ProductFilter{IncludeHidden: true})
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]*productModels.Product, len(products))
+	for i := range products {
+		byId[products[i].Id] = &products[i]
+	}
+
+	var lines []pricing.Line
+	for _, item := range items {
+		priced := models.PricedItem{ProductId: item.ProductId, Quantity: item.Quantity}
+		if p, ok := byId[item.ProductId]; ok {
+			priced.Name = p.Name
+			priced.Available = orderable(p)
+		}
+		if priced.Available {
+			line := pricing.Line{UnitPrice: byId[item.ProductId].Price, Quantity: uint32(item.Quantity)}
+			priced.UnitPrice, priced.LineTotal = line.UnitPrice, line.Total()
+			lines = append(lines, line)
+		}
+		c.Items = append(c.Items, priced)
+	}
+
+	if len(lines) > 0 {
+		subtotal, err := pricing.Total(lines...)
+		if err != nil && !errors.Is(err, money.ErrCurrencyMismatch) {
+			return nil, err
+		}
+		if err == nil {
+			c.Subtotal = &subtotal
+		}
+	}
+	return c, nil
+}
+
+func orderable(p *productModels.Product) bool {
+	return p.IsPublished() && !p.IsDeleted()
+}
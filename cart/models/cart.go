@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+// Owner is who a cart belongs to: a signed in account or, before signing
+// in, an anonymous session.
+type Owner struct {
+	AccountId uint64
+	SessionId string
+}
+
+// Key identifies the cart of the owner in storage.
+func (o Owner) Key() string {
+	if o.AccountId != 0 {
+		return "account:" + strconv.FormatUint(o.AccountId, 10)
+	}
+	return "session:" + o.SessionId
+}
+
+// IsZero reports whether the owner is neither an account nor a session.
+func (o Owner) IsZero() bool {
+	return o.AccountId == 0 && o.SessionId == ""
+}
+
+// Item is a product in a cart as stored, without prices.
+type Item struct {
+	ProductId string
+	Quantity  int
+	AddedAt   time.Time
+}
+
+// Cart is a cart priced against the current product catalog.
+type Cart struct {
+	Owner Owner
+	Items []PricedItem
+	// Subtotal adds up the available items, or is nil when they are priced
+	// in more than one currency.
+	Subtotal *money.Money
+}
+
+// PricedItem is an item with the current details of its product.
+// Unavailable items are products that were unpublished or deleted since they
+// were added; they are not part of the subtotal and block checkout.
+type PricedItem struct {
+	ProductId string
+	Name      string
+	Quantity  int
+	UnitPrice money.Money
+	LineTotal money.Money
+	Available bool
+}
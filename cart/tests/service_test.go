@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abhiii71/orderStream/cart"
+	"github.com/abhiii71/orderStream/cart/internal"
+	"github.com/abhiii71/orderStream/cart/models"
+	orderModels "github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+	productModels "github.com/abhiii71/orderStream/product/models"
+)
+
+type fakeCatalog map[string]productModels.Product
+
+func (c fakeCatalog) GetProducts(_ context.Context, _, _ uint64, ids []string, _ string, _ productModels.ProductFilter) ([]productModels.Product, error) {
+	var products []productModels.Product
+	for id, p := range c {
+		if slices.Contains(ids, id) {
+			p.Id = id
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+type fakeOrders struct {
+	placed [][]*orderModels.OrderedProduct
+}
+
+func (o *fakeOrders) PostOrder(_ context.Context, _ uint64, products []*orderModels.OrderedProduct, _ orderModels.Address, _, _ string) (*orderModels.Order, error) {
+	o.placed = append(o.placed, products)
+	return &orderModels.Order{ID: uint(len(o.placed)), Products: products}, nil
+}
+
+func newService() (internal.Service, fakeCatalog, *fakeOrders) {
+	catalog := fakeCatalog{
+		"mug":    {Name: "Mug", Price: money.New(1250, "USD")},
+		"shirt":  {Name: "Shirt", Price: money.New(2000, "USD")},
+		"poster": {Name: "Poster", Price: money.New(900, "EUR")},
+	}
+	orders := &fakeOrders{}
+	return internal.NewCartService(internal.NewMemoryRepository(), catalog, orders), catalog, orders
+}
+
+func TestAddItemStartsSessionCart(t *testing.T) {
+	service, _, _ := newService()
+	ctx := context.Background()
+
+	c, err := service.AddItem(ctx, models.Owner{}, "mug", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Owner.SessionId == "" {
+		t.Fatal("AddItem without an owner did not start a session cart")
+	}
+	c, err = service.AddItem(ctx, c.Owner, "mug", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Items) != 1 || c.Items[0].Quantity != 3 {
+		t.Fatalf("items = %+v, want 3 mugs", c.Items)
+	}
+	if c.Subtotal == nil || *c.Subtotal != money.New(3750, "USD") {
+		t.Errorf("subtotal = %v, want 37.50 USD", c.Subtotal)
+	}
+}
+
+func TestMergeCarts(t *testing.T) {
+	service, _, _ := newService()
+	ctx := context.Background()
+	account := models.Owner{AccountId: 7}
+
+	session, err := service.AddItem(ctx, models.Owner{}, "mug", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddItem(ctx, session.Owner, "shirt", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddItem(ctx, account, "mug", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := service.MergeCarts(ctx, session.Owner.SessionId, account.AccountId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Items) != 2 || c.Subtotal == nil || *c.Subtotal != money.New(5750, "USD") {
+		t.Errorf("merged cart = %+v, want 3 mugs and a shirt", c)
+	}
+	if c, _ := service.GetCart(ctx, session.Owner); len(c.Items) != 0 {
+		t.Errorf("session cart still has %d items after merging", len(c.Items))
+	}
+}
+
+func TestUpdateAndRemoveItems(t *testing.T) {
+	service, _, _ := newService()
+	ctx := context.Background()
+	owner := models.Owner{AccountId: 1}
+
+	if _, err := service.UpdateItem(ctx, owner, "mug", 1); !errors.Is(err, cart.ErrItemNotFound) {
+		t.Errorf("UpdateItem of a missing item error = %v, want ErrItemNotFound", err)
+	}
+	if _, err := service.AddItem(ctx, owner, "mug", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddItem(ctx, owner, "mug", cart.MaxQuantity); !errors.Is(err, cart.ErrInvalidQuantity) {
+		t.Errorf("AddItem over the maximum error = %v, want ErrInvalidQuantity", err)
+	}
+	c, err := service.UpdateItem(ctx, owner, "mug", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Items[0].Quantity != 5 {
+		t.Errorf("quantity = %d, want 5", c.Items[0].Quantity)
+	}
+	c, err = service.UpdateItem(ctx, owner, "mug", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Items) != 0 {
+		t.Errorf("UpdateItem to 0 left %d items", len(c.Items))
+	}
+}
+
+func TestCheckoutClearsCart(t *testing.T) {
+	service, _, orders := newService()
+	ctx := context.Background()
+	owner := models.Owner{AccountId: 1}
+
+	if _, err := service.Checkout(ctx, owner.AccountId, orderModels.Address{}, "", ""); !errors.Is(err, cart.ErrEmptyCart) {
+		t.Errorf("Checkout of an empty cart error = %v, want ErrEmptyCart", err)
+	}
+	if _, err := service.AddItem(ctx, owner, "mug", 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Checkout(ctx, owner.AccountId, orderModels.Address{}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders.placed) != 1 || orders.placed[0][0].ID != "mug" || orders.placed[0][0].Quantity != 2 {
+		t.Errorf("placed orders = %+v, want one order of 2 mugs", orders.placed)
+	}
+	if c, _ := service.GetCart(ctx, owner); len(c.Items) != 0 {
+		t.Errorf("cart has %d items after checkout", len(c.Items))
+	}
+}
+
+func TestUnavailableItemsBlockCheckout(t *testing.T) {
+	service, catalog, orders := newService()
+	ctx := context.Background()
+	owner := models.Owner{AccountId: 1}
+
+	if _, err := service.AddItem(ctx, owner, "mug", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddItem(ctx, owner, "shirt", 1); err != nil {
+		t.Fatal(err)
+	}
+	shirt := catalog["shirt"]
+	shirt.Status = product.StatusArchived
+	catalog["shirt"] = shirt
+
+	c, err := service.GetCart(ctx, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Items[1].Available || c.Subtotal == nil || *c.Subtotal != money.New(1250, "USD") {
+		t.Errorf("cart = %+v, want the shirt unavailable and left out of the subtotal", c)
+	}
+	if _, err := service.Checkout(ctx, owner.AccountId, orderModels.Address{}, "", ""); !errors.Is(err, cart.ErrUnavailableItems) {
+		t.Errorf("Checkout error = %v, want ErrUnavailableItems", err)
+	}
+	if len(orders.placed) != 0 {
+		t.Errorf("placed %d orders, want none", len(orders.placed))
+	}
+	if _, err := service.AddItem(ctx, owner, "shirt", 1); !errors.Is(err, cart.ErrProductUnavailable) {
+		t.Errorf("AddItem of an archived product error = %v, want ErrProductUnavailable", err)
+	}
+}
+
+func TestAddItemRejectsOtherCurrency(t *testing.T) {
+	service, _, _ := newService()
+	ctx := context.Background()
+	owner := models.Owner{AccountId: 1}
+
+	if _, err := service.AddItem(ctx, owner, "mug", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddItem(ctx, owner, "poster", 1); !errors.Is(err, cart.ErrMixedCurrencies) {
+		t.Errorf("AddItem in another currency error = %v, want ErrMixedCurrencies", err)
+	}
+}
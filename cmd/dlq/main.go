@@ -0,0 +1,160 @@
+// Command dlq inspects and resolves the dead letter topic of a Kafka topic.
+//
+//	dlq list <topic> [-n 20]
+//	dlq replay <topic> [-n 1]
+//	dlq replay <topic> -partition 0 -offset 42
+//	dlq discard <topic> [-n 1]
+//
+// list shows pending dead letters, oldest first. replay publishes the next n
+// pending dead letters to their original topic and acknowledges them;
+// given -partition and -offset it replays that single message and leaves
+// the pending list as it is. discard acknowledges the next n pending dead
+// letters without replaying them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/abhiii71/orderStream/pkg/kafka"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 3 {
+		usage()
+	}
+	command, topic := os.Args[1], os.Args[2]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	brokers := flags.String("brokers", os.Getenv("KAFKA_BOOTSTRAP_SERVERS"), "Kafka broker address")
+	n := flags.Int("n", 0, "number of dead letters")
+	partition := flags.Int("partition", -1, "partition of a single dead letter to replay")
+	offset := flags.Int64("offset", -1, "offset of a single dead letter to replay")
+	flags.Parse(os.Args[3:])
+
+	if *brokers == "" {
+		*brokers = "localhost:9092"
+	}
+
+	letters, err := kafka.OpenDeadLetters(*brokers, topic)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer letters.Close()
+
+	switch command {
+	case "list":
+		err = list(letters, orDefault(*n, 20))
+	case "replay":
+		if *partition >= 0 && *offset >= 0 {
+			err = replayOne(letters, *brokers, int32(*partition), *offset)
+		} else {
+			err = replay(letters, *brokers, orDefault(*n, 1))
+		}
+	case "discard":
+		err = discard(letters, orDefault(*n, 1))
+	default:
+		usage()
+	}
+	if err != nil {
+		letters.Close()
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	log.Fatal("usage: dlq list|replay|discard <topic> [-brokers host:port] [-n count] [-partition p -offset o]")
+}
+
+func list(letters *kafka.DeadLetters, n int) error {
+	pending, err := letters.Pending(n)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("no pending dead letters")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PARTITION/OFFSET\tFAILED AT\tATTEMPTS\tKEY\tERROR\tVALUE")
+	for _, l := range pending {
+		fmt.Fprintf(w, "%d/%d\t%s\t%s\t%s\t%s\t%s\n", l.Partition, l.Offset,
+			l.Headers[kafka.HeaderFailedAt], l.Headers[kafka.HeaderAttempts], l.Key,
+			truncate(l.Headers[kafka.HeaderError], 60), truncate(string(l.Value), 80))
+	}
+	return w.Flush()
+}
+
+func replay(letters *kafka.DeadLetters, brokers string, n int) error {
+	pending, err := letters.Pending(n)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	producer, err := kafka.NewSyncProducer(brokers)
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	if err := letters.Replay(producer, pending...); err != nil {
+		return err
+	}
+	if err := letters.Acknowledge(pending...); err != nil {
+		return err
+	}
+	fmt.Printf("replayed %d dead letters\n", len(pending))
+	return nil
+}
+
+func replayOne(letters *kafka.DeadLetters, brokers string, partition int32, offset int64) error {
+	letter, err := letters.Get(partition, offset)
+	if err != nil {
+		return err
+	}
+
+	producer, err := kafka.NewSyncProducer(brokers)
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	if err := letters.Replay(producer, letter); err != nil {
+		return err
+	}
+	fmt.Printf("replayed %d/%d to %s\n", partition, offset, letter.OriginalTopic())
+	return nil
+}
+
+func discard(letters *kafka.DeadLetters, n int) error {
+	pending, err := letters.Pending(n)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	if err := letters.Acknowledge(pending...); err != nil {
+		return err
+	}
+	fmt.Printf("discarded %d dead letters\n", len(pending))
+	return nil
+}
+
+func orDefault(n, def int) int {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -15,7 +15,7 @@ import (
 
 func main() {
 
-	server, err :=  graph.NewGraphQLServer(config.AccountUrl, config.ProductUrl, config.OrderUrl, config.PaymentUrl, config.RecommenderUrl)
+	server, err := graph.NewGraphQLServer(config.AccountUrl, config.ProductUrl, config.OrderUrl, config.PaymentUrl, config.CartUrl, config.RecommenderUrl)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -36,6 +36,7 @@ func main() {
 
 	engine.POST("/graphql",
 		middleware.AuthorizeJWT(),
+		middleware.IdempotencyKey(),
 		gin.WrapH(serv),
 	)
 
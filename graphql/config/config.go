@@ -7,6 +7,7 @@ var (
 	ProductUrl     string
 	OrderUrl       string
 	PaymentUrl     string
+	CartUrl        string
 	RecommenderUrl string
 	SecretKey      string
 	Issuer         string
@@ -17,6 +18,7 @@ func init() {
 	ProductUrl = os.Getenv("PRODUCT_URL")
 	OrderUrl = os.Getenv("ORDER_URL")
 	PaymentUrl = os.Getenv("PAYMENT_URL")
+	CartUrl = os.Getenv("CART_URL")
 	RecommenderUrl = os.Getenv("RECOMMENDER_URL")
 	SecretKey = os.Getenv("SECRET_KEY")
 	Issuer = os.Getenv("ISSUER")
@@ -3,20 +3,61 @@
 package generated
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 )
 
+type Address struct {
+	Name       *string `json:"name,omitempty"`
+	Line1      *string `json:"line1,omitempty"`
+	Line2      *string `json:"line2,omitempty"`
+	City       *string `json:"city,omitempty"`
+	PostalCode *string `json:"postalCode,omitempty"`
+	Country    string  `json:"country"`
+	Region     *string `json:"region,omitempty"`
+}
+
+type AddressInput struct {
+	Name       *string `json:"name,omitempty"`
+	Line1      *string `json:"line1,omitempty"`
+	Line2      *string `json:"line2,omitempty"`
+	City       *string `json:"city,omitempty"`
+	PostalCode *string `json:"postalCode,omitempty"`
+	Country    string  `json:"country"`
+	Region     *string `json:"region,omitempty"`
+}
+
 type AuthResponse struct {
 	Token string `json:"token"`
 }
 
+type Cart struct {
+	SessionID *string     `json:"sessionId,omitempty"`
+	Items     []*CartItem `json:"items"`
+	Subtotal  *float64    `json:"subtotal,omitempty"`
+	Currency  *string     `json:"currency,omitempty"`
+}
+
+type CartItem struct {
+	ProductID string   `json:"productId"`
+	Name      string   `json:"name"`
+	Quantity  int      `json:"quantity"`
+	UnitPrice *float64 `json:"unitPrice,omitempty"`
+	LineTotal *float64 `json:"lineTotal,omitempty"`
+	Available bool     `json:"available"`
+}
+
 type CheckoutInput struct {
-	AccounID    int                     `json:"accounId"`
-	Name        string                  `json:"name"`
-	Email       string                  `json:"email"`
-	RedirectURL string                  `json:"redirectUrl"`
-	Products    []*CheckoutProductInput `json:"products"`
-	OrderID     int                     `json:"orderId"`
+	AccounID      int                     `json:"accounId"`
+	Name          string                  `json:"name"`
+	Email         string                  `json:"email"`
+	RedirectURL   string                  `json:"redirectUrl"`
+	Products      []*CheckoutProductInput `json:"products"`
+	OrderID       int                     `json:"orderId"`
+	ExpectedTotal *MoneyInput             `json:"expectedTotal,omitempty"`
 }
 
 type CheckoutProductInput struct {
@@ -24,10 +65,65 @@ type CheckoutProductInput struct {
 	Quantity int    `json:"quantity"`
 }
 
+type Coupon struct {
+	ID                       int         `json:"id"`
+	Code                     string      `json:"code"`
+	Description              string      `json:"description"`
+	SellerID                 *int        `json:"sellerId,omitempty"`
+	Type                     CouponType  `json:"type"`
+	PercentOff               *float64    `json:"percentOff,omitempty"`
+	AmountOff                *float64    `json:"amountOff,omitempty"`
+	Currency                 *string     `json:"currency,omitempty"`
+	Scope                    CouponScope `json:"scope"`
+	ProductIds               []string    `json:"productIds"`
+	Categories               []string    `json:"categories"`
+	MinOrderValue            *float64    `json:"minOrderValue,omitempty"`
+	MaxRedemptions           *int        `json:"maxRedemptions,omitempty"`
+	MaxRedemptionsPerAccount *int        `json:"maxRedemptionsPerAccount,omitempty"`
+	Redemptions              int         `json:"redemptions"`
+	StartsAt                 *time.Time  `json:"startsAt,omitempty"`
+	EndsAt                   *time.Time  `json:"endsAt,omitempty"`
+	Active                   bool        `json:"active"`
+	CreatedAt                time.Time   `json:"createdAt"`
+}
+
+type CouponInput struct {
+	Code                     string      `json:"code"`
+	Description              *string     `json:"description,omitempty"`
+	Type                     CouponType  `json:"type"`
+	PercentOff               *float64    `json:"percentOff,omitempty"`
+	AmountOff                *float64    `json:"amountOff,omitempty"`
+	Currency                 *string     `json:"currency,omitempty"`
+	Scope                    CouponScope `json:"scope"`
+	ProductIds               []string    `json:"productIds,omitempty"`
+	Categories               []string    `json:"categories,omitempty"`
+	MinOrderValue            *float64    `json:"minOrderValue,omitempty"`
+	MaxRedemptions           *int        `json:"maxRedemptions,omitempty"`
+	MaxRedemptionsPerAccount *int        `json:"maxRedemptionsPerAccount,omitempty"`
+	StartsAt                 *time.Time  `json:"startsAt,omitempty"`
+	EndsAt                   *time.Time  `json:"endsAt,omitempty"`
+}
+
 type CreateProductInput struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Name           string                `json:"name"`
+	Description    string                `json:"description"`
+	Price          float64               `json:"price"`
+	Currency       *string               `json:"currency,omitempty"`
+	PriceOverrides []*PriceOverrideInput `json:"priceOverrides,omitempty"`
+	Status         *ProductStatus        `json:"status,omitempty"`
+	PublishAt      *time.Time            `json:"publishAt,omitempty"`
+	Stock          *int                  `json:"stock,omitempty"`
+	Sku            *string               `json:"sku,omitempty"`
+	Category       *string               `json:"category,omitempty"`
+	TaxCategory    *TaxCategory          `json:"taxCategory,omitempty"`
+	WeightGrams    *int                  `json:"weightGrams,omitempty"`
+}
+
+type CreateReviewInput struct {
+	ProductID string `json:"productId"`
+	Rating    int    `json:"rating"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
 }
 
 type CustomerPortalSessionInput struct {
@@ -37,30 +133,84 @@ type CustomerPortalSessionInput struct {
 }
 
 type LoginInput struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email         string  `json:"email"`
+	Password      string  `json:"password"`
+	CartSessionID *string `json:"cartSessionId,omitempty"`
+}
+
+type MoneyInput struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
 }
 
 type Mutation struct {
 }
 
 type Order struct {
-	ID         int               `json:"id"`
-	CreatedAt  time.Time         `json:"createdAt"`
-	TotalPrice float64           `json:"totalPrice"`
-	Products   []*OrderedProduct `json:"products"`
+	ID             int                  `json:"id"`
+	CreatedAt      time.Time            `json:"createdAt"`
+	TotalPrice     float64              `json:"totalPrice"`
+	Currency       string               `json:"currency"`
+	Products       []*OrderedProduct    `json:"products"`
+	Status         string               `json:"status"`
+	Timeline       []*OrderStatusChange `json:"timeline"`
+	Subtotal       float64              `json:"subtotal"`
+	Discount       float64              `json:"discount"`
+	CouponCode     *string              `json:"couponCode,omitempty"`
+	Discounts      []*OrderDiscount     `json:"discounts"`
+	Tax            float64              `json:"tax"`
+	ShipTo         *Address             `json:"shipTo,omitempty"`
+	ShippingMethod *string              `json:"shippingMethod,omitempty"`
+	Shipping       float64              `json:"shipping"`
+	Shipments      []*Shipment          `json:"shipments"`
+}
+
+type OrderConnection struct {
+	Orders      []*Order `json:"orders"`
+	EndCursor   *string  `json:"endCursor,omitempty"`
+	HasNextPage bool     `json:"hasNextPage"`
+}
+
+type OrderDiscount struct {
+	CouponCode string  `json:"couponCode"`
+	ProductID  string  `json:"productId"`
+	Amount     float64 `json:"amount"`
+}
+
+type OrderFilter struct {
+	Status        []string   `json:"status,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	MinTotal      *float64   `json:"minTotal,omitempty"`
+	Currency      *string    `json:"currency,omitempty"`
 }
 
 type OrderInput struct {
-	Products []*OrderedProductInput `json:"products"`
+	Products       []*OrderedProductInput `json:"products"`
+	CouponCode     *string                `json:"couponCode,omitempty"`
+	ShipTo         *AddressInput          `json:"shipTo,omitempty"`
+	ShippingMethod *string                `json:"shippingMethod,omitempty"`
+}
+
+type OrderStatusChange struct {
+	From      *string   `json:"from,omitempty"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type OrderedProduct struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Sku         *string      `json:"sku,omitempty"`
+	Price       float64      `json:"price"`
+	Quantity    int          `json:"quantity"`
+	LineTotal   float64      `json:"lineTotal"`
+	TaxCategory *TaxCategory `json:"taxCategory,omitempty"`
+	TaxRate     float64      `json:"taxRate"`
+	Tax         float64      `json:"tax"`
 }
 
 type OrderedProductInput struct {
@@ -73,12 +223,54 @@ type PaginationInput struct {
 	Take int `json:"take"`
 }
 
+type PriceHistoryEntry struct {
+	Price         float64   `json:"price"`
+	PreviousPrice float64   `json:"previousPrice"`
+	Reason        string    `json:"reason"`
+	ChangedAt     time.Time `json:"changedAt"`
+}
+
+type PriceOverride struct {
+	Currency string  `json:"currency"`
+	Price    float64 `json:"price"`
+}
+
+type PriceOverrideInput struct {
+	Currency string  `json:"currency"`
+	Price    float64 `json:"price"`
+}
+
 type Product struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	AccountID   int     `json:"accountId"`
+	ID              string               `json:"id"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description"`
+	Price           float64              `json:"price"`
+	Currency        string               `json:"currency"`
+	PriceOverrides  []*PriceOverride     `json:"priceOverrides"`
+	DisplayPrice    float64              `json:"displayPrice"`
+	DisplayCurrency string               `json:"displayCurrency"`
+	AccountID       int                  `json:"accountId"`
+	Images          []*ProductImage      `json:"images"`
+	Status          ProductStatus        `json:"status"`
+	PublishAt       *time.Time           `json:"publishAt,omitempty"`
+	Version         int                  `json:"version"`
+	RatingAverage   float64              `json:"ratingAverage"`
+	RatingCount     int                  `json:"ratingCount"`
+	PriceHistory    []*PriceHistoryEntry `json:"priceHistory"`
+	DeletedAt       *time.Time           `json:"deletedAt,omitempty"`
+	Stock           *int                 `json:"stock,omitempty"`
+	Sku             *string              `json:"sku,omitempty"`
+	Category        *string              `json:"category,omitempty"`
+	TaxCategory     TaxCategory          `json:"taxCategory"`
+	WeightGrams     int                  `json:"weightGrams"`
+}
+
+type ProductImage struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	ContentType  string `json:"contentType"`
+	Size         int    `json:"size"`
+	Position     int    `json:"position"`
 }
 
 type Query struct {
@@ -94,9 +286,515 @@ type RegisterInput struct {
 	Password string `json:"password"`
 }
 
+type Review struct {
+	ID        string       `json:"id"`
+	ProductID string       `json:"productId"`
+	AccountID int          `json:"accountId"`
+	Rating    int          `json:"rating"`
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	Reply     *ReviewReply `json:"reply,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+type ReviewReply struct {
+	AccountID int       `json:"accountId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SchedulePriceChangeInput struct {
+	ProductID string     `json:"productId"`
+	Price     float64    `json:"price"`
+	StartAt   *time.Time `json:"startAt,omitempty"`
+	EndAt     *time.Time `json:"endAt,omitempty"`
+}
+
+type ScheduledPrice struct {
+	ID        string     `json:"id"`
+	ProductID string     `json:"productId"`
+	Price     float64    `json:"price"`
+	StartAt   time.Time  `json:"startAt"`
+	EndAt     *time.Time `json:"endAt,omitempty"`
+	Status    string     `json:"status"`
+}
+
+type Shipment struct {
+	ID             int              `json:"id"`
+	Carrier        string           `json:"carrier"`
+	TrackingNumber string           `json:"trackingNumber"`
+	ProductIds     []string         `json:"productIds"`
+	Status         ShipmentStatus   `json:"status"`
+	Events         []*ShipmentEvent `json:"events"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
+}
+
+type ShipmentEvent struct {
+	Status      ShipmentStatus `json:"status"`
+	Description string         `json:"description"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+type ShipmentInput struct {
+	Carrier        string   `json:"carrier"`
+	TrackingNumber string   `json:"trackingNumber"`
+	ProductIds     []string `json:"productIds,omitempty"`
+}
+
+type ShippingMethod struct {
+	Code      string          `json:"code"`
+	Name      string          `json:"name"`
+	Carrier   *string         `json:"carrier,omitempty"`
+	Countries []string        `json:"countries"`
+	Rates     []*ShippingRate `json:"rates"`
+}
+
+type ShippingRate struct {
+	Currency string           `json:"currency"`
+	Type     ShippingRateType `json:"type"`
+	Amount   float64          `json:"amount"`
+	PerKg    float64          `json:"perKg"`
+	FreeOver *float64         `json:"freeOver,omitempty"`
+}
+
+type UpdateCouponInput struct {
+	ID                       int        `json:"id"`
+	Description              *string    `json:"description,omitempty"`
+	MinOrderValue            *float64   `json:"minOrderValue,omitempty"`
+	Currency                 *string    `json:"currency,omitempty"`
+	MaxRedemptions           *int       `json:"maxRedemptions,omitempty"`
+	MaxRedemptionsPerAccount *int       `json:"maxRedemptionsPerAccount,omitempty"`
+	StartsAt                 *time.Time `json:"startsAt,omitempty"`
+	EndsAt                   *time.Time `json:"endsAt,omitempty"`
+	Active                   bool       `json:"active"`
+}
+
 type UpdateProductInput struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	ID             string                `json:"id"`
+	Name           string                `json:"name"`
+	Description    string                `json:"description"`
+	Price          float64               `json:"price"`
+	Currency       *string               `json:"currency,omitempty"`
+	PriceOverrides []*PriceOverrideInput `json:"priceOverrides,omitempty"`
+	Version        int                   `json:"version"`
+	Stock          *int                  `json:"stock,omitempty"`
+	UntrackStock   *bool                 `json:"untrackStock,omitempty"`
+	Sku            *string               `json:"sku,omitempty"`
+	Category       *string               `json:"category,omitempty"`
+	TaxCategory    *TaxCategory          `json:"taxCategory,omitempty"`
+	WeightGrams    *int                  `json:"weightGrams,omitempty"`
+}
+
+type UpdateReviewInput struct {
+	ID     string `json:"id"`
+	Rating int    `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+type CouponScope string
+
+const (
+	CouponScopeCart     CouponScope = "CART"
+	CouponScopeProduct  CouponScope = "PRODUCT"
+	CouponScopeCategory CouponScope = "CATEGORY"
+)
+
+var AllCouponScope = []CouponScope{
+	CouponScopeCart,
+	CouponScopeProduct,
+	CouponScopeCategory,
+}
+
+func (e CouponScope) IsValid() bool {
+	switch e {
+	case CouponScopeCart, CouponScopeProduct, CouponScopeCategory:
+		return true
+	}
+	return false
+}
+
+func (e CouponScope) String() string {
+	return string(e)
+}
+
+func (e *CouponScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CouponScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CouponScope", str)
+	}
+	return nil
+}
+
+func (e CouponScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CouponScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CouponScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type CouponType string
+
+const (
+	CouponTypePercentage CouponType = "PERCENTAGE"
+	CouponTypeFixed      CouponType = "FIXED"
+)
+
+var AllCouponType = []CouponType{
+	CouponTypePercentage,
+	CouponTypeFixed,
+}
+
+func (e CouponType) IsValid() bool {
+	switch e {
+	case CouponTypePercentage, CouponTypeFixed:
+		return true
+	}
+	return false
+}
+
+func (e CouponType) String() string {
+	return string(e)
+}
+
+func (e *CouponType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CouponType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CouponType", str)
+	}
+	return nil
+}
+
+func (e CouponType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CouponType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CouponType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ProductSort string
+
+const (
+	ProductSortRelevance ProductSort = "RELEVANCE"
+	ProductSortRating    ProductSort = "RATING"
+)
+
+var AllProductSort = []ProductSort{
+	ProductSortRelevance,
+	ProductSortRating,
+}
+
+func (e ProductSort) IsValid() bool {
+	switch e {
+	case ProductSortRelevance, ProductSortRating:
+		return true
+	}
+	return false
+}
+
+func (e ProductSort) String() string {
+	return string(e)
+}
+
+func (e *ProductSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductSort", str)
+	}
+	return nil
+}
+
+func (e ProductSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProductSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProductSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ProductStatus string
+
+const (
+	ProductStatusDraft     ProductStatus = "DRAFT"
+	ProductStatusPublished ProductStatus = "PUBLISHED"
+	ProductStatusArchived  ProductStatus = "ARCHIVED"
+)
+
+var AllProductStatus = []ProductStatus{
+	ProductStatusDraft,
+	ProductStatusPublished,
+	ProductStatusArchived,
+}
+
+func (e ProductStatus) IsValid() bool {
+	switch e {
+	case ProductStatusDraft, ProductStatusPublished, ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+func (e ProductStatus) String() string {
+	return string(e)
+}
+
+func (e *ProductStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductStatus", str)
+	}
+	return nil
+}
+
+func (e ProductStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProductStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProductStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ShipmentStatus string
+
+const (
+	ShipmentStatusShipped        ShipmentStatus = "SHIPPED"
+	ShipmentStatusInTransit      ShipmentStatus = "IN_TRANSIT"
+	ShipmentStatusOutForDelivery ShipmentStatus = "OUT_FOR_DELIVERY"
+	ShipmentStatusDelivered      ShipmentStatus = "DELIVERED"
+	ShipmentStatusReturned       ShipmentStatus = "RETURNED"
+)
+
+var AllShipmentStatus = []ShipmentStatus{
+	ShipmentStatusShipped,
+	ShipmentStatusInTransit,
+	ShipmentStatusOutForDelivery,
+	ShipmentStatusDelivered,
+	ShipmentStatusReturned,
+}
+
+func (e ShipmentStatus) IsValid() bool {
+	switch e {
+	case ShipmentStatusShipped, ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered, ShipmentStatusReturned:
+		return true
+	}
+	return false
+}
+
+func (e ShipmentStatus) String() string {
+	return string(e)
+}
+
+func (e *ShipmentStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ShipmentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ShipmentStatus", str)
+	}
+	return nil
+}
+
+func (e ShipmentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ShipmentStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ShipmentStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ShippingRateType string
+
+const (
+	ShippingRateTypeFlat   ShippingRateType = "FLAT"
+	ShippingRateTypeWeight ShippingRateType = "WEIGHT"
+)
+
+var AllShippingRateType = []ShippingRateType{
+	ShippingRateTypeFlat,
+	ShippingRateTypeWeight,
+}
+
+func (e ShippingRateType) IsValid() bool {
+	switch e {
+	case ShippingRateTypeFlat, ShippingRateTypeWeight:
+		return true
+	}
+	return false
+}
+
+func (e ShippingRateType) String() string {
+	return string(e)
+}
+
+func (e *ShippingRateType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ShippingRateType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ShippingRateType", str)
+	}
+	return nil
+}
+
+func (e ShippingRateType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ShippingRateType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ShippingRateType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TaxCategory string
+
+const (
+	TaxCategoryPhysical        TaxCategory = "PHYSICAL"
+	TaxCategoryPhysicalReduced TaxCategory = "PHYSICAL_REDUCED"
+	TaxCategoryDigital         TaxCategory = "DIGITAL"
+	TaxCategoryEBook           TaxCategory = "E_BOOK"
+	TaxCategorySaas            TaxCategory = "SAAS"
+	TaxCategoryEdtech          TaxCategory = "EDTECH"
+	TaxCategoryExempt          TaxCategory = "EXEMPT"
+)
+
+var AllTaxCategory = []TaxCategory{
+	TaxCategoryPhysical,
+	TaxCategoryPhysicalReduced,
+	TaxCategoryDigital,
+	TaxCategoryEBook,
+	TaxCategorySaas,
+	TaxCategoryEdtech,
+	TaxCategoryExempt,
+}
+
+func (e TaxCategory) IsValid() bool {
+	switch e {
+	case TaxCategoryPhysical, TaxCategoryPhysicalReduced, TaxCategoryDigital, TaxCategoryEBook, TaxCategorySaas, TaxCategoryEdtech, TaxCategoryExempt:
+		return true
+	}
+	return false
+}
+
+func (e TaxCategory) String() string {
+	return string(e)
+}
+
+func (e *TaxCategory) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaxCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaxCategory", str)
+	}
+	return nil
+}
+
+func (e TaxCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaxCategory) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaxCategory) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
@@ -29,23 +29,7 @@ func (r *accountResolver) Orders(ctx context.Context, obj *models.Account) ([]*g
 
 	var orders []*generated.Order
 	for _, order := range orderList {
-		var products []*generated.OrderedProduct
-		for _, orderProduct := range order.Products {
-			products = append(products, &generated.OrderedProduct{
-				ID:          orderProduct.ID,
-				Name:        orderProduct.Name,
-				Description: orderProduct.Description,
-				Price:       order.TotalPrice,
-				Quantity:    int(orderProduct.Quantity),
-			})
-		}
-
-		orders = append(orders, &generated.Order{
-			ID:         int(order.ID),
-			CreatedAt:  order.CreatedAt,
-			TotalPrice: order.TotalPrice,
-			Products:   products,
-		})
+		orders = append(orders, toGraphQLOrder(&order))
 	}
 
 	return orders, nil
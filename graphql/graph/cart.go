@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/abhiii71/orderStream/cart/models"
+	"github.com/abhiii71/orderStream/graphql/generated"
+	"github.com/abhiii71/orderStream/pkg/auth"
+)
+
+// cartOwner is the signed in account or, for anonymous callers, the session
+// cart they were given by addToCart.
+func cartOwner(ctx context.Context, sessionId *string) models.Owner {
+	if accountId, err := auth.GetUserIdInt(ctx, false); err == nil {
+		return models.Owner{AccountId: uint64(accountId)}
+	}
+	if sessionId != nil {
+		return models.Owner{SessionId: *sessionId}
+	}
+	return models.Owner{}
+}
+
+func toGraphQLCart(c *models.Cart) *generated.Cart {
+	items := []*generated.CartItem{}
+	for _, item := range c.Items {
+		cartItem := &generated.CartItem{
+			ProductID: item.ProductId,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Available: item.Available,
+		}
+		if item.Available {
+			unitPrice, lineTotal := item.UnitPrice.Float(), item.LineTotal.Float()
+			cartItem.UnitPrice, cartItem.LineTotal = &unitPrice, &lineTotal
+		}
+		items = append(items, cartItem)
+	}
+
+	cart := &generated.Cart{Items: items}
+	if c.Owner.SessionId != "" {
+		cart.SessionID = &c.Owner.SessionId
+	}
+	if c.Subtotal != nil {
+		subtotal := c.Subtotal.Float()
+		cart.Subtotal, cart.Currency = &subtotal, &c.Subtotal.Currency
+	}
+	return cart
+}
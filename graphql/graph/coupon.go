@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"math"
+	"strings"
+
+	"github.com/abhiii71/orderStream/graphql/generated"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/promotions"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func fromCouponInput(in generated.CouponInput) *promotions.Coupon {
+	currency := ""
+	if in.Currency != nil {
+		currency = strings.ToUpper(*in.Currency)
+	}
+
+	coupon := &promotions.Coupon{
+		Code:       in.Code,
+		Type:       strings.ToLower(string(in.Type)),
+		Scope:      strings.ToLower(string(in.Scope)),
+		ProductIds: in.ProductIds,
+		Categories: in.Categories,
+		StartsAt:   in.StartsAt,
+		EndsAt:     in.EndsAt,
+	}
+	if in.Description != nil {
+		coupon.Description = *in.Description
+	}
+	if in.PercentOff != nil {
+		// percent to basis points
+		coupon.PercentOff = int64(math.Round(*in.PercentOff * 100))
+	}
+	if in.AmountOff != nil {
+		coupon.AmountOff = money.FromFloat(*in.AmountOff, currency)
+	}
+	if in.MinOrderValue != nil {
+		minOrderValue := money.FromFloat(*in.MinOrderValue, currency)
+		coupon.MinOrderValue = &minOrderValue
+	}
+	if in.MaxRedemptions != nil {
+		coupon.MaxRedemptions = *in.MaxRedemptions
+	}
+	if in.MaxRedemptionsPerAccount != nil {
+		coupon.MaxRedemptionsPerAccount = *in.MaxRedemptionsPerAccount
+	}
+	return coupon
+}
+
+func fromUpdateCouponInput(in generated.UpdateCouponInput) *promotions.Coupon {
+	coupon := &promotions.Coupon{
+		ID:       uint64(in.ID),
+		StartsAt: in.StartsAt,
+		EndsAt:   in.EndsAt,
+		Active:   in.Active,
+	}
+	if in.Description != nil {
+		coupon.Description = *in.Description
+	}
+	if in.MinOrderValue != nil {
+		currency := ""
+		if in.Currency != nil {
+			currency = strings.ToUpper(*in.Currency)
+		}
+		minOrderValue := money.FromFloat(*in.MinOrderValue, currency)
+		coupon.MinOrderValue = &minOrderValue
+	}
+	if in.MaxRedemptions != nil {
+		coupon.MaxRedemptions = *in.MaxRedemptions
+	}
+	if in.MaxRedemptionsPerAccount != nil {
+		coupon.MaxRedemptionsPerAccount = *in.MaxRedemptionsPerAccount
+	}
+	return coupon
+}
+
+func toGraphQLCoupon(c *promotions.Coupon) *generated.Coupon {
+	coupon := &generated.Coupon{
+		ID:          int(c.ID),
+		Code:        c.Code,
+		Description: c.Description,
+		Type:        generated.CouponType(strings.ToUpper(c.Type)),
+		Scope:       generated.CouponScope(strings.ToUpper(c.Scope)),
+		ProductIds:  append([]string{}, c.ProductIds...),
+		Categories:  append([]string{}, c.Categories...),
+		Redemptions: c.Redemptions,
+		StartsAt:    c.StartsAt,
+		EndsAt:      c.EndsAt,
+		Active:      c.Active,
+		CreatedAt:   c.CreatedAt,
+	}
+	if c.SellerId != 0 {
+		sellerId := int(c.SellerId)
+		coupon.SellerID = &sellerId
+	}
+	switch c.Type {
+	case promotions.TypePercentage:
+		percentOff := float64(c.PercentOff) / 100
+		coupon.PercentOff = &percentOff
+	case promotions.TypeFixed:
+		amountOff := c.AmountOff.Float()
+		coupon.AmountOff, coupon.Currency = &amountOff, &c.AmountOff.Currency
+	}
+	if c.MinOrderValue != nil {
+		minOrderValue := c.MinOrderValue.Float()
+		coupon.MinOrderValue, coupon.Currency = &minOrderValue, &c.MinOrderValue.Currency
+	}
+	if c.MaxRedemptions != 0 {
+		coupon.MaxRedemptions = &c.MaxRedemptions
+	}
+	if c.MaxRedemptionsPerAccount != 0 {
+		coupon.MaxRedemptionsPerAccount = &c.MaxRedemptionsPerAccount
+	}
+	return coupon
+}
+
+// couponError reports orders rejected for their coupon with error codes
+// clients can act on.
+func couponError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || !strings.HasPrefix(s.Message(), "promotions:") {
+		return err
+	}
+	switch s.Code() {
+	case codes.NotFound:
+		return &gqlerror.Error{
+			Message:    s.Message(),
+			Extensions: map[string]interface{}{"code": "COUPON_NOT_FOUND"},
+		}
+	case codes.FailedPrecondition:
+		return &gqlerror.Error{
+			Message:    s.Message(),
+			Extensions: map[string]interface{}{"code": "COUPON_NOT_APPLICABLE"},
+		}
+	}
+	return err
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"github.com/99designs/gqlgen/graphql"
 	account "github.com/abhiii71/orderStream/account/client"
+	cart "github.com/abhiii71/orderStream/cart/client"
 	"github.com/abhiii71/orderStream/graphql/generated"
 	order "github.com/abhiii71/orderStream/order/client"
 	payment "github.com/abhiii71/orderStream/payment/client"
@@ -15,10 +16,11 @@ type Server struct {
 	productClient     *product.Client
 	orderClient       *order.Client
 	paymentClient     *payment.Client
+	cartClient        *cart.Client
 	recommenderClient *recommender.Client
 }
 
-func NewGraphQLServer(accountUrl, productUrl, orderUrl, paymentUrl, recommenderUrl string) (*Server, error) {
+func NewGraphQLServer(accountUrl, productUrl, orderUrl, paymentUrl, cartUrl, recommenderUrl string) (*Server, error) {
 	accClient, err := account.NewClient(accountUrl)
 	if err != nil {
 		return nil, err
@@ -45,12 +47,22 @@ func NewGraphQLServer(accountUrl, productUrl, orderUrl, paymentUrl, recommenderU
 		return nil, err
 	}
 
+	cartClient, err := cart.NewClient(cartUrl)
+	if err != nil {
+		accClient.Close()
+		prodClient.Close()
+		orderClient.Close()
+		paymentClient.Close()
+		return nil, err
+	}
+
 	recClient, err := recommender.NewClient(productUrl)
 	if err != nil {
 		accClient.Close()
 		prodClient.Close()
 		orderClient.Close()
 		paymentClient.Close()
+		cartClient.Close()
 		return nil, err
 	}
 
@@ -59,6 +71,7 @@ func NewGraphQLServer(accountUrl, productUrl, orderUrl, paymentUrl, recommenderU
 		productClient:     prodClient,
 		orderClient:       orderClient,
 		paymentClient:     paymentClient,
+		cartClient:        cartClient,
 		recommenderClient: recClient,
 	}, nil
 }
@@ -81,6 +94,12 @@ func (s *Server) Account() generated.AccountResolver {
 	}
 }
 
+func (s *Server) Product() generated.ProductResolver {
+	return &productResolver{
+		server: s,
+	}
+}
+
 func (s *Server) ToExecutableSchema() graphql.ExecutableSchema {
 	return generated.NewExecutableSchema(generated.Config{
 		Resolvers: s,
@@ -3,15 +3,23 @@ package graph
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/abhiii71/orderStream/graphql/generated"
 	"github.com/abhiii71/orderStream/order/models"
 	payment "github.com/abhiii71/orderStream/payment/proto/pb"
 	"github.com/abhiii71/orderStream/pkg/auth"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
 	"github.com/abhiii71/orderStream/pkg/middleware"
+	"github.com/abhiii71/orderStream/pkg/money"
 	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -57,9 +65,27 @@ func (r *mutationResolver) Login(ctx context.Context, in generated.LoginInput) (
 	}
 	ginContext.SetCookie("token", token, 3600, "/", "localhost", false, true)
 
+	if in.CartSessionID != nil {
+		r.mergeCartOnLogin(ctx, token, *in.CartSessionID)
+	}
+
 	return &generated.AuthResponse{Token: token}, nil
 }
 
+// mergeCartOnLogin moves the session cart into the cart of the account that
+// just signed in. Signing in does not fail when this does.
+func (r *mutationResolver) mergeCartOnLogin(ctx context.Context, token, sessionId string) {
+	parsed, err := auth.ValidateToken(token)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	claims := parsed.Claims.(*auth.JWTCustomClaims)
+	if _, err := r.server.cartClient.MergeCarts(ctx, sessionId, claims.UserID); err != nil {
+		log.Printf("Failed to merge cart of session %s: %v", sessionId, err)
+	}
+}
+
 func (r *mutationResolver) CreateProduct(ctx context.Context, in generated.CreateProductInput) (*generated.Product, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
@@ -71,7 +97,37 @@ func (r *mutationResolver) CreateProduct(ctx context.Context, in generated.Creat
 		return nil, err
 	}
 	log.Println("CreateProduct called with accountId: ", accountId)
-	postProduct, err := r.server.productClient.PostProduct(ctx, in.Name, in.Description, in.Price, int64(accountId))
+	status := ""
+	if in.Status != nil {
+		status = strings.ToLower(string(*in.Status))
+	}
+
+	currency := ""
+	if in.Currency != nil {
+		currency = strings.ToUpper(*in.Currency)
+	}
+
+	sku := ""
+	if in.Sku != nil {
+		sku = *in.Sku
+	}
+
+	category := ""
+	if in.Category != nil {
+		category = *in.Category
+	}
+
+	taxCategory := ""
+	if in.TaxCategory != nil {
+		taxCategory = strings.ToLower(string(*in.TaxCategory))
+	}
+
+	var weightGrams int64
+	if in.WeightGrams != nil {
+		weightGrams = int64(*in.WeightGrams)
+	}
+
+	postProduct, err := r.server.productClient.PostProduct(ctx, in.Name, in.Description, sku, category, taxCategory, weightGrams, money.FromFloat(in.Price, currency), fromPriceOverrideInputs(in.PriceOverrides), in.Stock, int64(accountId), status, in.PublishAt)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -80,13 +136,7 @@ func (r *mutationResolver) CreateProduct(ctx context.Context, in generated.Creat
 	log.Println("Created product: ", postProduct)
 	log.Println("Product Id: ", postProduct.Id)
 
-	return &generated.Product{
-		ID:          postProduct.Id,
-		Name:        postProduct.Name,
-		Description: postProduct.Description,
-		Price:       postProduct.Price,
-		AccountID:   accountId,
-	}, nil
+	return toGraphQLProduct(postProduct), nil
 }
 
 func (r *mutationResolver) UpdateProduct(ctx context.Context, in generated.UpdateProductInput) (*generated.Product, error) {
@@ -98,18 +148,29 @@ func (r *mutationResolver) UpdateProduct(ctx context.Context, in generated.Updat
 		return nil, err
 	}
 
-	updatedProduct, err := r.server.productClient.UpdateProduct(ctx, in.ID, in.Name, in.Description, in.Price, int64(accountId))
+	currency := ""
+	if in.Currency != nil {
+		currency = strings.ToUpper(*in.Currency)
+	}
+
+	var taxCategory *string
+	if in.TaxCategory != nil {
+		category := strings.ToLower(string(*in.TaxCategory))
+		taxCategory = &category
+	}
+
+	var weightGrams *int64
+	if in.WeightGrams != nil {
+		weight := int64(*in.WeightGrams)
+		weightGrams = &weight
+	}
+
+	updatedProduct, err := r.server.productClient.UpdateProduct(ctx, in.ID, in.Name, in.Description, in.Sku, in.Category, taxCategory, weightGrams, money.FromFloat(in.Price, currency), fromPriceOverrideInputs(in.PriceOverrides), in.Stock, in.UntrackStock != nil && *in.UntrackStock, int64(accountId), int64(in.Version))
 	if err != nil {
-		return nil, err
+		return nil, productVersionError(err)
 	}
 
-	return &generated.Product{
-		ID:          updatedProduct.Id,
-		Name:        updatedProduct.Name,
-		Description: updatedProduct.Description,
-		Price:       updatedProduct.Price,
-		AccountID:   accountId,
-	}, nil
+	return toGraphQLProduct(updatedProduct), nil
 }
 
 func (r *mutationResolver) DeleteProduct(ctx context.Context, id string) (*bool, error) {
@@ -122,6 +183,133 @@ func (r *mutationResolver) DeleteProduct(ctx context.Context, id string) (*bool,
 	}
 
 	err = r.server.productClient.DeleteProduct(ctx, id, int64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, productVersionError(err)
+	}
+
+	success := true
+	return &success, nil
+}
+
+func (r *mutationResolver) RestoreProduct(ctx context.Context, id string) (*generated.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := r.server.productClient.RestoreProduct(ctx, id, int64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, productVersionError(err)
+	}
+
+	return toGraphQLProduct(product), nil
+}
+
+func (r *mutationResolver) UploadProductImage(ctx context.Context, productID string, file graphql.Upload) (*generated.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(file.File)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := r.server.productClient.UploadProductImage(ctx, productID, int64(accountId), file.ContentType, data)
+	if err != nil {
+		log.Println(err)
+		return nil, productVersionError(err)
+	}
+
+	return toGraphQLProduct(product), nil
+}
+
+func (r *mutationResolver) PublishProduct(ctx context.Context, id string, publishAt *time.Time) (*generated.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := r.server.productClient.PublishProduct(ctx, id, int64(accountId), publishAt)
+	if status.Code(err) == codes.FailedPrecondition {
+		return nil, &gqlerror.Error{
+			Message:    "product is already published",
+			Extensions: map[string]interface{}{"code": "PRODUCT_ALREADY_PUBLISHED"},
+		}
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, productVersionError(err)
+	}
+
+	return toGraphQLProduct(product), nil
+}
+
+func (r *mutationResolver) ArchiveProduct(ctx context.Context, id string) (*generated.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := r.server.productClient.ArchiveProduct(ctx, id, int64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, productVersionError(err)
+	}
+
+	return toGraphQLProduct(product), nil
+}
+
+func (r *mutationResolver) SchedulePriceChange(ctx context.Context, in generated.SchedulePriceChangeInput) (*generated.ScheduledPrice, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new price is in the product's own currency
+	p, err := r.server.productClient.GetProduct(ctx, in.ProductID, int64(accountId), "")
+	if err != nil {
+		return nil, err
+	}
+
+	price := money.FromFloat(in.Price, p.Price.Currency)
+	change, err := r.server.productClient.SchedulePriceChange(ctx, in.ProductID, int64(accountId), price, in.StartAt, in.EndAt)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLScheduledPrice(change), nil
+}
+
+func (r *mutationResolver) CancelPriceChange(ctx context.Context, id string) (*bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.server.productClient.CancelPriceChange(ctx, id, int64(accountId))
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -131,6 +319,79 @@ func (r *mutationResolver) DeleteProduct(ctx context.Context, id string) (*bool,
 	return &success, nil
 }
 
+func (r *mutationResolver) CreateReview(ctx context.Context, in generated.CreateReviewInput) (*generated.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := r.server.productClient.CreateReview(ctx, in.ProductID, int64(accountId), in.Rating, in.Title, in.Body)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLReview(review), nil
+}
+
+func (r *mutationResolver) UpdateReview(ctx context.Context, in generated.UpdateReviewInput) (*generated.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := r.server.productClient.UpdateReview(ctx, in.ID, int64(accountId), in.Rating, in.Title, in.Body)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLReview(review), nil
+}
+
+func (r *mutationResolver) DeleteReview(ctx context.Context, id string) (*bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.server.productClient.DeleteReview(ctx, id, int64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	success := true
+	return &success, nil
+}
+
+func (r *mutationResolver) ReplyToReview(ctx context.Context, id string, body string) (*generated.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := r.server.productClient.ReplyToReview(ctx, id, int64(accountId), body)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLReview(review), nil
+}
+
 func (r *mutationResolver) CreateOrder(ctx context.Context, in generated.OrderInput) (*generated.Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
@@ -152,29 +413,155 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, in generated.OrderIn
 		return nil, errors.New("unauthorized")
 	}
 
-	postOrder, err := r.server.orderClient.PostOrder(ctx, uint64(accountId), products)
+	couponCode := ""
+	if in.CouponCode != nil {
+		couponCode = *in.CouponCode
+	}
+
+	shippingMethod := ""
+	if in.ShippingMethod != nil {
+		shippingMethod = *in.ShippingMethod
+	}
+
+	// retries sent with the same Idempotency-Key get the first order back
+	postOrder, err := r.server.orderClient.PostOrder(idempotency.OutgoingContext(ctx), uint64(accountId), products, fromAddressInput(in.ShipTo), shippingMethod, couponCode)
+	if err != nil {
+		log.Println(err)
+		return nil, idempotencyError(shippingError(couponError(err)))
+	}
+
+	return toGraphQLOrder(postOrder), nil
+}
+
+func (r *mutationResolver) CancelOrder(ctx context.Context, id int, reason *string) (*generated.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	cancelReason := ""
+	if reason != nil {
+		cancelReason = *reason
+	}
+
+	cancelledOrder, err := r.server.orderClient.CancelOrder(ctx, uint64(id), uint64(accountId), cancelReason)
+	if status.Code(err) == codes.FailedPrecondition {
+		return nil, &gqlerror.Error{
+			Message:    "order can no longer be cancelled",
+			Extensions: map[string]interface{}{"code": "ORDER_NOT_CANCELLABLE"},
+		}
+	}
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	var orderedProducts []*generated.OrderedProduct
-	for _, orderProduct := range postOrder.Products {
-		orderedProducts = append(orderedProducts, &generated.OrderedProduct{
-			ID:          orderProduct.ID,
-			Name:        orderProduct.Name,
-			Description: orderProduct.Description,
-			Price:       orderProduct.Price,
-			Quantity:    int(orderProduct.Quantity),
-		})
+	return toGraphQLOrder(cancelledOrder), nil
+}
+
+func (r *mutationResolver) AddToCart(ctx context.Context, productId string, quantity int, sessionId *string) (*generated.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// anonymous callers without a session get a new session cart
+	c, err := r.server.cartClient.AddItem(ctx, cartOwner(ctx, sessionId), productId, quantity)
+	if err != nil {
+		log.Println(err)
+		return nil, err
 	}
 
-	return &generated.Order{
-		ID:         int(postOrder.ID),
-		CreatedAt:  postOrder.CreatedAt,
-		TotalPrice: postOrder.TotalPrice,
-		Products:   orderedProducts,
-	}, nil
+	return toGraphQLCart(c), nil
+}
+
+func (r *mutationResolver) UpdateCartItem(ctx context.Context, productId string, quantity int, sessionId *string) (*generated.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	c, err := r.server.cartClient.UpdateItem(ctx, cartOwner(ctx, sessionId), productId, quantity)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLCart(c), nil
+}
+
+func (r *mutationResolver) RemoveFromCart(ctx context.Context, productId string, sessionId *string) (*generated.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	c, err := r.server.cartClient.RemoveItem(ctx, cartOwner(ctx, sessionId), productId)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLCart(c), nil
+}
+
+func (r *mutationResolver) MergeCart(ctx context.Context, sessionId string) (*generated.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	c, err := r.server.cartClient.MergeCarts(ctx, sessionId, uint64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLCart(c), nil
+}
+
+func (r *mutationResolver) CheckoutCart(ctx context.Context, couponCode *string, shipTo *generated.AddressInput, shippingMethod *string) (*generated.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	code := ""
+	if couponCode != nil {
+		code = *couponCode
+	}
+
+	method := ""
+	if shippingMethod != nil {
+		method = *shippingMethod
+	}
+
+	// retries sent with the same Idempotency-Key get the first order back
+	orderId, err := r.server.cartClient.CheckoutCart(idempotency.OutgoingContext(ctx), uint64(accountId), fromAddressInput(shipTo), method, code)
+	if err, ok := shippingError(couponError(err)).(*gqlerror.Error); ok {
+		return nil, err
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		return nil, &gqlerror.Error{
+			Message:    status.Convert(err).Message(),
+			Extensions: map[string]interface{}{"code": "CART_NOT_ORDERABLE"},
+		}
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, idempotencyError(err)
+	}
+
+	o, err := r.server.orderClient.GetOrder(ctx, orderId, uint64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLOrder(o), nil
 }
 
 func (r *mutationResolver) CreateCustomerPortalSession(ctx context.Context, credentials *generated.CustomerPortalSessionInput) (*generated.RedirectResponse, error) {
@@ -202,12 +589,117 @@ func (r *mutationResolver) CreateCheckoutSession(ctx context.Context, details *g
 		})
 	}
 
-	UrlWithCheckoutSession, err := r.server.paymentClient.CreateCheckoutSession(ctx, details.OrderID, details.AccounID, details.Name, details.Email,
-		details.RedirectURL, products)
+	var expectedTotal *money.Money
+	if details.ExpectedTotal != nil {
+		total := money.FromFloat(details.ExpectedTotal.Amount, strings.ToUpper(details.ExpectedTotal.Currency))
+		expectedTotal = &total
+	}
+
+	UrlWithCheckoutSession, err := r.server.paymentClient.CreateCheckoutSession(idempotency.OutgoingContext(ctx), details.OrderID, details.AccounID, details.Name, details.Email,
+		details.RedirectURL, products, expectedTotal)
 
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return nil, idempotencyError(err)
 	}
 	return &generated.RedirectResponse{URL: UrlWithCheckoutSession}, nil
 }
+
+func (r *mutationResolver) CreateCoupon(ctx context.Context, in generated.CouponInput) (*generated.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	coupon, err := r.server.orderClient.CreateCoupon(ctx, uint64(accountId), fromCouponInput(in))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLCoupon(coupon), nil
+}
+
+func (r *mutationResolver) UpdateCoupon(ctx context.Context, in generated.UpdateCouponInput) (*generated.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	coupon, err := r.server.orderClient.UpdateCoupon(ctx, uint64(accountId), fromUpdateCouponInput(in))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLCoupon(coupon), nil
+}
+
+func (r *mutationResolver) MarkShipped(ctx context.Context, orderId int, in generated.ShipmentInput) (*generated.Shipment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	shipment, err := r.server.orderClient.MarkShipped(ctx, uint64(orderId), uint64(accountId), in.Carrier, in.TrackingNumber, in.ProductIds)
+	if err != nil {
+		log.Println(err)
+		return nil, shippingError(err)
+	}
+
+	return toGraphQLShipment(shipment), nil
+}
+
+func (r *mutationResolver) UpdateShipmentStatus(ctx context.Context, id int, shipmentStatus generated.ShipmentStatus, description *string) (*generated.Shipment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	desc := ""
+	if description != nil {
+		desc = *description
+	}
+
+	shipment, err := r.server.orderClient.UpdateShipmentStatus(ctx, uint64(id), uint64(accountId), strings.ToLower(string(shipmentStatus)), desc)
+	if err != nil {
+		log.Println(err)
+		return nil, shippingError(err)
+	}
+
+	return toGraphQLShipment(shipment), nil
+}
+
+// idempotencyError reports requests rejected for their Idempotency-Key with
+// error codes clients can act on.
+func idempotencyError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch {
+	case s.Code() == codes.Aborted && s.Message() == idempotency.ErrInProgress.Error():
+		return &gqlerror.Error{
+			Message:    s.Message(),
+			Extensions: map[string]interface{}{"code": "IDEMPOTENCY_KEY_IN_USE"},
+		}
+	case s.Code() == codes.InvalidArgument && s.Message() == idempotency.ErrKeyReused.Error():
+		return &gqlerror.Error{
+			Message:    s.Message(),
+			Extensions: map[string]interface{}{"code": "IDEMPOTENCY_KEY_REUSED"},
+		}
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/abhiii71/orderStream/graphql/generated"
+	"github.com/abhiii71/orderStream/order/models"
+)
+
+func fromAddressInput(in *generated.AddressInput) models.Address {
+	if in == nil {
+		return models.Address{}
+	}
+	address := models.Address{Country: in.Country}
+	for field, value := range map[*string]*string{
+		&address.Name:       in.Name,
+		&address.Line1:      in.Line1,
+		&address.Line2:      in.Line2,
+		&address.City:       in.City,
+		&address.PostalCode: in.PostalCode,
+		&address.Region:     in.Region,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+	return address
+}
+
+func toGraphQLAddress(a models.Address) *generated.Address {
+	address := &generated.Address{Country: a.Country}
+	for field, value := range map[**string]string{
+		&address.Name:       a.Name,
+		&address.Line1:      a.Line1,
+		&address.Line2:      a.Line2,
+		&address.City:       a.City,
+		&address.PostalCode: a.PostalCode,
+		&address.Region:     a.Region,
+	} {
+		if value != "" {
+			*field = &value
+		}
+	}
+	return address
+}
+
+func toGraphQLOrder(o *models.Order) *generated.Order {
+	products := []*generated.OrderedProduct{}
+	for _, p := range o.Products {
+		product := &generated.OrderedProduct{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price.Float(),
+			Quantity:    int(p.Quantity),
+			LineTotal:   p.LineTotal.Float(),
+			// basis points to percent
+			TaxRate: float64(p.TaxRate) / 100,
+			Tax:     p.Tax.Float(),
+		}
+		if p.Sku != "" {
+			product.Sku = &p.Sku
+		}
+		if p.TaxCategory != "" {
+			category := generated.TaxCategory(strings.ToUpper(p.TaxCategory))
+			product.TaxCategory = &category
+		}
+		products = append(products, product)
+	}
+
+	timeline := []*generated.OrderStatusChange{}
+	for _, change := range o.History {
+		entry := &generated.OrderStatusChange{
+			To:        change.To,
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+			CreatedAt: change.CreatedAt,
+		}
+		if change.From != "" {
+			entry.From = &change.From
+		}
+		timeline = append(timeline, entry)
+	}
+
+	discounts := []*generated.OrderDiscount{}
+	for _, d := range o.Discounts {
+		discounts = append(discounts, &generated.OrderDiscount{
+			CouponCode: d.CouponCode,
+			ProductID:  d.ProductId,
+			Amount:     d.Amount.Float(),
+		})
+	}
+
+	shipments := []*generated.Shipment{}
+	for i := range o.Shipments {
+		shipments = append(shipments, toGraphQLShipment(&o.Shipments[i]))
+	}
+
+	order := &generated.Order{
+		ID:         int(o.ID),
+		CreatedAt:  o.CreatedAt,
+		TotalPrice: o.TotalPrice.Float(),
+		Currency:   o.TotalPrice.Currency,
+		Products:   products,
+		Status:     o.Status,
+		Timeline:   timeline,
+		Subtotal:   o.Subtotal.Float(),
+		Discount:   o.Discount.Float(),
+		Discounts:  discounts,
+		Tax:        o.Tax.Float(),
+		Shipping:   o.Shipping.Float(),
+		Shipments:  shipments,
+	}
+	if o.CouponCode != "" {
+		order.CouponCode = &o.CouponCode
+	}
+	if o.ShipTo.Country != "" {
+		order.ShipTo = toGraphQLAddress(o.ShipTo)
+	}
+	if o.ShippingMethod != "" {
+		order.ShippingMethod = &o.ShippingMethod
+	}
+	return order
+}
@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abhiii71/orderStream/graphql/generated"
+	"github.com/abhiii71/orderStream/graphql/utils"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/tax"
+	"github.com/abhiii71/orderStream/product/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type productResolver struct {
+	server *Server
+}
+
+func (r *productResolver) PriceHistory(ctx context.Context, obj *generated.Product, pagination *generated.PaginationInput) ([]*generated.PriceHistoryEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	skip, take := uint64(0), uint64(0)
+	if pagination != nil {
+		skip, take = utils.Bounds(pagination)
+	}
+
+	entryList, err := r.server.productClient.GetPriceHistory(ctx, obj.ID, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	entries := []*generated.PriceHistoryEntry{}
+	for _, entry := range entryList {
+		entries = append(entries, &generated.PriceHistoryEntry{
+			Price:         entry.Price.Float(),
+			PreviousPrice: entry.PreviousPrice.Float(),
+			Reason:        entry.Reason,
+			ChangedAt:     entry.ChangedAt,
+		})
+	}
+	return entries, nil
+}
+
+func toGraphQLProduct(p *models.Product) *generated.Product {
+	product := &generated.Product{
+		ID:          p.Id,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price.Float(),
+		AccountID:   p.AccountId,
+		Images:      []*generated.ProductImage{},
+		Status:      generated.ProductStatus(strings.ToUpper(p.Status)),
+		PublishAt:   p.PublishAt,
+		DeletedAt:   p.DeletedAt,
+		Stock:       p.Stock,
+		Version:     int(p.Version),
+
+		RatingAverage: p.RatingAverage,
+		RatingCount:   p.RatingCount,
+
+		Currency:        p.Price.Currency,
+		PriceOverrides:  []*generated.PriceOverride{},
+		DisplayPrice:    p.DisplayPrice.Float(),
+		DisplayCurrency: p.DisplayPrice.Currency,
+	}
+	if p.Status == "" {
+		product.Status = generated.ProductStatusPublished
+	}
+	if p.Sku != "" {
+		product.Sku = &p.Sku
+	}
+	if p.Category != "" {
+		product.Category = &p.Category
+	}
+	product.TaxCategory = generated.TaxCategory(strings.ToUpper(tax.CategoryOf(p.TaxCategory)))
+	product.WeightGrams = int(p.WeightGrams)
+	if p.DisplayPrice.Currency == "" {
+		product.DisplayPrice, product.DisplayCurrency = product.Price, product.Currency
+	}
+
+	for _, override := range p.PriceOverrides {
+		product.PriceOverrides = append(product.PriceOverrides, &generated.PriceOverride{
+			Currency: override.Currency,
+			Price:    override.Float(),
+		})
+	}
+
+	for _, image := range p.Images {
+		product.Images = append(product.Images, &generated.ProductImage{
+			URL:          image.URL,
+			ThumbnailURL: image.ThumbnailURL,
+			ContentType:  image.ContentType,
+			Size:         int(image.Size),
+			Position:     image.Position,
+		})
+	}
+	return product
+}
+
+func fromPriceOverrideInputs(in []*generated.PriceOverrideInput) []money.Money {
+	var overrides []money.Money
+	for _, override := range in {
+		overrides = append(overrides, money.FromFloat(override.Price, strings.ToUpper(override.Currency)))
+	}
+	return overrides
+}
+
+func toGraphQLReview(r *models.Review) *generated.Review {
+	review := &generated.Review{
+		ID:        r.Id,
+		ProductID: r.ProductId,
+		AccountID: r.AccountId,
+		Rating:    r.Rating,
+		Title:     r.Title,
+		Body:      r.Body,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if r.Reply != nil {
+		review.Reply = &generated.ReviewReply{
+			AccountID: r.Reply.AccountId,
+			Body:      r.Reply.Body,
+			CreatedAt: r.Reply.CreatedAt,
+		}
+	}
+	return review
+}
+
+func toGraphQLScheduledPrice(c *models.ScheduledPrice) *generated.ScheduledPrice {
+	return &generated.ScheduledPrice{
+		ID:        c.Id,
+		ProductID: c.ProductId,
+		Price:     c.Price.Float(),
+		StartAt:   c.StartAt,
+		EndAt:     c.EndAt,
+		Status:    c.Status,
+	}
+}
+
+// productVersionError reports writes that lost against a concurrent change
+// of the product with an error code clients can act on.
+func productVersionError(err error) error {
+	if status.Code(err) != codes.Aborted {
+		return err
+	}
+	return &gqlerror.Error{
+		Message:    "product changed, reload",
+		Extensions: map[string]interface{}{"code": "PRODUCT_VERSION_CONFLICT"},
+	}
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/abhiii71/orderStream/graphql/generated"
 	"github.com/abhiii71/orderStream/graphql/models"
 	"github.com/abhiii71/orderStream/graphql/utils"
+	orderModels "github.com/abhiii71/orderStream/order/models"
 	"github.com/abhiii71/orderStream/pkg/auth"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+	productModels "github.com/abhiii71/orderStream/product/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type queryResolver struct {
@@ -56,25 +63,27 @@ func (r *queryResolver) Accounts(ctx context.Context, pagination *generated.Pagi
 	return accounts, nil
 }
 
-func (r *queryResolver) Product(ctx context.Context, pagination *generated.PaginationInput, query, id *string, viewedProductIds []*string, byAccountId *bool) ([]*generated.Product, error) {
+func (r *queryResolver) Product(ctx context.Context, pagination *generated.PaginationInput, query, id *string, viewedProductIds []*string, byAccountId *bool, minRating *float64, sortBy *generated.ProductSort, currency *string) ([]*generated.Product, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	// anonymous callers only ever see published products
+	viewerId, _ := auth.GetUserIdInt(ctx, false)
+	displayCurrency := ""
+	if currency != nil {
+		displayCurrency = strings.ToUpper(*currency)
+	}
+
 	// Get single
 
 	if id != nil {
-		res, err := r.server.productClient.GetProduct(ctx, *id)
+		res, err := r.server.productClient.GetProduct(ctx, *id, int64(viewerId), displayCurrency)
 		if err != nil {
 			log.Println(err)
 			return nil, err
 		}
 
-		return []*generated.Product{{
-			ID:          res.Id,
-			Name:        res.Name,
-			Description: res.Description,
-			Price:       res.Price,
-		}}, nil
+		return []*generated.Product{toGraphQLProduct(res)}, nil
 	}
 	skip, take := uint64(0), uint64(0)
 	if pagination != nil {
@@ -134,7 +143,15 @@ func (r *queryResolver) Product(ctx context.Context, pagination *generated.Pagin
 		q = *query
 	}
 
-	productList, err := r.server.productClient.GetProducts(ctx, skip, take, nil, q)
+	filter := productModels.ProductFilter{ViewerId: viewerId, DisplayCurrency: displayCurrency}
+	if minRating != nil {
+		filter.MinRating = *minRating
+	}
+	if sortBy != nil && *sortBy == generated.ProductSortRating {
+		filter.SortBy = product.SortByRating
+	}
+
+	productList, err := r.server.productClient.GetProducts(ctx, skip, take, nil, q, filter)
 
 	if err != nil {
 		log.Println(err)
@@ -143,12 +160,249 @@ func (r *queryResolver) Product(ctx context.Context, pagination *generated.Pagin
 
 	var products []*generated.Product
 	for _, product := range productList {
-		products = append(products, &generated.Product{
-			ID:          product.Id,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       product.Price,
-		})
+		products = append(products, toGraphQLProduct(&product))
 	}
 	return products, nil
 }
+
+func (r *queryResolver) Reviews(ctx context.Context, productID string, pagination *generated.PaginationInput) ([]*generated.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	skip, take := uint64(0), uint64(0)
+	if pagination != nil {
+		skip, take = utils.Bounds(pagination)
+	}
+
+	reviewList, err := r.server.productClient.ListReviews(ctx, productID, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	reviews := []*generated.Review{}
+	for _, review := range reviewList {
+		reviews = append(reviews, toGraphQLReview(&review))
+	}
+	return reviews, nil
+}
+
+func (r *queryResolver) ScheduledPrices(ctx context.Context, productID string) ([]*generated.ScheduledPrice, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	changeList, err := r.server.productClient.ListScheduledPrices(ctx, productID, int64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	changes := []*generated.ScheduledPrice{}
+	for _, change := range changeList {
+		changes = append(changes, toGraphQLScheduledPrice(&change))
+	}
+	return changes, nil
+}
+
+func (r *queryResolver) DeletedProducts(ctx context.Context, pagination *generated.PaginationInput) ([]*generated.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if pagination == nil {
+		pagination = &generated.PaginationInput{}
+	}
+	skip, take := utils.Bounds(pagination)
+
+	productList, err := r.server.productClient.ListDeletedProducts(ctx, int64(accountId), skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	products := []*generated.Product{}
+	for _, p := range productList {
+		products = append(products, toGraphQLProduct(&p))
+	}
+	return products, nil
+}
+
+func (r *queryResolver) MyProducts(ctx context.Context, pagination *generated.PaginationInput, status []generated.ProductStatus) ([]*generated.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if pagination == nil {
+		pagination = &generated.PaginationInput{}
+	}
+	skip, take := utils.Bounds(pagination)
+
+	var statuses []string
+	for _, s := range status {
+		statuses = append(statuses, strings.ToLower(string(s)))
+	}
+
+	productList, err := r.server.productClient.ListProductsByAccount(ctx, int64(accountId), statuses, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	products := []*generated.Product{}
+	for _, p := range productList {
+		products = append(products, toGraphQLProduct(&p))
+	}
+	return products, nil
+}
+
+func (r *queryResolver) Order(ctx context.Context, id int) (*generated.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := r.server.orderClient.GetOrder(ctx, uint64(id), uint64(accountId))
+	if code := status.Code(err); code == codes.NotFound || code == codes.PermissionDenied {
+		// orders of other accounts are not found either
+		return nil, nil
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLOrder(o), nil
+}
+
+func (r *queryResolver) MyOrders(ctx context.Context, filter *generated.OrderFilter, after *string, first *int) (*generated.OrderConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderFilter orderModels.OrderFilter
+	if filter != nil {
+		for _, s := range filter.Status {
+			orderFilter.Statuses = append(orderFilter.Statuses, strings.ToLower(s))
+		}
+		if filter.CreatedAfter != nil {
+			orderFilter.CreatedAfter = *filter.CreatedAfter
+		}
+		if filter.CreatedBefore != nil {
+			orderFilter.CreatedBefore = *filter.CreatedBefore
+		}
+		if filter.MinTotal != nil {
+			if filter.Currency == nil {
+				return nil, ErrInvalidParameter
+			}
+			minTotal := money.FromFloat(*filter.MinTotal, strings.ToUpper(*filter.Currency))
+			orderFilter.MinTotal = &minTotal
+		}
+	}
+
+	cursor, pageSize := "", 0
+	if after != nil {
+		cursor = *after
+	}
+	if first != nil {
+		if *first < 0 {
+			return nil, ErrInvalidParameter
+		}
+		pageSize = *first
+	}
+
+	page, err := r.server.orderClient.ListOrders(ctx, uint64(accountId), orderFilter, cursor, pageSize)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	connection := &generated.OrderConnection{
+		Orders:      []*generated.Order{},
+		HasNextPage: page.HasNextPage,
+	}
+	if page.EndCursor != "" {
+		connection.EndCursor = &page.EndCursor
+	}
+	for _, o := range page.Orders {
+		connection.Orders = append(connection.Orders, toGraphQLOrder(o))
+	}
+	return connection, nil
+}
+
+func (r *queryResolver) Cart(ctx context.Context, sessionId *string) (*generated.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	owner := cartOwner(ctx, sessionId)
+	if owner.IsZero() {
+		// nothing was added yet
+		return &generated.Cart{Items: []*generated.CartItem{}}, nil
+	}
+
+	c, err := r.server.cartClient.GetCart(ctx, owner)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return toGraphQLCart(c), nil
+}
+
+func (r *queryResolver) MyCoupons(ctx context.Context) ([]*generated.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	accountId, err := auth.GetUserIdInt(ctx, true)
+	if err != nil {
+		return nil, errors.New("unauthorized")
+	}
+
+	coupons, err := r.server.orderClient.ListCoupons(ctx, uint64(accountId))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	result := []*generated.Coupon{}
+	for _, c := range coupons {
+		result = append(result, toGraphQLCoupon(c))
+	}
+	return result, nil
+}
+
+func (r *queryResolver) ShippingMethods(ctx context.Context) ([]*generated.ShippingMethod, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	methods, err := r.server.orderClient.ListShippingMethods(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	result := []*generated.ShippingMethod{}
+	for _, m := range methods {
+		result = append(result, toGraphQLShippingMethod(m))
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/abhiii71/orderStream/graphql/generated"
+	"github.com/abhiii71/orderStream/order"
+	"github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/status"
+)
+
+func toGraphQLShipment(s *models.Shipment) *generated.Shipment {
+	events := []*generated.ShipmentEvent{}
+	for _, e := range s.Events {
+		events = append(events, &generated.ShipmentEvent{
+			Status:      generated.ShipmentStatus(strings.ToUpper(e.Status)),
+			Description: e.Description,
+			CreatedAt:   e.CreatedAt,
+		})
+	}
+
+	return &generated.Shipment{
+		ID:             int(s.ID),
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		ProductIds:     append([]string{}, s.ProductIds...),
+		Status:         generated.ShipmentStatus(strings.ToUpper(s.Status)),
+		Events:         events,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+func toGraphQLShippingMethod(m order.ShippingMethod) *generated.ShippingMethod {
+	rates := []*generated.ShippingRate{}
+	for _, r := range m.Rates {
+		rate := &generated.ShippingRate{
+			Currency: r.Currency,
+			Type:     generated.ShippingRateType(strings.ToUpper(r.Type)),
+			Amount:   money.New(r.Amount, r.Currency).Float(),
+			PerKg:    money.New(r.PerKg, r.Currency).Float(),
+		}
+		if r.FreeOver > 0 {
+			freeOver := money.New(r.FreeOver, r.Currency).Float()
+			rate.FreeOver = &freeOver
+		}
+		rates = append(rates, rate)
+	}
+
+	method := &generated.ShippingMethod{
+		Code:      m.Code,
+		Name:      m.Name,
+		Countries: append([]string{}, m.Countries...),
+		Rates:     rates,
+	}
+	if m.Carrier != "" {
+		method.Carrier = &m.Carrier
+	}
+	return method
+}
+
+// shippingErrorCodes are the error codes clients get for shipping errors of
+// the order service.
+var shippingErrorCodes = map[string]string{
+	order.ErrUnknownShippingMethod.Error(): "UNKNOWN_SHIPPING_METHOD",
+	order.ErrShippingUnavailable.Error():   "SHIPPING_UNAVAILABLE",
+	order.ErrNotShippable.Error():          "ORDER_NOT_SHIPPABLE",
+	order.ErrNothingToShip.Error():         "NOTHING_TO_SHIP",
+	order.ErrShipmentClosed.Error():        "SHIPMENT_CLOSED",
+}
+
+// shippingError reports shipping errors with error codes clients can act on.
+func shippingError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if code, ok := shippingErrorCodes[s.Message()]; ok {
+		return &gqlerror.Error{
+			Message:    s.Message(),
+			Extensions: map[string]interface{}{"code": code},
+		}
+	}
+	return err
+}
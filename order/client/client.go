@@ -3,12 +3,16 @@ package client
 import (
 	"context"
 	"log"
-	"time"
 
+	"github.com/abhiii71/orderStream/order"
 	"github.com/abhiii71/orderStream/order/models"
 	"github.com/abhiii71/orderStream/order/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/promotions"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -34,7 +38,10 @@ func (c *Client) Close() {
 	}
 }
 
-func (c *Client) PostOrder(ctx context.Context, accountId uint64, products []*models.OrderedProduct) (*models.Order, error) {
+// PostOrder places an order for the products shipped to shipTo by
+// shippingMethod, discounted by the coupon with couponCode unless it is
+// empty. An empty shippingMethod is the default method.
+func (c *Client) PostOrder(ctx context.Context, accountId uint64, products []*models.OrderedProduct, shipTo models.Address, shippingMethod, couponCode string) (*models.Order, error) {
 	var protoProducts []*pb.OrderProduct
 	for _, p := range products {
 		protoProducts = append(protoProducts, &pb.OrderProduct{
@@ -44,28 +51,20 @@ func (c *Client) PostOrder(ctx context.Context, accountId uint64, products []*mo
 	}
 
 	r, err := c.service.PostOrder(ctx, &pb.PostOrderRequest{
-		AccountId: accountId,
-		Products:  protoProducts,
-	})
-	if err != nil {
-		return nil, err
-	}
+		AccountId:  accountId,
+		Products:   protoProducts,
+		CouponCode: couponCode,
+		ShipTo:     addressToProto(shipTo),
 
-	// create response order
-	newOrder := r.Order
-	newOrderCreatedAt := time.Time{}
-	err = newOrderCreatedAt.UnmarshalBinary(newOrder.CreatedAt)
+		ShippingMethod: shippingMethod,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.Order{
-		ID:         uint(r.Order.GetId()),
-		CreatedAt:  newOrderCreatedAt,
-		TotalPrice: newOrder.TotalPrice,
-		AccountID:  newOrder.AccountId,
-		Products:   products,
-	}, nil
+	// the response has the products as they were ordered, which is also what
+	// a retry with the same idempotency key gets back
+	return orderFromProto(r.Order)
 }
 
 func (c *Client) GetordersForAccount(ctx context.Context, accountID uint64) ([]models.Order, error) {
@@ -74,48 +73,367 @@ func (c *Client) GetordersForAccount(ctx context.Context, accountID uint64) ([]m
 		return nil, err
 	}
 
-	// create response order
 	var orders []models.Order
-
 	for _, orderProto := range r.Orders {
-		newOrder := models.Order{
-			ID:         uint(orderProto.Id),
-			TotalPrice: orderProto.TotalPrice,
-			AccountID:  orderProto.AccountId,
-		}
-
-		newOrder.CreatedAt = time.Time{}
-		err := newOrder.CreatedAt.UnmarshalBinary(orderProto.CreatedAt)
+		o, err := orderFromProto(orderProto)
 		if err != nil {
 			return nil, err
 		}
+		orders = append(orders, *o)
+	}
 
-		var products []*models.OrderedProduct
-		for _, p := range orderProto.Products {
-			products = append(products, &models.OrderedProduct{
-				ID:          p.Id,
-				Quantity:    p.Quantity,
-				Name:        p.Name,
-				Description: p.Description,
-				Price:       p.Price,
-			})
+	return orders, nil
+}
+
+// GetOrder returns an order of the account.
+func (c *Client) GetOrder(ctx context.Context, orderId, accountId uint64) (*models.Order, error) {
+	r, err := c.service.GetOrder(ctx, &pb.GetOrderRequest{OrderId: orderId, AccountId: accountId})
+	if err != nil {
+		return nil, err
+	}
+	return orderFromProto(r)
+}
+
+// ListOrders returns a page of the orders of an account that match filter,
+// newest first. Pass the end cursor of a page as after to get the next one.
+func (c *Client) ListOrders(ctx context.Context, accountId uint64, filter models.OrderFilter, after string, first int) (*models.OrderPage, error) {
+	request := &pb.ListOrdersRequest{
+		AccountId: accountId,
+		Filter:    &pb.OrderFilter{Statuses: filter.Statuses},
+		After:     after,
+		First:     uint32(max(first, 0)),
+	}
+	if !filter.CreatedAfter.IsZero() {
+		request.Filter.CreatedAfter = timestamppb.New(filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		request.Filter.CreatedBefore = timestamppb.New(filter.CreatedBefore)
+	}
+	if filter.MinTotal != nil {
+		request.Filter.MinTotal = money.ToProto(*filter.MinTotal)
+	}
+
+	r, err := c.service.ListOrders(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.OrderPage{EndCursor: r.EndCursor, HasNextPage: r.HasNextPage}
+	for _, orderProto := range r.Orders {
+		o, err := orderFromProto(orderProto)
+		if err != nil {
+			return nil, err
 		}
-		newOrder.Products = products
+		page.Orders = append(page.Orders, o)
+	}
+	return page, nil
+}
 
-		orders = append(orders, newOrder)
+// UpdateOrderStatus moves an order to another lifecycle status. actor
+// and reason are recorded in the order's status history.
+func (c *Client) UpdateOrderStatus(ctx context.Context, orderId uint64, status, actor, reason string) (*models.Order, error) {
+	r, err := c.service.UpdateOrderStatus(ctx, &pb.UpdateOrderStatusRequest{
+		OrderId: orderId,
+		Status:  status,
+		Actor:   actor,
+		Reason:  reason,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return orderFromProto(r)
+}
 
-	return orders, nil
+// CancelOrder cancels an order of the account, recording reason in its
+// status history.
+func (c *Client) CancelOrder(ctx context.Context, orderId, accountId uint64, reason string) (*models.Order, error) {
+	r, err := c.service.CancelOrder(ctx, &pb.CancelOrderRequest{
+		OrderId:   orderId,
+		AccountId: accountId,
+		Reason:    reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orderFromProto(r)
 }
 
-func (c *Client) UpdateOrderStatus(ctx context.Context, orderId uint64, status string) error {
-	_, err := c.service.UpdateOrderStatus(ctx, &pb.UpdateOrderStatusRequest{
+// UpdatePaymentStatus reports the payment status of an order.
+func (c *Client) UpdatePaymentStatus(ctx context.Context, orderId uint64, status string) error {
+	_, err := c.service.UpdatePaymentStatus(ctx, &pb.UpdatePaymentStatusRequest{
 		OrderId: orderId,
 		Status:  status,
 	})
+	return err
+}
+
+func (c *Client) HasPurchased(ctx context.Context, accountId uint64, productId string) (bool, error) {
+	res, err := c.service.HasPurchased(ctx, &pb.HasPurchasedRequest{
+		AccountId: accountId,
+		ProductId: productId,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.GetValue(), nil
+}
+
+// CreateCoupon creates a coupon issued by the account.
+func (c *Client) CreateCoupon(ctx context.Context, accountId uint64, coupon *promotions.Coupon) (*promotions.Coupon, error) {
+	r, err := c.service.CreateCoupon(ctx, &pb.CreateCouponRequest{AccountId: accountId, Coupon: couponToProto(coupon)})
+	if err != nil {
+		return nil, err
+	}
+	return couponFromProto(r), nil
+}
+
+// UpdateCoupon changes a coupon of the account.
+func (c *Client) UpdateCoupon(ctx context.Context, accountId uint64, coupon *promotions.Coupon) (*promotions.Coupon, error) {
+	r, err := c.service.UpdateCoupon(ctx, &pb.UpdateCouponRequest{AccountId: accountId, Coupon: couponToProto(coupon)})
+	if err != nil {
+		return nil, err
+	}
+	return couponFromProto(r), nil
+}
+
+// ListCoupons returns the coupons the account manages.
+func (c *Client) ListCoupons(ctx context.Context, accountId uint64) ([]*promotions.Coupon, error) {
+	r, err := c.service.ListCoupons(ctx, &pb.ListCouponsRequest{AccountId: accountId})
+	if err != nil {
+		return nil, err
+	}
+
+	coupons := make([]*promotions.Coupon, len(r.Coupons))
+	for i, coupon := range r.Coupons {
+		coupons[i] = couponFromProto(coupon)
+	}
+	return coupons, nil
+}
+
+// ListShippingMethods returns the shipping methods orders may choose from,
+// the default first.
+func (c *Client) ListShippingMethods(ctx context.Context) ([]order.ShippingMethod, error) {
+	r, err := c.service.ListShippingMethods(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]order.ShippingMethod, len(r.Methods))
+	for i, m := range r.Methods {
+		methods[i] = order.ShippingMethod{Code: m.Code, Name: m.Name, Carrier: m.Carrier, Countries: m.Countries}
+		for _, rate := range m.Rates {
+			methods[i].Rates = append(methods[i].Rates, order.ShippingRate{
+				Currency: rate.Currency,
+				Type:     rate.Type,
+				Amount:   rate.Amount,
+				PerKg:    rate.PerKg,
+				FreeOver: rate.FreeOver,
+			})
+		}
+	}
+	return methods, nil
+}
+
+// MarkShipped records a parcel the seller sent with their products of an
+// order, all of those not shipped yet if productIds is empty.
+func (c *Client) MarkShipped(ctx context.Context, orderId, sellerId uint64, carrier, trackingNumber string, productIds []string) (*models.Shipment, error) {
+	r, err := c.service.MarkShipped(ctx, &pb.MarkShippedRequest{
+		OrderId:        orderId,
+		SellerId:       sellerId,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		ProductIds:     productIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	shipment := shipmentFromProto(r)
+	return &shipment, nil
+}
+
+// UpdateShipmentStatus records a tracking update of a shipment the account
+// sent.
+func (c *Client) UpdateShipmentStatus(ctx context.Context, shipmentId, accountId uint64, status, description string) (*models.Shipment, error) {
+	r, err := c.service.UpdateShipmentStatus(ctx, &pb.UpdateShipmentStatusRequest{
+		ShipmentId:  shipmentId,
+		AccountId:   accountId,
+		Status:      status,
+		Description: description,
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	shipment := shipmentFromProto(r)
+	return &shipment, nil
+}
+
+func shipmentFromProto(s *pb.Shipment) models.Shipment {
+	shipment := models.Shipment{
+		ID:             s.Id,
+		OrderId:        s.OrderId,
+		SellerId:       s.SellerId,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		ProductIds:     s.ProductIds,
+		Status:         s.Status,
+		CreatedAt:      s.CreatedAt.AsTime(),
+		UpdatedAt:      s.UpdatedAt.AsTime(),
+	}
+	for _, e := range s.Events {
+		shipment.Events = append(shipment.Events, models.ShipmentEvent{
+			Status:      e.Status,
+			Description: e.Description,
+			CreatedAt:   e.CreatedAt.AsTime(),
+		})
+	}
+	return shipment
+}
+
+func addressToProto(a models.Address) *pb.Address {
+	return &pb.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Region:     a.Region,
+	}
+}
+
+func addressFromProto(a *pb.Address) models.Address {
+	return models.Address{
+		Name:       a.GetName(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+		City:       a.GetCity(),
+		PostalCode: a.GetPostalCode(),
+		Country:    a.GetCountry(),
+		Region:     a.GetRegion(),
+	}
+}
+
+func couponToProto(c *promotions.Coupon) *pb.Coupon {
+	coupon := &pb.Coupon{
+		Id:                       c.ID,
+		Code:                     c.Code,
+		Description:              c.Description,
+		Type:                     c.Type,
+		PercentOff:               c.PercentOff,
+		Scope:                    c.Scope,
+		ProductIds:               c.ProductIds,
+		Categories:               c.Categories,
+		MaxRedemptions:           uint32(max(c.MaxRedemptions, 0)),
+		MaxRedemptionsPerAccount: uint32(max(c.MaxRedemptionsPerAccount, 0)),
+		Active:                   c.Active,
+	}
+	if c.AmountOff.Currency != "" {
+		coupon.AmountOff = money.ToProto(c.AmountOff)
+	}
+	if c.MinOrderValue != nil {
+		coupon.MinOrderValue = money.ToProto(*c.MinOrderValue)
+	}
+	if c.StartsAt != nil {
+		coupon.StartsAt = timestamppb.New(*c.StartsAt)
+	}
+	if c.EndsAt != nil {
+		coupon.EndsAt = timestamppb.New(*c.EndsAt)
+	}
+	return coupon
+}
+
+func couponFromProto(c *pb.Coupon) *promotions.Coupon {
+	coupon := &promotions.Coupon{
+		ID:                       c.Id,
+		Code:                     c.Code,
+		Description:              c.Description,
+		SellerId:                 c.SellerId,
+		Type:                     c.Type,
+		PercentOff:               c.PercentOff,
+		AmountOff:                money.FromProto(c.AmountOff),
+		Scope:                    c.Scope,
+		ProductIds:               c.ProductIds,
+		Categories:               c.Categories,
+		MaxRedemptions:           int(c.MaxRedemptions),
+		MaxRedemptionsPerAccount: int(c.MaxRedemptionsPerAccount),
+		Redemptions:              int(c.Redemptions),
+		Active:                   c.Active,
+		CreatedAt:                c.CreatedAt.AsTime(),
+	}
+	if c.MinOrderValue != nil {
+		minOrderValue := money.FromProto(c.MinOrderValue)
+		coupon.MinOrderValue = &minOrderValue
+	}
+	if c.StartsAt != nil {
+		startsAt := c.StartsAt.AsTime()
+		coupon.StartsAt = &startsAt
 	}
+	if c.EndsAt != nil {
+		endsAt := c.EndsAt.AsTime()
+		coupon.EndsAt = &endsAt
+	}
+	return coupon
+}
+
+func orderFromProto(r *pb.Order) (*models.Order, error) {
+	o := &models.Order{
+		ID:         uint(r.Id),
+		Subtotal:   money.FromProto(r.Subtotal),
+		TotalPrice: money.FromProto(r.TotalPrice),
+		CouponCode: r.CouponCode,
+		Discount:   money.FromProto(r.Discount),
+		Tax:        money.FromProto(r.Tax),
+		ShipTo:     addressFromProto(r.ShipTo),
+		AccountID:  r.AccountId,
+		Status:     r.Status,
+		History:    statusHistoryFromProto(r.History),
+
+		ShippingMethod: r.ShippingMethod,
+		Shipping:       money.FromProto(r.Shipping),
+	}
+	if err := o.CreatedAt.UnmarshalBinary(r.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	for _, p := range r.Products {
+		o.Products = append(o.Products, &models.OrderedProduct{
+			ID:          p.Id,
+			Quantity:    p.Quantity,
+			Name:        p.Name,
+			Description: p.Description,
+			Sku:         p.Sku,
+			Price:       money.FromProto(p.Price),
+			LineTotal:   money.FromProto(p.LineTotal),
+			TaxCategory: p.TaxCategory,
+			TaxRate:     p.TaxRate,
+			Tax:         money.FromProto(p.Tax),
+			WeightGrams: p.WeightGrams,
+		})
+	}
+	for _, d := range r.Discounts {
+		o.Discounts = append(o.Discounts, models.Discount{
+			CouponCode: d.CouponCode,
+			ProductId:  d.ProductId,
+			Amount:     money.FromProto(d.Amount),
+		})
+	}
+	for _, s := range r.Shipments {
+		o.Shipments = append(o.Shipments, shipmentFromProto(s))
+	}
+	return o, nil
+}
 
-	return nil
+func statusHistoryFromProto(changes []*pb.StatusChange) []models.StatusChange {
+	history := make([]models.StatusChange, len(changes))
+	for i, c := range changes {
+		history[i] = models.StatusChange{
+			From:      c.From,
+			To:        c.To,
+			Actor:     c.Actor,
+			Reason:    c.Reason,
+			CreatedAt: c.CreatedAt.AsTime(),
+		}
+	}
+	return history
 }
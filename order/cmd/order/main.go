@@ -9,8 +9,12 @@ import (
 
 	"github.com/IBM/sarama"
 	"github.com/abhiii71/orderStream/account"
+	"github.com/abhiii71/orderStream/order"
 	"github.com/abhiii71/orderStream/order/config"
 	"github.com/abhiii71/orderStream/order/internal"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/pkg/outbox"
 	"github.com/joho/godotenv"
 	"github.com/tinrab/retry"
 
@@ -23,18 +27,6 @@ func main() {
 		log.Println(".env file not found!")
 	}
 
-	producer, err := sarama.NewAsyncProducer([]string{config.BootStrapServers}, nil)
-	if err != nil {
-		log.Println(err)
-	}
-
-	defer func(producer sarama.AsyncProducer) {
-		err := producer.Close()
-		if err != nil {
-			log.Println(err)
-		}
-	}(producer)
-
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
 		log.Fatal("DATABASE_URL not set")
@@ -42,11 +34,14 @@ func main() {
 
 	log.Println("DATABASE_URL:", dbURL)
 
-	var repository internal.OrderRepository
+	var (
+		db         *sql.DB
+		repository internal.OrderRepository
+	)
 
 	// Retry connecting to DB
 	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
-		db, err := sql.Open("pgx", dbURL)
+		db, err = sql.Open("pgx", dbURL)
 		if err != nil {
 			log.Println("DB connection error:", err)
 			return err
@@ -67,9 +62,101 @@ func main() {
 
 	defer repository.Close()
 
+	// events are stored with the orders and relayed to Kafka from the
+	// outbox, so orders are accepted while Kafka is unavailable
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := outbox.NewPostgresStore(db)
+	defer store.Close()
+	go func() {
+		var producer sarama.SyncProducer
+		retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+			producer, err = kafka.NewSyncProducer(config.BootStrapServers)
+			if err != nil {
+				log.Println("Kafka producer error:", err)
+			}
+			return
+		})
+		defer producer.Close()
+
+		outbox.NewRelay(store, outbox.NewKafkaPublisher(producer)).Run(ctx)
+	}()
+
 	port := account.Port
 	log.Printf("Listening on port %d...", port)
 
-	service := internal.NewOrderService(repository, producer)
-	log.Fatal(internal.ListenGRPC(service, config.AccountURL, config.ProductURL, port))
+	rules := order.DefaultTaxRules
+	if config.TaxRulesFile != "" {
+		if rules, err = order.LoadTaxRules(config.TaxRulesFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	taxes, err := order.NewRuleTable(rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	methods := order.DefaultShippingMethods
+	if config.ShippingMethodsFile != "" {
+		if methods, err = order.LoadShippingMethods(config.ShippingMethodsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	shipping, err := order.NewShippingTable(methods)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	service := internal.NewOrderService(repository, taxes, shipping)
+	go cancelUnpaidOrders(ctx, service)
+
+	keys := idempotency.NewPostgresStore(db, config.IdempotencyKeyTTL)
+	go pruneIdempotencyKeys(ctx, keys)
+
+	log.Fatal(internal.ListenGRPC(service, keys, config.AccountURL, config.ProductURL, port))
+}
+
+// cancelUnpaidOrders cancels orders whose payment timed out, which releases
+// their stock.
+func cancelUnpaidOrders(ctx context.Context, service internal.Service) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		cancelled, err := service.CancelUnpaidOrders(ctx)
+		if err != nil {
+			log.Printf("Failed to cancel unpaid orders: %v", err)
+		}
+		if cancelled > 0 {
+			log.Printf("Cancelled %d orders after the payment timeout", cancelled)
+		}
+	}
+}
+
+// pruneIdempotencyKeys deletes idempotency keys once their responses are no
+// longer replayed.
+func pruneIdempotencyKeys(ctx context.Context, keys *idempotency.PostgresStore) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := keys.Prune(ctx)
+		if err != nil {
+			log.Printf("Failed to prune idempotency keys: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d expired idempotency keys", pruned)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
@@ -1,12 +1,40 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+)
 
 var (
-	DatabaseURL     string
-	AccountURL      string
-	ProductURL      string
+	DatabaseURL      string
+	AccountURL       string
+	ProductURL       string
 	BootStrapServers string
+	// OrderEventsTopic receives order_status_changed events.
+	OrderEventsTopic string
+	// PaymentTimeout is how long an order waits for payment before it is
+	// cancelled.
+	PaymentTimeout time.Duration
+	// IdempotencyKeyTTL is how long the response to a request sent with an
+	// idempotency key is replayed.
+	IdempotencyKeyTTL time.Duration
+	// AdminAccountIds may manage every coupon and issue coupons that
+	// discount any product.
+	AdminAccountIds []uint64
+	// TaxRulesFile is a JSON file of tax rules that replaces the default
+	// rules.
+	TaxRulesFile string
+	// TaxOriginCountry and TaxOriginRegion are where the store is; orders
+	// placed without an address are taxed there.
+	TaxOriginCountry string
+	TaxOriginRegion  string
+	// ShippingMethodsFile is a JSON file of shipping methods that replaces
+	// the default methods.
+	ShippingMethodsFile string
 )
 
 func init() {
@@ -14,4 +42,25 @@ func init() {
 	AccountURL = os.Getenv("ACCOUNT_URL")
 	ProductURL = os.Getenv("PRODUCT_URL")
 	BootStrapServers = os.Getenv("KAFKA_BOOTSTRAP_SERVERS")
+	OrderEventsTopic = os.Getenv("ORDER_EVENTS_TOPIC")
+	if OrderEventsTopic == "" {
+		OrderEventsTopic = "order_events"
+	}
+	PaymentTimeout = 30 * time.Minute
+	if timeout, err := time.ParseDuration(os.Getenv("ORDER_PAYMENT_TIMEOUT")); err == nil {
+		PaymentTimeout = timeout
+	}
+	IdempotencyKeyTTL = idempotency.DefaultTTL
+	if ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL")); err == nil {
+		IdempotencyKeyTTL = ttl
+	}
+	for _, id := range strings.Split(os.Getenv("ADMIN_ACCOUNT_IDS"), ",") {
+		if accountId, err := strconv.ParseUint(strings.TrimSpace(id), 10, 64); err == nil {
+			AdminAccountIds = append(AdminAccountIds, accountId)
+		}
+	}
+	TaxRulesFile = os.Getenv("TAX_RULES_FILE")
+	TaxOriginCountry = strings.ToUpper(os.Getenv("TAX_ORIGIN_COUNTRY"))
+	TaxOriginRegion = strings.ToUpper(os.Getenv("TAX_ORIGIN_REGION"))
+	ShippingMethodsFile = os.Getenv("SHIPPING_METHODS_FILE")
 }
@@ -0,0 +1,77 @@
+package order
+
+import (
+	"errors"
+	"slices"
+)
+
+// Page sizes of order lists.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Payment statuses reported by the payment service.
+const (
+	PaymentStatusPaid              = "Success"
+	PaymentStatusPartiallyRefunded = "PartiallyRefunded"
+	PaymentStatusRefunded          = "Refunded"
+)
+
+// Order lifecycle statuses.
+const (
+	StatusPendingPayment = "pending_payment"
+	StatusPaid           = "paid"
+	StatusProcessing     = "processing"
+	StatusShipped        = "shipped"
+	StatusDelivered      = "delivered"
+	StatusCancelled      = "cancelled"
+	StatusRefunded       = "refunded"
+)
+
+// transitions lists the statuses an order may move to from each status.
+// Cancelled and refunded orders are final.
+var transitions = map[string][]string{
+	StatusPendingPayment: {StatusPaid, StatusCancelled},
+	StatusPaid:           {StatusProcessing, StatusCancelled, StatusRefunded},
+	StatusProcessing:     {StatusShipped, StatusCancelled, StatusRefunded},
+	StatusShipped:        {StatusDelivered, StatusRefunded},
+	StatusDelivered:      {StatusRefunded},
+}
+
+// IsValidStatus reports whether status is an order lifecycle status.
+func IsValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok || status == StatusCancelled || status == StatusRefunded
+}
+
+// CanTransition reports whether an order may move from one status to another.
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+var (
+	ErrMixedCurrencies   = errors.New("all products in an order must be priced in the same currency")
+	ErrNotFound          = errors.New("order not found")
+	ErrInvalidStatus     = errors.New("invalid order status")
+	ErrInvalidTransition = errors.New("order cannot move to that status")
+	ErrStatusConflict    = errors.New("order status was changed concurrently")
+	ErrNotOrderOwner     = errors.New("order belongs to another account")
+	ErrOrderCancelled    = errors.New("order was cancelled")
+	ErrInvalidCursor     = errors.New("invalid order cursor")
+	ErrNotCouponOwner    = errors.New("coupon belongs to another seller")
+	ErrInvalidAddress    = errors.New("address needs a two-letter country code")
+	ErrInvalidTaxRule    = errors.New("invalid tax rule")
+
+	ErrInvalidShippingMethod = errors.New("invalid shipping method")
+	ErrUnknownShippingMethod = errors.New("unknown shipping method")
+	ErrShippingUnavailable   = errors.New("shipping method does not deliver this order")
+	ErrShipmentNotFound      = errors.New("shipment not found")
+	ErrInvalidShipment       = errors.New("shipment needs a carrier and a tracking number")
+	ErrInvalidShipmentStatus = errors.New("invalid shipment status")
+	ErrShipmentClosed        = errors.New("shipment was delivered or returned already")
+	ErrNotShipmentOwner      = errors.New("shipment belongs to another seller")
+	ErrNotOrderSeller        = errors.New("order has no products of the seller")
+	ErrNotShippable          = errors.New("only paid orders can be shipped")
+	ErrNothingToShip         = errors.New("no products of the seller are left to ship")
+)
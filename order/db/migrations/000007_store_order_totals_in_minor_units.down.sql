@@ -0,0 +1,4 @@
+ALTER TABLE orders ALTER COLUMN total_price TYPE DOUBLE PRECISION USING
+    CASE WHEN currency IN ('JPY', 'KRW', 'VND') THEN total_price
+         ELSE total_price / 100.0
+    END;
@@ -0,0 +1,4 @@
+ALTER TABLE orders ALTER COLUMN total_price TYPE BIGINT USING
+    CASE WHEN currency IN ('JPY', 'KRW', 'VND') THEN ROUND(total_price)
+         ELSE ROUND(total_price * 100)
+    END::BIGINT;
@@ -0,0 +1,27 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'pending_payment';
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(32) NOT NULL DEFAULT '',   -- empty for the status an order was created with
+    to_status VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,                   -- account:<id>, payment or system
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history (order_id, id);
+
+-- unpaid orders older than the payment timeout (30 minutes) are closed here;
+-- newer ones are still waiting for payment and left to the scheduler
+UPDATE orders o SET status = CASE
+    WHEN payment_status = 'Success' THEN 'paid'
+    WHEN created_at IS NULL OR created_at < NOW() - INTERVAL '30 minutes' THEN 'cancelled'
+    ELSE 'pending_payment'
+END
+WHERE NOT EXISTS (SELECT 1 FROM order_status_history h WHERE h.order_id = o.id);
+
+-- existing orders start their history with their current status
+INSERT INTO order_status_history (order_id, to_status, actor, reason, created_at)
+SELECT id, status, 'system', 'migrated', COALESCE(created_at, NOW()) FROM orders o
+WHERE NOT EXISTS (SELECT 1 FROM order_status_history h WHERE h.order_id = o.id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_status_created_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS stock_reservation_id;
@@ -0,0 +1,4 @@
+-- the product service reservation holding stock for the order, released when it is cancelled
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stock_reservation_id VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at ON orders (status, created_at);
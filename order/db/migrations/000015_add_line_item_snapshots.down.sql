@@ -0,0 +1,7 @@
+ALTER TABLE order_products
+    DROP COLUMN IF EXISTS line_total,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS unit_price,
+    DROP COLUMN IF EXISTS sku,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,9 @@
+-- product details as they were when the order was placed; lines of older
+-- orders have no unit price and are filled in from the product service
+ALTER TABLE order_products
+    ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS unit_price BIGINT, -- minor units of currency
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS line_total BIGINT;
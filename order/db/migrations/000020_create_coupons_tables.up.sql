@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(32) UNIQUE NOT NULL,          -- uppercase
+    description TEXT NOT NULL DEFAULT '',
+    seller_id BIGINT NOT NULL DEFAULT 0,       -- 0 for coupons issued by admins
+    type VARCHAR(16) NOT NULL,                 -- percentage or fixed
+    percent_off BIGINT NOT NULL DEFAULT 0,     -- basis points
+    amount_off BIGINT NOT NULL DEFAULT 0,      -- minor units of currency
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    scope VARCHAR(16) NOT NULL,                -- cart, product or category
+    product_ids TEXT[] NOT NULL DEFAULT '{}',
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    min_order_value BIGINT,                    -- minor units of min_order_currency
+    min_order_currency VARCHAR(3),
+    max_redemptions INT NOT NULL DEFAULT 0,    -- 0 is unlimited
+    max_redemptions_per_account INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupons_seller_id ON coupons (seller_id);
+
+-- orders placed with a coupon; redemptions of cancelled orders do not count
+-- against the usage limits
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    order_id BIGINT PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    coupon_id BIGINT NOT NULL REFERENCES coupons(id),
+    account_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_id ON coupon_redemptions (coupon_id, account_id);
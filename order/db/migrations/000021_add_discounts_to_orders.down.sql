@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS order_discounts;
+
+ALTER TABLE order_products
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS seller_id;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS discount_total,
+    DROP COLUMN IF EXISTS coupon_code;
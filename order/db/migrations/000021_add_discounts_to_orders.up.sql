@@ -0,0 +1,20 @@
+-- total_price is net of discount_total
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS discount_total BIGINT NOT NULL DEFAULT 0;
+
+-- seller and category of the product when the order was placed
+ALTER TABLE order_products
+    ADD COLUMN IF NOT EXISTS seller_id BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS category VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    coupon_code VARCHAR(32) NOT NULL,
+    product_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,                    -- minor units of currency
+    currency VARCHAR(3) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_discounts_order_id ON order_discounts (order_id);
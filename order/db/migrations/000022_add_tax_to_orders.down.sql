@@ -0,0 +1,9 @@
+ALTER TABLE order_products
+    DROP COLUMN IF EXISTS tax,
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS tax_category;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS ship_region,
+    DROP COLUMN IF EXISTS ship_country,
+    DROP COLUMN IF EXISTS tax_total;
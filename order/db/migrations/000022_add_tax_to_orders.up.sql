@@ -0,0 +1,10 @@
+-- total_price includes tax_total; orders are taxed by where they are shipped
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS tax_total BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS ship_country VARCHAR(2) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ship_region VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE order_products
+    ADD COLUMN IF NOT EXISTS tax_category VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tax_rate BIGINT NOT NULL DEFAULT 0, -- basis points
+    ADD COLUMN IF NOT EXISTS tax BIGINT NOT NULL DEFAULT 0;      -- minor units of currency
@@ -0,0 +1,11 @@
+ALTER TABLE order_products
+    DROP COLUMN IF EXISTS weight_grams;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS ship_postal_code,
+    DROP COLUMN IF EXISTS ship_city,
+    DROP COLUMN IF EXISTS ship_line2,
+    DROP COLUMN IF EXISTS ship_line1,
+    DROP COLUMN IF EXISTS ship_name,
+    DROP COLUMN IF EXISTS shipping_cost,
+    DROP COLUMN IF EXISTS shipping_method;
@@ -0,0 +1,13 @@
+-- total_price includes shipping_cost; orders of only digital products are
+-- not shipped and have no shipping_method
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_method VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_cost BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS ship_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ship_line1 TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ship_line2 TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ship_city TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ship_postal_code VARCHAR(16) NOT NULL DEFAULT '';
+
+ALTER TABLE order_products
+    ADD COLUMN IF NOT EXISTS weight_grams BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- the relay looks for messages of the same key still waiting to be retried
+CREATE INDEX IF NOT EXISTS outbox_message_key_idx ON outbox (topic, message_key, id);
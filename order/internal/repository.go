@@ -5,15 +5,44 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/abhiii71/orderStream/order"
 	"github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/outbox"
+	"github.com/abhiii71/orderStream/pkg/promotions"
+	"github.com/lib/pq"
 )
 
 type OrderRepository interface {
 	Close()
-	PutOrder(ctx context.Context, order *models.Order) error
+	PutOrder(ctx context.Context, order *models.Order, messages ...outbox.Message) error
+	GetOrder(ctx context.Context, orderId uint64) (*models.Order, error)
 	GetOrdersForAccount(ctx context.Context, accountId uint64) ([]*models.Order, error)
+	ListOrders(ctx context.Context, accountId uint64, filter models.OrderFilter, after *order.Cursor, limit int) ([]*models.Order, error)
+	GetOrderProducts(ctx context.Context, orderIds ...uint64) (map[uint64][]*models.OrderedProduct, error)
+	GetStatusHistory(ctx context.Context, orderIds ...uint64) (map[uint64][]models.StatusChange, error)
+	UpdateOrderStatus(ctx context.Context, orderId uint64, change models.StatusChange, messages ...outbox.Message) error
 	UpdateOrderPaymentStatus(ctx context.Context, orderId uint64, status string) error
+	HasPaidOrderWithProduct(ctx context.Context, accountId uint64, productId string) (bool, error)
+	GetUnpaidOrderIds(ctx context.Context, createdBefore time.Time) ([]uint64, error)
+	GetOrderDiscounts(ctx context.Context, orderIds ...uint64) (map[uint64][]models.Discount, error)
+	PutCoupon(ctx context.Context, coupon *promotions.Coupon) error
+	UpdateCoupon(ctx context.Context, coupon *promotions.Coupon) error
+	GetCoupon(ctx context.Context, id uint64) (*promotions.Coupon, error)
+	GetCouponByCode(ctx context.Context, code string) (*promotions.Coupon, error)
+	// ListCoupons returns the coupons of a seller, or all coupons if
+	// sellerId is nil, newest first.
+	ListCoupons(ctx context.Context, sellerId *uint64) ([]*promotions.Coupon, error)
+	// PutShipment stores a shipment and the event message builds for it once
+	// it has an id.
+	PutShipment(ctx context.Context, shipment *models.Shipment, message func(*models.Shipment) (outbox.Message, error)) error
+	GetShipment(ctx context.Context, id uint64) (*models.Shipment, error)
+	GetShipments(ctx context.Context, orderIds ...uint64) (map[uint64][]models.Shipment, error)
+	UpdateShipmentStatus(ctx context.Context, id uint64, from string, event models.ShipmentEvent, messages ...outbox.Message) error
 }
 
 type repo struct {
@@ -30,7 +59,10 @@ func (r *repo) Close() {
 	}
 }
 
-func (r *repo) PutOrder(ctx context.Context, order *models.Order) error {
+// PutOrder stores an order together with the events it produces. An order
+// placed with a coupon redeems it, failing with promotions.ErrUsageLimit or
+// promotions.ErrAccountUsageLimit if the coupon was used up.
+func (r *repo) PutOrder(ctx context.Context, order *models.Order, messages ...outbox.Message) error {
 	txn, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -43,26 +75,63 @@ func (r *repo) PutOrder(ctx context.Context, order *models.Order) error {
 	}()
 
 	// Insert
-	QueryOrder := `INSERT INTO orders (account_id, total_price, created_at, payment_status) VALUES($1, $2, $3, $4) RETURNING id;`
+	QueryOrder := `INSERT INTO orders (account_id, total_price, currency, created_at, payment_status, status, stock_reservation_id, coupon_code, discount_total,
+		tax_total, ship_country, ship_region, shipping_method, shipping_cost, ship_name, ship_line1, ship_line2, ship_city, ship_postal_code)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19) RETURNING id;`
 	var orderID uint64
 
-	err = txn.QueryRowContext(ctx, QueryOrder, order.AccountID, order.TotalPrice, order.CreatedAt, order.PaymentStatus).Scan(&orderID)
+	shipTo := order.ShipTo
+	err = txn.QueryRowContext(ctx, QueryOrder, order.AccountID, order.TotalPrice.Amount, order.TotalPrice.Currency, order.CreatedAt, order.PaymentStatus, order.Status, order.StockReservationId,
+		order.CouponCode, order.Discount.Amount, order.Tax.Amount, shipTo.Country, shipTo.Region, order.ShippingMethod, order.Shipping.Amount,
+		shipTo.Name, shipTo.Line1, shipTo.Line2, shipTo.City, shipTo.PostalCode).Scan(&orderID)
 	if err != nil {
 		txn.Rollback()
 		return err
 	}
+	order.ID = uint(orderID)
+
+	for _, change := range order.History {
+		if err = insertStatusChange(ctx, txn, orderID, change); err != nil {
+			txn.Rollback()
+			return err
+		}
+	}
 
 	// Insert products for this order
-	productQuery := `INSERT INTO order_products(order_id, product_id, quantity) VALUES($1, $2, $3);`
+	productQuery := `INSERT INTO order_products(order_id, product_id, quantity, name, description, sku, unit_price, currency, line_total, seller_id, category,
+		tax_category, tax_rate, tax, weight_grams)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);`
 
 	for _, product := range order.Products {
-		_, err = txn.ExecContext(ctx, productQuery, orderID, product.ID, product.Quantity)
+		_, err = txn.ExecContext(ctx, productQuery, orderID, product.ID, product.Quantity, product.Name, product.Description, product.Sku,
+			product.Price.Amount, product.Price.Currency, product.LineTotal.Amount, product.SellerId, product.Category,
+			product.TaxCategory, product.TaxRate, product.Tax.Amount, product.WeightGrams)
 		if err != nil {
 			txn.Rollback()
 			return err
 		}
 	}
 
+	if order.CouponCode != "" {
+		if err = redeemCoupon(ctx, txn, order.CouponCode, orderID, order.AccountID); err != nil {
+			txn.Rollback()
+			return err
+		}
+	}
+	for _, discount := range order.Discounts {
+		_, err = txn.ExecContext(ctx, `INSERT INTO order_discounts (order_id, coupon_code, product_id, amount, currency) VALUES ($1, $2, $3, $4, $5)`,
+			orderID, discount.CouponCode, discount.ProductId, discount.Amount.Amount, discount.Amount.Currency)
+		if err != nil {
+			txn.Rollback()
+			return err
+		}
+	}
+
+	if err = outbox.Enqueue(ctx, txn, messages...); err != nil {
+		txn.Rollback()
+		return err
+	}
+
 	// commit transaction
 	if err = txn.Commit(); err != nil {
 		return err
@@ -70,65 +139,227 @@ func (r *repo) PutOrder(ctx context.Context, order *models.Order) error {
 	return nil
 }
 
+// orderColumns are the columns scanned by scanOrder.
+const orderColumns = `id, created_at, account_id, total_price, currency, payment_status, status, stock_reservation_id, coupon_code, discount_total,
+	tax_total, ship_country, ship_region, shipping_method, shipping_cost, ship_name, ship_line1, ship_line2, ship_city, ship_postal_code`
+
+func scanOrder(row interface{ Scan(dest ...any) error }) (*models.Order, error) {
+	var (
+		o                                                 models.Order
+		id                                                uint64
+		totalPrice, discountTotal, taxTotal, shippingCost int64
+		currency                                          string
+	)
+	err := row.Scan(&id, &o.CreatedAt, &o.AccountID, &totalPrice, &currency, &o.PaymentStatus, &o.Status, &o.StockReservationId, &o.CouponCode, &discountTotal,
+		&taxTotal, &o.ShipTo.Country, &o.ShipTo.Region, &o.ShippingMethod, &shippingCost,
+		&o.ShipTo.Name, &o.ShipTo.Line1, &o.ShipTo.Line2, &o.ShipTo.City, &o.ShipTo.PostalCode)
+	if err != nil {
+		return nil, err
+	}
+	o.ID, o.TotalPrice = uint(id), money.New(totalPrice, currency)
+	o.Discount, o.Tax = money.New(discountTotal, currency), money.New(taxTotal, currency)
+	o.Shipping = money.New(shippingCost, currency)
+	o.Subtotal = money.New(totalPrice+discountTotal-taxTotal-shippingCost, currency)
+	return &o, nil
+}
+
+// GetOrdersForAccount returns all orders of an account with their products,
+// newest first.
 func (r *repo) GetOrdersForAccount(ctx context.Context, accountId uint64) ([]*models.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE account_id = $1 ORDER BY created_at DESC, id DESC`
+	return r.queryOrders(ctx, query, accountId)
+}
 
-	query := `SELECT o.id, o.created_at, o.account_id, o.total_price, o.payment_status, 
-	op.product_id, op.quantity 
-	FROM orders o
-	JOIN order_products op ON o.id = op.order_id
-	WHERE o.account_id=$1
-	ORDER_BY o.id;`
+// ListOrders returns up to limit orders of an account that match filter,
+// newest first, starting after the cursor if there is one. Orders come with
+// their products.
+func (r *repo) ListOrders(ctx context.Context, accountId uint64, filter models.OrderFilter, after *order.Cursor, limit int) ([]*models.Order, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	conditions = append(conditions, "account_id = "+arg(accountId))
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "status = ANY("+arg(pq.Array(filter.Statuses))+")")
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(filter.CreatedBefore))
+	}
+	if filter.MinTotal != nil {
+		conditions = append(conditions, "currency = "+arg(filter.MinTotal.Currency), "total_price >= "+arg(filter.MinTotal.Amount))
+	}
+	if after != nil {
+		conditions = append(conditions, "(created_at, id) < ("+arg(after.CreatedAt)+", "+arg(after.ID)+")")
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, accountId)
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY created_at DESC, id DESC LIMIT ` + arg(limit)
+	return r.queryOrders(ctx, query, args...)
+}
+
+// queryOrders runs a query selecting orderColumns and loads the products of
+// the orders it returns.
+func (r *repo) queryOrders(ctx context.Context, query string, args ...any) ([]*models.Order, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	orderMap := make(map[uint64]*models.Order)
+	var (
+		orders []*models.Order
+		ids    []uint64
+	)
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+		ids = append(ids, uint64(o.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
 
+	products, err := r.GetOrderProducts(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		o.Products = products[uint64(o.ID)]
+		if o.Products == nil {
+			o.Products = []*models.OrderedProduct{}
+		}
+	}
+	return orders, nil
+}
+
+// GetOrderProducts returns the products of each order.
+func (r *repo) GetOrderProducts(ctx context.Context, orderIds ...uint64) (map[uint64][]*models.OrderedProduct, error) {
+	query := `SELECT order_id, product_id, quantity, name, description, sku, unit_price, currency, line_total, seller_id, category,
+		tax_category, tax_rate, tax, weight_grams
+		FROM order_products WHERE order_id = ANY($1) ORDER BY order_id, id`
+
+	ids := make([]int64, len(orderIds))
+	for i, id := range orderIds {
+		ids[i] = int64(id)
+	}
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[uint64][]*models.OrderedProduct)
 	for rows.Next() {
 		var (
-			orderID       uint64
-			createdAt     []byte
-			accID         uint64
-			totalPrice    float64
-			paymentStatus string
-			productID     string
-			quantity      int
+			orderId              uint64
+			p                    models.OrderedProduct
+			unitPrice, lineTotal sql.NullInt64
+			currency             sql.NullString
+			tax                  int64
 		)
-
-		if err := rows.Scan(&orderID, &createdAt, &accID, &totalPrice, &paymentStatus, &productID, &quantity); err != nil {
+		err := rows.Scan(&orderId, &p.ID, &p.Quantity, &p.Name, &p.Description, &p.Sku, &unitPrice, &currency, &lineTotal, &p.SellerId, &p.Category,
+			&p.TaxCategory, &p.TaxRate, &tax, &p.WeightGrams)
+		if err != nil {
 			return nil, err
 		}
-
-		if _, exists := orderMap[orderID]; !exists {
-			orderMap[orderID] = &models.Order{
-				ID:            uint(orderID),
-				AccountID:     accID,
-				TotalPrice:    totalPrice,
-				PaymentStatus: paymentStatus,
-				Products:      []*models.OrderedProduct{},
-			}
+		// lines stored before snapshots have no price
+		if currency.Valid {
+			p.Price = money.New(unitPrice.Int64, currency.String)
+			p.LineTotal = money.New(lineTotal.Int64, currency.String)
+			p.Tax = money.New(tax, currency.String)
 		}
+		products[orderId] = append(products[orderId], &p)
+	}
+	return products, rows.Err()
+}
 
-		orderMap[orderID].Products = append(orderMap[orderID].Products, &models.OrderedProduct{
-			ID:       productID,
-			Quantity: uint32(quantity),
-		})
+// GetOrder returns an order without its products.
+func (r *repo) GetOrder(ctx context.Context, orderId uint64) (*models.Order, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, orderId)
+	o, err := scanOrder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, order.ErrNotFound
 	}
+	return o, err
+}
 
-	if err = rows.Err(); err != nil {
+// GetStatusHistory returns the status changes of each order, oldest first.
+func (r *repo) GetStatusHistory(ctx context.Context, orderIds ...uint64) (map[uint64][]models.StatusChange, error) {
+	query := `SELECT order_id, from_status, to_status, actor, reason, created_at
+		FROM order_status_history WHERE order_id = ANY($1) ORDER BY order_id, id`
+
+	ids := make([]int64, len(orderIds))
+	for i, id := range orderIds {
+		ids[i] = int64(id)
+	}
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// convert map to slice
-	var orders []*models.Order
-	for _, o := range orderMap {
-		orders = append(orders, o)
+	history := make(map[uint64][]models.StatusChange)
+	for rows.Next() {
+		var (
+			orderId uint64
+			c       models.StatusChange
+		)
+		if err := rows.Scan(&orderId, &c.From, &c.To, &c.Actor, &c.Reason, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		history[orderId] = append(history[orderId], c)
 	}
+	return history, rows.Err()
+}
 
-	return orders, nil
+// UpdateOrderStatus moves an order from change.From to change.To, recording
+// the change and the events it produces. It fails with
+// order.ErrStatusConflict if the order is no longer in change.From.
+func (r *repo) UpdateOrderStatus(ctx context.Context, orderId uint64, change models.StatusChange, messages ...outbox.Message) error {
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	res, err := txn.ExecContext(ctx, `UPDATE orders SET status = $1 WHERE id = $2 AND status = $3`, change.To, orderId, change.From)
+	if err != nil {
+		return err
+	}
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return order.ErrStatusConflict
+	}
+
+	if err := insertStatusChange(ctx, txn, orderId, change); err != nil {
+		return err
+	}
+	if err := outbox.Enqueue(ctx, txn, messages...); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+func insertStatusChange(ctx context.Context, txn *sql.Tx, orderId uint64, change models.StatusChange) error {
+	query := `INSERT INTO order_status_history (order_id, from_status, to_status, actor, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := txn.ExecContext(ctx, query, orderId, change.From, change.To, change.Actor, change.Reason, change.CreatedAt)
+	return err
 }
 
 func (r *repo) UpdateOrderPaymentStatus(ctx context.Context, orderId uint64, status string) error {
@@ -148,3 +379,397 @@ func (r *repo) UpdateOrderPaymentStatus(ctx context.Context, orderId uint64, sta
 
 	return nil
 }
+
+// HasPaidOrderWithProduct reports whether the account paid for the product.
+// Partially refunded orders still count; fully refunded ones do not.
+func (r *repo) HasPaidOrderWithProduct(ctx context.Context, accountId uint64, productId string) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM orders o
+		JOIN order_products op ON o.id = op.order_id
+		WHERE o.account_id = $1 AND op.product_id = $2 AND o.payment_status IN ($3, $4)
+	);`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, accountId, productId, order.PaymentStatusPaid, order.PaymentStatusPartiallyRefunded).Scan(&exists)
+	return exists, err
+}
+
+// GetUnpaidOrderIds returns the orders created before createdBefore that are
+// still waiting for payment.
+func (r *repo) GetUnpaidOrderIds(ctx context.Context, createdBefore time.Time) ([]uint64, error) {
+	query := `SELECT id FROM orders WHERE status = $1 AND created_at < $2 ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, order.StatusPendingPayment, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetOrderDiscounts returns the discount lines of each order.
+func (r *repo) GetOrderDiscounts(ctx context.Context, orderIds ...uint64) (map[uint64][]models.Discount, error) {
+	query := `SELECT order_id, coupon_code, product_id, amount, currency
+		FROM order_discounts WHERE order_id = ANY($1) ORDER BY order_id, id`
+
+	ids := make([]int64, len(orderIds))
+	for i, id := range orderIds {
+		ids[i] = int64(id)
+	}
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discounts := make(map[uint64][]models.Discount)
+	for rows.Next() {
+		var (
+			orderId  uint64
+			d        models.Discount
+			amount   int64
+			currency string
+		)
+		if err := rows.Scan(&orderId, &d.CouponCode, &d.ProductId, &amount, &currency); err != nil {
+			return nil, err
+		}
+		d.Amount = money.New(amount, currency)
+		discounts[orderId] = append(discounts[orderId], d)
+	}
+	return discounts, rows.Err()
+}
+
+// redeemCoupon records that an order was placed with a coupon. The coupon
+// row is locked so concurrent orders cannot both take its last redemption.
+func redeemCoupon(ctx context.Context, txn *sql.Tx, code string, orderId, accountId uint64) error {
+	var (
+		couponId                        uint64
+		maxRedemptions, maxPerAccount   int
+		redemptions, accountRedemptions int
+	)
+	err := txn.QueryRowContext(ctx, `SELECT id, max_redemptions, max_redemptions_per_account FROM coupons WHERE code = $1 FOR UPDATE`, code).
+		Scan(&couponId, &maxRedemptions, &maxPerAccount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return promotions.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = txn.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(*) FILTER (WHERE cr.account_id = $2)
+		FROM coupon_redemptions cr JOIN orders o ON o.id = cr.order_id
+		WHERE cr.coupon_id = $1 AND o.status <> $3`, couponId, accountId, order.StatusCancelled).Scan(&redemptions, &accountRedemptions)
+	if err != nil {
+		return err
+	}
+	if maxRedemptions > 0 && redemptions >= maxRedemptions {
+		return promotions.ErrUsageLimit
+	}
+	if maxPerAccount > 0 && accountRedemptions >= maxPerAccount {
+		return promotions.ErrAccountUsageLimit
+	}
+
+	_, err = txn.ExecContext(ctx, `INSERT INTO coupon_redemptions (order_id, coupon_id, account_id) VALUES ($1, $2, $3)`, orderId, couponId, accountId)
+	return err
+}
+
+// couponColumns are the columns scanned by scanCoupon. Redemptions of
+// cancelled orders are not counted.
+const couponColumns = `id, code, description, seller_id, type, percent_off, amount_off, currency, scope, product_ids, categories,
+	min_order_value, min_order_currency, max_redemptions, max_redemptions_per_account, starts_at, ends_at, active, created_at,
+	(SELECT COUNT(*) FROM coupon_redemptions cr JOIN orders o ON o.id = cr.order_id
+		WHERE cr.coupon_id = coupons.id AND o.status <> 'cancelled')`
+
+func scanCoupon(row interface{ Scan(dest ...any) error }) (*promotions.Coupon, error) {
+	var (
+		c                      promotions.Coupon
+		amountOff              int64
+		currency               string
+		minOrderValue          sql.NullInt64
+		minOrderCurrency       sql.NullString
+		startsAt, endsAt       sql.NullTime
+		productIds, categories pq.StringArray
+	)
+	err := row.Scan(&c.ID, &c.Code, &c.Description, &c.SellerId, &c.Type, &c.PercentOff, &amountOff, &currency, &c.Scope, &productIds, &categories,
+		&minOrderValue, &minOrderCurrency, &c.MaxRedemptions, &c.MaxRedemptionsPerAccount, &startsAt, &endsAt, &c.Active, &c.CreatedAt, &c.Redemptions)
+	if err != nil {
+		return nil, err
+	}
+	c.AmountOff = money.New(amountOff, currency)
+	c.ProductIds, c.Categories = productIds, categories
+	if minOrderValue.Valid {
+		value := money.New(minOrderValue.Int64, minOrderCurrency.String)
+		c.MinOrderValue = &value
+	}
+	if startsAt.Valid {
+		c.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		c.EndsAt = &endsAt.Time
+	}
+	return &c, nil
+}
+
+// couponArgs returns the values of the columns of a coupon that can be
+// changed after it is created, in the order PutCoupon and UpdateCoupon use.
+func couponArgs(c *promotions.Coupon) []any {
+	var minOrderValue, minOrderCurrency any
+	if c.MinOrderValue != nil {
+		minOrderValue, minOrderCurrency = c.MinOrderValue.Amount, c.MinOrderValue.Currency
+	}
+	return []any{c.Description, minOrderValue, minOrderCurrency, c.MaxRedemptions, c.MaxRedemptionsPerAccount, c.StartsAt, c.EndsAt, c.Active}
+}
+
+// PutCoupon stores a new coupon and sets its id. It fails with
+// promotions.ErrCodeTaken if another coupon has the code.
+func (r *repo) PutCoupon(ctx context.Context, c *promotions.Coupon) error {
+	query := `INSERT INTO coupons (description, min_order_value, min_order_currency, max_redemptions, max_redemptions_per_account, starts_at, ends_at, active,
+			code, seller_id, type, percent_off, amount_off, currency, scope, product_ids, categories, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (code) DO NOTHING RETURNING id`
+	args := append(couponArgs(c), c.Code, c.SellerId, c.Type, c.PercentOff, c.AmountOff.Amount, c.AmountOff.Currency, c.Scope,
+		pq.Array(c.ProductIds), pq.Array(c.Categories), c.CreatedAt)
+
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&c.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return promotions.ErrCodeTaken
+	}
+	return err
+}
+
+// UpdateCoupon stores the description, minimum order value, usage limits,
+// validity window and active flag of a coupon. Its code and discount cannot
+// change once orders may have used it.
+func (r *repo) UpdateCoupon(ctx context.Context, c *promotions.Coupon) error {
+	query := `UPDATE coupons SET description = $1, min_order_value = $2, min_order_currency = $3, max_redemptions = $4,
+		max_redemptions_per_account = $5, starts_at = $6, ends_at = $7, active = $8 WHERE id = $9`
+	res, err := r.db.ExecContext(ctx, query, append(couponArgs(c), c.ID)...)
+	if err != nil {
+		return err
+	}
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return promotions.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) GetCoupon(ctx context.Context, id uint64) (*promotions.Coupon, error) {
+	c, err := scanCoupon(r.db.QueryRowContext(ctx, `SELECT `+couponColumns+` FROM coupons WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, promotions.ErrNotFound
+	}
+	return c, err
+}
+
+func (r *repo) GetCouponByCode(ctx context.Context, code string) (*promotions.Coupon, error) {
+	c, err := scanCoupon(r.db.QueryRowContext(ctx, `SELECT `+couponColumns+` FROM coupons WHERE code = $1`, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, promotions.ErrNotFound
+	}
+	return c, err
+}
+
+func (r *repo) ListCoupons(ctx context.Context, sellerId *uint64) ([]*promotions.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons`
+	var args []any
+	if sellerId != nil {
+		query += ` WHERE seller_id = $1`
+		args = append(args, *sellerId)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY created_at DESC, id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := []*promotions.Coupon{}
+	for rows.Next() {
+		c, err := scanCoupon(rows)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, c)
+	}
+	return coupons, rows.Err()
+}
+
+// PutShipment stores a shipment with its first tracking event and the event
+// message builds for it. It fails with order.ErrNothingToShip if one of its
+// products is in another shipment of the order already.
+func (r *repo) PutShipment(ctx context.Context, shipment *models.Shipment, message func(*models.Shipment) (outbox.Message, error)) error {
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	// concurrent shipments of the order wait for each other
+	if _, err := txn.ExecContext(ctx, `SELECT id FROM orders WHERE id = $1 FOR UPDATE`, shipment.OrderId); err != nil {
+		return err
+	}
+	var shipped bool
+	err = txn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM shipments WHERE order_id = $1 AND product_ids && $2)`,
+		shipment.OrderId, pq.Array(shipment.ProductIds)).Scan(&shipped)
+	if err != nil {
+		return err
+	}
+	if shipped {
+		return order.ErrNothingToShip
+	}
+
+	err = txn.QueryRowContext(ctx, `INSERT INTO shipments (order_id, seller_id, carrier, tracking_number, product_ids, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		shipment.OrderId, shipment.SellerId, shipment.Carrier, shipment.TrackingNumber, pq.Array(shipment.ProductIds), shipment.Status,
+		shipment.CreatedAt, shipment.UpdatedAt).Scan(&shipment.ID)
+	if err != nil {
+		return err
+	}
+	for _, event := range shipment.Events {
+		if err := insertShipmentEvent(ctx, txn, shipment.ID, event); err != nil {
+			return err
+		}
+	}
+
+	m, err := message(shipment)
+	if err != nil {
+		return err
+	}
+	if err := outbox.Enqueue(ctx, txn, m); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+func insertShipmentEvent(ctx context.Context, txn *sql.Tx, shipmentId uint64, event models.ShipmentEvent) error {
+	_, err := txn.ExecContext(ctx, `INSERT INTO shipment_events (shipment_id, status, description, created_at) VALUES ($1, $2, $3, $4)`,
+		shipmentId, event.Status, event.Description, event.CreatedAt)
+	return err
+}
+
+// shipmentColumns are the columns scanned by scanShipment.
+const shipmentColumns = `id, order_id, seller_id, carrier, tracking_number, product_ids, status, created_at, updated_at`
+
+func scanShipment(row interface{ Scan(dest ...any) error }) (*models.Shipment, error) {
+	var (
+		s          models.Shipment
+		productIds pq.StringArray
+	)
+	err := row.Scan(&s.ID, &s.OrderId, &s.SellerId, &s.Carrier, &s.TrackingNumber, &productIds, &s.Status, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.ProductIds = productIds
+	return &s, nil
+}
+
+// GetShipment returns a shipment without its tracking history.
+func (r *repo) GetShipment(ctx context.Context, id uint64) (*models.Shipment, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE id = $1`, id)
+	s, err := scanShipment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, order.ErrShipmentNotFound
+	}
+	return s, err
+}
+
+// GetShipments returns the shipments of each order with their tracking
+// history, oldest first.
+func (r *repo) GetShipments(ctx context.Context, orderIds ...uint64) (map[uint64][]models.Shipment, error) {
+	ids := make([]int64, len(orderIds))
+	for i, id := range orderIds {
+		ids[i] = int64(id)
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT `+shipmentColumns+` FROM shipments WHERE order_id = ANY($1) ORDER BY order_id, id`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shipments []*models.Shipment
+	for rows.Next() {
+		s, err := scanShipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byOrder := make(map[uint64][]models.Shipment)
+	if len(shipments) == 0 {
+		return byOrder, nil
+	}
+
+	shipmentIds := make([]int64, len(shipments))
+	byId := make(map[uint64]*models.Shipment, len(shipments))
+	for i, s := range shipments {
+		shipmentIds[i] = int64(s.ID)
+		byId[s.ID] = s
+	}
+	eventRows, err := r.db.QueryContext(ctx, `SELECT shipment_id, status, description, created_at
+		FROM shipment_events WHERE shipment_id = ANY($1) ORDER BY shipment_id, created_at, id`, pq.Array(shipmentIds))
+	if err != nil {
+		return nil, err
+	}
+	defer eventRows.Close()
+	for eventRows.Next() {
+		var (
+			shipmentId uint64
+			e          models.ShipmentEvent
+		)
+		if err := eventRows.Scan(&shipmentId, &e.Status, &e.Description, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		byId[shipmentId].Events = append(byId[shipmentId].Events, e)
+	}
+	if err := eventRows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range shipments {
+		byOrder[s.OrderId] = append(byOrder[s.OrderId], *s)
+	}
+	return byOrder, nil
+}
+
+// UpdateShipmentStatus moves a shipment from status from to event.Status,
+// recording the event and the events it produces. It fails with
+// order.ErrStatusConflict if the shipment is no longer in from.
+func (r *repo) UpdateShipmentStatus(ctx context.Context, id uint64, from string, event models.ShipmentEvent, messages ...outbox.Message) error {
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	res, err := txn.ExecContext(ctx, `UPDATE shipments SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`, event.Status, event.CreatedAt, id, from)
+	if err != nil {
+		return err
+	}
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return order.ErrStatusConflict
+	}
+
+	if err := insertShipmentEvent(ctx, txn, id, event); err != nil {
+		return err
+	}
+	if err := outbox.Enqueue(ctx, txn, messages...); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
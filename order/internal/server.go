@@ -2,30 +2,43 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"strconv"
 
 	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/google/uuid"
 
 	account "github.com/abhiii71/orderStream/account/client"
+	"github.com/abhiii71/orderStream/order"
 	"github.com/abhiii71/orderStream/order/models"
 	"github.com/abhiii71/orderStream/order/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/pricing"
+	"github.com/abhiii71/orderStream/pkg/promotions"
 	product "github.com/abhiii71/orderStream/product/client"
+	productModels "github.com/abhiii71/orderStream/product/models"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type grpcServer struct {
 	pb.UnimplementedOrderServiceServer
 	service       Service
+	keys          idempotency.Store
 	accountClient *account.Client
 	productClient *product.Client
 }
 
-func ListenGRPC(service Service, accountURL string, productURL string, port int) error {
+func ListenGRPC(service Service, keys idempotency.Store, accountURL string, productURL string, port int) error {
 	accountClient, err := account.NewClient(accountURL)
 	if err != nil {
 		return err
@@ -47,6 +60,7 @@ func ListenGRPC(service Service, accountURL string, productURL string, port int)
 	pb.RegisterOrderServiceServer(serv, &grpcServer{
 		pb.UnimplementedOrderServiceServer{},
 		service,
+		keys,
 		accountClient,
 		productClient,
 	})
@@ -55,141 +69,560 @@ func ListenGRPC(service Service, accountURL string, productURL string, port int)
 	return serv.Serve(lis)
 }
 
+// PostOrder places an order. Retries of a request sent with an idempotency
+// key get the order placed by the first one.
 func (s *grpcServer) PostOrder(ctx context.Context, request *pb.PostOrderRequest) (*pb.PostOrderResponse, error) {
+	scope := "PostOrder/account:" + strconv.FormatUint(request.GetAccountId(), 10)
+	response, err := idempotency.Do(ctx, s.keys, scope, idempotency.IncomingKey(ctx), request, func(ctx context.Context) (*pb.PostOrderResponse, error) {
+		return s.postOrder(ctx, request)
+	})
+	if err != nil {
+		return nil, orderError(err)
+	}
+	return response, nil
+}
 
+func (s *grpcServer) postOrder(ctx context.Context, request *pb.PostOrderRequest) (*pb.PostOrderResponse, error) {
 	_, err := s.accountClient.GetAccount(ctx, request.AccountId)
 	if err != nil {
 		log.Println("error getting account", err)
 		return nil, err
 	}
 
+	// lines of the same product are ordered together
+	quantities := make(map[string]uint32)
 	var productIDs []string
 	for _, p := range request.Products {
-		productIDs = append(productIDs, p.Id)
+		if _, ok := quantities[p.Id]; !ok {
+			productIDs = append(productIDs, p.Id)
+		}
+		quantities[p.Id] += p.Quantity
 	}
 
-	orderedProducts, err := s.productClient.GetProducts(ctx, 0, 0, productIDs, "")
+	orderedProducts, err := s.productClient.GetProducts(ctx, 0, 0, productIDs, "", productModels.ProductFilter{IncludeHidden: true})
 	if err != nil {
 		log.Println("error getting ordered products", err)
 		return nil, err
 	}
 
-	var products []*models.OrderedProduct
-	totalPrice := 0.0
+	// products that do not exist are not returned at all
+	found := mapset.NewSet[string]()
+	for _, p := range orderedProducts {
+		found.Add(p.Id)
+	}
+	for _, id := range productIDs {
+		if !found.Contains(id) {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is not available", id)
+		}
+	}
 
+	currency := ""
+	for _, p := range orderedProducts {
+		if !p.IsPublished() || p.IsDeleted() {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is not available", p.Id)
+		}
+		if currency != "" && p.Price.Currency != currency {
+			return nil, orderError(order.ErrMixedCurrencies)
+		}
+		currency = p.Price.Currency
+	}
+
+	var products []*models.OrderedProduct
 	for _, p := range orderedProducts {
 		productObj := &models.OrderedProduct{
 			ID:          p.Id,
 			Name:        p.Name,
 			Description: p.Description,
+			Sku:         p.Sku,
 			Price:       p.Price,
-			Quantity:    0,
-		}
-
-		for _, requestProduct := range request.Products {
-			if requestProduct.Id == p.Id {
-				productObj.Quantity = requestProduct.Quantity
-				break
-			}
+			Quantity:    quantities[p.Id],
+			SellerId:    uint64(p.AccountId),
+			Category:    p.Category,
+			TaxCategory: p.TaxCategory,
+			WeightGrams: p.WeightGrams,
 		}
 		if productObj.Quantity != 0 {
 			products = append(products, productObj)
-			totalPrice += productObj.Price * float64(productObj.Price)
 		}
 	}
+	if len(products) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order has no products")
+	}
+
+	// stock is held before the order is stored, and given back if storing
+	// the order fails
+	reservationId := uuid.NewString()
+	items := make([]productModels.ReservedItem, len(products))
+	for i, p := range products {
+		items[i] = productModels.ReservedItem{ProductId: p.ID, Quantity: int(p.Quantity)}
+	}
+	if err := s.productClient.ReserveStock(ctx, reservationId, items); err != nil {
+		log.Println("error reserving stock", err)
+		return nil, err
+	}
 
-	postOrder, err := s.service.PostOrder(ctx, request.AccountId, totalPrice, products)
+	postOrder, err := s.service.PostOrder(ctx, request.AccountId, products, addressFromProto(request.GetShipTo()), request.GetShippingMethod(), request.GetCouponCode(), reservationId)
 	if err != nil {
 		log.Println("error  posting postOrder", err)
+		if err := s.productClient.ReleaseStock(context.WithoutCancel(ctx), reservationId); err != nil {
+			log.Println("error releasing stock", err)
+		}
+		return nil, orderError(err)
+	}
+
+	return &pb.PostOrderResponse{Order: orderToProto(postOrder)}, nil
+}
+
+func (s *grpcServer) GetOrdersForAccount(ctx context.Context, request *wrapperspb.UInt64Value) (*pb.GetOrdersForAccountResponse, error) {
+	accountOrders, err := s.service.GetOrdersForAccount(ctx, request.Value)
+	if err != nil {
+		log.Println(err)
 		return nil, err
 	}
 
-	orderProto := &pb.Order{
-		Id:         uint64(postOrder.ID),
-		TotalPrice: postOrder.TotalPrice,
-		Products:   []*pb.ProductInfo{},
+	if err := s.withLegacyProductDetails(ctx, accountOrders); err != nil {
+		log.Println("error getting account products: ", err)
+		return nil, err
 	}
 
-	orderProto.CreatedAt, _ = postOrder.CreatedAt.MarshalBinary()
-	for _, p := range postOrder.Products {
-		orderProto.Products = append(orderProto.Products, &pb.ProductInfo{
-			Id:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
-		})
+	var orders []*pb.Order
+	for _, o := range accountOrders {
+		orders = append(orders, orderToProto(o))
+	}
+	return &pb.GetOrdersForAccountResponse{Orders: orders}, nil
+}
+
+func (s *grpcServer) GetOrder(ctx context.Context, request *pb.GetOrderRequest) (*pb.Order, error) {
+	o, err := s.service.GetOrder(ctx, request.GetOrderId(), request.GetAccountId())
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
 	}
 
-	return &pb.PostOrderResponse{Order: orderProto}, nil
+	if err := s.withLegacyProductDetails(ctx, []*models.Order{o}); err != nil {
+		log.Println("error getting order products: ", err)
+		return nil, err
+	}
+	return orderToProto(o), nil
 }
 
-func (s *grpcServer) GetOrdersForAccount(ctx context.Context, request *wrapperspb.UInt64Value) (*pb.GetOrdersForAccountResponse, error) {
-	accountOrders, err := s.service.GetOrdersForAccount(ctx, request.Value)
+func (s *grpcServer) ListOrders(ctx context.Context, request *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	var filter models.OrderFilter
+	if f := request.GetFilter(); f != nil {
+		filter.Statuses = f.Statuses
+		if f.CreatedAfter != nil {
+			filter.CreatedAfter = f.CreatedAfter.AsTime()
+		}
+		if f.CreatedBefore != nil {
+			filter.CreatedBefore = f.CreatedBefore.AsTime()
+		}
+		if f.MinTotal != nil {
+			minTotal := money.FromProto(f.MinTotal)
+			filter.MinTotal = &minTotal
+		}
+	}
+
+	page, err := s.service.ListOrders(ctx, request.GetAccountId(), filter, request.GetAfter(), int(request.GetFirst()))
 	if err != nil {
 		log.Println(err)
+		return nil, orderError(err)
+	}
+
+	if err := s.withLegacyProductDetails(ctx, page.Orders); err != nil {
+		log.Println("error getting order products: ", err)
 		return nil, err
 	}
 
+	response := &pb.ListOrdersResponse{EndCursor: page.EndCursor, HasNextPage: page.HasNextPage}
+	for _, o := range page.Orders {
+		response.Orders = append(response.Orders, orderToProto(o))
+	}
+	return response, nil
+}
+
+// withLegacyProductDetails fills in the names, descriptions and prices of
+// order lines stored without a snapshot from the product service. Their
+// prices are today's, not the ones that were paid.
+func (s *grpcServer) withLegacyProductDetails(ctx context.Context, orders []*models.Order) error {
 	// Taking unique products. We use set to avoid repeating
 	productIdsSet := mapset.NewSet[string]()
-	for _, o := range accountOrders {
+	for _, o := range orders {
 		for _, p := range o.Products {
-			productIdsSet.Add(p.ID)
+			if !p.Snapshotted() {
+				productIdsSet.Add(p.ID)
+			}
+		}
+	}
+	if productIdsSet.IsEmpty() {
+		return nil
+	}
+
+	// past orders keep showing products since deleted or unpublished
+	products, err := s.productClient.GetProducts(ctx, 0, 0, productIdsSet.ToSlice(), "", productModels.ProductFilter{IncludeHidden: true})
+	if err != nil {
+		return err
+	}
+
+	byId := make(map[string]productModels.Product, len(products))
+	for _, p := range products {
+		byId[p.Id] = p
+	}
+	for _, o := range orders {
+		for _, orderedProduct := range o.Products {
+			if p, ok := byId[orderedProduct.ID]; ok && !orderedProduct.Snapshotted() {
+				orderedProduct.Name = p.Name
+				orderedProduct.Description = p.Description
+				orderedProduct.Sku = p.Sku
+				orderedProduct.Price = p.Price
+				orderedProduct.LineTotal = p.Price.Mul(int64(orderedProduct.Quantity))
+			}
 		}
 	}
+	return nil
+}
 
-	productIds := productIdsSet.ToSlice()
+func (s *grpcServer) UpdateOrderStatus(ctx context.Context, request *pb.UpdateOrderStatusRequest) (*pb.Order, error) {
+	if request.GetActor() == "" {
+		return nil, status.Error(codes.InvalidArgument, "actor is required")
+	}
 
-	products, err := s.productClient.GetProducts(ctx, 0, 0, productIds, "")
+	o, err := s.service.UpdateOrderStatus(ctx, request.GetOrderId(), request.GetStatus(), request.GetActor(), request.GetReason())
 	if err != nil {
-		log.Println("error getting account products: ", err)
+		log.Println(err)
+		return nil, orderError(err)
+	}
+	return orderToProto(o), nil
+}
+
+func (s *grpcServer) CancelOrder(ctx context.Context, request *pb.CancelOrderRequest) (*pb.Order, error) {
+	o, err := s.service.CancelOrder(ctx, request.GetOrderId(), request.GetAccountId(), request.GetReason())
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
+	}
+	return orderToProto(o), nil
+}
+
+func (s *grpcServer) UpdatePaymentStatus(ctx context.Context, request *pb.UpdatePaymentStatusRequest) (*emptypb.Empty, error) {
+	err := s.service.UpdateOrderPaymentStatus(ctx, request.GetOrderId(), request.GetStatus())
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcServer) HasPurchased(ctx context.Context, request *pb.HasPurchasedRequest) (*wrapperspb.BoolValue, error) {
+	purchased, err := s.service.HasPurchased(ctx, request.GetAccountId(), request.GetProductId())
+	if err != nil {
+		log.Println(err)
 		return nil, err
 	}
+	return wrapperspb.Bool(purchased), nil
+}
 
-	// collecting orders
-	var orders []*pb.Order
-	for _, order := range accountOrders {
-		// encounter order
-		encounterOrder := &pb.Order{
-			AccountId:  order.AccountID,
-			Id:         uint64(order.ID),
-			TotalPrice: order.TotalPrice,
-			Products:   []*pb.ProductInfo{},
+func (s *grpcServer) CreateCoupon(ctx context.Context, request *pb.CreateCouponRequest) (*pb.Coupon, error) {
+	coupon := couponFromProto(request.GetCoupon())
+	if err := s.checkCouponProducts(ctx, request.GetAccountId(), coupon); err != nil {
+		return nil, err
+	}
+
+	created, err := s.service.CreateCoupon(ctx, request.GetAccountId(), coupon)
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
+	}
+	return couponToProto(created), nil
+}
+
+// checkCouponProducts makes sure the products of a product coupon exist and,
+// unless an admin issues it, belong to the seller issuing it.
+func (s *grpcServer) checkCouponProducts(ctx context.Context, accountId uint64, coupon *promotions.Coupon) error {
+	if coupon.Scope != promotions.ScopeProduct || len(coupon.ProductIds) == 0 {
+		return nil
+	}
+
+	// sellers may issue coupons for their own unpublished products
+	products, err := s.productClient.GetProducts(ctx, 0, 0, coupon.ProductIds, "", productModels.ProductFilter{ViewerId: int(accountId)})
+	if err != nil {
+		log.Println("error getting coupon products", err)
+		return err
+	}
+	if len(products) != mapset.NewSet(coupon.ProductIds...).Cardinality() {
+		return status.Error(codes.InvalidArgument, "coupon products do not exist")
+	}
+	for _, p := range products {
+		if !isAdmin(accountId) && uint64(p.AccountId) != accountId {
+			return status.Errorf(codes.PermissionDenied, "product %s belongs to another seller", p.Id)
 		}
+	}
+	return nil
+}
 
-		encounterOrder.CreatedAt, _ = order.CreatedAt.MarshalBinary()
-
-		// Decorate orders with products
-		for _, orderedProduct := range order.Products {
-			// Populate product fields
-			for _, prod := range products {
-				if prod.Id == orderedProduct.ID {
-					orderedProduct.Name = prod.Name
-					orderedProduct.Description = prod.Description
-					orderedProduct.Price = prod.Price
-					break
-				}
-			}
+func (s *grpcServer) UpdateCoupon(ctx context.Context, request *pb.UpdateCouponRequest) (*pb.Coupon, error) {
+	updated, err := s.service.UpdateCoupon(ctx, request.GetAccountId(), couponFromProto(request.GetCoupon()))
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
+	}
+	return couponToProto(updated), nil
+}
 
-			encounterOrder.Products = append(encounterOrder.Products, &pb.ProductInfo{
-				Id:          orderedProduct.ID,
-				Name:        orderedProduct.Name,
-				Description: orderedProduct.Description,
-				Price:       orderedProduct.Price,
-				Quantity:    orderedProduct.Quantity,
+func (s *grpcServer) ListCoupons(ctx context.Context, request *pb.ListCouponsRequest) (*pb.ListCouponsResponse, error) {
+	coupons, err := s.service.ListCoupons(ctx, request.GetAccountId())
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
+	}
+
+	response := &pb.ListCouponsResponse{}
+	for _, c := range coupons {
+		response.Coupons = append(response.Coupons, couponToProto(c))
+	}
+	return response, nil
+}
+
+func (s *grpcServer) ListShippingMethods(ctx context.Context, _ *emptypb.Empty) (*pb.ListShippingMethodsResponse, error) {
+	response := &pb.ListShippingMethodsResponse{}
+	for _, m := range s.service.ShippingMethods() {
+		method := &pb.ShippingMethod{Code: m.Code, Name: m.Name, Carrier: m.Carrier, Countries: m.Countries}
+		for _, r := range m.Rates {
+			method.Rates = append(method.Rates, &pb.ShippingRate{
+				Currency: r.Currency,
+				Type:     r.Type,
+				Amount:   r.Amount,
+				PerKg:    r.PerKg,
+				FreeOver: r.FreeOver,
 			})
 		}
-		orders = append(orders, encounterOrder)
+		response.Methods = append(response.Methods, method)
 	}
+	return response, nil
+}
 
-	return &pb.GetOrdersForAccountResponse{Orders: orders}, nil
+func (s *grpcServer) MarkShipped(ctx context.Context, request *pb.MarkShippedRequest) (*pb.Shipment, error) {
+	shipment, err := s.service.MarkShipped(ctx, request.GetOrderId(), request.GetSellerId(), request.GetCarrier(), request.GetTrackingNumber(), request.GetProductIds())
+	if err != nil {
+		log.Println(err)
+		return nil, orderError(err)
+	}
+	return shipmentToProto(shipment), nil
 }
 
-func (s *grpcServer) UpdateOrderStatus(ctx context.Context, request *pb.UpdateOrderStatusRequest) (*emptypb.Empty, error) {
-	err := s.service.UpdateOrderPaymentStatus(ctx, request.OrderId, request.Status)
+func (s *grpcServer) UpdateShipmentStatus(ctx context.Context, request *pb.UpdateShipmentStatusRequest) (*pb.Shipment, error) {
+	shipment, err := s.service.UpdateShipmentStatus(ctx, request.GetShipmentId(), request.GetAccountId(), request.GetStatus(), request.GetDescription())
 	if err != nil {
-		return nil, err
+		log.Println(err)
+		return nil, orderError(err)
 	}
-	return &emptypb.Empty{}, nil
+	return shipmentToProto(shipment), nil
+}
+
+func shipmentToProto(s *models.Shipment) *pb.Shipment {
+	shipment := &pb.Shipment{
+		Id:             s.ID,
+		OrderId:        s.OrderId,
+		SellerId:       s.SellerId,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		ProductIds:     s.ProductIds,
+		Status:         s.Status,
+		CreatedAt:      timestamppb.New(s.CreatedAt),
+		UpdatedAt:      timestamppb.New(s.UpdatedAt),
+	}
+	for _, e := range s.Events {
+		shipment.Events = append(shipment.Events, &pb.ShipmentEvent{
+			Status:      e.Status,
+			Description: e.Description,
+			CreatedAt:   timestamppb.New(e.CreatedAt),
+		})
+	}
+	return shipment
+}
+
+func couponFromProto(c *pb.Coupon) *promotions.Coupon {
+	coupon := &promotions.Coupon{
+		ID:                       c.GetId(),
+		Code:                     c.GetCode(),
+		Description:              c.GetDescription(),
+		Type:                     c.GetType(),
+		PercentOff:               c.GetPercentOff(),
+		Scope:                    c.GetScope(),
+		ProductIds:               c.GetProductIds(),
+		Categories:               c.GetCategories(),
+		MaxRedemptions:           int(c.GetMaxRedemptions()),
+		MaxRedemptionsPerAccount: int(c.GetMaxRedemptionsPerAccount()),
+		Active:                   c.GetActive(),
+		AmountOff:                money.FromProto(c.GetAmountOff()),
+	}
+	if c.GetMinOrderValue() != nil {
+		minOrderValue := money.FromProto(c.GetMinOrderValue())
+		coupon.MinOrderValue = &minOrderValue
+	}
+	if c.GetStartsAt() != nil {
+		startsAt := c.GetStartsAt().AsTime()
+		coupon.StartsAt = &startsAt
+	}
+	if c.GetEndsAt() != nil {
+		endsAt := c.GetEndsAt().AsTime()
+		coupon.EndsAt = &endsAt
+	}
+	return coupon
+}
+
+func couponToProto(c *promotions.Coupon) *pb.Coupon {
+	coupon := &pb.Coupon{
+		Id:                       c.ID,
+		Code:                     c.Code,
+		Description:              c.Description,
+		SellerId:                 c.SellerId,
+		Type:                     c.Type,
+		PercentOff:               c.PercentOff,
+		Scope:                    c.Scope,
+		ProductIds:               c.ProductIds,
+		Categories:               c.Categories,
+		MaxRedemptions:           uint32(c.MaxRedemptions),
+		MaxRedemptionsPerAccount: uint32(c.MaxRedemptionsPerAccount),
+		Redemptions:              uint32(c.Redemptions),
+		Active:                   c.Active,
+		CreatedAt:                timestamppb.New(c.CreatedAt),
+	}
+	if c.Type == promotions.TypeFixed {
+		coupon.AmountOff = money.ToProto(c.AmountOff)
+	}
+	if c.MinOrderValue != nil {
+		coupon.MinOrderValue = money.ToProto(*c.MinOrderValue)
+	}
+	if c.StartsAt != nil {
+		coupon.StartsAt = timestamppb.New(*c.StartsAt)
+	}
+	if c.EndsAt != nil {
+		coupon.EndsAt = timestamppb.New(*c.EndsAt)
+	}
+	return coupon
+}
+
+func orderToProto(o *models.Order) *pb.Order {
+	orderProto := &pb.Order{
+		Id:         uint64(o.ID),
+		AccountId:  o.AccountID,
+		TotalPrice: money.ToProto(o.TotalPrice),
+		Products:   []*pb.ProductInfo{},
+		Status:     o.Status,
+		History:    statusHistoryToProto(o.History),
+		CouponCode: o.CouponCode,
+		Discount:   money.ToProto(o.Discount),
+		Subtotal:   money.ToProto(o.Subtotal),
+		Tax:        money.ToProto(o.Tax),
+		ShipTo:     addressToProto(o.ShipTo),
+
+		ShippingMethod: o.ShippingMethod,
+		Shipping:       money.ToProto(o.Shipping),
+	}
+	orderProto.CreatedAt, _ = o.CreatedAt.MarshalBinary()
+
+	for _, p := range o.Products {
+		orderProto.Products = append(orderProto.Products, &pb.ProductInfo{
+			Id:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Sku:         p.Sku,
+			Price:       money.ToProto(p.Price),
+			Quantity:    p.Quantity,
+			LineTotal:   money.ToProto(p.LineTotal),
+			TaxCategory: p.TaxCategory,
+			TaxRate:     p.TaxRate,
+			Tax:         money.ToProto(p.Tax),
+			WeightGrams: p.WeightGrams,
+		})
+	}
+	for _, d := range o.Discounts {
+		orderProto.Discounts = append(orderProto.Discounts, &pb.Discount{
+			CouponCode: d.CouponCode,
+			ProductId:  d.ProductId,
+			Amount:     money.ToProto(d.Amount),
+		})
+	}
+	for i := range o.Shipments {
+		orderProto.Shipments = append(orderProto.Shipments, shipmentToProto(&o.Shipments[i]))
+	}
+	return orderProto
+}
+
+func addressFromProto(a *pb.Address) models.Address {
+	return models.Address{
+		Name:       a.GetName(),
+		Line1:      a.GetLine1(),
+		Line2:      a.GetLine2(),
+		City:       a.GetCity(),
+		PostalCode: a.GetPostalCode(),
+		Country:    a.GetCountry(),
+		Region:     a.GetRegion(),
+	}
+}
+
+func addressToProto(a models.Address) *pb.Address {
+	return &pb.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Region:     a.Region,
+	}
+}
+
+func statusHistoryToProto(history []models.StatusChange) []*pb.StatusChange {
+	changes := make([]*pb.StatusChange, len(history))
+	for i, c := range history {
+		changes[i] = &pb.StatusChange{
+			From:      c.From,
+			To:        c.To,
+			Actor:     c.Actor,
+			Reason:    c.Reason,
+			CreatedAt: timestamppb.New(c.CreatedAt),
+		}
+	}
+	return changes
+}
+
+// orderError maps order errors to gRPC status codes.
+func orderError(err error) error {
+	switch {
+	case errors.Is(err, order.ErrNotFound), errors.Is(err, order.ErrShipmentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, order.ErrUnknownShippingMethod), errors.Is(err, order.ErrInvalidShipment), errors.Is(err, order.ErrInvalidShipmentStatus):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, order.ErrShippingUnavailable), errors.Is(err, order.ErrNotShippable), errors.Is(err, order.ErrNothingToShip),
+		errors.Is(err, order.ErrShipmentClosed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, order.ErrNotOrderSeller), errors.Is(err, order.ErrNotShipmentOwner):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, order.ErrInvalidStatus), errors.Is(err, order.ErrInvalidCursor),
+		errors.Is(err, order.ErrMixedCurrencies), errors.Is(err, order.ErrInvalidAddress), errors.Is(err, pricing.ErrNoLines), errors.Is(err, pricing.ErrInvalidQuantity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, order.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, order.ErrStatusConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, promotions.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, promotions.ErrInvalidCoupon):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, promotions.ErrCodeTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, promotions.ErrInactive), errors.Is(err, promotions.ErrNotApplicable), errors.Is(err, promotions.ErrMinOrderValue),
+		errors.Is(err, promotions.ErrUsageLimit), errors.Is(err, promotions.ErrAccountUsageLimit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, order.ErrNotOrderOwner), errors.Is(err, order.ErrNotCouponOwner):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, order.ErrOrderCancelled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, idempotency.ErrInvalidKey), errors.Is(err, idempotency.ErrKeyReused):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, idempotency.ErrInProgress):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return err
 }
@@ -2,77 +2,794 @@ package internal
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/IBM/sarama"
+	"github.com/abhiii71/orderStream/order"
+	"github.com/abhiii71/orderStream/order/config"
 	"github.com/abhiii71/orderStream/order/models"
-	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/outbox"
+	"github.com/abhiii71/orderStream/pkg/pricing"
+	"github.com/abhiii71/orderStream/pkg/promotions"
+	"github.com/abhiii71/orderStream/pkg/tax"
 )
 
 type Service interface {
-	PostOrder(ctx context.Context, accountId uint64, totalPrice float64, products []*models.OrderedProduct) (*models.Order, error)
+	PostOrder(ctx context.Context, accountId uint64, products []*models.OrderedProduct, shipTo models.Address, shippingMethod, couponCode, stockReservationId string) (*models.Order, error)
 	GetOrdersForAccount(ctx context.Context, accountId uint64) ([]*models.Order, error)
+	GetOrder(ctx context.Context, orderId, accountId uint64) (*models.Order, error)
+	ListOrders(ctx context.Context, accountId uint64, filter models.OrderFilter, after string, first int) (*models.OrderPage, error)
+	UpdateOrderStatus(ctx context.Context, orderId uint64, status, actor, reason string) (*models.Order, error)
+	CancelOrder(ctx context.Context, orderId, accountId uint64, reason string) (*models.Order, error)
+	CancelUnpaidOrders(ctx context.Context) (int, error)
 	UpdateOrderPaymentStatus(ctx context.Context, orderId uint64, status string) error
-	GetProducer() sarama.AsyncProducer
+	HasPurchased(ctx context.Context, accountId uint64, productId string) (bool, error)
+	CreateCoupon(ctx context.Context, accountId uint64, coupon *promotions.Coupon) (*promotions.Coupon, error)
+	UpdateCoupon(ctx context.Context, accountId uint64, coupon *promotions.Coupon) (*promotions.Coupon, error)
+	ListCoupons(ctx context.Context, accountId uint64) ([]*promotions.Coupon, error)
+	ShippingMethods() []order.ShippingMethod
+	MarkShipped(ctx context.Context, orderId, sellerId uint64, carrier, trackingNumber string, productIds []string) (*models.Shipment, error)
+	UpdateShipmentStatus(ctx context.Context, shipmentId, accountId uint64, status, description string) (*models.Shipment, error)
 }
 
 type orderService struct {
 	repo     OrderRepository
-	producer sarama.AsyncProducer
+	tax      order.TaxCalculator
+	shipping *order.ShippingTable
 }
 
-func NewOrderService(repository OrderRepository, producer sarama.AsyncProducer) Service {
-	return &orderService{repository, producer}
+func NewOrderService(repository OrderRepository, tax order.TaxCalculator, shipping *order.ShippingTable) Service {
+	return &orderService{repository, tax, shipping}
 }
 
-func (s *orderService) PostOrder(ctx context.Context, accountId uint64, totalPrice float64, products []*models.OrderedProduct) (*models.Order, error) {
-	order := models.Order{
-		AccountID:  accountId,
-		TotalPrice: totalPrice,
-		Products:   products,
-		CreatedAt:  time.Now().UTC(),
+// PostOrder places an order waiting for payment. products hold the product
+// details at the time of ordering; the line totals and the order total are
+// computed from them, less the discount of the coupon if there is one, plus
+// tax where the order is shipped and the cost of shipping its physical
+// products by shippingMethod, the default method if it is empty. Orders
+// without an address are taxed where the store is. stockReservationId is the
+// reservation holding stock for the products.
+func (s *orderService) PostOrder(ctx context.Context, accountId uint64, products []*models.OrderedProduct, shipTo models.Address, shippingMethod, couponCode, stockReservationId string) (*models.Order, error) {
+	shipTo, err := normalizeAddress(shipTo)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]pricing.Line, len(products))
+	for i, p := range products {
+		lines[i] = pricing.Line{UnitPrice: p.Price, Quantity: p.Quantity}
+		p.LineTotal = lines[i].Total()
+	}
+	subtotal, err := pricing.Total(lines...)
+	if errors.Is(err, money.ErrCurrencyMismatch) {
+		return nil, order.ErrMixedCurrencies
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.repo.PutOrder(ctx, &order)
+	now := time.Now().UTC()
+	var discounts []models.Discount
+	discount := money.Zero(subtotal.Currency)
+	if couponCode != "" {
+		couponCode = promotions.NormalizeCode(couponCode)
+		if discounts, discount, err = s.applyCoupon(ctx, couponCode, products, now); err != nil {
+			return nil, err
+		}
+	}
+	taxTotal, err := s.applyTax(ctx, shipTo, products, discounts)
+	if err != nil {
+		return nil, err
+	}
+	shippingMethod, shipping, err := s.quoteShipping(shipTo, shippingMethod, products, discounts)
+	if err != nil {
+		return nil, err
+	}
+	totalPrice, err := subtotal.Sub(discount)
 	if err != nil {
 		return nil, err
 	}
+	if totalPrice, err = totalPrice.Add(taxTotal); err != nil {
+		return nil, err
+	}
+	if totalPrice, err = totalPrice.Add(shipping); err != nil {
+		return nil, err
+	}
 
-	// send to recommendation service
-	go func() {
+	order := models.Order{
+		AccountID:          accountId,
+		Subtotal:           subtotal,
+		TotalPrice:         totalPrice,
+		CouponCode:         couponCode,
+		Discount:           discount,
+		Discounts:          discounts,
+		Tax:                taxTotal,
+		ShipTo:             shipTo,
+		ShippingMethod:     shippingMethod,
+		Shipping:           shipping,
+		Products:           products,
+		CreatedAt:          now,
+		Status:             order.StatusPendingPayment,
+		StockReservationId: stockReservationId,
+		History: []models.StatusChange{{
+			To:        order.StatusPendingPayment,
+			Actor:     accountActor(accountId),
+			Reason:    "order placed",
+			CreatedAt: now,
+		}},
+	}
 
+	// purchases are sent to the recommendation service
+	var messages []outbox.Message
+	for _, product := range products {
+		payload, err := json.Marshal(models.Event{
+			Type: "purchase",
+			Data: models.EventData{
+				AccountId: int(accountId),
+				ProductId: product.ID,
+			},
+		})
 		if err != nil {
-			log.Println("failed to convert account ID to int:", err)
-			return
-		}
-
-		for _, product := range products {
-			err := kafka.SendMessageToRecommender(s, models.Event{
-				Type: "purchase",
-				Data: models.EventData{
-					AccountId: int(accountId),
-					ProductId: string(product.ID),
-					// ProductId: strconv.FormatUint(uint64(product.ID), 10),
-				},
-			}, "interaction_events")
-			if err != nil {
-				log.Println("failed to send event to recommendation service: ", err)
-			}
+			return nil, err
 		}
-	}()
+		messages = append(messages, outbox.Message{
+			Topic:   "interaction_events",
+			Key:     strconv.FormatUint(accountId, 10),
+			Payload: payload,
+		})
+	}
+
+	err = s.repo.PutOrder(ctx, &order, messages...)
+	if err != nil {
+		return nil, err
+	}
 
 	return &order, nil
 }
 
+// normalizeAddress trims an address and uppercases its codes. An address
+// without a country is in the store's country and region.
+func normalizeAddress(a models.Address) (models.Address, error) {
+	a.Name, a.Line1, a.Line2 = strings.TrimSpace(a.Name), strings.TrimSpace(a.Line1), strings.TrimSpace(a.Line2)
+	a.City, a.PostalCode = strings.TrimSpace(a.City), strings.ToUpper(strings.TrimSpace(a.PostalCode))
+	a.Country, a.Region = strings.ToUpper(strings.TrimSpace(a.Country)), strings.ToUpper(strings.TrimSpace(a.Region))
+	if a.Country == "" {
+		a.Country, a.Region = config.TaxOriginCountry, config.TaxOriginRegion
+		return a, nil
+	}
+	if len(a.Country) != 2 || a.Country[0] < 'A' || a.Country[0] > 'Z' || a.Country[1] < 'A' || a.Country[1] > 'Z' {
+		return models.Address{}, order.ErrInvalidAddress
+	}
+	return a, nil
+}
+
+// applyTax sets the tax on each line of an order, charged on the line total
+// less its discounts, and returns their total.
+func (s *orderService) applyTax(ctx context.Context, shipTo models.Address, products []*models.OrderedProduct, discounts []models.Discount) (money.Money, error) {
+	currency := products[0].LineTotal.Currency
+	lines := make([]order.TaxLine, len(products))
+	for i, p := range products {
+		p.TaxCategory = tax.CategoryOf(p.TaxCategory)
+		lines[i] = order.TaxLine{Category: p.TaxCategory, Amount: p.LineTotal}
+		for _, d := range discounts {
+			if d.ProductId == p.ID {
+				lines[i].Amount.Amount -= d.Amount.Amount
+			}
+		}
+	}
+
+	taxes, err := s.tax.Calculate(ctx, shipTo.Country, shipTo.Region, lines)
+	if err != nil {
+		return money.Money{}, err
+	}
+	amounts := make([]money.Money, len(products))
+	for i, p := range products {
+		p.TaxRate, p.Tax = taxes[i].Rate, taxes[i].Amount
+		amounts[i] = p.Tax
+	}
+	return money.Sum(currency, amounts...)
+}
+
+// quoteShipping returns the shipping method delivering the physical products
+// of an order and what it charges for them, net of their discounts. Orders
+// without physical products are not shipped.
+func (s *orderService) quoteShipping(shipTo models.Address, code string, products []*models.OrderedProduct, discounts []models.Discount) (string, money.Money, error) {
+	goods := money.Zero(products[0].LineTotal.Currency)
+	var weightGrams int64
+	physical := false
+	for _, p := range products {
+		if !tax.IsPhysical(p.TaxCategory) {
+			continue
+		}
+		physical = true
+		goods.Amount += p.LineTotal.Amount
+		weightGrams += p.WeightGrams * int64(p.Quantity)
+		for _, d := range discounts {
+			if d.ProductId == p.ID {
+				goods.Amount -= d.Amount.Amount
+			}
+		}
+	}
+	if !physical {
+		return "", goods, nil
+	}
+
+	method, ok := s.shipping.Method(strings.ToLower(strings.TrimSpace(code)))
+	if !ok {
+		return "", money.Money{}, order.ErrUnknownShippingMethod
+	}
+	cost, err := method.Quote(shipTo.Country, goods, weightGrams)
+	if err != nil {
+		return "", money.Money{}, err
+	}
+	return method.Code, cost, nil
+}
+
+// applyCoupon returns the discounts of a coupon on the lines of an order and
+// their total.
+func (s *orderService) applyCoupon(ctx context.Context, code string, products []*models.OrderedProduct, now time.Time) ([]models.Discount, money.Money, error) {
+	coupon, err := s.repo.GetCouponByCode(ctx, code)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+
+	lines := make([]promotions.Line, len(products))
+	for i, p := range products {
+		lines[i] = promotions.Line{ProductId: p.ID, SellerId: p.SellerId, Category: p.Category, Total: p.LineTotal}
+	}
+	applied, err := promotions.Apply(coupon, lines, now)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+	total, err := promotions.Total(products[0].LineTotal.Currency, applied...)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+
+	discounts := make([]models.Discount, len(applied))
+	for i, d := range applied {
+		discounts[i] = models.Discount{CouponCode: code, ProductId: d.ProductId, Amount: d.Amount}
+	}
+	return discounts, total, nil
+}
+
 func (s *orderService) GetOrdersForAccount(ctx context.Context, accountId uint64) ([]*models.Order, error) {
-	return s.repo.GetOrdersForAccount(ctx, accountId)
+	orders, err := s.repo.GetOrdersForAccount(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	return orders, s.attachDetails(ctx, orders)
+}
+
+// GetOrder returns an order of the account with its products and history.
+func (s *orderService) GetOrder(ctx context.Context, orderId, accountId uint64) (*models.Order, error) {
+	o, err := s.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if o.AccountID != accountId {
+		return nil, order.ErrNotOrderOwner
+	}
+
+	products, err := s.repo.GetOrderProducts(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	o.Products = products[orderId]
+	if o.Products == nil {
+		o.Products = []*models.OrderedProduct{}
+	}
+	return o, s.attachDetails(ctx, []*models.Order{o})
+}
+
+// ListOrders returns a page of the orders of an account that match filter,
+// newest first. after is the end cursor of the previous page, and first the
+// page size, which defaults to order.DefaultPageSize.
+func (s *orderService) ListOrders(ctx context.Context, accountId uint64, filter models.OrderFilter, after string, first int) (*models.OrderPage, error) {
+	for _, status := range filter.Statuses {
+		if !order.IsValidStatus(status) {
+			return nil, order.ErrInvalidStatus
+		}
+	}
+
+	var cursor *order.Cursor
+	if after != "" {
+		c, err := order.DecodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
+	if first <= 0 {
+		first = order.DefaultPageSize
+	}
+	first = min(first, order.MaxPageSize)
+
+	// one more order than asked for tells whether there is a next page
+	orders, err := s.repo.ListOrders(ctx, accountId, filter, cursor, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.OrderPage{Orders: orders}
+	if len(orders) > first {
+		page.Orders, page.HasNextPage = orders[:first], true
+	}
+	if len(page.Orders) > 0 {
+		last := page.Orders[len(page.Orders)-1]
+		page.EndCursor = order.Cursor{CreatedAt: last.CreatedAt, ID: uint64(last.ID)}.Encode()
+	}
+	return page, s.attachDetails(ctx, page.Orders)
+}
+
+// attachDetails loads the status history, discounts and shipments of orders.
+func (s *orderService) attachDetails(ctx context.Context, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(orders))
+	for i, o := range orders {
+		ids[i] = uint64(o.ID)
+	}
+	history, err := s.repo.GetStatusHistory(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	discounts, err := s.repo.GetOrderDiscounts(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	shipments, err := s.repo.GetShipments(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders {
+		o.History = history[uint64(o.ID)]
+		o.Discounts = discounts[uint64(o.ID)]
+		o.Shipments = shipments[uint64(o.ID)]
+	}
+	return nil
+}
+
+// UpdateOrderStatus moves an order to status if its lifecycle allows it,
+// records who changed it and why, and publishes order_status_changed.
+func (s *orderService) UpdateOrderStatus(ctx context.Context, orderId uint64, status, actor, reason string) (*models.Order, error) {
+	if !order.IsValidStatus(status) {
+		return nil, order.ErrInvalidStatus
+	}
+
+	o, err := s.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if !order.CanTransition(o.Status, status) {
+		return nil, order.ErrInvalidTransition
+	}
+
+	change := models.StatusChange{
+		From:      o.Status,
+		To:        status,
+		Actor:     actor,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	}
+	payload, err := json.Marshal(models.OrderStatusChangedEvent{
+		Type: models.OrderStatusChanged,
+		Data: models.OrderStatusChangedData{
+			OrderId:   orderId,
+			AccountId: o.AccountID,
+			From:      change.From,
+			To:        change.To,
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+			ChangedAt: change.CreatedAt,
+
+			StockReservationId: o.StockReservationId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.UpdateOrderStatus(ctx, orderId, change, outbox.Message{
+		Topic:   config.OrderEventsTopic,
+		Key:     strconv.FormatUint(orderId, 10),
+		Payload: payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o.Status = status
+	history, err := s.repo.GetStatusHistory(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	o.History = history[orderId]
+	return o, nil
 }
 
+// CancelOrder cancels an order of the account before it ships. Reserved
+// stock is released and paid orders are refunded by the consumers of the
+// order_status_changed event.
+func (s *orderService) CancelOrder(ctx context.Context, orderId, accountId uint64, reason string) (*models.Order, error) {
+	o, err := s.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if o.AccountID != accountId {
+		return nil, order.ErrNotOrderOwner
+	}
+
+	if reason == "" {
+		reason = "cancelled by customer"
+	}
+	return s.UpdateOrderStatus(ctx, orderId, order.StatusCancelled, accountActor(accountId), reason)
+}
+
+// CancelUnpaidOrders cancels the orders that have been waiting for payment
+// longer than config.PaymentTimeout, and returns how many it cancelled.
+func (s *orderService) CancelUnpaidOrders(ctx context.Context) (int, error) {
+	ids, err := s.repo.GetUnpaidOrderIds(ctx, time.Now().UTC().Add(-config.PaymentTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, id := range ids {
+		_, err := s.UpdateOrderStatus(ctx, id, order.StatusCancelled, systemActor, "payment timeout")
+		if errors.Is(err, order.ErrStatusConflict) || errors.Is(err, order.ErrInvalidTransition) {
+			// paid or cancelled in the meantime
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// UpdateOrderPaymentStatus records the payment status reported by the
+// payment service. A successful payment moves a pending order to paid and a
+// full refund moves it to refunded; a payment for a cancelled order fails
+// with order.ErrOrderCancelled so the payment service refunds it.
 func (s *orderService) UpdateOrderPaymentStatus(ctx context.Context, orderId uint64, status string) error {
-	return s.repo.UpdateOrderPaymentStatus(ctx, orderId, status)
+	if err := s.repo.UpdateOrderPaymentStatus(ctx, orderId, status); err != nil {
+		return err
+	}
+
+	switch status {
+	case order.PaymentStatusPaid:
+		_, err := s.UpdateOrderStatus(ctx, orderId, order.StatusPaid, paymentActor, "payment succeeded")
+		if errors.Is(err, order.ErrInvalidTransition) {
+			o, err := s.repo.GetOrder(ctx, orderId)
+			if err != nil {
+				return err
+			}
+			if o.Status == order.StatusCancelled {
+				return order.ErrOrderCancelled
+			}
+			// the webhook was delivered again, or the order moved on already
+			return nil
+		}
+		return err
+	case order.PaymentStatusRefunded:
+		_, err := s.UpdateOrderStatus(ctx, orderId, order.StatusRefunded, paymentActor, "payment refunded")
+		if errors.Is(err, order.ErrInvalidTransition) {
+			// cancelled orders stay cancelled once their refund goes through
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+const (
+	// paymentActor is recorded for status changes made by the payment service.
+	paymentActor = "payment"
+	// systemActor is recorded for status changes made by the order service
+	// itself.
+	systemActor = "system"
+)
+
+func accountActor(accountId uint64) string {
+	return "account:" + strconv.FormatUint(accountId, 10)
+}
+
+// HasPurchased reports whether the account has a paid order containing the
+// product.
+func (s *orderService) HasPurchased(ctx context.Context, accountId uint64, productId string) (bool, error) {
+	return s.repo.HasPaidOrderWithProduct(ctx, accountId, productId)
+}
+
+func isAdmin(accountId uint64) bool {
+	return slices.Contains(config.AdminAccountIds, accountId)
+}
+
+// CreateCoupon creates an active coupon issued by an account. Coupons of
+// admins discount any product; coupons of sellers only their own products.
+func (s *orderService) CreateCoupon(ctx context.Context, accountId uint64, coupon *promotions.Coupon) (*promotions.Coupon, error) {
+	coupon.Code = promotions.NormalizeCode(coupon.Code)
+	for i, category := range coupon.Categories {
+		// categories are stored on products as lowercase slugs
+		coupon.Categories[i] = strings.ToLower(strings.TrimSpace(category))
+	}
+	coupon.SellerId = accountId
+	if isAdmin(accountId) {
+		coupon.SellerId = 0
+	}
+	coupon.Active, coupon.Redemptions = true, 0
+	coupon.CreatedAt = time.Now().UTC()
+	if err := coupon.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PutCoupon(ctx, coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// UpdateCoupon changes the description, minimum order value, usage limits,
+// validity window and active flag of a coupon. The code and discount stay as
+// they are, since orders may have been placed with them.
+func (s *orderService) UpdateCoupon(ctx context.Context, accountId uint64, coupon *promotions.Coupon) (*promotions.Coupon, error) {
+	current, err := s.repo.GetCoupon(ctx, coupon.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(accountId) && current.SellerId != accountId {
+		return nil, order.ErrNotCouponOwner
+	}
+
+	current.Description = coupon.Description
+	current.MinOrderValue = coupon.MinOrderValue
+	current.MaxRedemptions = coupon.MaxRedemptions
+	current.MaxRedemptionsPerAccount = coupon.MaxRedemptionsPerAccount
+	current.StartsAt, current.EndsAt = coupon.StartsAt, coupon.EndsAt
+	current.Active = coupon.Active
+	if err := current.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateCoupon(ctx, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// ListCoupons returns every coupon to admins and their own coupons to
+// sellers.
+func (s *orderService) ListCoupons(ctx context.Context, accountId uint64) ([]*promotions.Coupon, error) {
+	if isAdmin(accountId) {
+		return s.repo.ListCoupons(ctx, nil)
+	}
+	return s.repo.ListCoupons(ctx, &accountId)
+}
+
+// ShippingMethods returns the shipping methods orders may choose from, the
+// default first.
+func (s *orderService) ShippingMethods() []order.ShippingMethod {
+	return s.shipping.Methods()
 }
 
-func (s *orderService) GetProducer() sarama.AsyncProducer {
-	return s.producer
+// MarkShipped records a parcel a seller sent with some of their physical
+// products of a paid order, or all of those not shipped yet if productIds is
+// empty. The order moves to processing with its first shipment and to
+// shipped once all its physical products are shipped.
+func (s *orderService) MarkShipped(ctx context.Context, orderId, sellerId uint64, carrier, trackingNumber string, productIds []string) (*models.Shipment, error) {
+	carrier, trackingNumber = strings.TrimSpace(carrier), strings.TrimSpace(trackingNumber)
+	if carrier == "" || trackingNumber == "" {
+		return nil, order.ErrInvalidShipment
+	}
+
+	o, err := s.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != order.StatusPaid && o.Status != order.StatusProcessing {
+		return nil, order.ErrNotShippable
+	}
+	products, err := s.repo.GetOrderProducts(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	shipments, err := s.repo.GetShipments(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipped := shippedProducts(shipments[orderId])
+	var unshipped []string
+	sells := false
+	for _, p := range products[orderId] {
+		if p.SellerId != sellerId {
+			continue
+		}
+		sells = true
+		if tax.IsPhysical(p.TaxCategory) && !shipped[p.ID] {
+			unshipped = append(unshipped, p.ID)
+		}
+	}
+	if !sells {
+		return nil, order.ErrNotOrderSeller
+	}
+	if len(productIds) == 0 {
+		productIds = unshipped
+	}
+	productIds = slices.Compact(slices.Sorted(slices.Values(productIds)))
+	if len(productIds) == 0 {
+		return nil, order.ErrNothingToShip
+	}
+	for _, id := range productIds {
+		if !slices.Contains(unshipped, id) {
+			return nil, order.ErrNothingToShip
+		}
+	}
+
+	now := time.Now().UTC()
+	event := models.ShipmentEvent{Status: order.ShipmentShipped, Description: "handed to " + carrier, CreatedAt: now}
+	shipment := &models.Shipment{
+		OrderId:        orderId,
+		SellerId:       sellerId,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		ProductIds:     productIds,
+		Status:         order.ShipmentShipped,
+		Events:         []models.ShipmentEvent{event},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err = s.repo.PutShipment(ctx, shipment, func(shipment *models.Shipment) (outbox.Message, error) {
+		return shipmentMessage(o, shipment, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.advanceOrder(ctx, o, accountActor(sellerId)); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// UpdateShipmentStatus records a tracking update of a shipment by the seller
+// who sent it. Shipments can be updated until they are delivered or
+// returned; the order is delivered once all of its shipments are.
+func (s *orderService) UpdateShipmentStatus(ctx context.Context, shipmentId, accountId uint64, status, description string) (*models.Shipment, error) {
+	if !order.IsValidShipmentStatus(status) {
+		return nil, order.ErrInvalidShipmentStatus
+	}
+
+	shipment, err := s.repo.GetShipment(ctx, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.SellerId != accountId && !isAdmin(accountId) {
+		return nil, order.ErrNotShipmentOwner
+	}
+	if order.IsFinalShipmentStatus(shipment.Status) {
+		return nil, order.ErrShipmentClosed
+	}
+	o, err := s.repo.GetOrder(ctx, shipment.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	event := models.ShipmentEvent{Status: status, Description: strings.TrimSpace(description), CreatedAt: time.Now().UTC()}
+	message, err := shipmentMessage(o, shipment, event)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateShipmentStatus(ctx, shipmentId, shipment.Status, event, message); err != nil {
+		return nil, err
+	}
+	shipment.Status, shipment.UpdatedAt = status, event.CreatedAt
+
+	if status == order.ShipmentDelivered {
+		if err := s.advanceOrder(ctx, o, accountActor(accountId)); err != nil {
+			return nil, err
+		}
+	}
+
+	shipments, err := s.repo.GetShipments(ctx, shipment.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	for _, sh := range shipments[shipment.OrderId] {
+		if sh.ID == shipmentId {
+			return &sh, nil
+		}
+	}
+	return shipment, nil
+}
+
+// shipmentMessage returns the shipment_status_changed event of a shipment of
+// an order moving to event.Status.
+func shipmentMessage(o *models.Order, shipment *models.Shipment, event models.ShipmentEvent) (outbox.Message, error) {
+	payload, err := json.Marshal(models.ShipmentStatusChangedEvent{
+		Type: models.ShipmentStatusChanged,
+		Data: models.ShipmentStatusChangedData{
+			ShipmentId:     shipment.ID,
+			OrderId:        shipment.OrderId,
+			AccountId:      o.AccountID,
+			SellerId:       shipment.SellerId,
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			Status:         event.Status,
+			Description:    event.Description,
+			ChangedAt:      event.CreatedAt,
+		},
+	})
+	if err != nil {
+		return outbox.Message{}, err
+	}
+	return outbox.Message{
+		Topic:   config.OrderEventsTopic,
+		Key:     strconv.FormatUint(shipment.OrderId, 10),
+		Payload: payload,
+	}, nil
+}
+
+func shippedProducts(shipments []models.Shipment) map[string]bool {
+	shipped := make(map[string]bool)
+	for _, sh := range shipments {
+		for _, id := range sh.ProductIds {
+			shipped[id] = true
+		}
+	}
+	return shipped
+}
+
+// advanceOrder moves an order along with its shipments, as far as
+// order.FulfilmentStatuses allows.
+func (s *orderService) advanceOrder(ctx context.Context, o *models.Order, actor string) error {
+	id := uint64(o.ID)
+	products, err := s.repo.GetOrderProducts(ctx, id)
+	if err != nil {
+		return err
+	}
+	shipments, err := s.repo.GetShipments(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	shipped := shippedProducts(shipments[id])
+	allShipped := true
+	for _, p := range products[id] {
+		if tax.IsPhysical(p.TaxCategory) && !shipped[p.ID] {
+			allShipped = false
+		}
+	}
+	allDelivered := true
+	for _, sh := range shipments[id] {
+		if sh.Status != order.ShipmentDelivered {
+			allDelivered = false
+		}
+	}
+
+	reasons := map[string]string{
+		order.StatusProcessing: "first shipment sent",
+		order.StatusShipped:    "all products shipped",
+		order.StatusDelivered:  "all shipments delivered",
+	}
+	for _, status := range order.FulfilmentStatuses(o.Status, allShipped, allDelivered) {
+		_, err := s.UpdateOrderStatus(ctx, id, status, actor, reasons[status])
+		if errors.Is(err, order.ErrStatusConflict) || errors.Is(err, order.ErrInvalidTransition) {
+			// moved on concurrently, for example refunded
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type EventData struct {
 	AccountId int    `json:"user_id"`
 	ProductId string `json:"product_id"`
@@ -9,3 +11,46 @@ type Event struct {
 	Type string    `json:"type"`
 	Data EventData `json:"data"`
 }
+
+// OrderStatusChanged is the type of OrderStatusChangedEvent.
+const OrderStatusChanged = "order_status_changed"
+
+// OrderStatusChangedEvent is published on every order status change.
+type OrderStatusChangedEvent struct {
+	Type string                 `json:"type"`
+	Data OrderStatusChangedData `json:"data"`
+}
+
+type OrderStatusChangedData struct {
+	OrderId   uint64    `json:"order_id"`
+	AccountId uint64    `json:"account_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	ChangedAt time.Time `json:"changed_at"`
+	// StockReservationId is the reservation holding stock for the order.
+	StockReservationId string `json:"stock_reservation_id,omitempty"`
+}
+
+// ShipmentStatusChanged is the type of ShipmentStatusChangedEvent.
+const ShipmentStatusChanged = "shipment_status_changed"
+
+// ShipmentStatusChangedEvent is published when a shipment is sent and on
+// every tracking update.
+type ShipmentStatusChangedEvent struct {
+	Type string                    `json:"type"`
+	Data ShipmentStatusChangedData `json:"data"`
+}
+
+type ShipmentStatusChangedData struct {
+	ShipmentId     uint64    `json:"shipment_id"`
+	OrderId        uint64    `json:"order_id"`
+	AccountId      uint64    `json:"account_id"`
+	SellerId       uint64    `json:"seller_id"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	Status         string    `json:"status"`
+	Description    string    `json:"description"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
@@ -1,22 +1,143 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+)
 
 type Order struct {
-	ID            uint
-	CreatedAt     time.Time
-	TotalPrice    float64
+	ID        uint
+	CreatedAt time.Time
+	// Subtotal adds up the line totals. TotalPrice is what the customer
+	// pays: the subtotal less the discount, plus tax.
+	Subtotal      money.Money
+	TotalPrice    money.Money
 	AccountID     uint64
 	Status        string
 	PaymentStatus string
 	ProductInfos  []ProductInfo
 	Products      []*OrderedProduct
+	// History lists the status changes of the order, oldest first.
+	History []StatusChange
+	// StockReservationId is the product service reservation holding stock
+	// for the order.
+	StockReservationId string
+	// CouponCode is the coupon the order was placed with, if any, and
+	// Discounts what it took off each line.
+	CouponCode string
+	Discount   money.Money
+	Discounts  []Discount
+	// Tax adds up the tax on the lines.
+	Tax money.Money
+	// ShipTo is where the order is delivered, which decides its tax.
+	ShipTo Address
+	// ShippingMethod is the code of the order.ShippingMethod delivering the
+	// order, and Shipping what it costs. Orders without physical products
+	// have neither.
+	ShippingMethod string
+	Shipping       money.Money
+	// Shipments are the parcels the sellers sent, oldest first.
+	Shipments []Shipment
+}
+
+// Address is where an order is delivered.
+type Address struct {
+	Name  string
+	Line1 string
+	Line2 string
+	City  string
+	// PostalCode is empty in countries without postal codes.
+	PostalCode string
+	// Country is an ISO 3166-1 alpha-2 code such as "DE".
+	Country string
+	// Region is a subdivision code such as "CA", empty where tax does not
+	// depend on it.
+	Region string
+}
+
+// Shipment is a parcel a seller sent with some of the lines of an order.
+type Shipment struct {
+	ID             uint64
+	OrderId        uint64
+	SellerId       uint64
+	Carrier        string
+	TrackingNumber string
+	ProductIds     []string
+	// Status is one of the order.Shipment statuses.
+	Status string
+	// Events is the tracking history, oldest first.
+	Events    []ShipmentEvent
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ShipmentEvent is an entry in the tracking history of a shipment.
+type ShipmentEvent struct {
+	Status      string
+	Description string
+	CreatedAt   time.Time
+}
+
+// Discount is the part of a coupon taken off one line of an order.
+type Discount struct {
+	CouponCode string
+	ProductId  string
+	Amount     money.Money
+}
+
+// StatusChange is an entry in the status history of an order. From is empty
+// for the status the order was created with.
+type StatusChange struct {
+	From      string
+	To        string
+	Actor     string
+	Reason    string
+	CreatedAt time.Time
 }
 
+// OrderedProduct is a line of an order. Name, description, SKU and price are
+// a snapshot of the product when the order was placed.
 type OrderedProduct struct {
 	ID          string
 	Name        string
 	Description string
-	Price       float64
-	Quantity    uint32
+	Sku         string
+	// Price is the unit price.
+	Price     money.Money
+	Quantity  uint32
+	LineTotal money.Money
+	// SellerId and Category decide which coupons discount the line.
+	SellerId uint64
+	Category string
+	// TaxCategory is the pkg/tax category of the product. Tax is charged at
+	// TaxRate basis points on the line total less its discount.
+	TaxCategory string
+	TaxRate     int64
+	Tax         money.Money
+	// WeightGrams is the weight of one unit, which weight based shipping
+	// rates charge by.
+	WeightGrams int64
+}
+
+// Snapshotted reports whether the line was stored with its product details.
+// Lines of orders placed before snapshots were introduced were not.
+func (p *OrderedProduct) Snapshotted() bool {
+	return p.Price.Currency != ""
+}
+
+// OrderFilter narrows down a list of orders. Zero fields match every order.
+type OrderFilter struct {
+	Statuses      []string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// MinTotal matches orders in its currency that cost at least as much.
+	MinTotal *money.Money
+}
+
+// OrderPage is a page of orders, newest first.
+type OrderPage struct {
+	Orders      []*Order
+	EndCursor   string
+	HasNextPage bool
 }
@@ -7,9 +7,11 @@
 package pb
 
 import (
+	pb "github.com/abhiii71/orderStream/pkg/money/proto/pb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
@@ -24,31 +26,1609 @@ const (
 )
 
 type ProductInfo struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity    uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// unit price
+	Price       *pb.Money `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	Sku         string    `protobuf:"bytes,8,opt,name=sku,proto3" json:"sku,omitempty"`
+	LineTotal   *pb.Money `protobuf:"bytes,9,opt,name=lineTotal,proto3" json:"lineTotal,omitempty"`
+	TaxCategory string    `protobuf:"bytes,10,opt,name=taxCategory,proto3" json:"taxCategory,omitempty"`
+	// basis points
+	TaxRate int64     `protobuf:"varint,11,opt,name=taxRate,proto3" json:"taxRate,omitempty"`
+	Tax     *pb.Money `protobuf:"bytes,12,opt,name=tax,proto3" json:"tax,omitempty"`
+	// weight of one unit
+	WeightGrams   int64 `protobuf:"varint,13,opt,name=weightGrams,proto3" json:"weightGrams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductInfo) Reset() {
+	*x = ProductInfo{}
+	mi := &file_order_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductInfo) ProtoMessage() {}
+
+func (x *ProductInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductInfo.ProtoReflect.Descriptor instead.
+func (*ProductInfo) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProductInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductInfo) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ProductInfo) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductInfo) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductInfo) GetLineTotal() *pb.Money {
+	if x != nil {
+		return x.LineTotal
+	}
+	return nil
+}
+
+func (x *ProductInfo) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+func (x *ProductInfo) GetTaxRate() int64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *ProductInfo) GetTax() *pb.Money {
+	if x != nil {
+		return x.Tax
+	}
+	return nil
+}
+
+func (x *ProductInfo) GetWeightGrams() int64 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
+}
+
+type Address struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO 3166-1 alpha-2
+	Country       string `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string `protobuf:"bytes,4,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string `protobuf:"bytes,5,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode    string `protobuf:"bytes,7,opt,name=postalCode,proto3" json:"postalCode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+type ShipmentEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipmentEvent) Reset() {
+	*x = ShipmentEvent{}
+	mi := &file_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentEvent) ProtoMessage() {}
+
+func (x *ShipmentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentEvent.ProtoReflect.Descriptor instead.
+func (*ShipmentEvent) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ShipmentEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Shipment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId        uint64                 `protobuf:"varint,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	SellerId       uint64                 `protobuf:"varint,3,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Carrier        string                 `protobuf:"bytes,4,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,5,opt,name=trackingNumber,proto3" json:"trackingNumber,omitempty"`
+	ProductIds     []string               `protobuf:"bytes,6,rep,name=productIds,proto3" json:"productIds,omitempty"`
+	// shipped, in_transit, out_for_delivery, delivered or returned
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	// oldest first
+	Events        []*ShipmentEvent       `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Shipment) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Shipment) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Shipment) GetSellerId() uint64 {
+	if x != nil {
+		return x.SellerId
+	}
+	return 0
+}
+
+func (x *Shipment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Shipment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Shipment) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+func (x *Shipment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Shipment) GetEvents() []*ShipmentEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Shipment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Shipment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Discount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CouponCode    string                 `protobuf:"bytes,1,opt,name=couponCode,proto3" json:"couponCode,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	Amount        *pb.Money              `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discount) Reset() {
+	*x = Discount{}
+	mi := &file_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discount) ProtoMessage() {}
+
+func (x *Discount) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discount.ProtoReflect.Descriptor instead.
+func (*Discount) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Discount) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *Discount) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Discount) GetAmount() *pb.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type Order struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt  []byte                 `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	AccountId  uint64                 `protobuf:"varint,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Products   []*ProductInfo         `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
+	TotalPrice *pb.Money              `protobuf:"bytes,7,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
+	Status     string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	History    []*StatusChange        `protobuf:"bytes,9,rep,name=history,proto3" json:"history,omitempty"`
+	CouponCode string                 `protobuf:"bytes,10,opt,name=couponCode,proto3" json:"couponCode,omitempty"`
+	Discount   *pb.Money              `protobuf:"bytes,11,opt,name=discount,proto3" json:"discount,omitempty"`
+	Discounts  []*Discount            `protobuf:"bytes,12,rep,name=discounts,proto3" json:"discounts,omitempty"`
+	// total before discounts and tax
+	Subtotal *pb.Money `protobuf:"bytes,13,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Tax      *pb.Money `protobuf:"bytes,14,opt,name=tax,proto3" json:"tax,omitempty"`
+	ShipTo   *Address  `protobuf:"bytes,15,opt,name=shipTo,proto3" json:"shipTo,omitempty"`
+	// empty for orders that are not shipped
+	ShippingMethod string      `protobuf:"bytes,16,opt,name=shippingMethod,proto3" json:"shippingMethod,omitempty"`
+	Shipping       *pb.Money   `protobuf:"bytes,17,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	Shipments      []*Shipment `protobuf:"bytes,18,rep,name=shipments,proto3" json:"shipments,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Order) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Order) GetProducts() []*ProductInfo {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *Order) GetTotalPrice() *pb.Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetHistory() []*StatusChange {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *Order) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *Order) GetDiscount() *pb.Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *Order) GetDiscounts() []*Discount {
+	if x != nil {
+		return x.Discounts
+	}
+	return nil
+}
+
+func (x *Order) GetSubtotal() *pb.Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *Order) GetTax() *pb.Money {
+	if x != nil {
+		return x.Tax
+	}
+	return nil
+}
+
+func (x *Order) GetShipTo() *Address {
+	if x != nil {
+		return x.ShipTo
+	}
+	return nil
+}
+
+func (x *Order) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+func (x *Order) GetShipping() *pb.Money {
+	if x != nil {
+		return x.Shipping
+	}
+	return nil
+}
+
+func (x *Order) GetShipments() []*Shipment {
+	if x != nil {
+		return x.Shipments
+	}
+	return nil
+}
+
+type StatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusChange) Reset() {
+	*x = StatusChange{}
+	mi := &file_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusChange) ProtoMessage() {}
+
+func (x *StatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusChange.ProtoReflect.Descriptor instead.
+func (*StatusChange) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatusChange) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *StatusChange) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *StatusChange) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *StatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StatusChange) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type OrderProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderProduct) Reset() {
+	*x = OrderProduct{}
+	mi := &file_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderProduct) ProtoMessage() {}
+
+func (x *OrderProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderProduct.ProtoReflect.Descriptor instead.
+func (*OrderProduct) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderProduct) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderProduct) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type PostOrderRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	AccountId  uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Products   []*OrderProduct        `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	CouponCode string                 `protobuf:"bytes,3,opt,name=couponCode,proto3" json:"couponCode,omitempty"`
+	// unset for orders taxed where the store is
+	ShipTo *Address `protobuf:"bytes,4,opt,name=shipTo,proto3" json:"shipTo,omitempty"`
+	// empty for the default method
+	ShippingMethod string `protobuf:"bytes,5,opt,name=shippingMethod,proto3" json:"shippingMethod,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PostOrderRequest) Reset() {
+	*x = PostOrderRequest{}
+	mi := &file_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostOrderRequest) ProtoMessage() {}
+
+func (x *PostOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostOrderRequest.ProtoReflect.Descriptor instead.
+func (*PostOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PostOrderRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *PostOrderRequest) GetProducts() []*OrderProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *PostOrderRequest) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *PostOrderRequest) GetShipTo() *Address {
+	if x != nil {
+		return x.ShipTo
+	}
+	return nil
+}
+
+func (x *PostOrderRequest) GetShippingMethod() string {
+	if x != nil {
+		return x.ShippingMethod
+	}
+	return ""
+}
+
+type PostOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostOrderResponse) Reset() {
+	*x = PostOrderResponse{}
+	mi := &file_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostOrderResponse) ProtoMessage() {}
+
+func (x *PostOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostOrderResponse.ProtoReflect.Descriptor instead.
+func (*PostOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PostOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetOrdersForAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrdersForAccountResponse) Reset() {
+	*x = GetOrdersForAccountResponse{}
+	mi := &file_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrdersForAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrdersForAccountResponse) ProtoMessage() {}
+
+func (x *GetOrdersForAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrdersForAccountResponse.ProtoReflect.Descriptor instead.
+func (*GetOrdersForAccountResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrdersForAccountResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateOrderStatusRequest) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UpdatePaymentStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePaymentStatusRequest) Reset() {
+	*x = UpdatePaymentStatusRequest{}
+	mi := &file_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePaymentStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePaymentStatusRequest) ProtoMessage() {}
+
+func (x *UpdatePaymentStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePaymentStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePaymentStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdatePaymentStatusRequest) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *UpdatePaymentStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	AccountId     uint64                 `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelOrderRequest) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CancelOrderRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *CancelOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	AccountId     uint64                 `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetOrderRequest) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *GetOrderRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type OrderFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=createdAfter,proto3" json:"createdAfter,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=createdBefore,proto3" json:"createdBefore,omitempty"`
+	MinTotal      *pb.Money              `protobuf:"bytes,4,opt,name=minTotal,proto3" json:"minTotal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderFilter) Reset() {
+	*x = OrderFilter{}
+	mi := &file_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFilter) ProtoMessage() {}
+
+func (x *OrderFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFilter.ProtoReflect.Descriptor instead.
+func (*OrderFilter) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *OrderFilter) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *OrderFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *OrderFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *OrderFilter) GetMinTotal() *pb.Money {
+	if x != nil {
+		return x.MinTotal
+	}
+	return nil
+}
+
+type ListOrdersRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Filter    *OrderFilter           `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// end cursor of the previous page
+	After         string `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	First         uint32 `protobuf:"varint,4,opt,name=first,proto3" json:"first,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListOrdersRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetFilter() *OrderFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetFirst() uint32 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+type ListOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Quantity      uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	EndCursor     string                 `protobuf:"bytes,2,opt,name=endCursor,proto3" json:"endCursor,omitempty"`
+	HasNextPage   bool                   `protobuf:"varint,3,opt,name=hasNextPage,proto3" json:"hasNextPage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetEndCursor() string {
+	if x != nil {
+		return x.EndCursor
+	}
+	return ""
+}
+
+func (x *ListOrdersResponse) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+type HasPurchasedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasPurchasedRequest) Reset() {
+	*x = HasPurchasedRequest{}
+	mi := &file_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasPurchasedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasPurchasedRequest) ProtoMessage() {}
+
+func (x *HasPurchasedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasPurchasedRequest.ProtoReflect.Descriptor instead.
+func (*HasPurchasedRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HasPurchasedRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *HasPurchasedRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type Coupon struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code        string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// 0 for coupons issued by admins
+	SellerId uint64 `protobuf:"varint,4,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Type     string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	// basis points
+	PercentOff               int64                  `protobuf:"varint,6,opt,name=percentOff,proto3" json:"percentOff,omitempty"`
+	AmountOff                *pb.Money              `protobuf:"bytes,7,opt,name=amountOff,proto3" json:"amountOff,omitempty"`
+	Scope                    string                 `protobuf:"bytes,8,opt,name=scope,proto3" json:"scope,omitempty"`
+	ProductIds               []string               `protobuf:"bytes,9,rep,name=productIds,proto3" json:"productIds,omitempty"`
+	Categories               []string               `protobuf:"bytes,10,rep,name=categories,proto3" json:"categories,omitempty"`
+	MinOrderValue            *pb.Money              `protobuf:"bytes,11,opt,name=minOrderValue,proto3" json:"minOrderValue,omitempty"`
+	MaxRedemptions           uint32                 `protobuf:"varint,12,opt,name=maxRedemptions,proto3" json:"maxRedemptions,omitempty"`
+	MaxRedemptionsPerAccount uint32                 `protobuf:"varint,13,opt,name=maxRedemptionsPerAccount,proto3" json:"maxRedemptionsPerAccount,omitempty"`
+	Redemptions              uint32                 `protobuf:"varint,14,opt,name=redemptions,proto3" json:"redemptions,omitempty"`
+	StartsAt                 *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=startsAt,proto3" json:"startsAt,omitempty"`
+	EndsAt                   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=endsAt,proto3" json:"endsAt,omitempty"`
+	Active                   bool                   `protobuf:"varint,17,opt,name=active,proto3" json:"active,omitempty"`
+	CreatedAt                *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Coupon) Reset() {
+	*x = Coupon{}
+	mi := &file_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coupon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coupon) ProtoMessage() {}
+
+func (x *Coupon) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coupon.ProtoReflect.Descriptor instead.
+func (*Coupon) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Coupon) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Coupon) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Coupon) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Coupon) GetSellerId() uint64 {
+	if x != nil {
+		return x.SellerId
+	}
+	return 0
+}
+
+func (x *Coupon) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Coupon) GetPercentOff() int64 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *Coupon) GetAmountOff() *pb.Money {
+	if x != nil {
+		return x.AmountOff
+	}
+	return nil
+}
+
+func (x *Coupon) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Coupon) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+func (x *Coupon) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Coupon) GetMinOrderValue() *pb.Money {
+	if x != nil {
+		return x.MinOrderValue
+	}
+	return nil
+}
+
+func (x *Coupon) GetMaxRedemptions() uint32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *Coupon) GetMaxRedemptionsPerAccount() uint32 {
+	if x != nil {
+		return x.MaxRedemptionsPerAccount
+	}
+	return 0
+}
+
+func (x *Coupon) GetRedemptions() uint32 {
+	if x != nil {
+		return x.Redemptions
+	}
+	return 0
+}
+
+func (x *Coupon) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *Coupon) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *Coupon) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Coupon) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Coupon        *Coupon                `protobuf:"bytes,2,opt,name=coupon,proto3" json:"coupon,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProductInfo) Reset() {
-	*x = ProductInfo{}
-	mi := &file_order_proto_msgTypes[0]
+func (x *CreateCouponRequest) Reset() {
+	*x = CreateCouponRequest{}
+	mi := &file_order_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProductInfo) String() string {
+func (x *CreateCouponRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProductInfo) ProtoMessage() {}
+func (*CreateCouponRequest) ProtoMessage() {}
 
-func (x *ProductInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[0]
+func (x *CreateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,72 +1639,99 @@ func (x *ProductInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProductInfo.ProtoReflect.Descriptor instead.
-func (*ProductInfo) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use CreateCouponRequest.ProtoReflect.Descriptor instead.
+func (*CreateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *ProductInfo) GetId() string {
+func (x *CreateCouponRequest) GetAccountId() uint64 {
 	if x != nil {
-		return x.Id
+		return x.AccountId
 	}
-	return ""
+	return 0
 }
 
-func (x *ProductInfo) GetName() string {
+func (x *CreateCouponRequest) GetCoupon() *Coupon {
 	if x != nil {
-		return x.Name
+		return x.Coupon
 	}
-	return ""
+	return nil
 }
 
-func (x *ProductInfo) GetDescription() string {
+type UpdateCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Coupon        *Coupon                `protobuf:"bytes,2,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCouponRequest) Reset() {
+	*x = UpdateCouponRequest{}
+	mi := &file_order_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCouponRequest) ProtoMessage() {}
+
+func (x *UpdateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[21]
 	if x != nil {
-		return x.Description
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCouponRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *ProductInfo) GetPrice() float64 {
+func (x *UpdateCouponRequest) GetAccountId() uint64 {
 	if x != nil {
-		return x.Price
+		return x.AccountId
 	}
 	return 0
 }
 
-func (x *ProductInfo) GetQuantity() uint32 {
+func (x *UpdateCouponRequest) GetCoupon() *Coupon {
 	if x != nil {
-		return x.Quantity
+		return x.Coupon
 	}
-	return 0
+	return nil
 }
 
-type Order struct {
+type ListCouponsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	CreatedAt     []byte                 `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	AccountId     uint64                 `protobuf:"varint,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
-	TotalPrice    float64                `protobuf:"fixed64,4,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
-	Products      []*ProductInfo         `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
+	AccountId     uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order) Reset() {
-	*x = Order{}
-	mi := &file_order_proto_msgTypes[1]
+func (x *ListCouponsRequest) Reset() {
+	*x = ListCouponsRequest{}
+	mi := &file_order_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order) String() string {
+func (x *ListCouponsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Order) ProtoMessage() {}
+func (*ListCouponsRequest) ProtoMessage() {}
 
-func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[1]
+func (x *ListCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -135,69 +1742,90 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Order.ProtoReflect.Descriptor instead.
-func (*Order) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ListCouponsRequest.ProtoReflect.Descriptor instead.
+func (*ListCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *Order) GetId() uint64 {
+func (x *ListCouponsRequest) GetAccountId() uint64 {
 	if x != nil {
-		return x.Id
+		return x.AccountId
 	}
 	return 0
 }
 
-func (x *Order) GetCreatedAt() []byte {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
+type ListCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupons       []*Coupon              `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order) GetAccountId() uint64 {
-	if x != nil {
-		return x.AccountId
-	}
-	return 0
+func (x *ListCouponsResponse) Reset() {
+	*x = ListCouponsResponse{}
+	mi := &file_order_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Order) GetTotalPrice() float64 {
+func (*ListCouponsResponse) ProtoMessage() {}
+
+func (x *ListCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[23]
 	if x != nil {
-		return x.TotalPrice
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Order) GetProducts() []*ProductInfo {
+// Deprecated: Use ListCouponsResponse.ProtoReflect.Descriptor instead.
+func (*ListCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListCouponsResponse) GetCoupons() []*Coupon {
 	if x != nil {
-		return x.Products
+		return x.Coupons
 	}
 	return nil
 }
 
-type OrderProduct struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+type ShippingRate struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Currency string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// flat or weight
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// minor units of currency
+	Amount        int64 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	PerKg         int64 `protobuf:"varint,4,opt,name=perKg,proto3" json:"perKg,omitempty"`
+	FreeOver      int64 `protobuf:"varint,5,opt,name=freeOver,proto3" json:"freeOver,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderProduct) Reset() {
-	*x = OrderProduct{}
-	mi := &file_order_proto_msgTypes[2]
+func (x *ShippingRate) Reset() {
+	*x = ShippingRate{}
+	mi := &file_order_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderProduct) String() string {
+func (x *ShippingRate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderProduct) ProtoMessage() {}
+func (*ShippingRate) ProtoMessage() {}
 
-func (x *OrderProduct) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[2]
+func (x *ShippingRate) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,48 +1836,73 @@ func (x *OrderProduct) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderProduct.ProtoReflect.Descriptor instead.
-func (*OrderProduct) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ShippingRate.ProtoReflect.Descriptor instead.
+func (*ShippingRate) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *OrderProduct) GetId() string {
+func (x *ShippingRate) GetCurrency() string {
 	if x != nil {
-		return x.Id
+		return x.Currency
 	}
 	return ""
 }
 
-func (x *OrderProduct) GetQuantity() uint32 {
+func (x *ShippingRate) GetType() string {
 	if x != nil {
-		return x.Quantity
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ShippingRate) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
 	}
 	return 0
 }
 
-type PostOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     uint64                 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
-	Products      []*OrderProduct        `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+func (x *ShippingRate) GetPerKg() int64 {
+	if x != nil {
+		return x.PerKg
+	}
+	return 0
+}
+
+func (x *ShippingRate) GetFreeOver() int64 {
+	if x != nil {
+		return x.FreeOver
+	}
+	return 0
+}
+
+type ShippingMethod struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Carrier string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	// empty for every country
+	Countries     []string        `protobuf:"bytes,4,rep,name=countries,proto3" json:"countries,omitempty"`
+	Rates         []*ShippingRate `protobuf:"bytes,5,rep,name=rates,proto3" json:"rates,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PostOrderRequest) Reset() {
-	*x = PostOrderRequest{}
-	mi := &file_order_proto_msgTypes[3]
+func (x *ShippingMethod) Reset() {
+	*x = ShippingMethod{}
+	mi := &file_order_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PostOrderRequest) String() string {
+func (x *ShippingMethod) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostOrderRequest) ProtoMessage() {}
+func (*ShippingMethod) ProtoMessage() {}
 
-func (x *PostOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[3]
+func (x *ShippingMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -260,47 +1913,69 @@ func (x *PostOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostOrderRequest.ProtoReflect.Descriptor instead.
-func (*PostOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ShippingMethod.ProtoReflect.Descriptor instead.
+func (*ShippingMethod) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *PostOrderRequest) GetAccountId() uint64 {
+func (x *ShippingMethod) GetCode() string {
 	if x != nil {
-		return x.AccountId
+		return x.Code
 	}
-	return 0
+	return ""
 }
 
-func (x *PostOrderRequest) GetProducts() []*OrderProduct {
+func (x *ShippingMethod) GetName() string {
 	if x != nil {
-		return x.Products
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingMethod) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *ShippingMethod) GetCountries() []string {
+	if x != nil {
+		return x.Countries
 	}
 	return nil
 }
 
-type PostOrderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+func (x *ShippingMethod) GetRates() []*ShippingRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type ListShippingMethodsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the default method first
+	Methods       []*ShippingMethod `protobuf:"bytes,1,rep,name=methods,proto3" json:"methods,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PostOrderResponse) Reset() {
-	*x = PostOrderResponse{}
-	mi := &file_order_proto_msgTypes[4]
+func (x *ListShippingMethodsResponse) Reset() {
+	*x = ListShippingMethodsResponse{}
+	mi := &file_order_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PostOrderResponse) String() string {
+func (x *ListShippingMethodsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostOrderResponse) ProtoMessage() {}
+func (*ListShippingMethodsResponse) ProtoMessage() {}
 
-func (x *PostOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[4]
+func (x *ListShippingMethodsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -311,40 +1986,45 @@ func (x *PostOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostOrderResponse.ProtoReflect.Descriptor instead.
-func (*PostOrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListShippingMethodsResponse.ProtoReflect.Descriptor instead.
+func (*ListShippingMethodsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *PostOrderResponse) GetOrder() *Order {
+func (x *ListShippingMethodsResponse) GetMethods() []*ShippingMethod {
 	if x != nil {
-		return x.Order
+		return x.Methods
 	}
 	return nil
 }
 
-type GetOrdersForAccountResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+type MarkShippedRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderId        uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	SellerId       uint64                 `protobuf:"varint,2,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Carrier        string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,4,opt,name=trackingNumber,proto3" json:"trackingNumber,omitempty"`
+	// empty for all of the seller's products not shipped yet
+	ProductIds    []string `protobuf:"bytes,5,rep,name=productIds,proto3" json:"productIds,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrdersForAccountResponse) Reset() {
-	*x = GetOrdersForAccountResponse{}
-	mi := &file_order_proto_msgTypes[5]
+func (x *MarkShippedRequest) Reset() {
+	*x = MarkShippedRequest{}
+	mi := &file_order_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrdersForAccountResponse) String() string {
+func (x *MarkShippedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrdersForAccountResponse) ProtoMessage() {}
+func (*MarkShippedRequest) ProtoMessage() {}
 
-func (x *GetOrdersForAccountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[5]
+func (x *MarkShippedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -355,41 +2035,71 @@ func (x *GetOrdersForAccountResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrdersForAccountResponse.ProtoReflect.Descriptor instead.
-func (*GetOrdersForAccountResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use MarkShippedRequest.ProtoReflect.Descriptor instead.
+func (*MarkShippedRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *GetOrdersForAccountResponse) GetOrders() []*Order {
+func (x *MarkShippedRequest) GetOrderId() uint64 {
 	if x != nil {
-		return x.Orders
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *MarkShippedRequest) GetSellerId() uint64 {
+	if x != nil {
+		return x.SellerId
+	}
+	return 0
+}
+
+func (x *MarkShippedRequest) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *MarkShippedRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *MarkShippedRequest) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
 	}
 	return nil
 }
 
-type UpdateOrderStatusRequest struct {
+type UpdateShipmentStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	ShipmentId    uint64                 `protobuf:"varint,1,opt,name=shipmentId,proto3" json:"shipmentId,omitempty"`
+	AccountId     uint64                 `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrderStatusRequest) Reset() {
-	*x = UpdateOrderStatusRequest{}
-	mi := &file_order_proto_msgTypes[6]
+func (x *UpdateShipmentStatusRequest) Reset() {
+	*x = UpdateShipmentStatusRequest{}
+	mi := &file_order_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrderStatusRequest) String() string {
+func (x *UpdateShipmentStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrderStatusRequest) ProtoMessage() {}
+func (*UpdateShipmentStatusRequest) ProtoMessage() {}
 
-func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[6]
+func (x *UpdateShipmentStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -400,61 +2110,252 @@ func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use UpdateShipmentStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateShipmentStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *UpdateOrderStatusRequest) GetOrderId() uint64 {
+func (x *UpdateShipmentStatusRequest) GetShipmentId() uint64 {
 	if x != nil {
-		return x.OrderId
+		return x.ShipmentId
 	}
 	return 0
 }
 
-func (x *UpdateOrderStatusRequest) GetStatus() string {
+func (x *UpdateShipmentStatusRequest) GetAccountId() uint64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UpdateShipmentStatusRequest) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
+func (x *UpdateShipmentStatusRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
 var File_order_proto protoreflect.FileDescriptor
 
 const file_order_proto_rawDesc = "" +
 	"\n" +
-	"\vorder.proto\x12\x02pb\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x85\x01\n" +
+	"\vorder.proto\x12\x02pb\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\x1a\vmoney.proto\"\xdb\x02\n" +
 	"\vProductInfo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
-	"\bquantity\x18\x05 \x01(\rR\bquantity\"\xa0\x01\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\rR\bquantity\x12\"\n" +
+	"\x05price\x18\a \x01(\v2\f.money.MoneyR\x05price\x12\x10\n" +
+	"\x03sku\x18\b \x01(\tR\x03sku\x12*\n" +
+	"\tlineTotal\x18\t \x01(\v2\f.money.MoneyR\tlineTotal\x12 \n" +
+	"\vtaxCategory\x18\n" +
+	" \x01(\tR\vtaxCategory\x12\x18\n" +
+	"\ataxRate\x18\v \x01(\x03R\ataxRate\x12\x1e\n" +
+	"\x03tax\x18\f \x01(\v2\f.money.MoneyR\x03tax\x12 \n" +
+	"\vweightGrams\x18\r \x01(\x03R\vweightGramsJ\x04\b\x04\x10\x05J\x04\b\x06\x10\a\"\xaf\x01\n" +
+	"\aAddress\x12\x18\n" +
+	"\acountry\x18\x01 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x04 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x05 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x1e\n" +
+	"\n" +
+	"postalCode\x18\a \x01(\tR\n" +
+	"postalCode\"\x83\x01\n" +
+	"\rShipmentEvent\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x128\n" +
+	"\tcreatedAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xe9\x02\n" +
+	"\bShipment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x18\n" +
+	"\aorderId\x18\x02 \x01(\x04R\aorderId\x12\x1a\n" +
+	"\bsellerId\x18\x03 \x01(\x04R\bsellerId\x12\x18\n" +
+	"\acarrier\x18\x04 \x01(\tR\acarrier\x12&\n" +
+	"\x0etrackingNumber\x18\x05 \x01(\tR\x0etrackingNumber\x12\x1e\n" +
+	"\n" +
+	"productIds\x18\x06 \x03(\tR\n" +
+	"productIds\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12)\n" +
+	"\x06events\x18\b \x03(\v2\x11.pb.ShipmentEventR\x06events\x128\n" +
+	"\tcreatedAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x128\n" +
+	"\tupdatedAt\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"n\n" +
+	"\bDiscount\x12\x1e\n" +
+	"\n" +
+	"couponCode\x18\x01 \x01(\tR\n" +
+	"couponCode\x12\x1c\n" +
+	"\tproductId\x18\x02 \x01(\tR\tproductId\x12$\n" +
+	"\x06amount\x18\x03 \x01(\v2\f.money.MoneyR\x06amount\"\xe1\x04\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1c\n" +
 	"\tcreatedAt\x18\x02 \x01(\fR\tcreatedAt\x12\x1c\n" +
-	"\taccountId\x18\x03 \x01(\x04R\taccountId\x12\x1e\n" +
+	"\taccountId\x18\x03 \x01(\x04R\taccountId\x12+\n" +
+	"\bproducts\x18\x05 \x03(\v2\x0f.pb.ProductInfoR\bproducts\x12,\n" +
+	"\n" +
+	"totalPrice\x18\a \x01(\v2\f.money.MoneyR\n" +
+	"totalPrice\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12*\n" +
+	"\ahistory\x18\t \x03(\v2\x10.pb.StatusChangeR\ahistory\x12\x1e\n" +
 	"\n" +
-	"totalPrice\x18\x04 \x01(\x01R\n" +
-	"totalPrice\x12+\n" +
-	"\bproducts\x18\x05 \x03(\v2\x0f.pb.ProductInfoR\bproducts\":\n" +
+	"couponCode\x18\n" +
+	" \x01(\tR\n" +
+	"couponCode\x12(\n" +
+	"\bdiscount\x18\v \x01(\v2\f.money.MoneyR\bdiscount\x12*\n" +
+	"\tdiscounts\x18\f \x03(\v2\f.pb.DiscountR\tdiscounts\x12(\n" +
+	"\bsubtotal\x18\r \x01(\v2\f.money.MoneyR\bsubtotal\x12\x1e\n" +
+	"\x03tax\x18\x0e \x01(\v2\f.money.MoneyR\x03tax\x12#\n" +
+	"\x06shipTo\x18\x0f \x01(\v2\v.pb.AddressR\x06shipTo\x12&\n" +
+	"\x0eshippingMethod\x18\x10 \x01(\tR\x0eshippingMethod\x12(\n" +
+	"\bshipping\x18\x11 \x01(\v2\f.money.MoneyR\bshipping\x12*\n" +
+	"\tshipments\x18\x12 \x03(\v2\f.pb.ShipmentR\tshipmentsJ\x04\b\x04\x10\x05J\x04\b\x06\x10\a\"\x9a\x01\n" +
+	"\fStatusChange\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x128\n" +
+	"\tcreatedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\":\n" +
 	"\fOrderProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\rR\bquantity\"^\n" +
+	"\bquantity\x18\x02 \x01(\rR\bquantity\"\xcb\x01\n" +
 	"\x10PostOrderRequest\x12\x1c\n" +
 	"\taccountId\x18\x01 \x01(\x04R\taccountId\x12,\n" +
-	"\bproducts\x18\x02 \x03(\v2\x10.pb.OrderProductR\bproducts\"4\n" +
+	"\bproducts\x18\x02 \x03(\v2\x10.pb.OrderProductR\bproducts\x12\x1e\n" +
+	"\n" +
+	"couponCode\x18\x03 \x01(\tR\n" +
+	"couponCode\x12#\n" +
+	"\x06shipTo\x18\x04 \x01(\v2\v.pb.AddressR\x06shipTo\x12&\n" +
+	"\x0eshippingMethod\x18\x05 \x01(\tR\x0eshippingMethod\"4\n" +
 	"\x11PostOrderResponse\x12\x1f\n" +
 	"\x05order\x18\x01 \x01(\v2\t.pb.OrderR\x05order\"@\n" +
 	"\x1bGetOrdersForAccountResponse\x12!\n" +
-	"\x06orders\x18\x01 \x03(\v2\t.pb.OrderR\x06orders\"L\n" +
+	"\x06orders\x18\x01 \x03(\v2\t.pb.OrderR\x06orders\"z\n" +
 	"\x18UpdateOrderStatusRequest\x12\x18\n" +
 	"\aorderId\x18\x01 \x01(\x04R\aorderId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status2\xef\x01\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"N\n" +
+	"\x1aUpdatePaymentStatusRequest\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\x04R\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"d\n" +
+	"\x12CancelOrderRequest\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\x04R\aorderId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x04R\taccountId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"I\n" +
+	"\x0fGetOrderRequest\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\x04R\aorderId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x04R\taccountId\"\xd5\x01\n" +
+	"\vOrderFilter\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12>\n" +
+	"\fcreatedAfter\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12@\n" +
+	"\rcreatedBefore\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12(\n" +
+	"\bminTotal\x18\x04 \x01(\v2\f.money.MoneyR\bminTotal\"\x86\x01\n" +
+	"\x11ListOrdersRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x04R\taccountId\x12'\n" +
+	"\x06filter\x18\x02 \x01(\v2\x0f.pb.OrderFilterR\x06filter\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\tR\x05after\x12\x14\n" +
+	"\x05first\x18\x04 \x01(\rR\x05first\"w\n" +
+	"\x12ListOrdersResponse\x12!\n" +
+	"\x06orders\x18\x01 \x03(\v2\t.pb.OrderR\x06orders\x12\x1c\n" +
+	"\tendCursor\x18\x02 \x01(\tR\tendCursor\x12 \n" +
+	"\vhasNextPage\x18\x03 \x01(\bR\vhasNextPage\"Q\n" +
+	"\x13HasPurchasedRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x04R\taccountId\x12\x1c\n" +
+	"\tproductId\x18\x02 \x01(\tR\tproductId\"\x98\x05\n" +
+	"\x06Coupon\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bsellerId\x18\x04 \x01(\x04R\bsellerId\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x1e\n" +
+	"\n" +
+	"percentOff\x18\x06 \x01(\x03R\n" +
+	"percentOff\x12*\n" +
+	"\tamountOff\x18\a \x01(\v2\f.money.MoneyR\tamountOff\x12\x14\n" +
+	"\x05scope\x18\b \x01(\tR\x05scope\x12\x1e\n" +
+	"\n" +
+	"productIds\x18\t \x03(\tR\n" +
+	"productIds\x12\x1e\n" +
+	"\n" +
+	"categories\x18\n" +
+	" \x03(\tR\n" +
+	"categories\x122\n" +
+	"\rminOrderValue\x18\v \x01(\v2\f.money.MoneyR\rminOrderValue\x12&\n" +
+	"\x0emaxRedemptions\x18\f \x01(\rR\x0emaxRedemptions\x12:\n" +
+	"\x18maxRedemptionsPerAccount\x18\r \x01(\rR\x18maxRedemptionsPerAccount\x12 \n" +
+	"\vredemptions\x18\x0e \x01(\rR\vredemptions\x126\n" +
+	"\bstartsAt\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x122\n" +
+	"\x06endsAt\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12\x16\n" +
+	"\x06active\x18\x11 \x01(\bR\x06active\x128\n" +
+	"\tcreatedAt\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"W\n" +
+	"\x13CreateCouponRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x04R\taccountId\x12\"\n" +
+	"\x06coupon\x18\x02 \x01(\v2\n" +
+	".pb.CouponR\x06coupon\"W\n" +
+	"\x13UpdateCouponRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x04R\taccountId\x12\"\n" +
+	"\x06coupon\x18\x02 \x01(\v2\n" +
+	".pb.CouponR\x06coupon\"2\n" +
+	"\x12ListCouponsRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x04R\taccountId\";\n" +
+	"\x13ListCouponsResponse\x12$\n" +
+	"\acoupons\x18\x01 \x03(\v2\n" +
+	".pb.CouponR\acoupons\"\x88\x01\n" +
+	"\fShippingRate\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x14\n" +
+	"\x05perKg\x18\x04 \x01(\x03R\x05perKg\x12\x1a\n" +
+	"\bfreeOver\x18\x05 \x01(\x03R\bfreeOver\"\x98\x01\n" +
+	"\x0eShippingMethod\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12\x1c\n" +
+	"\tcountries\x18\x04 \x03(\tR\tcountries\x12&\n" +
+	"\x05rates\x18\x05 \x03(\v2\x10.pb.ShippingRateR\x05rates\"K\n" +
+	"\x1bListShippingMethodsResponse\x12,\n" +
+	"\amethods\x18\x01 \x03(\v2\x12.pb.ShippingMethodR\amethods\"\xac\x01\n" +
+	"\x12MarkShippedRequest\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\x04R\aorderId\x12\x1a\n" +
+	"\bsellerId\x18\x02 \x01(\x04R\bsellerId\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12&\n" +
+	"\x0etrackingNumber\x18\x04 \x01(\tR\x0etrackingNumber\x12\x1e\n" +
+	"\n" +
+	"productIds\x18\x05 \x03(\tR\n" +
+	"productIds\"\x95\x01\n" +
+	"\x1bUpdateShipmentStatusRequest\x12\x1e\n" +
+	"\n" +
+	"shipmentId\x18\x01 \x01(\x04R\n" +
+	"shipmentId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x04R\taccountId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription2\x9d\a\n" +
 	"\fOrderService\x12:\n" +
 	"\tPostOrder\x12\x14.pb.PostOrderRequest\x1a\x15.pb.PostOrderResponse\"\x00\x12V\n" +
-	"\x13GetOrdersForAccount\x12\x1c.google.protobuf.UInt64Value\x1a\x1f.pb.GetOrdersForAccountResponse\"\x00\x12K\n" +
-	"\x11UpdateOrderStatus\x12\x1c.pb.UpdateOrderStatusRequest\x1a\x16.google.protobuf.Empty\"\x00B\x06Z\x04./pbb\x06proto3"
+	"\x13GetOrdersForAccount\x12\x1c.google.protobuf.UInt64Value\x1a\x1f.pb.GetOrdersForAccountResponse\"\x00\x12,\n" +
+	"\bGetOrder\x12\x13.pb.GetOrderRequest\x1a\t.pb.Order\"\x00\x12=\n" +
+	"\n" +
+	"ListOrders\x12\x15.pb.ListOrdersRequest\x1a\x16.pb.ListOrdersResponse\"\x00\x12>\n" +
+	"\x11UpdateOrderStatus\x12\x1c.pb.UpdateOrderStatusRequest\x1a\t.pb.Order\"\x00\x12O\n" +
+	"\x13UpdatePaymentStatus\x12\x1e.pb.UpdatePaymentStatusRequest\x1a\x16.google.protobuf.Empty\"\x00\x122\n" +
+	"\vCancelOrder\x12\x16.pb.CancelOrderRequest\x1a\t.pb.Order\"\x00\x12E\n" +
+	"\fHasPurchased\x12\x17.pb.HasPurchasedRequest\x1a\x1a.google.protobuf.BoolValue\"\x00\x125\n" +
+	"\fCreateCoupon\x12\x17.pb.CreateCouponRequest\x1a\n" +
+	".pb.Coupon\"\x00\x125\n" +
+	"\fUpdateCoupon\x12\x17.pb.UpdateCouponRequest\x1a\n" +
+	".pb.Coupon\"\x00\x12@\n" +
+	"\vListCoupons\x12\x16.pb.ListCouponsRequest\x1a\x17.pb.ListCouponsResponse\"\x00\x12P\n" +
+	"\x13ListShippingMethods\x12\x16.google.protobuf.Empty\x1a\x1f.pb.ListShippingMethodsResponse\"\x00\x125\n" +
+	"\vMarkShipped\x12\x16.pb.MarkShippedRequest\x1a\f.pb.Shipment\"\x00\x12G\n" +
+	"\x14UpdateShipmentStatus\x12\x1f.pb.UpdateShipmentStatusRequest\x1a\f.pb.Shipment\"\x00B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -468,34 +2369,115 @@ func file_order_proto_rawDescGZIP() []byte {
 	return file_order_proto_rawDescData
 }
 
-var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_order_proto_goTypes = []any{
 	(*ProductInfo)(nil),                 // 0: pb.ProductInfo
-	(*Order)(nil),                       // 1: pb.Order
-	(*OrderProduct)(nil),                // 2: pb.OrderProduct
-	(*PostOrderRequest)(nil),            // 3: pb.PostOrderRequest
-	(*PostOrderResponse)(nil),           // 4: pb.PostOrderResponse
-	(*GetOrdersForAccountResponse)(nil), // 5: pb.GetOrdersForAccountResponse
-	(*UpdateOrderStatusRequest)(nil),    // 6: pb.UpdateOrderStatusRequest
-	(*wrapperspb.UInt64Value)(nil),      // 7: google.protobuf.UInt64Value
-	(*emptypb.Empty)(nil),               // 8: google.protobuf.Empty
+	(*Address)(nil),                     // 1: pb.Address
+	(*ShipmentEvent)(nil),               // 2: pb.ShipmentEvent
+	(*Shipment)(nil),                    // 3: pb.Shipment
+	(*Discount)(nil),                    // 4: pb.Discount
+	(*Order)(nil),                       // 5: pb.Order
+	(*StatusChange)(nil),                // 6: pb.StatusChange
+	(*OrderProduct)(nil),                // 7: pb.OrderProduct
+	(*PostOrderRequest)(nil),            // 8: pb.PostOrderRequest
+	(*PostOrderResponse)(nil),           // 9: pb.PostOrderResponse
+	(*GetOrdersForAccountResponse)(nil), // 10: pb.GetOrdersForAccountResponse
+	(*UpdateOrderStatusRequest)(nil),    // 11: pb.UpdateOrderStatusRequest
+	(*UpdatePaymentStatusRequest)(nil),  // 12: pb.UpdatePaymentStatusRequest
+	(*CancelOrderRequest)(nil),          // 13: pb.CancelOrderRequest
+	(*GetOrderRequest)(nil),             // 14: pb.GetOrderRequest
+	(*OrderFilter)(nil),                 // 15: pb.OrderFilter
+	(*ListOrdersRequest)(nil),           // 16: pb.ListOrdersRequest
+	(*ListOrdersResponse)(nil),          // 17: pb.ListOrdersResponse
+	(*HasPurchasedRequest)(nil),         // 18: pb.HasPurchasedRequest
+	(*Coupon)(nil),                      // 19: pb.Coupon
+	(*CreateCouponRequest)(nil),         // 20: pb.CreateCouponRequest
+	(*UpdateCouponRequest)(nil),         // 21: pb.UpdateCouponRequest
+	(*ListCouponsRequest)(nil),          // 22: pb.ListCouponsRequest
+	(*ListCouponsResponse)(nil),         // 23: pb.ListCouponsResponse
+	(*ShippingRate)(nil),                // 24: pb.ShippingRate
+	(*ShippingMethod)(nil),              // 25: pb.ShippingMethod
+	(*ListShippingMethodsResponse)(nil), // 26: pb.ListShippingMethodsResponse
+	(*MarkShippedRequest)(nil),          // 27: pb.MarkShippedRequest
+	(*UpdateShipmentStatusRequest)(nil), // 28: pb.UpdateShipmentStatusRequest
+	(*pb.Money)(nil),                    // 29: money.Money
+	(*timestamppb.Timestamp)(nil),       // 30: google.protobuf.Timestamp
+	(*wrapperspb.UInt64Value)(nil),      // 31: google.protobuf.UInt64Value
+	(*emptypb.Empty)(nil),               // 32: google.protobuf.Empty
+	(*wrapperspb.BoolValue)(nil),        // 33: google.protobuf.BoolValue
 }
 var file_order_proto_depIdxs = []int32{
-	0, // 0: pb.Order.products:type_name -> pb.ProductInfo
-	2, // 1: pb.PostOrderRequest.products:type_name -> pb.OrderProduct
-	1, // 2: pb.PostOrderResponse.order:type_name -> pb.Order
-	1, // 3: pb.GetOrdersForAccountResponse.orders:type_name -> pb.Order
-	3, // 4: pb.OrderService.PostOrder:input_type -> pb.PostOrderRequest
-	7, // 5: pb.OrderService.GetOrdersForAccount:input_type -> google.protobuf.UInt64Value
-	6, // 6: pb.OrderService.UpdateOrderStatus:input_type -> pb.UpdateOrderStatusRequest
-	4, // 7: pb.OrderService.PostOrder:output_type -> pb.PostOrderResponse
-	5, // 8: pb.OrderService.GetOrdersForAccount:output_type -> pb.GetOrdersForAccountResponse
-	8, // 9: pb.OrderService.UpdateOrderStatus:output_type -> google.protobuf.Empty
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	29, // 0: pb.ProductInfo.price:type_name -> money.Money
+	29, // 1: pb.ProductInfo.lineTotal:type_name -> money.Money
+	29, // 2: pb.ProductInfo.tax:type_name -> money.Money
+	30, // 3: pb.ShipmentEvent.createdAt:type_name -> google.protobuf.Timestamp
+	2,  // 4: pb.Shipment.events:type_name -> pb.ShipmentEvent
+	30, // 5: pb.Shipment.createdAt:type_name -> google.protobuf.Timestamp
+	30, // 6: pb.Shipment.updatedAt:type_name -> google.protobuf.Timestamp
+	29, // 7: pb.Discount.amount:type_name -> money.Money
+	0,  // 8: pb.Order.products:type_name -> pb.ProductInfo
+	29, // 9: pb.Order.totalPrice:type_name -> money.Money
+	6,  // 10: pb.Order.history:type_name -> pb.StatusChange
+	29, // 11: pb.Order.discount:type_name -> money.Money
+	4,  // 12: pb.Order.discounts:type_name -> pb.Discount
+	29, // 13: pb.Order.subtotal:type_name -> money.Money
+	29, // 14: pb.Order.tax:type_name -> money.Money
+	1,  // 15: pb.Order.shipTo:type_name -> pb.Address
+	29, // 16: pb.Order.shipping:type_name -> money.Money
+	3,  // 17: pb.Order.shipments:type_name -> pb.Shipment
+	30, // 18: pb.StatusChange.createdAt:type_name -> google.protobuf.Timestamp
+	7,  // 19: pb.PostOrderRequest.products:type_name -> pb.OrderProduct
+	1,  // 20: pb.PostOrderRequest.shipTo:type_name -> pb.Address
+	5,  // 21: pb.PostOrderResponse.order:type_name -> pb.Order
+	5,  // 22: pb.GetOrdersForAccountResponse.orders:type_name -> pb.Order
+	30, // 23: pb.OrderFilter.createdAfter:type_name -> google.protobuf.Timestamp
+	30, // 24: pb.OrderFilter.createdBefore:type_name -> google.protobuf.Timestamp
+	29, // 25: pb.OrderFilter.minTotal:type_name -> money.Money
+	15, // 26: pb.ListOrdersRequest.filter:type_name -> pb.OrderFilter
+	5,  // 27: pb.ListOrdersResponse.orders:type_name -> pb.Order
+	29, // 28: pb.Coupon.amountOff:type_name -> money.Money
+	29, // 29: pb.Coupon.minOrderValue:type_name -> money.Money
+	30, // 30: pb.Coupon.startsAt:type_name -> google.protobuf.Timestamp
+	30, // 31: pb.Coupon.endsAt:type_name -> google.protobuf.Timestamp
+	30, // 32: pb.Coupon.createdAt:type_name -> google.protobuf.Timestamp
+	19, // 33: pb.CreateCouponRequest.coupon:type_name -> pb.Coupon
+	19, // 34: pb.UpdateCouponRequest.coupon:type_name -> pb.Coupon
+	19, // 35: pb.ListCouponsResponse.coupons:type_name -> pb.Coupon
+	24, // 36: pb.ShippingMethod.rates:type_name -> pb.ShippingRate
+	25, // 37: pb.ListShippingMethodsResponse.methods:type_name -> pb.ShippingMethod
+	8,  // 38: pb.OrderService.PostOrder:input_type -> pb.PostOrderRequest
+	31, // 39: pb.OrderService.GetOrdersForAccount:input_type -> google.protobuf.UInt64Value
+	14, // 40: pb.OrderService.GetOrder:input_type -> pb.GetOrderRequest
+	16, // 41: pb.OrderService.ListOrders:input_type -> pb.ListOrdersRequest
+	11, // 42: pb.OrderService.UpdateOrderStatus:input_type -> pb.UpdateOrderStatusRequest
+	12, // 43: pb.OrderService.UpdatePaymentStatus:input_type -> pb.UpdatePaymentStatusRequest
+	13, // 44: pb.OrderService.CancelOrder:input_type -> pb.CancelOrderRequest
+	18, // 45: pb.OrderService.HasPurchased:input_type -> pb.HasPurchasedRequest
+	20, // 46: pb.OrderService.CreateCoupon:input_type -> pb.CreateCouponRequest
+	21, // 47: pb.OrderService.UpdateCoupon:input_type -> pb.UpdateCouponRequest
+	22, // 48: pb.OrderService.ListCoupons:input_type -> pb.ListCouponsRequest
+	32, // 49: pb.OrderService.ListShippingMethods:input_type -> google.protobuf.Empty
+	27, // 50: pb.OrderService.MarkShipped:input_type -> pb.MarkShippedRequest
+	28, // 51: pb.OrderService.UpdateShipmentStatus:input_type -> pb.UpdateShipmentStatusRequest
+	9,  // 52: pb.OrderService.PostOrder:output_type -> pb.PostOrderResponse
+	10, // 53: pb.OrderService.GetOrdersForAccount:output_type -> pb.GetOrdersForAccountResponse
+	5,  // 54: pb.OrderService.GetOrder:output_type -> pb.Order
+	17, // 55: pb.OrderService.ListOrders:output_type -> pb.ListOrdersResponse
+	5,  // 56: pb.OrderService.UpdateOrderStatus:output_type -> pb.Order
+	32, // 57: pb.OrderService.UpdatePaymentStatus:output_type -> google.protobuf.Empty
+	5,  // 58: pb.OrderService.CancelOrder:output_type -> pb.Order
+	33, // 59: pb.OrderService.HasPurchased:output_type -> google.protobuf.BoolValue
+	19, // 60: pb.OrderService.CreateCoupon:output_type -> pb.Coupon
+	19, // 61: pb.OrderService.UpdateCoupon:output_type -> pb.Coupon
+	23, // 62: pb.OrderService.ListCoupons:output_type -> pb.ListCouponsResponse
+	26, // 63: pb.OrderService.ListShippingMethods:output_type -> pb.ListShippingMethodsResponse
+	3,  // 64: pb.OrderService.MarkShipped:output_type -> pb.Shipment
+	3,  // 65: pb.OrderService.UpdateShipmentStatus:output_type -> pb.Shipment
+	52, // [52:66] is the sub-list for method output_type
+	38, // [38:52] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_order_proto_init() }
@@ -509,7 +2491,7 @@ func file_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_proto_rawDesc), len(file_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,211 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+// Shipping rate types.
+const (
+	// RateFlat charges the same for every order.
+	RateFlat = "flat"
+	// RateWeight charges a base amount plus an amount per started kilogram.
+	RateWeight = "weight"
+)
+
+// ShippingRate prices a shipping method for orders in one currency. Amounts
+// are in minor units of the currency.
+type ShippingRate struct {
+	Currency string `json:"currency"`
+	Type     string `json:"type"`
+	// Amount is the flat price, or the base price of weight rates.
+	Amount int64 `json:"amount"`
+	// PerKg is charged for every started kilogram by weight rates.
+	PerKg int64 `json:"perKg,omitempty"`
+	// FreeOver ships orders whose goods cost at least this much, after
+	// discounts, for free; 0 for never.
+	FreeOver int64 `json:"freeOver,omitempty"`
+}
+
+// ShippingMethod is a way orders are delivered.
+type ShippingMethod struct {
+	// Code is the lowercase name orders choose the method by, such as
+	// "standard".
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Carrier string `json:"carrier,omitempty"`
+	// Countries are the ISO 3166-1 alpha-2 codes the method delivers to;
+	// empty for every country.
+	Countries []string       `json:"countries,omitempty"`
+	Rates     []ShippingRate `json:"rates"`
+}
+
+// Quote returns what the method charges to deliver an order to country whose
+// goods cost goods, net of discounts, and weigh weightGrams.
+func (m *ShippingMethod) Quote(country string, goods money.Money, weightGrams int64) (money.Money, error) {
+	if len(m.Countries) > 0 && !slices.Contains(m.Countries, strings.ToUpper(country)) {
+		return money.Money{}, ErrShippingUnavailable
+	}
+	i := slices.IndexFunc(m.Rates, func(r ShippingRate) bool { return r.Currency == goods.Currency })
+	if i < 0 {
+		return money.Money{}, ErrShippingUnavailable
+	}
+
+	rate := m.Rates[i]
+	if rate.FreeOver > 0 && goods.Amount >= rate.FreeOver {
+		return money.Zero(goods.Currency), nil
+	}
+	amount := rate.Amount
+	if rate.Type == RateWeight {
+		kilograms := (weightGrams + 999) / 1000
+		amount += kilograms * rate.PerKg
+	}
+	return money.New(amount, goods.Currency), nil
+}
+
+func (m *ShippingMethod) validate() error {
+	if m.Code == "" || m.Code != strings.ToLower(m.Code) || m.Name == "" || len(m.Rates) == 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidShippingMethod, m.Code)
+	}
+	for i, country := range m.Countries {
+		if len(country) != 2 {
+			return fmt.Errorf("%w: %q delivers to %q", ErrInvalidShippingMethod, m.Code, country)
+		}
+		m.Countries[i] = strings.ToUpper(country)
+	}
+	currencies := make(map[string]bool, len(m.Rates))
+	for _, r := range m.Rates {
+		if len(r.Currency) != 3 || currencies[r.Currency] || (r.Type != RateFlat && r.Type != RateWeight) ||
+			r.Amount < 0 || r.PerKg < 0 || r.FreeOver < 0 {
+			return fmt.Errorf("%w: %q rate %+v", ErrInvalidShippingMethod, m.Code, r)
+		}
+		currencies[r.Currency] = true
+	}
+	return nil
+}
+
+// DefaultShippingMethods are used unless SHIPPING_METHODS_FILE names other
+// methods.
+var DefaultShippingMethods = []ShippingMethod{
+	{
+		Code: "standard",
+		Name: "Standard",
+		Rates: []ShippingRate{
+			{Currency: "USD", Type: RateFlat, Amount: 499, FreeOver: 5000},
+			{Currency: "EUR", Type: RateFlat, Amount: 499, FreeOver: 5000},
+			{Currency: "INR", Type: RateFlat, Amount: 4900, FreeOver: 49900},
+		},
+	},
+	{
+		Code: "express",
+		Name: "Express",
+		Rates: []ShippingRate{
+			{Currency: "USD", Type: RateWeight, Amount: 999, PerKg: 200},
+			{Currency: "EUR", Type: RateWeight, Amount: 999, PerKg: 200},
+			{Currency: "INR", Type: RateWeight, Amount: 14900, PerKg: 2000},
+		},
+	},
+}
+
+// LoadShippingMethods reads the JSON array of methods in the file at path.
+func LoadShippingMethods(path string) ([]ShippingMethod, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var methods []ShippingMethod
+	if err := json.Unmarshal(data, &methods); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidShippingMethod, err)
+	}
+	return methods, nil
+}
+
+// ShippingTable holds the shipping methods orders may choose from. The first
+// method is the default.
+type ShippingTable struct {
+	methods []ShippingMethod
+}
+
+// NewShippingTable returns a ShippingTable of methods.
+func NewShippingTable(methods []ShippingMethod) (*ShippingTable, error) {
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("%w: no methods", ErrInvalidShippingMethod)
+	}
+	t := &ShippingTable{methods: make([]ShippingMethod, 0, len(methods))}
+	for _, m := range methods {
+		m.Countries = slices.Clone(m.Countries)
+		if err := m.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := t.Method(m.Code); ok {
+			return nil, fmt.Errorf("%w: duplicate method %q", ErrInvalidShippingMethod, m.Code)
+		}
+		t.methods = append(t.methods, m)
+	}
+	return t, nil
+}
+
+// Methods returns the shipping methods, the default first.
+func (t *ShippingTable) Methods() []ShippingMethod {
+	return slices.Clone(t.methods)
+}
+
+// Method returns the shipping method with code, or the default method if
+// code is empty.
+func (t *ShippingTable) Method(code string) (ShippingMethod, bool) {
+	if code == "" && len(t.methods) > 0 {
+		return t.methods[0], true
+	}
+	for _, m := range t.methods {
+		if m.Code == code {
+			return m, true
+		}
+	}
+	return ShippingMethod{}, false
+}
+
+// FulfilmentStatuses returns the statuses an order in status moves through
+// once something of it was shipped: processing, then shipped when all its
+// physical products were shipped, then delivered when all its shipments were
+// delivered.
+func FulfilmentStatuses(status string, allShipped, allDelivered bool) []string {
+	var next []string
+	if status == StatusPaid {
+		next, status = append(next, StatusProcessing), StatusProcessing
+	}
+	if status == StatusProcessing && allShipped {
+		next, status = append(next, StatusShipped), StatusShipped
+	}
+	if status == StatusShipped && allShipped && allDelivered {
+		next = append(next, StatusDelivered)
+	}
+	return next
+}
+
+// Shipment statuses, in the order a parcel goes through them. Delivered and
+// returned shipments are final.
+const (
+	ShipmentShipped        = "shipped"
+	ShipmentInTransit      = "in_transit"
+	ShipmentOutForDelivery = "out_for_delivery"
+	ShipmentDelivered      = "delivered"
+	ShipmentReturned       = "returned"
+)
+
+var shipmentStatuses = []string{ShipmentShipped, ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered, ShipmentReturned}
+
+// IsValidShipmentStatus reports whether status is a shipment status.
+func IsValidShipmentStatus(status string) bool {
+	return slices.Contains(shipmentStatuses, status)
+}
+
+// IsFinalShipmentStatus reports whether a shipment in status can no longer
+// change.
+func IsFinalShipmentStatus(status string) bool {
+	return status == ShipmentDelivered || status == ShipmentReturned
+}
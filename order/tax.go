@@ -0,0 +1,136 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/tax"
+)
+
+// MaxTaxRate is 100% in basis points.
+const MaxTaxRate = 10000
+
+// TaxRule is the rate of tax on products of a tax category delivered to a
+// country, or to one of its regions.
+type TaxRule struct {
+	// Country is an ISO 3166-1 alpha-2 code such as "DE".
+	Country string `json:"country"`
+	// Region is a subdivision code such as "CA"; empty for the whole country.
+	Region string `json:"region,omitempty"`
+	// Category is a pkg/tax category; empty for every category.
+	Category string `json:"category,omitempty"`
+	// Rate is in basis points: 1900 is 19%.
+	Rate int64 `json:"rate"`
+}
+
+// TaxLine is a line of an order as far as tax is concerned: its tax category
+// and what it costs after discounts.
+type TaxLine struct {
+	Category string
+	Amount   money.Money
+}
+
+// LineTax is the tax on one line of an order.
+type LineTax struct {
+	Rate   int64
+	Amount money.Money
+}
+
+// TaxCalculator works out the tax on the lines of an order delivered to a
+// country and region, one LineTax per line.
+type TaxCalculator interface {
+	Calculate(ctx context.Context, country, region string, lines []TaxLine) ([]LineTax, error)
+}
+
+// DefaultTaxRules are used unless TAX_RULES_FILE names other rules.
+var DefaultTaxRules = []TaxRule{
+	{Country: "DE", Rate: 1900},
+	{Country: "DE", Category: tax.PhysicalReduced, Rate: 700},
+	{Country: "FR", Rate: 2000},
+	{Country: "FR", Category: tax.PhysicalReduced, Rate: 550},
+	{Country: "GB", Rate: 2000},
+	{Country: "GB", Category: tax.PhysicalReduced, Rate: 0},
+	{Country: "IN", Rate: 1800},
+	{Country: "IN", Category: tax.PhysicalReduced, Rate: 500},
+	{Country: "US", Region: "CA", Rate: 725},
+	{Country: "US", Region: "CA", Category: tax.Digital, Rate: 0},
+	{Country: "US", Region: "CA", Category: tax.EBook, Rate: 0},
+	{Country: "US", Region: "CA", Category: tax.Saas, Rate: 0},
+	{Country: "US", Region: "NY", Rate: 400},
+	{Country: "US", Region: "NY", Category: tax.PhysicalReduced, Rate: 0},
+}
+
+// LoadTaxRules reads the JSON array of rules in the file at path.
+func LoadTaxRules(path string) ([]TaxRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []TaxRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTaxRule, err)
+	}
+	return rules, nil
+}
+
+type ruleKey struct {
+	country, region, category string
+}
+
+// RuleTable charges tax by a table of rules. The most specific rule for a
+// line wins: one for its region and category, then its region, then its
+// country and category, then its country. Lines no rule matches, and exempt
+// lines, are not taxed.
+type RuleTable struct {
+	rates map[ruleKey]int64
+}
+
+// NewRuleTable returns a RuleTable of rules.
+func NewRuleTable(rules []TaxRule) (*RuleTable, error) {
+	t := &RuleTable{rates: make(map[ruleKey]int64, len(rules))}
+	for _, r := range rules {
+		key := ruleKey{strings.ToUpper(r.Country), strings.ToUpper(r.Region), r.Category}
+		if len(key.country) != 2 || r.Rate < 0 || r.Rate > MaxTaxRate || (r.Category != "" && !tax.Valid(r.Category)) {
+			return nil, fmt.Errorf("%w: %+v", ErrInvalidTaxRule, r)
+		}
+		if _, ok := t.rates[key]; ok {
+			return nil, fmt.Errorf("%w: duplicate rule %+v", ErrInvalidTaxRule, r)
+		}
+		t.rates[key] = r.Rate
+	}
+	return t, nil
+}
+
+// Rate returns the rate in basis points of tax on products of category
+// delivered to country and region.
+func (t *RuleTable) Rate(country, region, category string) int64 {
+	category = tax.CategoryOf(category)
+	if category == tax.Exempt {
+		return 0
+	}
+	country, region = strings.ToUpper(country), strings.ToUpper(region)
+	keys := []ruleKey{{country, "", category}, {country, "", ""}}
+	if region != "" {
+		keys = append([]ruleKey{{country, region, category}, {country, region, ""}}, keys...)
+	}
+	for _, key := range keys {
+		if rate, ok := t.rates[key]; ok {
+			return rate
+		}
+	}
+	return 0
+}
+
+// Calculate rounds the tax of each line half up to the minor unit.
+func (t *RuleTable) Calculate(_ context.Context, country, region string, lines []TaxLine) ([]LineTax, error) {
+	taxes := make([]LineTax, len(lines))
+	for i, l := range lines {
+		rate := t.Rate(country, region, l.Category)
+		taxes[i] = LineTax{Rate: rate, Amount: l.Amount.MulRate(float64(rate)/MaxTaxRate, money.HalfUp)}
+	}
+	return taxes, nil
+}
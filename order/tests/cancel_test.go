@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/order"
+	"github.com/abhiii71/orderStream/order/config"
+	"github.com/abhiii71/orderStream/order/internal"
+	"github.com/abhiii71/orderStream/order/models"
+)
+
+const customerId = 5
+
+func customerOrder(id uint, status string, age time.Duration) models.Order {
+	return models.Order{
+		ID:                 id,
+		AccountID:          customerId,
+		Status:             status,
+		CreatedAt:          time.Now().UTC().Add(-age),
+		StockReservationId: "order-reservation",
+	}
+}
+
+// cancellations decodes the order_status_changed events staged for
+// cancelled orders.
+func cancellations(t *testing.T, repo *memoryRepository) []models.OrderStatusChangedData {
+	t.Helper()
+
+	var cancelled []models.OrderStatusChangedData
+	for _, message := range repo.staged() {
+		var event models.OrderStatusChangedEvent
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Data.To == order.StatusCancelled {
+			cancelled = append(cancelled, event.Data)
+		}
+	}
+	return cancelled
+}
+
+func TestCancelOrderRejectsOtherAccounts(t *testing.T) {
+	repo := newMemoryRepository(customerOrder(1, order.StatusPaid, time.Hour))
+	service := internal.NewOrderService(repo, nil, nil)
+
+	if _, err := service.CancelOrder(context.Background(), 1, customerId+1, ""); !errors.Is(err, order.ErrNotOrderOwner) {
+		t.Errorf("CancelOrder by another account error = %v, want ErrNotOrderOwner", err)
+	}
+	if status := repo.status(1); status != order.StatusPaid {
+		t.Errorf("status = %q, want the order left paid", status)
+	}
+}
+
+func TestCancelOrderAfterShipmentIsRefused(t *testing.T) {
+	for _, status := range []string{order.StatusShipped, order.StatusDelivered, order.StatusRefunded} {
+		repo := newMemoryRepository(customerOrder(1, status, time.Hour))
+		service := internal.NewOrderService(repo, nil, nil)
+
+		if _, err := service.CancelOrder(context.Background(), 1, customerId, ""); !errors.Is(err, order.ErrInvalidTransition) {
+			t.Errorf("%s: CancelOrder error = %v, want ErrInvalidTransition", status, err)
+		}
+		if len(repo.staged()) != 0 {
+			t.Errorf("%s: staged %d events, want none", status, len(repo.staged()))
+		}
+	}
+}
+
+func TestCancelOrderReleasesStockAndRefundsPaidOrders(t *testing.T) {
+	for _, from := range []string{order.StatusPendingPayment, order.StatusPaid, order.StatusProcessing} {
+		repo := newMemoryRepository(customerOrder(1, from, time.Hour))
+		service := internal.NewOrderService(repo, nil, nil)
+
+		cancelled, err := service.CancelOrder(context.Background(), 1, customerId, "")
+		if err != nil {
+			t.Fatalf("%s: %v", from, err)
+		}
+		if cancelled.Status != order.StatusCancelled || len(cancelled.History) != 1 {
+			t.Errorf("%s: cancelled = %+v, want it cancelled with its history", from, cancelled)
+		}
+
+		// the product service releases the reservation of the event, and the
+		// payment service refunds orders cancelled from paid or processing
+		events := cancellations(t, repo)
+		if len(events) != 1 {
+			t.Fatalf("%s: staged %d cancellations, want 1", from, len(events))
+		}
+		if got := events[0]; got.From != from || got.StockReservationId != "order-reservation" || got.Reason != "cancelled by customer" {
+			t.Errorf("%s: event = %+v, want the reservation and the status it was cancelled from", from, got)
+		}
+	}
+}
+
+func TestCancelUnpaidOrdersWaitsForThePaymentTimeout(t *testing.T) {
+	repo := newMemoryRepository(
+		customerOrder(1, order.StatusPendingPayment, config.PaymentTimeout+time.Minute),
+		customerOrder(2, order.StatusPendingPayment, config.PaymentTimeout-time.Minute),
+		customerOrder(3, order.StatusPaid, config.PaymentTimeout+time.Minute),
+	)
+	service := internal.NewOrderService(repo, nil, nil)
+
+	cancelled, err := service.CancelUnpaidOrders(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled != 1 {
+		t.Errorf("cancelled %d orders, want 1", cancelled)
+	}
+
+	var statuses []string
+	for id := range uint64(3) {
+		statuses = append(statuses, repo.status(id+1))
+	}
+	if want := []string{order.StatusCancelled, order.StatusPendingPayment, order.StatusPaid}; !slices.Equal(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+	if events := cancellations(t, repo); len(events) != 1 || events[0].Reason != "payment timeout" {
+		t.Errorf("cancellations = %+v, want the expired order's", events)
+	}
+}
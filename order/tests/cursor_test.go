@@ -0,0 +1,28 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/order"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := order.Cursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC), ID: 42}
+	got, err := order.DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CreatedAt.Equal(c.CreatedAt) || got.ID != c.ID {
+		t.Errorf("DecodeCursor(Encode()) = %+v, want %+v", got, c)
+	}
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, s := range []string{"not base64!", "bm9jb2xvbg", "YWJjOjE"} {
+		if _, err := order.DecodeCursor(s); !errors.Is(err, order.ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
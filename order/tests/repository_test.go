@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/abhiii71/orderStream/order"
+	"github.com/abhiii71/orderStream/order/internal"
+	"github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/outbox"
+)
+
+// memoryRepository keeps orders, their status history and the messages
+// staged with status changes in memory. Methods the tests do not need are
+// left to the embedded nil OrderRepository and panic if called.
+type memoryRepository struct {
+	internal.OrderRepository
+
+	mu       sync.Mutex
+	orders   map[uint64]models.Order
+	history  map[uint64][]models.StatusChange
+	messages []outbox.Message
+}
+
+func newMemoryRepository(orders ...models.Order) *memoryRepository {
+	r := &memoryRepository{orders: map[uint64]models.Order{}, history: map[uint64][]models.StatusChange{}}
+	for _, o := range orders {
+		r.orders[uint64(o.ID)] = o
+	}
+	return r
+}
+
+func (r *memoryRepository) GetOrder(_ context.Context, orderId uint64) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[orderId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &o, nil
+}
+
+func (r *memoryRepository) UpdateOrderStatus(_ context.Context, orderId uint64, change models.StatusChange, messages ...outbox.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o, ok := r.orders[orderId]
+	if !ok || o.Status != change.From {
+		return order.ErrStatusConflict
+	}
+	o.Status = change.To
+	r.orders[orderId] = o
+	r.history[orderId] = append(r.history[orderId], change)
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+func (r *memoryRepository) GetStatusHistory(_ context.Context, orderIds ...uint64) (map[uint64][]models.StatusChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := make(map[uint64][]models.StatusChange, len(orderIds))
+	for _, id := range orderIds {
+		history[id] = r.history[id]
+	}
+	return history, nil
+}
+
+func (r *memoryRepository) GetUnpaidOrderIds(_ context.Context, createdBefore time.Time) ([]uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []uint64
+	for id, o := range r.orders {
+		if o.Status == order.StatusPendingPayment && o.CreatedAt.Before(createdBefore) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *memoryRepository) status(orderId uint64) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.orders[orderId].Status
+}
+
+// staged returns the messages staged with status changes so far.
+func (r *memoryRepository) staged() []outbox.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]outbox.Message(nil), r.messages...)
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abhiii71/orderStream/order"
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+func TestShippingQuote(t *testing.T) {
+	flat := order.ShippingMethod{Code: "standard", Name: "Standard", Rates: []order.ShippingRate{
+		{Currency: "USD", Type: order.RateFlat, Amount: 499, FreeOver: 5000},
+	}}
+	weight := order.ShippingMethod{Code: "express", Name: "Express", Countries: []string{"US"}, Rates: []order.ShippingRate{
+		{Currency: "USD", Type: order.RateWeight, Amount: 999, PerKg: 200},
+	}}
+
+	tests := []struct {
+		name        string
+		method      order.ShippingMethod
+		country     string
+		goods       int64
+		weightGrams int64
+		want        int64
+	}{
+		{"flat", flat, "DE", 4999, 20000, 499},
+		{"free over threshold", flat, "DE", 5000, 0, 0},
+		{"weightless", weight, "US", 1000, 0, 999},
+		{"started kilogram", weight, "us", 1000, 1001, 1399},
+		{"whole kilograms", weight, "US", 1000, 3000, 1599},
+	}
+	for _, tt := range tests {
+		got, err := tt.method.Quote(tt.country, money.New(tt.goods, "USD"), tt.weightGrams)
+		if err != nil {
+			t.Errorf("%s: Quote error = %v", tt.name, err)
+			continue
+		}
+		if want := money.New(tt.want, "USD"); got != want {
+			t.Errorf("%s: Quote = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	if _, err := weight.Quote("DE", money.New(1000, "USD"), 0); !errors.Is(err, order.ErrShippingUnavailable) {
+		t.Errorf("Quote to another country error = %v, want ErrShippingUnavailable", err)
+	}
+	if _, err := flat.Quote("DE", money.New(1000, "EUR"), 0); !errors.Is(err, order.ErrShippingUnavailable) {
+		t.Errorf("Quote in another currency error = %v, want ErrShippingUnavailable", err)
+	}
+}
+
+func TestNewShippingTable(t *testing.T) {
+	table, err := order.NewShippingTable(order.DefaultShippingMethods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := table.Method(""); !ok || m.Code != order.DefaultShippingMethods[0].Code {
+		t.Errorf("Method(\"\") = %q, want the first method", m.Code)
+	}
+	if _, ok := table.Method("overnight"); ok {
+		t.Error("Method(\"overnight\") found a method")
+	}
+
+	rate := []order.ShippingRate{{Currency: "USD", Type: order.RateFlat, Amount: 499}}
+	cases := [][]order.ShippingMethod{
+		nil,
+		{{Code: "Standard", Name: "Standard", Rates: rate}},
+		{{Code: "standard", Name: "Standard"}},
+		{{Code: "standard", Name: "Standard", Countries: []string{"USA"}, Rates: rate}},
+		{{Code: "standard", Name: "Standard", Rates: []order.ShippingRate{{Currency: "USD", Type: "zone", Amount: 499}}}},
+		{{Code: "standard", Name: "Standard", Rates: append(rate, rate...)}},
+		{{Code: "standard", Name: "Standard", Rates: rate}, {Code: "standard", Name: "Other", Rates: rate}},
+	}
+	for _, methods := range cases {
+		if _, err := order.NewShippingTable(methods); !errors.Is(err, order.ErrInvalidShippingMethod) {
+			t.Errorf("NewShippingTable(%+v) error = %v, want ErrInvalidShippingMethod", methods, err)
+		}
+	}
+}
+
+func TestFulfilmentStatuses(t *testing.T) {
+	tests := []struct {
+		status                   string
+		allShipped, allDelivered bool
+		want                     []string
+	}{
+		{order.StatusPaid, false, false, []string{order.StatusProcessing}},
+		{order.StatusPaid, true, false, []string{order.StatusProcessing, order.StatusShipped}},
+		{order.StatusPaid, true, true, []string{order.StatusProcessing, order.StatusShipped, order.StatusDelivered}},
+		{order.StatusProcessing, false, true, nil},
+		{order.StatusShipped, true, true, []string{order.StatusDelivered}},
+		{order.StatusDelivered, true, true, nil},
+		{order.StatusCancelled, true, true, nil},
+	}
+	for _, tt := range tests {
+		got := order.FulfilmentStatuses(tt.status, tt.allShipped, tt.allDelivered)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("FulfilmentStatuses(%q, %t, %t) = %v, want %v", tt.status, tt.allShipped, tt.allDelivered, got, tt.want)
+		}
+	}
+}
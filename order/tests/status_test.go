@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/abhiii71/orderStream/order"
+)
+
+func TestOrderStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{order.StatusPendingPayment, order.StatusPaid, true},
+		{order.StatusPendingPayment, order.StatusCancelled, true},
+		{order.StatusPendingPayment, order.StatusShipped, false},
+		{order.StatusPaid, order.StatusProcessing, true},
+		{order.StatusProcessing, order.StatusShipped, true},
+		{order.StatusShipped, order.StatusDelivered, true},
+		{order.StatusShipped, order.StatusCancelled, false},
+		{order.StatusDelivered, order.StatusRefunded, true},
+		{order.StatusCancelled, order.StatusPaid, false},
+		{order.StatusRefunded, order.StatusPaid, false},
+		{order.StatusPaid, order.StatusPaid, false},
+	}
+	for _, tt := range tests {
+		if got := order.CanTransition(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("CanTransition(%s, %s) = %v", tt.from, tt.to, got)
+		}
+	}
+
+	if !order.IsValidStatus(order.StatusRefunded) || order.IsValidStatus("Success") {
+		t.Error("IsValidStatus accepts the wrong statuses")
+	}
+}
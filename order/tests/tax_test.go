@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abhiii71/orderStream/order"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/tax"
+)
+
+func TestTaxRatePrecedence(t *testing.T) {
+	table, err := order.NewRuleTable([]order.TaxRule{
+		{Country: "US", Rate: 500},
+		{Country: "US", Category: tax.Digital, Rate: 300},
+		{Country: "US", Region: "CA", Rate: 725},
+		{Country: "US", Region: "CA", Category: tax.Saas, Rate: 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		country, region, category string
+		want                      int64
+	}{
+		{"US", "CA", tax.Saas, 0},
+		{"US", "CA", tax.Digital, 725},
+		{"us", "ca", "", 725},
+		{"US", "NY", tax.Digital, 300},
+		{"US", "", tax.Physical, 500},
+		{"US", "CA", tax.Exempt, 0},
+		{"DE", "", tax.Physical, 0},
+	}
+	for _, tt := range tests {
+		if got := table.Rate(tt.country, tt.region, tt.category); got != tt.want {
+			t.Errorf("Rate(%q, %q, %q) = %d, want %d", tt.country, tt.region, tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestTaxCalculateRoundsHalfUp(t *testing.T) {
+	table, err := order.NewRuleTable(order.DefaultTaxRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taxes, err := table.Calculate(context.Background(), "DE", "", []order.TaxLine{
+		{Category: tax.Physical, Amount: money.New(1050, "EUR")},
+		{Category: tax.PhysicalReduced, Amount: money.New(150, "EUR")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 199.5 and 10.5 cents
+	want := []order.LineTax{
+		{Rate: 1900, Amount: money.New(200, "EUR")},
+		{Rate: 700, Amount: money.New(11, "EUR")},
+	}
+	for i := range want {
+		if taxes[i] != want[i] {
+			t.Errorf("line %d tax = %+v, want %+v", i, taxes[i], want[i])
+		}
+	}
+}
+
+func TestNewRuleTableRejectsInvalidRules(t *testing.T) {
+	cases := [][]order.TaxRule{
+		{{Country: "USA", Rate: 500}},
+		{{Country: "US", Rate: order.MaxTaxRate + 1}},
+		{{Country: "US", Category: "food", Rate: 500}},
+		{{Country: "US", Rate: 500}, {Country: "us", Rate: 600}},
+	}
+	for _, rules := range cases {
+		if _, err := order.NewRuleTable(rules); !errors.Is(err, order.ErrInvalidTaxRule) {
+			t.Errorf("NewRuleTable(%+v) error = %v, want ErrInvalidTaxRule", rules, err)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"log"
 
+	"github.com/abhiii71/orderStream/payment/models"
 	"github.com/abhiii71/orderStream/payment/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -44,15 +46,20 @@ func (c *Client) CreateCustomerPortalSession(ctx context.Context, userId uint64,
 	return res.Value, nil
 }
 
-func (c *Client) CreateCheckoutSession(ctx context.Context, orderId, userId int, name, email, redirectUrl string, products []*pb.CartItem) (string, error) {
-	res, err := c.service.CreateCheckoutSession(ctx, &pb.CheckoutRequest{
+func (c *Client) CreateCheckoutSession(ctx context.Context, orderId, userId int, name, email, redirectUrl string, products []*pb.CartItem, expectedTotal *money.Money) (string, error) {
+	request := &pb.CheckoutRequest{
 		UserId:      uint64(userId),
 		Name:        name,
 		Email:       email,
 		RedirectURL: redirectUrl,
 		Products:    products,
 		OrderId:     uint64(orderId),
-	})
+	}
+	if expectedTotal != nil {
+		request.ExpectedTotal = money.ToProto(*expectedTotal)
+	}
+
+	res, err := c.service.CreateCheckoutSession(ctx, request)
 	if err != nil {
 		log.Println(err)
 		return "", err
@@ -60,3 +67,28 @@ func (c *Client) CreateCheckoutSession(ctx context.Context, orderId, userId int,
 
 	return res.Value, nil
 }
+
+// CreateRefund refunds amount of the payment of an order, or everything that
+// is left to refund if amount is nil.
+func (c *Client) CreateRefund(ctx context.Context, orderId uint64, amount *money.Money, reason string) (*models.Refund, error) {
+	request := &pb.CreateRefundRequest{OrderId: orderId, Reason: reason}
+	if amount != nil {
+		request.Amount = money.ToProto(*amount)
+	}
+
+	res, err := c.service.CreateRefund(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded := money.FromProto(res.Amount)
+	return &models.Refund{
+		RefundId:  res.Id,
+		OrderId:   res.OrderId,
+		Amount:    refunded.Amount,
+		Currency:  refunded.Currency,
+		Reason:    res.Reason,
+		Status:    res.Status,
+		CreatedAt: res.CreatedAt.AsTime(),
+	}, nil
+}
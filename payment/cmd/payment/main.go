@@ -5,15 +5,21 @@ import (
 	"database/sql"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/IBM/sarama"
+	order "github.com/abhiii71/orderStream/order/client"
 	"github.com/abhiii71/orderStream/payment/config"
 	"github.com/abhiii71/orderStream/payment/internal"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/abhiii71/orderStream/pkg/inbox"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/pkg/outbox"
 	"github.com/joho/godotenv"
 	"github.com/tinrab/retry"
 
-
 	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver
 )
 
@@ -23,7 +29,10 @@ func main() {
 		log.Println(".env file not found!")
 	}
 
-	var repository internal.PaymentRepository
+	var (
+		db         *sql.DB
+		repository internal.PaymentRepository
+	)
 
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -34,7 +43,7 @@ func main() {
 
 	// Retry connecting to DB
 	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
-		db, err := sql.Open("pgx", dbURL)
+		db, err = sql.Open("pgx", dbURL)
 		if err != nil {
 			log.Println("DB connection error:", err)
 			return err
@@ -55,22 +64,105 @@ func main() {
 
 	defer repository.Close()
 
-	// Setup Kafka consumer
-	var consumer sarama.Consumer
+	// stop consuming on shutdown so the group rebalances right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Setup Kafka. Events are stored with the transactions and relayed from
+	// the outbox; product and order events that fail to apply are retried
+	// through the retry topics before they end up in the dead letter topic.
+	store := outbox.NewPostgresStore(db)
+	defer store.Close()
+
+	// refunds report the new payment status of their order
+	orderClient, err := order.NewClient(config.OrderServiceURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer orderClient.Close()
+
+	dodoClient := internal.NewDodoClient(config.DodoAPIKEY, config.DodoTestMode)
+	service := internal.NewPaymentService(dodoClient, repository, orderClient)
+
+	var eventConsumer *internal.EventConsumer
 	if config.KafkaBrokers != "" {
+		var (
+			producer sarama.SyncProducer
+			consumer *kafka.Consumer
+		)
 		retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
-			kafkaConfig := sarama.NewConfig()
-			kafkaConfig.Consumer.Return.Errors = true
+			producer, err = kafka.NewSyncProducer(config.KafkaBrokers)
+			if err != nil {
+				log.Printf("Failed to create Kafka producer: %v", err)
+			}
+			return
+		})
+		defer producer.Close()
 
-			consumer, err = sarama.NewConsumer([]string{config.KafkaBrokers}, kafkaConfig)
+		retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+			consumer, err = kafka.NewConsumer(config.KafkaBrokers, config.ConsumerGroup)
 			if err != nil {
 				log.Printf("Failed to create Kafka consumer: %v", err)
 			}
 			return
 		})
+		consumer.EnableRetries(kafka.DefaultRetryPolicy(), producer)
+
+		events := inbox.New(db, config.ConsumerGroup)
+		eventConsumer = internal.NewEventConsumer(consumer, events, service)
+		go pruneInbox(ctx, events)
+
+		go outbox.NewRelay(store, outbox.NewKafkaPublisher(producer)).Run(ctx)
 	}
-	dodoClient := internal.NewDodoClient(config.DodoAPIKEY, config.DodoTestMode)
-	service := internal.NewPaymentService(dodoClient, repository)
 
-	log.Fatal(internal.StartServers(service, consumer, config.OrderServiceURL, config.GrpcPort, config.WebhookPort))
+	keys := idempotency.NewPostgresStore(db, config.IdempotencyKeyTTL)
+	go pruneIdempotencyKeys(ctx, keys)
+
+	if err := internal.StartServers(ctx, service, eventConsumer, keys, config.OrderServiceURL, config.GrpcPort, config.WebhookPort); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// pruneInbox forgets processed events once redeliveries are no longer
+// expected, well after the last retry.
+func pruneInbox(ctx context.Context, events *inbox.Inbox) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := events.Prune(ctx, time.Now().Add(-config.InboxRetention))
+		if err != nil {
+			log.Printf("Failed to prune inbox: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d processed events from the inbox", pruned)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pruneIdempotencyKeys deletes idempotency keys once their responses are no
+// longer replayed.
+func pruneIdempotencyKeys(ctx context.Context, keys *idempotency.PostgresStore) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := keys.Prune(ctx)
+		if err != nil {
+			log.Printf("Failed to prune idempotency keys: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d expired idempotency keys", pruned)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
@@ -1,16 +1,32 @@
 package config
 
-import "os"
+import (
+	"os"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+)
 
 var (
-	DatabaseURL        string
-	DodoAPIKEY         string
-	DodoWebhookSecret  string
-	DodoCheckoutURL    string
-	DodoTestMode       bool
-	OrderServiceURL    string
-	KafkaBrokers       string
+	DatabaseURL       string
+	DodoAPIKEY        string
+	DodoWebhookSecret string
+	DodoCheckoutURL   string
+	DodoTestMode      bool
+	OrderServiceURL   string
+	KafkaBrokers      string
+	// ConsumerGroup is the Kafka consumer group shared by all replicas.
+	ConsumerGroup      string
 	ProductEventsTopic string
+	PaymentEventsTopic string
+	// OrderEventsTopic is consumed to refund cancelled orders.
+	OrderEventsTopic string
+	// InboxRetention is how long processed event ids are kept to detect
+	// redeliveries.
+	InboxRetention time.Duration
+	// IdempotencyKeyTTL is how long the response to a request sent with an
+	// idempotency key is replayed.
+	IdempotencyKeyTTL time.Duration
 )
 
 const (
@@ -26,9 +42,29 @@ func init() {
 	DodoCheckoutURL = os.Getenv("DODO_CHECKOUT_URL")
 	DodoTestMode = os.Getenv("DODO_TEST_MODE") == "true"
 	KafkaBrokers = os.Getenv("KAFKA_BOOTSTRAP_SERVERS")
+	ConsumerGroup = os.Getenv("KAFKA_CONSUMER_GROUP")
+	if ConsumerGroup == "" {
+		ConsumerGroup = "payment-service"
+	}
 	ProductEventsTopic = os.Getenv("PRODUCT_EVENTS_TOPIC")
 	if ProductEventsTopic == "" {
 		ProductEventsTopic = "product_events"
 	}
+	PaymentEventsTopic = os.Getenv("PAYMENT_EVENTS_TOPIC")
+	if PaymentEventsTopic == "" {
+		PaymentEventsTopic = "payment_events"
+	}
+	OrderEventsTopic = os.Getenv("ORDER_EVENTS_TOPIC")
+	if OrderEventsTopic == "" {
+		OrderEventsTopic = "order_events"
+	}
+	InboxRetention = 7 * 24 * time.Hour
+	if retention, err := time.ParseDuration(os.Getenv("INBOX_RETENTION")); err == nil {
+		InboxRetention = retention
+	}
+	IdempotencyKeyTTL = idempotency.DefaultTTL
+	if ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL")); err == nil {
+		IdempotencyKeyTTL = ttl
+	}
 
 }
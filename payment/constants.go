@@ -1,8 +1,26 @@
 package payment
 
+import "errors"
+
+var (
+	ErrTotalMismatch       = errors.New("cart total does not match the expected total")
+	ErrNotPaid             = errors.New("order has no successful payment")
+	ErrInvalidRefundAmount = errors.New("refund amount must be positive and at most the amount left to refund")
+	ErrRefundRejected      = errors.New("payment provider rejected the refund")
+)
+
 type TransactionStatus string
 
 const (
-	Failed  = TransactionStatus("Failed")
-	Success = TransactionStatus("Success")
+	Failed            = TransactionStatus("Failed")
+	Success           = TransactionStatus("Success")
+	PartiallyRefunded = TransactionStatus("PartiallyRefunded")
+	Refunded          = TransactionStatus("Refunded")
+)
+
+// Refund statuses, as reported by the payment provider.
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
 )
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS products (
+    id BIGSERIAL PRIMARY KEY,
+    product_id VARCHAR(255) UNIQUE NOT NULL,       -- id in the product service
+    dodo_product_id VARCHAR(255) NOT NULL,         -- id at the payment provider
+    price BIGINT NOT NULL,                         -- minor units
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
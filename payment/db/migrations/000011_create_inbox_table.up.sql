@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS inbox (
+    consumer VARCHAR(255) NOT NULL,                -- each consumer deduplicates on its own
+    event_id VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_processed_at ON inbox (consumer, processed_at);
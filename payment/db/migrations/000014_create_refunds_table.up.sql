@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS refunds (
+    id BIGSERIAL PRIMARY KEY,
+    refund_id VARCHAR(255) UNIQUE NOT NULL,        -- refund id at the payment provider
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    order_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL,                        -- minor units of currency
+    currency VARCHAR(10) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL DEFAULT 'pending', -- pending, succeeded or failed
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_transaction_id ON refunds (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds (order_id);
@@ -0,0 +1,4 @@
+-- products registered before pricing versions existed are repriced at their
+-- next checkout
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS pricing_version INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DELETE FROM refunds WHERE refund_id IS NULL;
+ALTER TABLE refunds ALTER COLUMN refund_id SET NOT NULL;
@@ -0,0 +1,3 @@
+-- refunds are stored before the payment provider is asked for them, so they
+-- have no provider id until it answers
+ALTER TABLE refunds ALTER COLUMN refund_id DROP NOT NULL;
@@ -38,4 +38,20 @@ type WebhookData struct {
 	SettledAmount int64                 `json:"settled_amount"`
 	Currency      dodopayments.Currency `json:"currency"`
 	Status        string                `json:"status"`
-}
\ No newline at end of file
+}
+
+// RefundWebhookPayload is the body of refund.succeeded and refund.failed
+// webhooks.
+type RefundWebhookPayload struct {
+	Type string            `json:"type"`
+	Data RefundWebhookData `json:"data"`
+}
+
+type RefundWebhookData struct {
+	RefundID  string                `json:"refund_id"`
+	PaymentID string                `json:"payment_id"`
+	Amount    int64                 `json:"amount"`
+	Currency  dodopayments.Currency `json:"currency"`
+	Reason    string                `json:"reason"`
+	Status    string                `json:"status"`
+}
@@ -3,107 +3,182 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
 
 	"github.com/IBM/sarama"
-	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/abhiii71/orderStream/order"
+	orderModels "github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/payment/config"
+	"github.com/abhiii71/orderStream/pkg/events"
+	eventspb "github.com/abhiii71/orderStream/pkg/events/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/inbox"
 	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/pkg/money"
 )
 
+// EventConsumer applies product events to the payment provider and refunds
+// cancelled orders. Events are processed through the inbox, so redelivered
+// events are skipped.
 type EventConsumer struct {
-	consumer sarama.Consumer
+	consumer *kafka.Consumer
+	inbox    *inbox.Inbox
 	service  PaymentService
 }
 
-func NewEventConsumer(consumer sarama.Consumer, service PaymentService) *EventConsumer {
+func NewEventConsumer(consumer *kafka.Consumer, inbox *inbox.Inbox, service PaymentService) *EventConsumer {
 	return &EventConsumer{
 		consumer: consumer,
+		inbox:    inbox,
 		service:  service,
 	}
 }
 
-func (ec *EventConsumer) GetConsumer() sarama.Consumer {
-	return ec.consumer
+// Close leaves the consumer group.
+func (ec *EventConsumer) Close() error {
+	return ec.consumer.Close()
 }
 
-func (ec *EventConsumer) StartProductEventConsumer(ctx context.Context) error {
-	return kafka.StartEventConsumer(ctx, ec, "product_events", ec.handleProductEvent)
+// Start consumes product and order events until ctx is cancelled.
+func (ec *EventConsumer) Start(ctx context.Context) error {
+	return ec.consumer.Consume(ctx, []string{config.ProductEventsTopic, config.OrderEventsTopic}, ec.handleEvent)
 }
 
-func (ec *EventConsumer) handleProductEvent(partition int32, pc sarama.PartitionConsumer) {
-	for {
-		select {
-		case message := <-pc.Messages():
-			if message == nil {
-				continue
-			}
-
-			var event models.ProductEvent
-			if err := json.Unmarshal(message.Value, &event); err != nil {
-				log.Printf("failed to unmarshal product event: %v ", err)
-				continue
-			}
-
-			switch event.Type {
-			case "product_created":
-				ec.handleProductCreated(event)
-			case "product_updated":
-				ec.handleProductUpdated(event)
-			case "product_deleted":
-				ec.handleProductDeleted(event)
-			default:
-				log.Printf("Unknown event type: %s", event.Type)
-			}
-
-		case err := <-pc.Errors():
-			if err != nil {
-				log.Printf("kafka consumer error: %v", err)
-			}
-		}
+func (ec *EventConsumer) handleEvent(ctx context.Context, message *sarama.ConsumerMessage) error {
+	if kafka.OriginalTopic(message) == config.OrderEventsTopic {
+		return ec.handleOrderEvent(ctx, message)
 	}
+	return ec.handleProductEvent(ctx, message)
 }
 
-func (ec *EventConsumer) handleProductCreated(event models.ProductEvent) {
-	if event.Data.ProductID == nil || event.Data.Name == nil || event.Data.Price == nil {
-		log.Printf("invalid product created event: missing required fields")
-		return
+// handleOrderEvent refunds orders cancelled after they were paid. Orders
+// cancelled while waiting for payment have nothing to refund.
+func (ec *EventConsumer) handleOrderEvent(ctx context.Context, message *sarama.ConsumerMessage) error {
+	var event orderModels.OrderStatusChangedEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("failed to decode order event: %w", err))
+	}
+
+	data := event.Data
+	if event.Type != orderModels.OrderStatusChanged || data.To != order.StatusCancelled ||
+		(data.From != order.StatusPaid && data.From != order.StatusProcessing) {
+		return nil
 	}
 
-	log.Printf("Payment service received  product created event: ID=%s, Name=%s, Price=%.2f",
-		*event.Data.ProductID, *event.Data.Name, *event.Data.Price)
+	// an order is cancelled at most once
+	id := fmt.Sprintf("%s/%d/%s", event.Type, data.OrderId, data.To)
+	processed, err := ec.inbox.Process(ctx, id, func(ctx context.Context) error {
+		log.Printf("Refunding cancelled order %d", data.OrderId)
+		return ec.service.RefundOrder(ctx, data.OrderId, data.Reason)
+	})
+	if err == nil && !processed {
+		log.Printf("Skipping order event %s: already processed", id)
+	}
+	return err
+}
 
-	ctx := context.Background()
-	err := ec.service.RegisterProduct(ctx, *event.Data.Name, int64(*event.Data.Price*100), "", *event.Data.ProductID)
+func (ec *EventConsumer) handleProductEvent(ctx context.Context, message *sarama.ConsumerMessage) error {
+	event, err := events.DecodeProductEvent(message.Value)
 	if err != nil {
-		log.Printf("failed to register product with payment provider: %v", err)
+		return kafka.Permanent(fmt.Errorf("failed to decode product event: %w", err))
+	}
+
+	id := eventID(event, message)
+	processed, err := ec.inbox.Process(ctx, id, func(ctx context.Context) error {
+		return ec.applyProductEvent(ctx, event)
+	})
+	if err == nil && !processed {
+		log.Printf("Skipping product event %s: already processed", id)
 	}
+	return err
 }
 
-func (ec *EventConsumer) handleProductUpdated(event models.ProductEvent) {
-	if event.Data.ProductID == nil {
-		log.Printf("invalid product updated event: missing product ID")
-		return
+// eventID identifies an event in the inbox. Legacy events carry no id and
+// are identified by where they were first published instead.
+func eventID(event *eventspb.ProductEvent, message *sarama.ConsumerMessage) string {
+	if event.EventId != "" {
+		return event.EventId
+	}
+
+	topic, partition, offset := message.Topic, strconv.Itoa(int(message.Partition)), strconv.FormatInt(message.Offset, 10)
+	for _, h := range message.Headers {
+		switch string(h.Key) {
+		case kafka.HeaderOriginalTopic:
+			topic = string(h.Value)
+		case kafka.HeaderOriginalPartition:
+			partition = string(h.Value)
+		case kafka.HeaderOriginalOffset:
+			offset = string(h.Value)
+		}
 	}
+	return fmt.Sprintf("%s/%s/%s", topic, partition, offset)
+}
 
-	log.Printf("Payment service received product updated event: ID=%s", *event.Data.ProductID)
-	ctx := context.Background()
-	err := ec.service.UpdateProduct(ctx, *event.Data.ProductID, *event.Data.Name, int64(*event.Data.Price*100))
+func (ec *EventConsumer) applyProductEvent(ctx context.Context, event *eventspb.ProductEvent) error {
+	// Products are only registered with the payment provider once they
+	// are published; drafts never reach checkout.
+	switch event.Type {
+	case events.ProductCreated:
+		// wait for product_published
+		return nil
+	case events.ProductPublished:
+		return ec.handleProductPublished(ctx, event)
+	case events.ProductUpdated:
+		return ec.handleProductUpdated(ctx, event)
+	case events.ProductArchived, events.ProductDeleted:
+		return ec.handleProductDeleted(ctx, event)
+	default:
+		log.Printf("Unknown event type: %s", event.Type)
+		return nil
+	}
+}
+
+func (ec *EventConsumer) handleProductPublished(ctx context.Context, event *eventspb.ProductEvent) error {
+	after := event.GetAfter()
+	if after.GetName() == "" || after.GetPrice() == nil {
+		return kafka.Permanent(errors.New("invalid product published event: missing required fields"))
+	}
+
+	price := money.FromProto(after.Price)
+	log.Printf("Payment service received product published event: ID=%s, Name=%s, Price=%s",
+		event.ProductId, after.Name, price)
+
+	_, err := ec.service.RegisterProduct(ctx, after.Name, price.Amount, price.Currency, after.GetTaxCategory(), "", event.ProductId)
 	if err != nil {
-		log.Printf("failed to update product with payment provider: %v", err)
+		return fmt.Errorf("failed to register product with payment provider: %w", err)
 	}
+	return nil
 }
 
-func (ec *EventConsumer) handleProductDeleted(event models.ProductEvent) {
-	if event.Data.ProductID == nil {
-		log.Printf("invalid product deleted event: missing product ID")
-		return
+func (ec *EventConsumer) handleProductUpdated(ctx context.Context, event *eventspb.ProductEvent) error {
+	after := event.GetAfter()
+	if after.GetName() == "" || after.GetPrice() == nil {
+		return kafka.Permanent(errors.New("invalid product updated event: missing required fields"))
 	}
 
-	log.Printf("Payment service received product deleted event: ID=%s", *event.Data.ProductID)
+	// legacy events may carry no status; those were only sent for published products
+	if after.Status != "" && after.Status != "published" {
+		// drafts and archived products are not registered with the provider
+		return nil
+	}
+
+	log.Printf("Payment service received product updated event: ID=%s", event.ProductId)
+	price := money.FromProto(after.Price)
+	err := ec.service.UpdateProduct(ctx, event.ProductId, after.Name, price.Amount, price.Currency, after.GetTaxCategory())
+	if err != nil {
+		return fmt.Errorf("failed to update product with payment provider: %w", err)
+	}
+	return nil
+}
+
+func (ec *EventConsumer) handleProductDeleted(ctx context.Context, event *eventspb.ProductEvent) error {
+	log.Printf("Payment service received product deleted event: ID=%s", event.ProductId)
 
-	ctx := context.Background()
-	err := ec.service.DeleteProduct(ctx, *event.Data.ProductID)
+	err := ec.service.DeleteProduct(ctx, event.ProductId)
 	if err != nil {
-		log.Printf("failed to delete product with payment provider: %v", err)
+		return fmt.Errorf("failed to delete product with payment provider: %w", err)
 	}
+	return nil
 }
@@ -2,9 +2,18 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"log"
+	"strconv"
 
 	order "github.com/abhiii71/orderStream/order/client"
+	"github.com/abhiii71/orderStream/payment"
 	"github.com/abhiii71/orderStream/payment/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -12,15 +21,58 @@ type grpcServer struct {
 	pb.UnimplementedPaymentServiceServer
 	service     PaymentService
 	orderClient order.Client
+	keys        idempotency.Store
 }
 
+// CreateCheckoutSession starts a checkout. Retries of a request sent with an
+// idempotency key get the checkout session created by the first one.
 func (s *grpcServer) CreateCheckoutSession(ctx context.Context, request *pb.CheckoutRequest) (*wrapperspb.StringValue, error) {
+	scope := "CreateCheckoutSession/account:" + strconv.FormatUint(request.GetUserId(), 10)
+	response, err := idempotency.Do(ctx, s.keys, scope, idempotency.IncomingKey(ctx), request, func(ctx context.Context) (*wrapperspb.StringValue, error) {
+		return s.createCheckoutSession(ctx, request)
+	})
+	if err != nil {
+		return nil, paymentError(err)
+	}
+	return response, nil
+}
+
+func (s *grpcServer) createCheckoutSession(ctx context.Context, request *pb.CheckoutRequest) (*wrapperspb.StringValue, error) {
 	customer, err := s.service.FindOrCreateCustomer(ctx, request.UserId, request.Name, request.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	checkoutUrl, err := s.service.CreateCheckoutSession(ctx, request.UserId, customer.CustomerId, request.RedirectURL, request.Products, request.OrderId)
+	var expectedTotal *money.Money
+	if request.ExpectedTotal != nil {
+		total := money.FromProto(request.ExpectedTotal)
+		expectedTotal = &total
+	}
+
+	// orders are paid for less the discount of each line, plus tax and shipping
+	var (
+		discounts     map[string]money.Money
+		tax, shipping money.Money
+	)
+	if request.OrderId != 0 {
+		o, err := s.orderClient.GetOrder(ctx, request.OrderId, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		discounts = make(map[string]money.Money, len(o.Discounts))
+		for _, d := range o.Discounts {
+			sum, ok := discounts[d.ProductId]
+			if !ok {
+				sum = money.Zero(d.Amount.Currency)
+			}
+			if discounts[d.ProductId], err = sum.Add(d.Amount); err != nil {
+				return nil, err
+			}
+		}
+		tax, shipping = o.Tax, o.Shipping
+	}
+
+	checkoutUrl, err := s.service.CreateCheckoutSession(ctx, request.UserId, customer.CustomerId, request.RedirectURL, request.Products, request.OrderId, expectedTotal, discounts, tax, shipping)
 	if err != nil {
 		return nil, err
 	}
@@ -45,3 +97,41 @@ func (s *grpcServer) CreateCustomerPortalSession(ctx context.Context, request *p
 		Value: link,
 	}, nil
 }
+
+func (s *grpcServer) CreateRefund(ctx context.Context, request *pb.CreateRefundRequest) (*pb.Refund, error) {
+	var amount *money.Money
+	if request.Amount != nil {
+		value := money.FromProto(request.Amount)
+		amount = &value
+	}
+
+	refund, err := s.service.CreateRefund(ctx, request.GetOrderId(), amount, request.GetReason())
+	if err != nil {
+		log.Println(err)
+		return nil, paymentError(err)
+	}
+
+	return &pb.Refund{
+		Id:        refund.RefundId,
+		OrderId:   refund.OrderId,
+		Amount:    money.ToProto(money.New(refund.Amount, refund.Currency)),
+		Reason:    refund.Reason,
+		Status:    refund.Status,
+		CreatedAt: timestamppb.New(refund.CreatedAt),
+	}, nil
+}
+
+// paymentError maps payment errors to gRPC status codes.
+func paymentError(err error) error {
+	switch {
+	case errors.Is(err, payment.ErrNotPaid), errors.Is(err, payment.ErrRefundRejected):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, payment.ErrInvalidRefundAmount), errors.Is(err, payment.ErrTotalMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, idempotency.ErrInvalidKey), errors.Is(err, idempotency.ErrKeyReused):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, idempotency.ErrInProgress):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return err
+}
@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/abhiii71/orderStream/payment"
 	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/abhiii71/orderStream/pkg/inbox"
+	"github.com/abhiii71/orderStream/pkg/outbox"
 	"github.com/lib/pq"
 )
 
@@ -24,7 +27,18 @@ type PaymentRepository interface {
 	DeleteProduct(ctx context.Context, productId string) error
 
 	RegisterTransaction(ctx context.Context, transaction *models.Transaction) error
-	UpdatedTransaction(ctx context.Context, transaction *models.Transaction) error
+	GetTransactionByOrderId(ctx context.Context, orderId uint64) (*models.Transaction, error)
+	GetTransactionByPaymentId(ctx context.Context, paymentId string) (*models.Transaction, error)
+	UpdatedTransaction(ctx context.Context, transaction *models.Transaction, messages ...outbox.Message) error
+	UpdateTransactionStatus(ctx context.Context, orderId uint64, status string, messages ...outbox.Message) error
+
+	LockTransaction(ctx context.Context, transactionId uint64, fn func(ctx context.Context) error) error
+
+	SavePendingRefund(ctx context.Context, refund *models.Refund) error
+	ClaimPendingRefund(ctx context.Context, refund *models.Refund) (bool, error)
+	SaveRefund(ctx context.Context, refund *models.Refund) error
+	UpdateRefundStatus(ctx context.Context, id uint64, status string) error
+	SumRefunds(ctx context.Context, transactionId uint64, statuses ...string) (int64, error)
 }
 
 type postgresRepository struct {
@@ -39,10 +53,34 @@ func (r *postgresRepository) Close() error {
 	return r.db.Close()
 }
 
+// conn joins the transaction of the event being processed, if any.
+func (r *postgresRepository) conn(ctx context.Context) inbox.DBTX {
+	return inbox.Conn(ctx, r.db)
+}
+
+// withTx runs fn in the transaction of the event being processed, or in a
+// transaction of its own.
+func (r *postgresRepository) withTx(ctx context.Context, fn func(tx inbox.DBTX) error) error {
+	if tx, ok := r.conn(ctx).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	if err := fn(txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
 func (r *postgresRepository) GetCustomerByCustomerID(ctx context.Context, customerId string) (*models.Customer, error) {
 	query := `SELECT user_id, customer_id, billing_email, created_at FROM customers WHERE customer_id = $1`
 	var c models.Customer
-	err := r.db.QueryRowContext(ctx, query, customerId).Scan(&c.UserId, &c.CustomerId, &c.BillingEmail, &c.CreatedAt)
+	err := r.conn(ctx).QueryRowContext(ctx, query, customerId).Scan(&c.UserId, &c.CustomerId, &c.BillingEmail, &c.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, sql.ErrNoRows
@@ -55,7 +93,7 @@ func (r *postgresRepository) GetCustomerByCustomerID(ctx context.Context, custom
 func (r *postgresRepository) GetCustomerByUserId(ctx context.Context, userId uint64) (*models.Customer, error) {
 	query := `SELECT user_id, customer_id, billing_email, created_at FROM customers WHERE user_id = $1`
 	var c models.Customer
-	err := r.db.QueryRowContext(ctx, query, userId).Scan(&c.UserId, &c.CustomerId, &c.BillingEmail, &c.CreatedAt)
+	err := r.conn(ctx).QueryRowContext(ctx, query, userId).Scan(&c.UserId, &c.CustomerId, &c.BillingEmail, &c.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, sql.ErrNoRows
@@ -68,16 +106,16 @@ func (r *postgresRepository) GetCustomerByUserId(ctx context.Context, userId uin
 func (r *postgresRepository) SaveCustomer(ctx context.Context, customer *models.Customer) error {
 	query := `INSERT INTO customers (user_id, customer_id, billing_email, created_at)
 			  VALUES ($1, $2, $3, NOW())`
-	_, err := r.db.ExecContext(ctx, query, customer.UserId, customer.CustomerId, customer.BillingEmail)
+	_, err := r.conn(ctx).ExecContext(ctx, query, customer.UserId, customer.CustomerId, customer.BillingEmail)
 	return err
 }
 
 func (r *postgresRepository) GetProductByProductId(ctx context.Context, productId string) (*models.Product, error) {
-	query := `SELECT id, product_id, dodo_product_id, price, currency, created_at, updated_at
+	query := `SELECT id, product_id, dodo_product_id, price, currency, pricing_version, created_at, updated_at
 			  FROM products WHERE product_id = $1`
 	var p models.Product
-	err := r.db.QueryRowContext(ctx, query, productId).Scan(
-		&p.ID, &p.ProductID, &p.DodoProductID, &p.Price, &p.Currency, &p.CreatedAt, &p.UpdatedAt)
+	err := r.conn(ctx).QueryRowContext(ctx, query, productId).Scan(
+		&p.ID, &p.ProductID, &p.DodoProductID, &p.Price, &p.Currency, &p.PricingVersion, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, sql.ErrNoRows
@@ -92,10 +130,10 @@ func (r *postgresRepository) GetProductsByIds(ctx context.Context, productIds []
 		return nil, nil
 	}
 
-	query := fmt.Sprintf(`SELECT id, product_id, dodo_product_id, price, currency, created_at, updated_at
+	query := fmt.Sprintf(`SELECT id, product_id, dodo_product_id, price, currency, pricing_version, created_at, updated_at
 		FROM products WHERE product_id = ANY($1)`)
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(productIds))
+	rows, err := r.conn(ctx).QueryContext(ctx, query, pq.Array(productIds))
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +142,7 @@ func (r *postgresRepository) GetProductsByIds(ctx context.Context, productIds []
 	var products []*models.Product
 	for rows.Next() {
 		var p models.Product
-		err := rows.Scan(&p.ID, &p.ProductID, &p.DodoProductID, &p.Price, &p.Currency, &p.CreatedAt, &p.UpdatedAt)
+		err := rows.Scan(&p.ID, &p.ProductID, &p.DodoProductID, &p.Price, &p.Currency, &p.PricingVersion, &p.CreatedAt, &p.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -115,38 +153,191 @@ func (r *postgresRepository) GetProductsByIds(ctx context.Context, productIds []
 }
 
 func (r *postgresRepository) SaveProduct(ctx context.Context, product *models.Product) error {
-	query := `INSERT INTO products (product_id, dodo_product_id, price, currency, created_at, updated_at)
-			  VALUES ($1, $2, $3, $4, NOW(), NOW())`
-	_, err := r.db.ExecContext(ctx, query, product.ProductID, product.DodoProductID, product.Price, product.Currency)
+	query := `INSERT INTO products (product_id, dodo_product_id, price, currency, pricing_version, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW(), NOW())`
+	_, err := r.conn(ctx).ExecContext(ctx, query, product.ProductID, product.DodoProductID, product.Price, product.Currency, product.PricingVersion)
 	return err
 }
 
 func (r *postgresRepository) UpdateProduct(ctx context.Context, product *models.Product) error {
-	query := `UPDATE products SET price = $1, currency = $2, updated_at = NOW() WHERE product_id = $3`
-	_, err := r.db.ExecContext(ctx, query, product.Price, product.Currency, product.ProductID)
+	query := `UPDATE products SET price = $1, currency = $2, pricing_version = $3, updated_at = NOW() WHERE product_id = $4`
+	_, err := r.conn(ctx).ExecContext(ctx, query, product.Price, product.Currency, product.PricingVersion, product.ProductID)
 	return err
 }
 
 func (r *postgresRepository) DeleteProduct(ctx context.Context, productId string) error {
 	query := `DELETE FROM products WHERE product_id = $1`
-	_, err := r.db.ExecContext(ctx, query, productId)
+	_, err := r.conn(ctx).ExecContext(ctx, query, productId)
 	return err
 }
 
 func (r *postgresRepository) RegisterTransaction(ctx context.Context, t *models.Transaction) error {
 	query := `INSERT INTO transactions (order_id, user_id, customer_id, payment_id, total_price, settled_price, currency, status, created_at, updated_at)
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.conn(ctx).ExecContext(ctx, query,
 		t.OrderId, t.UserId, t.CustomerId, t.PaymentId,
 		t.TotalPrice, t.SettledPrice, t.Currency, t.Status,
 	)
 	return err
 }
 
-func (r *postgresRepository) UpdatedTransaction(ctx context.Context, t *models.Transaction) error {
+func (r *postgresRepository) GetTransactionByOrderId(ctx context.Context, orderId uint64) (*models.Transaction, error) {
+	return r.getTransaction(ctx, "order_id", orderId)
+}
+
+func (r *postgresRepository) GetTransactionByPaymentId(ctx context.Context, paymentId string) (*models.Transaction, error) {
+	return r.getTransaction(ctx, "payment_id", paymentId)
+}
+
+func (r *postgresRepository) getTransaction(ctx context.Context, column string, value any) (*models.Transaction, error) {
+	query := fmt.Sprintf(`SELECT id, order_id, user_id, customer_id, COALESCE(payment_id, ''), total_price, COALESCE(settled_price, 0), currency, status
+			  FROM transactions WHERE %s = $1 ORDER BY id DESC LIMIT 1`, column)
+	var t models.Transaction
+	err := r.conn(ctx).QueryRowContext(ctx, query, value).Scan(
+		&t.ID, &t.OrderId, &t.UserId, &t.CustomerId, &t.PaymentId, &t.TotalPrice, &t.SettledPrice, &t.Currency, &t.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdatedTransaction stores the new status of a transaction together with
+// the events it produces. The first webhook of an order creates its
+// transaction, and refunded transactions keep their status.
+func (r *postgresRepository) UpdatedTransaction(ctx context.Context, t *models.Transaction, messages ...outbox.Message) error {
+	txn, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
 	query := `UPDATE transactions 
-			  SET status = $1, settled_price = $2, updated_at = NOW() 
-			  WHERE order_id = $3`
-	_, err := r.db.ExecContext(ctx, query, t.Status, t.SettledPrice, t.OrderId)
+			  SET status = $1, settled_price = $2, payment_id = $3, updated_at = NOW() 
+			  WHERE order_id = $4 AND status <> ALL($5)`
+	refunded := pq.Array([]string{string(payment.PartiallyRefunded), string(payment.Refunded)})
+	res, err := txn.ExecContext(ctx, query, t.Status, t.SettledPrice, t.PaymentId, t.OrderId, refunded)
+	if err != nil {
+		return err
+	}
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		query := `INSERT INTO transactions (order_id, user_id, customer_id, payment_id, total_price, settled_price, currency, status, created_at, updated_at)
+				  SELECT $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
+				  WHERE NOT EXISTS (SELECT 1 FROM transactions WHERE order_id = $1)`
+		_, err := txn.ExecContext(ctx, query,
+			t.OrderId, t.UserId, t.CustomerId, t.PaymentId,
+			t.TotalPrice, t.SettledPrice, t.Currency, t.Status,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := outbox.Enqueue(ctx, txn, messages...); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// UpdateTransactionStatus changes the status of a transaction together with
+// the events it produces.
+func (r *postgresRepository) UpdateTransactionStatus(ctx context.Context, orderId uint64, status string, messages ...outbox.Message) error {
+	return r.withTx(ctx, func(tx inbox.DBTX) error {
+		query := `UPDATE transactions SET status = $1, updated_at = NOW() WHERE order_id = $2`
+		if _, err := tx.ExecContext(ctx, query, status, orderId); err != nil {
+			return err
+		}
+		return outbox.Enqueue(ctx, tx, messages...)
+	})
+}
+
+// LockTransaction runs fn in a database transaction that holds a lock on the
+// transaction row until fn returns, so refunds of one payment are made one
+// at a time. The repository calls of fn run in that database transaction.
+func (r *postgresRepository) LockTransaction(ctx context.Context, transactionId uint64, fn func(ctx context.Context) error) error {
+	return r.withTx(ctx, func(tx inbox.DBTX) error {
+		var id uint64
+		query := `SELECT id FROM transactions WHERE id = $1 FOR UPDATE`
+		if err := tx.QueryRowContext(ctx, query, transactionId).Scan(&id); err != nil {
+			return err
+		}
+		return fn(inbox.WithTx(ctx, tx.(*sql.Tx)))
+	})
+}
+
+// SavePendingRefund stores a refund before the payment provider is asked for
+// it. It has no provider id yet; its id and timestamps are read back into
+// refund.
+func (r *postgresRepository) SavePendingRefund(ctx context.Context, refund *models.Refund) error {
+	query := `INSERT INTO refunds (transaction_id, order_id, amount, currency, reason, status, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+			  RETURNING id, status, created_at, updated_at`
+	return r.conn(ctx).QueryRowContext(ctx, query,
+		refund.TransactionId, refund.OrderId, refund.Amount, refund.Currency, refund.Reason, payment.RefundPending,
+	).Scan(&refund.ID, &refund.Status, &refund.CreatedAt, &refund.UpdatedAt)
+}
+
+// ClaimPendingRefund gives the provider id and status of refund to a pending
+// refund of its transaction still waiting for the provider's answer: the
+// one with refund.ID if it is, the oldest one otherwise. It reports false if
+// the provider id is stored already or no refund is waiting. The stored
+// refund is read back into refund.
+func (r *postgresRepository) ClaimPendingRefund(ctx context.Context, refund *models.Refund) (bool, error) {
+	query := `UPDATE refunds SET refund_id = $1, status = $2,
+				amount = CASE WHEN $3 > 0 THEN $3 ELSE amount END,
+				currency = COALESCE(NULLIF($4, ''), currency),
+				updated_at = NOW()
+			  WHERE id = (
+				SELECT id FROM refunds
+				WHERE transaction_id = $5 AND refund_id IS NULL AND status = $6
+				ORDER BY id = $7 DESC, id
+				LIMIT 1
+			  ) AND NOT EXISTS (SELECT 1 FROM refunds WHERE refund_id = $1)
+			  RETURNING id, transaction_id, order_id, amount, currency, reason, status, created_at, updated_at`
+	err := r.conn(ctx).QueryRowContext(ctx, query,
+		refund.RefundId, refund.Status, refund.Amount, refund.Currency,
+		refund.TransactionId, payment.RefundPending, refund.ID,
+	).Scan(&refund.ID, &refund.TransactionId, &refund.OrderId, &refund.Amount,
+		&refund.Currency, &refund.Reason, &refund.Status, &refund.CreatedAt, &refund.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SaveRefund stores a refund, or the new status of a refund stored before.
+// Refunds only leave the pending status once, so a late response of the
+// provider does not undo the status reported by a webhook. The stored refund
+// is read back into refund.
+func (r *postgresRepository) SaveRefund(ctx context.Context, refund *models.Refund) error {
+	query := `INSERT INTO refunds (refund_id, transaction_id, order_id, amount, currency, reason, status, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+			  ON CONFLICT (refund_id) DO UPDATE SET
+				status = CASE WHEN refunds.status = $8 THEN EXCLUDED.status ELSE refunds.status END,
+				updated_at = NOW()
+			  RETURNING id, transaction_id, order_id, amount, currency, reason, status, created_at, updated_at`
+	return r.conn(ctx).QueryRowContext(ctx, query,
+		refund.RefundId, refund.TransactionId, refund.OrderId, refund.Amount,
+		refund.Currency, refund.Reason, refund.Status, payment.RefundPending,
+	).Scan(&refund.ID, &refund.TransactionId, &refund.OrderId, &refund.Amount,
+		&refund.Currency, &refund.Reason, &refund.Status, &refund.CreatedAt, &refund.UpdatedAt)
+}
+
+// UpdateRefundStatus sets the status of a pending refund.
+func (r *postgresRepository) UpdateRefundStatus(ctx context.Context, id uint64, status string) error {
+	query := `UPDATE refunds SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`
+	_, err := r.conn(ctx).ExecContext(ctx, query, status, id, payment.RefundPending)
 	return err
 }
+
+// SumRefunds adds up the refunds of a transaction in the given statuses.
+func (r *postgresRepository) SumRefunds(ctx context.Context, transactionId uint64, statuses ...string) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE transaction_id = $1 AND status = ANY($2)`
+	var total int64
+	err := r.conn(ctx).QueryRowContext(ctx, query, transactionId, pq.Array(statuses)).Scan(&total)
+	return total, err
+}
@@ -16,18 +16,23 @@ import (
 	"github.com/abhiii71/orderStream/payment/config"
 	"github.com/abhiii71/orderStream/payment/dto"
 	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/abhiii71/orderStream/pkg/tax"
 	"github.com/dodopayments/dodopayments-go"
 	"github.com/dodopayments/dodopayments-go/option"
 )
 
 type PaymentClient interface {
-	CreateProduct(ctx context.Context, name string, price int64, currency dodopayments.Currency, taxCategory dodopayments.TaxCategory, customerId, productId string) (*dodopayments.Product, error)
-	UpdateProduct(ctx context.Context, productId, name string, price int64) error
+	CreateProduct(ctx context.Context, name string, price int64, currency dodopayments.Currency, taxCategory string, customerId, productId string) (*dodopayments.Product, error)
+	UpdateProduct(ctx context.Context, productId, name string, price int64, currency dodopayments.Currency, taxCategory string) error
 	ArchiveProduct(ctx context.Context, productId string) error
+	RepriceProduct(ctx context.Context, productId string, price int64, currency dodopayments.Currency) error
+	CreateFeeProduct(ctx context.Context, name string, currency dodopayments.Currency) (*dodopayments.Product, error)
 	CreateCustomer(ctx context.Context, userId int64, name, email string) (*models.Customer, error)
 	CreateCustomerSession(ctx context.Context, customerId string) (string, error)
 	CreateCheckoutSession(ctx context.Context, userId int64, customerId string, redirect string, dodoProducts []dodopayments.CheckoutSessionRequestProductCartParam, orderId uint64) (checkoutURL string, err error)
-	HandleWebhook(w http.ResponseWriter, r *http.Request) (*models.Transaction, error)
+	GetRefundableItems(ctx context.Context, paymentId string) ([]payment.RefundableItem, error)
+	CreateRefund(ctx context.Context, paymentId string, items []payment.RefundAllocation, reason, idempotencyKey string) (*models.Refund, error)
+	HandleWebhook(w http.ResponseWriter, r *http.Request) (*models.WebhookEvent, error)
 }
 
 func NewDodoClient(apiKey string, testMode bool) PaymentClient {
@@ -50,17 +55,51 @@ type dodoClient struct {
 	client *dodopayments.Client
 }
 
-func (d *dodoClient) CreateProduct(ctx context.Context, name string, price int64, currency dodopayments.Currency, taxCategory dodopayments.TaxCategory, customerId, productId string) (*dodopayments.Product, error) {
+// pricingVersion is the way products are priced at the provider. It is
+// stored with the products registered, and products registered under an
+// older version are repriced before they are checked out:
+//
+//  1. prices include the provider's tax
+//  2. products are pay what you want, and checkouts set the amount of every
+//     line
+const pricingVersion = 2
+
+// dodoTaxCategory returns the provider's tax category for a pkg/tax category.
+// The provider has no category for physical goods; they are registered as
+// digital products, its general category, and taxed by the order service.
+func dodoTaxCategory(category string) dodopayments.TaxCategory {
+	switch tax.CategoryOf(category) {
+	case tax.EBook:
+		return dodopayments.TaxCategoryEBook
+	case tax.Saas:
+		return dodopayments.TaxCategorySaas
+	case tax.Edtech:
+		return dodopayments.TaxCategoryEdtech
+	}
+	return dodopayments.TaxCategoryDigitalProducts
+}
+
+// oneTimePrice is the price of a product at the provider. Products are pay
+// what you want, suggesting price, and checkouts set the amount of every
+// line, discounts taken off. Prices include the provider's tax, so
+// checkouts charge exactly the amounts of the order; the tax of the order
+// is a line of its own.
+func oneTimePrice(price int64, currency dodopayments.Currency) dodopayments.PriceUnionParam {
+	return dodopayments.PriceOneTimePriceParam{
+		Price:          dodopayments.F[int64](0),
+		SuggestedPrice: dodopayments.F(price),
+		Currency:       dodopayments.F(currency),
+		Discount:       dodopayments.F[int64](0),
+		PayWhatYouWant: dodopayments.F(true),
+		TaxInclusive:   dodopayments.F(true),
+	}
+}
+
+func (d *dodoClient) CreateProduct(ctx context.Context, name string, price int64, currency dodopayments.Currency, taxCategory string, customerId, productId string) (*dodopayments.Product, error) {
 	product, err := d.client.Products.New(ctx, dodopayments.ProductNewParams{
-		Name: dodopayments.F(name),
-		Price: dodopayments.F[dodopayments.PriceUnionParam](
-			dodopayments.PriceOneTimePriceParam{
-				Price:    dodopayments.F(price),
-				Currency: dodopayments.F(currency),
-				Discount: dodopayments.F[int64](0),
-			},
-		),
-		TaxCategory: dodopayments.F(taxCategory),
+		Name:        dodopayments.F(name),
+		Price:       dodopayments.F(oneTimePrice(price, currency)),
+		TaxCategory: dodopayments.F(dodoTaxCategory(taxCategory)),
 	})
 	if err != nil {
 		return nil, err
@@ -68,17 +107,29 @@ func (d *dodoClient) CreateProduct(ctx context.Context, name string, price int64
 	return product, nil
 }
 
-func (d *dodoClient) UpdateProduct(ctx context.Context, productId, name string, price int64) error {
+func (d *dodoClient) UpdateProduct(ctx context.Context, productId, name string, price int64, currency dodopayments.Currency, taxCategory string) error {
+	return d.client.Products.Update(ctx, productId, dodopayments.ProductUpdateParams{
+		Name:        dodopayments.F(name),
+		Price:       dodopayments.F(oneTimePrice(price, currency)),
+		TaxCategory: dodopayments.F(dodoTaxCategory(taxCategory)),
+	})
+}
 
+// RepriceProduct moves a product to the current pricingVersion, leaving its
+// name and tax category alone.
+func (d *dodoClient) RepriceProduct(ctx context.Context, productId string, price int64, currency dodopayments.Currency) error {
 	return d.client.Products.Update(ctx, productId, dodopayments.ProductUpdateParams{
-		Name: dodopayments.F(name),
-		Price: dodopayments.F[dodopayments.PriceUnionParam](
-			dodopayments.PriceOneTimePriceParam{
-				Price:    dodopayments.F(price),
-				Currency: dodopayments.F(dodopayments.CurrencyUsd),
-				Discount: dodopayments.F[int64](0),
-			},
-		),
+		Price: dodopayments.F(oneTimePrice(price, currency)),
+	})
+}
+
+// CreateFeeProduct creates the pay what you want product a fee such as
+// shipping or tax in currency is charged as; checkouts set the amount.
+func (d *dodoClient) CreateFeeProduct(ctx context.Context, name string, currency dodopayments.Currency) (*dodopayments.Product, error) {
+	return d.client.Products.New(ctx, dodopayments.ProductNewParams{
+		Name:        dodopayments.F(name),
+		Price:       dodopayments.F(oneTimePrice(0, currency)),
+		TaxCategory: dodopayments.F(dodopayments.TaxCategoryDigitalProducts),
 	})
 }
 
@@ -114,20 +165,22 @@ func (d *dodoClient) CreateCustomerSession(ctx context.Context, customerId strin
 }
 
 func (d *dodoClient) CreateCheckoutSession(ctx context.Context, userId int64, customerId string, redirect string, dodoProducts []dodopayments.CheckoutSessionRequestProductCartParam, orderId uint64) (checkoutURL string, err error) {
+	request := dodopayments.CheckoutSessionRequestParam{
+		Customer: dodopayments.F[dodopayments.CustomerRequestUnionParam](
+			dodopayments.AttachExistingCustomerParam{
+				CustomerID: dodopayments.F(customerId),
+			},
+		),
+		ReturnURL:   dodopayments.F(redirect),
+		ProductCart: dodopayments.F(dodoProducts),
+		Metadata: dodopayments.F(map[string]string{
+			"order_id": fmt.Sprintf("%d", orderId),
+			"user_id":  fmt.Sprintf("%d", userId),
+		}),
+	}
+
 	checkoutSession, err := d.client.CheckoutSessions.New(ctx, dodopayments.CheckoutSessionNewParams{
-		CheckoutSessionRequest: dodopayments.CheckoutSessionRequestParam{
-			Customer: dodopayments.F[dodopayments.CustomerRequestUnionParam](
-				dodopayments.AttachExistingCustomerParam{
-					CustomerID: dodopayments.F(customerId),
-				},
-			),
-			ReturnURL:   dodopayments.F(redirect),
-			ProductCart: dodopayments.F(dodoProducts),
-			Metadata: dodopayments.F(map[string]string{
-				"order_id": fmt.Sprintf("%d", orderId),
-				"user_id":  fmt.Sprintf("%d", userId),
-			}),
-		},
+		CheckoutSessionRequest: request,
 	})
 	if err != nil {
 		return "", err
@@ -136,7 +189,88 @@ func (d *dodoClient) CreateCheckoutSession(ctx context.Context, userId int64, cu
 	return checkoutSession.CheckoutURL, nil
 }
 
-func (d *dodoClient) HandleWebhook(w http.ResponseWriter, r *http.Request) (*models.Transaction, error) {
+// GetRefundableItems returns the line items of a payment with the amount
+// of each that can still be refunded.
+func (d *dodoClient) GetRefundableItems(ctx context.Context, paymentId string) ([]payment.RefundableItem, error) {
+	lineItems, err := d.client.Payments.GetLineItems(ctx, paymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]payment.RefundableItem, len(lineItems.Items))
+	for i, item := range lineItems.Items {
+		items[i] = payment.RefundableItem{ItemId: item.ItemsID, Refundable: item.RefundableAmount}
+	}
+	return items, nil
+}
+
+// CreateRefund refunds the given amounts of the line items of a payment, or
+// the whole payment if items is empty. Requests with the same idempotency
+// key make one refund. Refunds the provider turns down fail with
+// payment.ErrRefundRejected.
+func (d *dodoClient) CreateRefund(ctx context.Context, paymentId string, items []payment.RefundAllocation, reason, idempotencyKey string) (*models.Refund, error) {
+	params := dodopayments.RefundNewParams{
+		PaymentID: dodopayments.F(paymentId),
+		Reason:    dodopayments.F(reason),
+	}
+	if len(items) > 0 {
+		refundItems := make([]dodopayments.RefundNewParamsItem, len(items))
+		for i, item := range items {
+			refundItems[i] = dodopayments.RefundNewParamsItem{
+				ItemID: dodopayments.F(item.ItemId),
+				Amount: dodopayments.F(item.Amount),
+			}
+		}
+		params.Items = dodopayments.F(refundItems)
+	}
+
+	refund, err := d.client.Refunds.New(ctx, params, option.WithHeader("Idempotency-Key", idempotencyKey))
+	if rejected(err) {
+		return nil, fmt.Errorf("%w: %v", payment.ErrRefundRejected, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Refund{
+		RefundId:  refund.RefundID,
+		PaymentId: refund.PaymentID,
+		Amount:    refund.Amount,
+		Currency:  string(refund.Currency),
+		Reason:    refund.Reason,
+		Status:    refundStatus(refund.Status),
+		CreatedAt: refund.CreatedAt,
+	}, nil
+}
+
+// rejected reports whether the provider turned a request down. Timeouts,
+// conflicts, rate limits and server errors may still be followed by the
+// request going through.
+func rejected(err error) bool {
+	var apiErr *dodopayments.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500
+}
+
+// refundStatus maps provider refund statuses to ours; refunds under review
+// are still pending.
+func refundStatus(status dodopayments.RefundStatus) string {
+	switch status {
+	case dodopayments.RefundStatusSucceeded:
+		return payment.RefundSucceeded
+	case dodopayments.RefundStatusFailed:
+		return payment.RefundFailed
+	}
+	return payment.RefundPending
+}
+
+func (d *dodoClient) HandleWebhook(w http.ResponseWriter, r *http.Request) (*models.WebhookEvent, error) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return nil, errors.New("method not allowed")
@@ -163,6 +297,27 @@ func (d *dodoClient) HandleWebhook(w http.ResponseWriter, r *http.Request) (*mod
 		return nil, err
 	}
 
+	if payload.Type == string(dodopayments.WebhookEventTypeRefundSucceeded) || payload.Type == string(dodopayments.WebhookEventTypeRefundFailed) {
+		var refundPayload dto.RefundWebhookPayload
+		if err := json.Unmarshal(body, &refundPayload); err != nil {
+			http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+			return nil, err
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return &models.WebhookEvent{
+			Type: payload.Type,
+			Refund: &models.Refund{
+				RefundId:  refundPayload.Data.RefundID,
+				PaymentId: refundPayload.Data.PaymentID,
+				Amount:    refundPayload.Data.Amount,
+				Currency:  string(refundPayload.Data.Currency),
+				Reason:    refundPayload.Data.Reason,
+				Status:    refundStatus(dodopayments.RefundStatus(refundPayload.Data.Status)),
+			},
+		}, nil
+	}
+
 	transaction := &models.Transaction{
 		OrderId:      payload.Data.Metadata.OrderId,
 		UserId:       payload.Data.Metadata.UserId,
@@ -186,7 +341,7 @@ func (d *dodoClient) HandleWebhook(w http.ResponseWriter, r *http.Request) (*mod
 
 	// Return a 200 ok to acknowledge  recepit of the webhook
 	w.WriteHeader(http.StatusOK)
-	return transaction, nil
+	return &models.WebhookEvent{Type: payload.Type, Transaction: transaction}, nil
 
 }
 
@@ -8,35 +8,39 @@ import (
 	"net/http"
 	"sync"
 
-	"github.com/IBM/sarama"
 	order "github.com/abhiii71/orderStream/order/client"
 	"github.com/abhiii71/orderStream/payment/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/idempotency"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
-func StartServers(service PaymentService, consumer sarama.Consumer, orderURL string, grpcPort, webhookPort int) error {
+// StartServers runs the gRPC and webhook servers and, if consumer is set, the
+// event consumer. It returns on the first error, or once ctx is
+// cancelled and the consumer has left its group.
+func StartServers(ctx context.Context, service PaymentService, consumer *EventConsumer, keys idempotency.Store, orderURL string, grpcPort, webhookPort int) error {
 	var wg sync.WaitGroup
 	errCh := make(chan error, 3)
 
 	// Start kafka consumer if available
+	consumerDone := make(chan struct{})
 	if consumer != nil {
-		wg.Add(1)
 		go func() {
-			defer wg.Done()
-			eventConsumer := NewEventConsumer(consumer, service)
-			ctx := context.Background()
-			if err := eventConsumer.StartProductEventConsumer(ctx); err != nil {
+			defer close(consumerDone)
+			defer consumer.Close()
+			if err := consumer.Start(ctx); err != nil {
 				errCh <- fmt.Errorf("kafka consumer error: %w", err)
 			}
 		}()
+	} else {
+		close(consumerDone)
 	}
 
 	// start gRPC Server
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := ListenGRPC(service, orderURL, grpcPort); err != nil {
+		if err := ListenGRPC(service, keys, orderURL, grpcPort); err != nil {
 			errCh <- fmt.Errorf("grpc server error: %w", err)
 		}
 	}()
@@ -56,10 +60,16 @@ func StartServers(service PaymentService, consumer sarama.Consumer, orderURL str
 		close(errCh)
 	}()
 
-	return <-errCh
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		<-consumerDone
+		return nil
+	}
 }
 
-func ListenGRPC(service PaymentService, orderURL string, port int) error {
+func ListenGRPC(service PaymentService, keys idempotency.Store, orderURL string, port int) error {
 	orderClient, err := order.NewClient(orderURL)
 	if err != nil {
 		return err
@@ -76,6 +86,7 @@ func ListenGRPC(service PaymentService, orderURL string, port int) error {
 		pb.UnimplementedPaymentServiceServer{},
 		service,
 		*orderClient,
+		keys,
 	})
 	reflection.Register(serv)
 
@@ -3,62 +3,93 @@ package internal
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/abhiii71/orderStream/payment"
+	"github.com/abhiii71/orderStream/payment/config"
 	"github.com/abhiii71/orderStream/payment/models"
 	"github.com/abhiii71/orderStream/payment/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/outbox"
+	"github.com/abhiii71/orderStream/pkg/pricing"
 	"github.com/dodopayments/dodopayments-go"
 )
 
 type PaymentService interface {
-	RegisterProduct(ctx context.Context, name string, price int64, customerId, productId string) error
-	UpdateProduct(ctx context.Context, productId string, name string, price int64) error
+	RegisterProduct(ctx context.Context, name string, price int64, currency, taxCategory string, customerId, productId string) (*models.Product, error)
+	UpdateProduct(ctx context.Context, productId string, name string, price int64, currency, taxCategory string) error
 	DeleteProduct(ctx context.Context, productId string) error
 	CreateCustomerPortalSession(ctx context.Context, customer *models.Customer) (string, error)
 	FindOrCreateCustomer(ctx context.Context, userId uint64, name, email string) (*models.Customer, error)
-	CreateCheckoutSession(ctx context.Context, userId uint64, customerId, redirect string, products []*pb.CartItem, orderId uint64) (checkoutURL string, err error)
+	CreateCheckoutSession(ctx context.Context, userId uint64, customerId, redirect string, products []*pb.CartItem, orderId uint64, expectedTotal *money.Money, discounts map[string]money.Money, tax, shipping money.Money) (checkoutURL string, err error)
 	HandlePaymentWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.Transaction, error)
+	CreateRefund(ctx context.Context, orderId uint64, amount *money.Money, reason string) (*models.Refund, error)
+	RefundOrder(ctx context.Context, orderId uint64, reason string) error
+}
+
+// OrderUpdater reports payment status changes to the order service.
+type OrderUpdater interface {
+	UpdatePaymentStatus(ctx context.Context, orderId uint64, status string) error
 }
 
 type paymentService struct {
 	client            PaymentClient
 	paymentRepository PaymentRepository
+	orders            OrderUpdater
 }
 
-func NewPaymentService(client PaymentClient, paymentRepository PaymentRepository) PaymentService {
-	return &paymentService{client: client, paymentRepository: paymentRepository}
+func NewPaymentService(client PaymentClient, paymentRepository PaymentRepository, orders OrderUpdater) PaymentService {
+	return &paymentService{client: client, paymentRepository: paymentRepository, orders: orders}
 }
 
-func (ds *paymentService) RegisterProduct(ctx context.Context, name string, price int64, customerId, productId string) error {
+// RegisterProduct creates the product at the payment provider. Products
+// registered before are returned as they are.
+func (ds *paymentService) RegisterProduct(ctx context.Context, name string, price int64, currency, taxCategory string, customerId, productId string) (*models.Product, error) {
+	existing, err := ds.paymentRepository.GetProductByProductId(ctx, productId)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
 
-	// we will use USD  as currency and Digital products as tax category for now to keep it simple
-	product, err := ds.client.CreateProduct(ctx, name, price, dodopayments.CurrencyUsd, dodopayments.TaxCategoryDigitalProducts, customerId, productId)
+	dodoProduct, err := ds.client.CreateProduct(ctx, name, price, dodopayments.Currency(currency), taxCategory, customerId, productId)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return ds.paymentRepository.SaveProduct(ctx, &models.Product{
-		ProductID:     productId,
-		DodoProductID: product.ProductID,
-		Price:         product.Price.FixedPrice,
-		Currency:      string(product.Price.Currency),
-	})
+
+	product := &models.Product{
+		ProductID:      productId,
+		DodoProductID:  dodoProduct.ProductID,
+		Price:          price,
+		Currency:       currency,
+		PricingVersion: pricingVersion,
+	}
+	if err := ds.paymentRepository.SaveProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
-func (ds *paymentService) UpdateProduct(ctx context.Context, productId string, name string, price int64) error {
+func (ds *paymentService) UpdateProduct(ctx context.Context, productId string, name string, price int64, currency, taxCategory string) error {
 
-	err := ds.client.UpdateProduct(ctx, productId, name, price)
+	product, err := ds.paymentRepository.GetProductByProductId(ctx, productId)
 	if err != nil {
 		return err
 	}
 
-	product, err := ds.paymentRepository.GetProductByProductId(ctx, productId)
+	err = ds.client.UpdateProduct(ctx, product.DodoProductID, name, price, dodopayments.Currency(currency), taxCategory)
 	if err != nil {
 		return err
 	}
 
-	if product.Price != price {
-		product.Price = price
+	if product.Price != price || product.Currency != currency || product.PricingVersion != pricingVersion {
+		product.Price, product.Currency, product.PricingVersion = price, currency, pricingVersion
 		err = ds.paymentRepository.UpdateProduct(ctx, product)
 		if err != nil {
 			return err
@@ -69,7 +100,16 @@ func (ds *paymentService) UpdateProduct(ctx context.Context, productId string, n
 }
 
 func (ds *paymentService) DeleteProduct(ctx context.Context, productId string) error {
-	err := ds.client.ArchiveProduct(ctx, productId)
+	product, err := ds.paymentRepository.GetProductByProductId(ctx, productId)
+	if errors.Is(err, sql.ErrNoRows) {
+		// never registered, or already deleted
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = ds.client.ArchiveProduct(ctx, product.DodoProductID)
 	if err != nil {
 		return err
 	}
@@ -113,8 +153,10 @@ func (ds *paymentService) FindOrCreateCustomer(ctx context.Context, userId uint6
 	return customer, nil
 }
 
-// createcheckoutsession - returns url to check out page  and error
-func (ds *paymentService) CreateCheckoutSession(ctx context.Context, userId uint64, customerId, redirect string, products []*pb.CartItem, orderId uint64) (checkoutURL string, err error) {
+// createcheckoutsession - returns url to check out page  and error.
+// discounts, keyed by product id, tax and shipping are those of the order:
+// the buyer agreed to pay the cart less the discounts, plus tax and shipping.
+func (ds *paymentService) CreateCheckoutSession(ctx context.Context, userId uint64, customerId, redirect string, products []*pb.CartItem, orderId uint64, expectedTotal *money.Money, discounts map[string]money.Money, tax, shipping money.Money) (checkoutURL string, err error) {
 	productIds := make([]string, len(products))
 	productQuantities := make(map[string]uint64, len(products))
 
@@ -128,29 +170,424 @@ func (ds *paymentService) CreateCheckoutSession(ctx context.Context, userId uint
 		return "", err
 	}
 
-	var dodoProducts []dodopayments.CheckoutSessionRequestProductCartParam
+	subtotal, err := cartTotal(modelsProducts, productQuantities)
+	if err != nil {
+		return "", err
+	}
+	discount := money.Zero(subtotal.Currency)
+	for productId, d := range discounts {
+		if _, ok := productQuantities[productId]; !ok {
+			return "", fmt.Errorf("%w: discount on product %s that is not in the cart", payment.ErrTotalMismatch, productId)
+		}
+		if discount, err = discount.Add(d); err != nil {
+			return "", err
+		}
+	}
+	total, err := orderTotal(subtotal, discount, tax, shipping)
+	if err != nil {
+		return "", err
+	}
+	if expectedTotal != nil && total != *expectedTotal {
+		return "", fmt.Errorf("%w: cart costs %s, expected %s", payment.ErrTotalMismatch, total, *expectedTotal)
+	}
 
+	// every line is charged the exact amount it costs after its discount;
+	// the provider's percentage discounts would round it differently
+	var dodoProducts []dodopayments.CheckoutSessionRequestProductCartParam
+	var charged int64
 	for _, product := range modelsProducts {
+		quantity := int64(productQuantities[product.ProductID])
+		if quantity == 0 {
+			continue
+		}
+		if err := ds.reprice(ctx, product); err != nil {
+			return "", err
+		}
+
+		lineTotal := product.Price*quantity - discounts[product.ProductID].Amount
+		if lineTotal < 0 {
+			return "", fmt.Errorf("%w: discount on product %s exceeds its price", payment.ErrTotalMismatch, product.ProductID)
+		}
+		for _, line := range splitLine(lineTotal, quantity) {
+			dodoProducts = append(dodoProducts, dodopayments.CheckoutSessionRequestProductCartParam{
+				ProductID: dodopayments.F(product.DodoProductID),
+				Quantity:  dodopayments.F(line.quantity),
+				Amount:    dodopayments.F(line.unitAmount),
+			})
+		}
+		charged += lineTotal
+	}
+
+	// tax and shipping are charged as lines of their own, which discounts
+	// leave out. Prices include the provider's tax, so the tax charged is
+	// the tax of the order.
+	fees := []struct {
+		kind, name string
+		amount     money.Money
+	}{
+		{"tax", "Tax", tax},
+		{"shipping", "Shipping", shipping},
+	}
+	for _, fee := range fees {
+		if fee.amount.Amount <= 0 {
+			continue
+		}
+		feeProduct, err := ds.feeProduct(ctx, fee.kind, fee.name, fee.amount.Currency)
+		if err != nil {
+			return "", err
+		}
 		dodoProducts = append(dodoProducts, dodopayments.CheckoutSessionRequestProductCartParam{
-			ProductID: dodopayments.F(product.DodoProductID),
-			Quantity:  dodopayments.F(int64(productQuantities[product.ProductID])),
+			ProductID: dodopayments.F(feeProduct.DodoProductID),
+			Quantity:  dodopayments.F[int64](1),
+			Amount:    dodopayments.F(fee.amount.Amount),
 		})
-
+		charged += fee.amount.Amount
 	}
 
+	if charged != total.Amount {
+		return "", fmt.Errorf("%w: checkout charges %s, order costs %s", payment.ErrTotalMismatch, money.New(charged, total.Currency), total)
+	}
 	return ds.client.CreateCheckoutSession(ctx, int64(userId), customerId, redirect, dodoProducts, orderId)
 }
 
+// checkoutLine is a part of a cart line charged at one unit amount.
+type checkoutLine struct {
+	quantity, unitAmount int64
+}
+
+// splitLine charges total over quantity units. The provider takes an amount
+// per unit, so units that do not divide total evenly are charged a minor
+// unit more than the rest.
+func splitLine(total, quantity int64) []checkoutLine {
+	unitAmount, rest := total/quantity, total%quantity
+	var lines []checkoutLine
+	if quantity > rest {
+		lines = append(lines, checkoutLine{quantity: quantity - rest, unitAmount: unitAmount})
+	}
+	if rest > 0 {
+		lines = append(lines, checkoutLine{quantity: rest, unitAmount: unitAmount + 1})
+	}
+	return lines
+}
+
+// reprice moves a product registered under an older pricingVersion to the
+// current one.
+func (ds *paymentService) reprice(ctx context.Context, product *models.Product) error {
+	if product.PricingVersion >= pricingVersion {
+		return nil
+	}
+	if err := ds.client.RepriceProduct(ctx, product.DodoProductID, product.Price, dodopayments.Currency(product.Currency)); err != nil {
+		return err
+	}
+	product.PricingVersion = pricingVersion
+	return ds.paymentRepository.UpdateProduct(ctx, product)
+}
+
+// feeProduct returns the product a fee of kind in currency is charged as,
+// registering it with the payment provider the first time.
+func (ds *paymentService) feeProduct(ctx context.Context, kind, name, currency string) (*models.Product, error) {
+	productId := kind + ":" + currency
+	existing, err := ds.paymentRepository.GetProductByProductId(ctx, productId)
+	if err == nil {
+		return existing, ds.reprice(ctx, existing)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	dodoProduct, err := ds.client.CreateFeeProduct(ctx, name, dodopayments.Currency(currency))
+	if err != nil {
+		return nil, err
+	}
+	product := &models.Product{ProductID: productId, DodoProductID: dodoProduct.ProductID, Currency: currency, PricingVersion: pricingVersion}
+	if err := ds.paymentRepository.SaveProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// cartTotal adds up the registered prices of the cart.
+func cartTotal(products []*models.Product, quantities map[string]uint64) (money.Money, error) {
+	var lines []pricing.Line
+	for _, product := range products {
+		lines = append(lines, pricing.Line{
+			UnitPrice: money.New(product.Price, product.Currency),
+			Quantity:  uint32(quantities[product.ProductID]),
+		})
+	}
+	return pricing.Total(lines...)
+}
+
+// orderTotal is what the cart costs less discount, plus tax and shipping.
+func orderTotal(subtotal, discount, tax, shipping money.Money) (money.Money, error) {
+	total := subtotal
+	var err error
+	if discount.Amount > 0 {
+		if total, err = total.Sub(discount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	if tax.Amount > 0 {
+		if total, err = total.Add(tax); err != nil {
+			return money.Money{}, err
+		}
+	}
+	if shipping.Amount > 0 {
+		if total, err = total.Add(shipping); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// HandlePaymentWebhook applies a webhook of the payment provider and returns
+// the transaction whose status it changed, or nil.
 func (ds *paymentService) HandlePaymentWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.Transaction, error) {
-	updatedTransaction, err := ds.client.HandleWebhook(w, r)
+	event, err := ds.client.HandleWebhook(w, r)
 	if err != nil {
 		return nil, err
 	}
+	if event.Refund != nil {
+		return ds.applyRefund(ctx, event.Refund)
+	}
 
-	err = ds.paymentRepository.UpdatedTransaction(ctx, updatedTransaction)
+	updatedTransaction := event.Transaction
+	message, err := transactionUpdated(updatedTransaction)
+	if err != nil {
+		return nil, err
+	}
+	err = ds.paymentRepository.UpdatedTransaction(ctx, updatedTransaction, message)
 	if err != nil {
 		return nil, err
 	}
 
 	return updatedTransaction, err
 }
+
+// CreateRefund refunds amount of the payment of an order, or all that is
+// left to refund if amount is nil. The refund is stored pending before the
+// provider is asked for it, and pending refunds count as refunded, so
+// refunds never add up to more than was paid, even if the provider's answer
+// is lost; the refund webhook settles it then. Once the refund goes through,
+// the new payment status is reported to the order service.
+func (ds *paymentService) CreateRefund(ctx context.Context, orderId uint64, amount *money.Money, reason string) (*models.Refund, error) {
+	transaction, err := ds.paymentRepository.GetTransactionByOrderId(ctx, orderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, payment.ErrNotPaid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// concurrent refunds of the payment wait here, so the refunded total
+	// read below holds until the new refund is stored
+	var (
+		pending *models.Refund
+		items   []payment.RefundAllocation
+	)
+	err = ds.paymentRepository.LockTransaction(ctx, transaction.ID, func(ctx context.Context) error {
+		pending, items, err = ds.reserveRefund(ctx, orderId, amount, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := ds.client.CreateRefund(ctx, transaction.PaymentId, items, reason, refundKey(pending.ID))
+	if errors.Is(err, payment.ErrRefundRejected) {
+		if err := ds.paymentRepository.UpdateRefundStatus(ctx, pending.ID, payment.RefundFailed); err != nil {
+			log.Printf("error failing refund %d of order %d: %v", pending.ID, orderId, err)
+		}
+		return nil, err
+	}
+	if err != nil {
+		// the provider may still make the refund, which stays pending
+		// until its webhook arrives
+		return nil, err
+	}
+
+	var updated *models.Transaction
+	refund.ID = pending.ID
+	err = ds.paymentRepository.LockTransaction(ctx, transaction.ID, func(ctx context.Context) error {
+		transaction, err := ds.paymentRepository.GetTransactionByOrderId(ctx, orderId)
+		if err != nil {
+			return err
+		}
+		updated, err = ds.recordRefund(ctx, transaction, refund)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if updated != nil {
+		if err := ds.orders.UpdatePaymentStatus(ctx, updated.OrderId, updated.Status); err != nil {
+			log.Printf("error reporting refund of order %d: %v", updated.OrderId, err)
+		}
+	}
+	return refund, nil
+}
+
+// refundKey is the idempotency key the provider is asked for a stored
+// refund with.
+func refundKey(id uint64) string {
+	return "refund_" + strconv.FormatUint(id, 10)
+}
+
+// reserveRefund stores a pending refund while the transaction is locked,
+// and returns it with the line items it is taken from.
+func (ds *paymentService) reserveRefund(ctx context.Context, orderId uint64, amount *money.Money, reason string) (*models.Refund, []payment.RefundAllocation, error) {
+	transaction, err := ds.paymentRepository.GetTransactionByOrderId(ctx, orderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if transaction.Status != string(payment.Success) && transaction.Status != string(payment.PartiallyRefunded) {
+		return nil, nil, payment.ErrNotPaid
+	}
+
+	refunded, err := ds.paymentRepository.SumRefunds(ctx, transaction.ID, payment.RefundPending, payment.RefundSucceeded)
+	if err != nil {
+		return nil, nil, err
+	}
+	remaining := transaction.TotalPrice - refunded
+
+	value := remaining
+	if amount != nil {
+		if amount.Currency != transaction.Currency {
+			return nil, nil, fmt.Errorf("%w: payment was made in %s", payment.ErrInvalidRefundAmount, transaction.Currency)
+		}
+		value = amount.Amount
+	}
+	if value <= 0 || value > remaining {
+		return nil, nil, payment.ErrInvalidRefundAmount
+	}
+
+	// partial refunds are taken from the line items of the payment
+	var items []payment.RefundAllocation
+	if value < transaction.TotalPrice {
+		refundable, err := ds.client.GetRefundableItems(ctx, transaction.PaymentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if items, err = payment.AllocateRefund(refundable, value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	refund := &models.Refund{
+		TransactionId: transaction.ID,
+		OrderId:       transaction.OrderId,
+		PaymentId:     transaction.PaymentId,
+		Amount:        value,
+		Currency:      transaction.Currency,
+		Reason:        reason,
+	}
+	if err := ds.paymentRepository.SavePendingRefund(ctx, refund); err != nil {
+		return nil, nil, err
+	}
+	return refund, items, nil
+}
+
+// RefundOrder refunds what is left of the payment of an order. Orders
+// without a successful payment, or refunded already, are left alone.
+func (ds *paymentService) RefundOrder(ctx context.Context, orderId uint64, reason string) error {
+	_, err := ds.CreateRefund(ctx, orderId, nil, reason)
+	if errors.Is(err, payment.ErrNotPaid) || errors.Is(err, payment.ErrInvalidRefundAmount) {
+		return nil
+	}
+	return err
+}
+
+// applyRefund records the outcome of a refund reported by a webhook.
+func (ds *paymentService) applyRefund(ctx context.Context, refund *models.Refund) (*models.Transaction, error) {
+	transaction, err := ds.paymentRepository.GetTransactionByPaymentId(ctx, refund.PaymentId)
+	if err != nil {
+		return nil, fmt.Errorf("refund %s: payment %s: %w", refund.RefundId, refund.PaymentId, err)
+	}
+
+	var updated *models.Transaction
+	err = ds.paymentRepository.LockTransaction(ctx, transaction.ID, func(ctx context.Context) error {
+		// read again, a refund made meanwhile may have changed the status
+		transaction, err := ds.paymentRepository.GetTransactionByPaymentId(ctx, refund.PaymentId)
+		if err != nil {
+			return err
+		}
+		updated, err = ds.recordRefund(ctx, transaction, refund)
+		return err
+	})
+	return updated, err
+}
+
+// recordRefund stores the provider's answer for a refund, from its response
+// or a webhook, while the transaction is locked, and returns the transaction
+// if the refund changed its status. A refund the provider reports for the
+// first time settles a pending refund still waiting for its answer,
+// refund.ID if it is one; refunds made at the provider directly are added.
+func (ds *paymentService) recordRefund(ctx context.Context, transaction *models.Transaction, refund *models.Refund) (*models.Transaction, error) {
+	refund.TransactionId, refund.OrderId = transaction.ID, transaction.OrderId
+	claimed, err := ds.paymentRepository.ClaimPendingRefund(ctx, refund)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		if err := ds.paymentRepository.SaveRefund(ctx, refund); err != nil {
+			return nil, err
+		}
+	}
+
+	if refund.Status != payment.RefundSucceeded {
+		return nil, nil
+	}
+	return ds.updateRefundedStatus(ctx, transaction)
+}
+
+// updateRefundedStatus marks a transaction partially or fully refunded once
+// its refunds succeed, and returns it if its status changed.
+func (ds *paymentService) updateRefundedStatus(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error) {
+	refunded, err := ds.paymentRepository.SumRefunds(ctx, transaction.ID, payment.RefundSucceeded)
+	if err != nil {
+		return nil, err
+	}
+
+	status := string(payment.PartiallyRefunded)
+	if refunded >= transaction.TotalPrice {
+		status = string(payment.Refunded)
+	}
+	if transaction.Status == status {
+		return nil, nil
+	}
+
+	transaction.Status = status
+	message, err := transactionUpdated(transaction)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.paymentRepository.UpdateTransactionStatus(ctx, transaction.OrderId, status, message); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// transactionUpdated is the event published when a transaction changes.
+func transactionUpdated(t *models.Transaction) (outbox.Message, error) {
+	payload, err := json.Marshal(models.TransactionEvent{
+		Type: "transaction_updated",
+		Data: models.TransactionEventData{
+			OrderId:      t.OrderId,
+			UserId:       t.UserId,
+			PaymentId:    t.PaymentId,
+			Status:       t.Status,
+			SettledPrice: t.SettledPrice,
+			Currency:     t.Currency,
+		},
+	})
+	if err != nil {
+		return outbox.Message{}, err
+	}
+
+	return outbox.Message{
+		Topic:   config.PaymentEventsTopic,
+		Key:     strconv.FormatUint(t.OrderId, 10),
+		Payload: payload,
+	}, nil
+}
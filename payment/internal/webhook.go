@@ -7,6 +7,9 @@ import (
 	"time"
 
 	order "github.com/abhiii71/orderStream/order/client"
+	"github.com/abhiii71/orderStream/payment"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type WebhookServer struct {
@@ -23,8 +26,15 @@ func (s *WebhookServer) HandlePaymentWebhook(w http.ResponseWriter, r *http.Requ
 		log.Println(err.Error())
 		return
 	}
+	if transaction == nil {
+		return
+	}
 
-	err = s.orderClient.UpdateOrderStatus(ctx, transaction.OrderId, transaction.Status)
+	err = s.orderClient.UpdatePaymentStatus(ctx, transaction.OrderId, transaction.Status)
+	if status.Code(err) == codes.FailedPrecondition && transaction.Status == string(payment.Success) {
+		// the order was cancelled before it was paid for
+		err = s.service.RefundOrder(ctx, transaction.OrderId, "order was cancelled before payment")
+	}
 	if err != nil {
 		log.Println(err.Error())
 	}
@@ -1,14 +1,17 @@
 package models
 
-type ProductEventData struct {
-	ProductID   *string  `json:"product_id"`
-	Name        *string  `json:"name"`
-	Description *string  `json:"description"`
-	Price       *float64 `json:"price"`
-	AccountID   *int     `json:"accountID"`
+type TransactionEventData struct {
+	OrderId      uint64 `json:"order_id"`
+	UserId       uint64 `json:"user_id"`
+	PaymentId    string `json:"payment_id"`
+	Status       string `json:"status"`
+	SettledPrice int64  `json:"settled_price"`
+	Currency     string `json:"currency"`
 }
 
-type ProductEvent struct {
-	Type string           `json:"type"`
-	Data ProductEventData `json:"data"`
+// TransactionEvent is published to the payment events topic whenever the
+// payment provider reports a new transaction status.
+type TransactionEvent struct {
+	Type string               `json:"type"`
+	Data TransactionEventData `json:"data"`
 }
@@ -8,6 +8,9 @@ type Product struct {
 	DodoProductID string
 	Price         int64
 	Currency      string
+	// PricingVersion is the way the product is priced at the provider;
+	// products of older versions are repriced before they are checked out.
+	PricingVersion int
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
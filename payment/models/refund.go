@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Refund struct {
+	ID uint64
+	// RefundId is the id of the refund at the payment provider.
+	RefundId      string
+	TransactionId uint64
+	OrderId       uint64
+	PaymentId     string
+	Amount        int64
+	Currency      string
+	Reason        string
+	Status        string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
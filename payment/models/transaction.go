@@ -1,6 +1,7 @@
 package models
 
 type Transaction struct {
+	ID           uint64
 	OrderId      uint64
 	UserId       uint64
 	CustomerId   string
@@ -10,3 +11,11 @@ type Transaction struct {
 	Currency     string
 	Status       string
 }
+
+// WebhookEvent is a webhook of the payment provider. Payment events carry a
+// Transaction, refund events a Refund.
+type WebhookEvent struct {
+	Type        string
+	Transaction *Transaction
+	Refund      *Refund
+}
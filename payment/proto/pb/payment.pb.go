@@ -7,8 +7,10 @@
 package pb
 
 import (
+	pb "github.com/abhiii71/orderStream/pkg/money/proto/pb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
@@ -75,13 +77,15 @@ func (x *CartItem) GetQuantity() uint64 {
 }
 
 type CheckoutRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint64                 `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	RedirectURL   string                 `protobuf:"bytes,4,opt,name=redirectURL,proto3" json:"redirectURL,omitempty"`
-	Products      []*CartItem            `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
-	OrderId       uint64                 `protobuf:"varint,6,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      uint64                 `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email       string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	RedirectURL string                 `protobuf:"bytes,4,opt,name=redirectURL,proto3" json:"redirectURL,omitempty"`
+	Products    []*CartItem            `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
+	OrderId     uint64                 `protobuf:"varint,6,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	// total the buyer was shown; checkout is refused if the cart costs anything else
+	ExpectedTotal *pb.Money `protobuf:"bytes,7,opt,name=expectedTotal,proto3" json:"expectedTotal,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -158,6 +162,13 @@ func (x *CheckoutRequest) GetOrderId() uint64 {
 	return 0
 }
 
+func (x *CheckoutRequest) GetExpectedTotal() *pb.Money {
+	if x != nil {
+		return x.ExpectedTotal
+	}
+	return nil
+}
+
 type CustomerPortalRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        uint64                 `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
@@ -218,30 +229,189 @@ func (x *CustomerPortalRequest) GetEmail() string {
 	return ""
 }
 
+type CreateRefundRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId uint64                 `protobuf:"varint,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	// amount to refund; everything that is left to refund when unset
+	Amount        *pb.Money `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string    `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRefundRequest) Reset() {
+	*x = CreateRefundRequest{}
+	mi := &file_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRefundRequest) ProtoMessage() {}
+
+func (x *CreateRefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRefundRequest.ProtoReflect.Descriptor instead.
+func (*CreateRefundRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateRefundRequest) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CreateRefundRequest) GetAmount() *pb.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *CreateRefundRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Refund struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       uint64                 `protobuf:"varint,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Amount        *pb.Money              `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Refund) Reset() {
+	*x = Refund{}
+	mi := &file_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Refund) ProtoMessage() {}
+
+func (x *Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Refund.ProtoReflect.Descriptor instead.
+func (*Refund) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Refund) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Refund) GetOrderId() uint64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Refund) GetAmount() *pb.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Refund) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Refund) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Refund) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 var File_payment_proto protoreflect.FileDescriptor
 
 const file_payment_proto_rawDesc = "" +
 	"\n" +
-	"\rpayment.proto\x12\x02pb\x1a\x1egoogle/protobuf/wrappers.proto\"D\n" +
+	"\rpayment.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\x1a\vmoney.proto\"D\n" +
 	"\bCartItem\x12\x1c\n" +
 	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x04R\bquantity\"\xb9\x01\n" +
+	"\bquantity\x18\x02 \x01(\x04R\bquantity\"\xed\x01\n" +
 	"\x0fCheckoutRequest\x12\x16\n" +
 	"\x06userId\x18\x01 \x01(\x04R\x06userId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12 \n" +
 	"\vredirectURL\x18\x04 \x01(\tR\vredirectURL\x12(\n" +
 	"\bproducts\x18\x05 \x03(\v2\f.pb.CartItemR\bproducts\x12\x18\n" +
-	"\aorderId\x18\x06 \x01(\x04R\aorderId\"v\n" +
+	"\aorderId\x18\x06 \x01(\x04R\aorderId\x122\n" +
+	"\rexpectedTotal\x18\a \x01(\v2\f.money.MoneyR\rexpectedTotal\"v\n" +
 	"\x15CustomerPortalRequest\x12\x16\n" +
 	"\x06userId\x18\x01 \x01(\x04R\x06userId\x12\x17\n" +
 	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x19\n" +
 	"\x05email\x18\x03 \x01(\tH\x01R\x05email\x88\x01\x01B\a\n" +
 	"\x05_nameB\b\n" +
-	"\x06_email2\xb8\x01\n" +
+	"\x06_email\"m\n" +
+	"\x13CreateRefundRequest\x12\x18\n" +
+	"\aorderId\x18\x01 \x01(\x04R\aorderId\x12$\n" +
+	"\x06amount\x18\x02 \x01(\v2\f.money.MoneyR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xc2\x01\n" +
+	"\x06Refund\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aorderId\x18\x02 \x01(\x04R\aorderId\x12$\n" +
+	"\x06amount\x18\x03 \x01(\v2\f.money.MoneyR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x128\n" +
+	"\tcreatedAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xef\x01\n" +
 	"\x0ePaymentService\x12L\n" +
 	"\x15CreateCheckoutSession\x12\x13.pb.CheckoutRequest\x1a\x1c.google.protobuf.StringValue\"\x00\x12X\n" +
-	"\x1bCreateCustomerPortalSession\x12\x19.pb.CustomerPortalRequest\x1a\x1c.google.protobuf.StringValue\"\x00B\x06Z\x04./pbb\x06proto3"
+	"\x1bCreateCustomerPortalSession\x12\x19.pb.CustomerPortalRequest\x1a\x1c.google.protobuf.StringValue\"\x00\x125\n" +
+	"\fCreateRefund\x12\x17.pb.CreateRefundRequest\x1a\n" +
+	".pb.Refund\"\x00B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_payment_proto_rawDescOnce sync.Once
@@ -255,24 +425,34 @@ func file_payment_proto_rawDescGZIP() []byte {
 	return file_payment_proto_rawDescData
 }
 
-var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_payment_proto_goTypes = []any{
 	(*CartItem)(nil),               // 0: pb.CartItem
 	(*CheckoutRequest)(nil),        // 1: pb.CheckoutRequest
 	(*CustomerPortalRequest)(nil),  // 2: pb.CustomerPortalRequest
-	(*wrapperspb.StringValue)(nil), // 3: google.protobuf.StringValue
+	(*CreateRefundRequest)(nil),    // 3: pb.CreateRefundRequest
+	(*Refund)(nil),                 // 4: pb.Refund
+	(*pb.Money)(nil),               // 5: money.Money
+	(*timestamppb.Timestamp)(nil),  // 6: google.protobuf.Timestamp
+	(*wrapperspb.StringValue)(nil), // 7: google.protobuf.StringValue
 }
 var file_payment_proto_depIdxs = []int32{
 	0, // 0: pb.CheckoutRequest.products:type_name -> pb.CartItem
-	1, // 1: pb.PaymentService.CreateCheckoutSession:input_type -> pb.CheckoutRequest
-	2, // 2: pb.PaymentService.CreateCustomerPortalSession:input_type -> pb.CustomerPortalRequest
-	3, // 3: pb.PaymentService.CreateCheckoutSession:output_type -> google.protobuf.StringValue
-	3, // 4: pb.PaymentService.CreateCustomerPortalSession:output_type -> google.protobuf.StringValue
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 1: pb.CheckoutRequest.expectedTotal:type_name -> money.Money
+	5, // 2: pb.CreateRefundRequest.amount:type_name -> money.Money
+	5, // 3: pb.Refund.amount:type_name -> money.Money
+	6, // 4: pb.Refund.createdAt:type_name -> google.protobuf.Timestamp
+	1, // 5: pb.PaymentService.CreateCheckoutSession:input_type -> pb.CheckoutRequest
+	2, // 6: pb.PaymentService.CreateCustomerPortalSession:input_type -> pb.CustomerPortalRequest
+	3, // 7: pb.PaymentService.CreateRefund:input_type -> pb.CreateRefundRequest
+	7, // 8: pb.PaymentService.CreateCheckoutSession:output_type -> google.protobuf.StringValue
+	7, // 9: pb.PaymentService.CreateCustomerPortalSession:output_type -> google.protobuf.StringValue
+	4, // 10: pb.PaymentService.CreateRefund:output_type -> pb.Refund
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_payment_proto_init() }
@@ -287,7 +467,7 @@ func file_payment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,41 @@
+package payment
+
+// RefundableItem is a line item of a payment and the part of it that has not
+// been refunded yet.
+type RefundableItem struct {
+	ItemId     string
+	Refundable int64
+}
+
+// RefundAllocation is the amount refunded from one line item.
+type RefundAllocation struct {
+	ItemId string
+	Amount int64
+}
+
+// AllocateRefund spreads a partial refund over the line items of a payment,
+// taking as much as possible from each item in order. It fails with
+// ErrInvalidRefundAmount if the items cannot cover amount.
+func AllocateRefund(items []RefundableItem, amount int64) ([]RefundAllocation, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	var allocations []RefundAllocation
+	for _, item := range items {
+		if amount == 0 {
+			break
+		}
+		take := min(item.Refundable, amount)
+		if take <= 0 {
+			continue
+		}
+		allocations = append(allocations, RefundAllocation{ItemId: item.ItemId, Amount: take})
+		amount -= take
+	}
+
+	if amount > 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+	return allocations, nil
+}
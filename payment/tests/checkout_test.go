@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abhiii71/orderStream/payment"
+	"github.com/abhiii71/orderStream/payment/internal"
+	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/abhiii71/orderStream/payment/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+// newCheckout returns a service with a mug registered under the current
+// pricing and a book registered before pricing versions existed.
+func newCheckout(t *testing.T) (internal.PaymentService, *fakeProvider) {
+	t.Helper()
+
+	repo := newMemoryRepository()
+	provider := &fakeProvider{prices: map[string]int64{}, payWhatYouWant: map[string]bool{}}
+	service := internal.NewPaymentService(provider, repo, &fakeOrders{})
+
+	for _, p := range []models.Product{
+		{ProductID: "mug", DodoProductID: "pdt_mug", Price: 1200, Currency: "USD"},
+		{ProductID: "book", DodoProductID: "pdt_book", Price: 800, Currency: "USD"},
+	} {
+		repo.products[p.ProductID] = p
+		provider.prices[p.DodoProductID] = p.Price
+	}
+
+	// checking the mug out once moves it to the current pricing
+	if _, err := service.CreateCheckoutSession(context.Background(), 1, "cus_1", "", []*pb.CartItem{{ProductId: "mug", Quantity: 1}}, 0, nil, nil, money.Money{}, money.Money{}); err != nil {
+		t.Fatal(err)
+	}
+	provider.repriced = nil
+	return service, provider
+}
+
+func TestCheckoutChargesTheOrderTotal(t *testing.T) {
+	service, provider := newCheckout(t)
+
+	items := []*pb.CartItem{{ProductId: "mug", Quantity: 2}, {ProductId: "book", Quantity: 1}}
+	tax, shipping := money.New(256, "USD"), money.New(499, "USD")
+	total := money.New(2*1200+800+256+499, "USD")
+
+	if _, err := service.CreateCheckoutSession(context.Background(), 1, "cus_1", "", items, 42, &total, nil, tax, shipping); err != nil {
+		t.Fatal(err)
+	}
+	if charged := provider.charged(); charged != total.Amount {
+		t.Errorf("checkout charges %d, want the order total %d", charged, total.Amount)
+	}
+	if !slices.Equal(provider.repriced, []string{"pdt_book"}) {
+		t.Errorf("repriced %v, want the book registered before pricing versions", provider.repriced)
+	}
+
+	// repriced products and fee products are not repriced again
+	provider.repriced = nil
+	if _, err := service.CreateCheckoutSession(context.Background(), 1, "cus_1", "", items, 42, &total, nil, tax, shipping); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.repriced) != 0 {
+		t.Errorf("repriced %v again", provider.repriced)
+	}
+}
+
+func TestCheckoutChargesExactDiscountsPerLine(t *testing.T) {
+	service, provider := newCheckout(t)
+
+	// a product coupon took 1.00 off the three mugs only
+	items := []*pb.CartItem{{ProductId: "mug", Quantity: 3}, {ProductId: "book", Quantity: 1}}
+	discounts := map[string]money.Money{"mug": money.New(100, "USD")}
+	total := money.New(3*1200-100+800, "USD")
+
+	if _, err := service.CreateCheckoutSession(context.Background(), 1, "cus_1", "", items, 42, &total, discounts, money.Money{}, money.Money{}); err != nil {
+		t.Fatal(err)
+	}
+	if charged := provider.charged(); charged != total.Amount {
+		t.Errorf("checkout charges %d, want the order total %d", charged, total.Amount)
+	}
+	for _, line := range provider.cart {
+		if line.ProductID.Value == "pdt_book" && line.Amount.Value != 800 {
+			t.Errorf("book charged %d, want its full price", line.Amount.Value)
+		}
+	}
+}
+
+func TestCheckoutRejectsDiscountsOutsideTheCart(t *testing.T) {
+	service, _ := newCheckout(t)
+
+	items := []*pb.CartItem{{ProductId: "mug", Quantity: 1}}
+	for _, discounts := range []map[string]money.Money{
+		{"book": money.New(100, "USD")},
+		{"mug": money.New(1201, "USD")},
+	} {
+		_, err := service.CreateCheckoutSession(context.Background(), 1, "cus_1", "", items, 42, nil, discounts, money.Money{}, money.Money{})
+		if !errors.Is(err, payment.ErrTotalMismatch) {
+			t.Errorf("CreateCheckoutSession with discounts %v error = %v, want ErrTotalMismatch", discounts, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/abhiii71/orderStream/payment/internal"
+	"github.com/abhiii71/orderStream/payment/models"
+)
+
+func TestDeleteProductArchivesItsProviderProduct(t *testing.T) {
+	repo := newMemoryRepository()
+	repo.products["mug"] = models.Product{ProductID: "mug", DodoProductID: "pdt_mug", Price: 1200, Currency: "USD"}
+	provider := &fakeProvider{}
+	service := internal.NewPaymentService(provider, repo, &fakeOrders{})
+
+	if err := service.DeleteProduct(context.Background(), "mug"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(provider.archived, []string{"pdt_mug"}) {
+		t.Errorf("archived %v, want the provider's product", provider.archived)
+	}
+	if _, ok := repo.products["mug"]; ok {
+		t.Error("product is still registered")
+	}
+
+	// a redelivered or never registered product has nothing to archive
+	if err := service.DeleteProduct(context.Background(), "mug"); err != nil {
+		t.Errorf("deleting it again: %v", err)
+	}
+	if len(provider.archived) != 1 {
+		t.Errorf("archived %v, want it archived once", provider.archived)
+	}
+}
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abhiii71/orderStream/payment"
+	"github.com/abhiii71/orderStream/payment/internal"
+	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/dodopayments/dodopayments-go"
+)
+
+// fakeProvider stands in for the payment provider. Refunds succeed right
+// away, unless refundErr is set. Methods the tests do not need are left to the embedded nil
+// PaymentClient and panic if called.
+type fakeProvider struct {
+	internal.PaymentClient
+
+	mu        sync.Mutex
+	refunds   int
+	items     []payment.RefundableItem
+	refundErr error
+	// refundKeys are the idempotency keys refunds were asked with
+	refundKeys []string
+	webhook    *models.WebhookEvent
+
+	// prices of the products at the provider, and which of them are pay
+	// what you want
+	prices         map[string]int64
+	payWhatYouWant map[string]bool
+	repriced       []string
+	cart           []dodopayments.CheckoutSessionRequestProductCartParam
+	archived       []string
+}
+
+func (p *fakeProvider) ArchiveProduct(_ context.Context, productId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.archived = append(p.archived, productId)
+	return nil
+}
+
+func (p *fakeProvider) RepriceProduct(_ context.Context, productId string, price int64, _ dodopayments.Currency) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.repriced = append(p.repriced, productId)
+	p.prices[productId], p.payWhatYouWant[productId] = price, true
+	return nil
+}
+
+func (p *fakeProvider) CreateFeeProduct(_ context.Context, name string, _ dodopayments.Currency) (*dodopayments.Product, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := "pdt_" + strings.ToLower(name)
+	p.prices[id], p.payWhatYouWant[id] = 0, true
+	return &dodopayments.Product{ProductID: id}, nil
+}
+
+func (p *fakeProvider) CreateCheckoutSession(_ context.Context, _ int64, _, _ string, cart []dodopayments.CheckoutSessionRequestProductCartParam, orderId uint64) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cart = cart
+	return "https://checkout.test/" + strconv.FormatUint(orderId, 10), nil
+}
+
+// charged is what the last checkout charges: pay what you want lines cost
+// their amount, others the price of their product.
+func (p *fakeProvider) charged() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var total int64
+	for _, line := range p.cart {
+		price := p.prices[line.ProductID.Value]
+		if p.payWhatYouWant[line.ProductID.Value] {
+			price = line.Amount.Value
+		}
+		total += price * line.Quantity.Value
+	}
+	return total
+}
+
+func (p *fakeProvider) GetRefundableItems(_ context.Context, _ string) ([]payment.RefundableItem, error) {
+	return p.items, nil
+}
+
+func (p *fakeProvider) CreateRefund(_ context.Context, paymentId string, items []payment.RefundAllocation, reason, idempotencyKey string) (*models.Refund, error) {
+	// the provider takes a while, long enough for concurrent refunds to
+	// overlap
+	time.Sleep(time.Millisecond)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.refundKeys = append(p.refundKeys, idempotencyKey)
+	if p.refundErr != nil {
+		return nil, p.refundErr
+	}
+
+	p.refunds++
+	refund := &models.Refund{RefundId: "rfd_" + strconv.Itoa(p.refunds), PaymentId: paymentId, Reason: reason, Status: payment.RefundSucceeded}
+	for _, item := range items {
+		refund.Amount += item.Amount
+	}
+	return refund, nil
+}
+
+func (p *fakeProvider) HandleWebhook(http.ResponseWriter, *http.Request) (*models.WebhookEvent, error) {
+	return p.webhook, nil
+}
+
+// fakeOrders records the payment statuses reported to the order service.
+type fakeOrders struct {
+	mu       sync.Mutex
+	statuses []string
+}
+
+func (o *fakeOrders) UpdatePaymentStatus(_ context.Context, _ uint64, status string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.statuses = append(o.statuses, status)
+	return nil
+}
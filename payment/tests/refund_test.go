@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/abhiii71/orderStream/payment"
+	"github.com/abhiii71/orderStream/payment/internal"
+	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+func TestAllocateRefund(t *testing.T) {
+	items := []payment.RefundableItem{
+		{ItemId: "pdt_shirt", Refundable: 2000},
+		{ItemId: "pdt_socks", Refundable: 0}, // refunded already
+		{ItemId: "pdt_hat", Refundable: 1500},
+	}
+
+	got, err := payment.AllocateRefund(items, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []payment.RefundAllocation{{ItemId: "pdt_shirt", Amount: 2000}, {ItemId: "pdt_hat", Amount: 500}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("AllocateRefund(2500) = %v, want %v", got, want)
+	}
+
+	for _, amount := range []int64{0, -1, 3501} {
+		if _, err := payment.AllocateRefund(items, amount); !errors.Is(err, payment.ErrInvalidRefundAmount) {
+			t.Errorf("AllocateRefund(%d) returned %v", amount, err)
+		}
+	}
+}
+
+func paidTransaction() models.Transaction {
+	return models.Transaction{ID: 1, OrderId: 42, PaymentId: "pay_1", TotalPrice: 1000, Currency: "USD", Status: string(payment.Success)}
+}
+
+func TestConcurrentRefundsDoNotExceedThePayment(t *testing.T) {
+	repo := newMemoryRepository(paidTransaction())
+	provider := &fakeProvider{items: []payment.RefundableItem{{ItemId: "pdt_mug", Refundable: 1000}}}
+	service := internal.NewPaymentService(provider, repo, &fakeOrders{})
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.CreateRefund(context.Background(), 42, &money.Money{Amount: 300, Currency: "USD"}, "damaged")
+			if err == nil {
+				succeeded.Add(1)
+			} else if !errors.Is(err, payment.ErrInvalidRefundAmount) {
+				t.Errorf("CreateRefund error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded.Load() != 3 {
+		t.Errorf("%d refunds of 300 succeeded, want 3", succeeded.Load())
+	}
+	if refunded, _ := repo.SumRefunds(context.Background(), 1, payment.RefundSucceeded); refunded != 900 {
+		t.Errorf("refunded %d, want 900", refunded)
+	}
+}
+
+func TestRefundsReportThePaymentStatusToTheOrder(t *testing.T) {
+	repo := newMemoryRepository(paidTransaction())
+	provider := &fakeProvider{items: []payment.RefundableItem{{ItemId: "pdt_mug", Refundable: 1000}}}
+	orders := &fakeOrders{}
+	service := internal.NewPaymentService(provider, repo, orders)
+
+	if _, err := service.CreateRefund(context.Background(), 42, &money.Money{Amount: 400, Currency: "USD"}, "damaged"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateRefund(context.Background(), 42, nil, "returned"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{string(payment.PartiallyRefunded), string(payment.Refunded)}
+	if !reflect.DeepEqual(orders.statuses, want) {
+		t.Errorf("reported statuses %v, want %v", orders.statuses, want)
+	}
+}
+
+func TestRefundsAreStoredBeforeTheProviderIsAsked(t *testing.T) {
+	repo := newMemoryRepository(paidTransaction())
+	provider := &fakeProvider{
+		items:     []payment.RefundableItem{{ItemId: "pdt_mug", Refundable: 1000}},
+		refundErr: errors.New("connection reset"),
+	}
+	service := internal.NewPaymentService(provider, repo, &fakeOrders{})
+
+	// the provider may have made the refund, so it stays pending and is not
+	// refunded again
+	if _, err := service.CreateRefund(context.Background(), 42, &money.Money{Amount: 400, Currency: "USD"}, "damaged"); err == nil {
+		t.Fatal("CreateRefund succeeded without the provider")
+	}
+	if !reflect.DeepEqual(provider.refundKeys, []string{"refund_1"}) {
+		t.Errorf("idempotency keys %v, want the stored refund's id", provider.refundKeys)
+	}
+	if pending, _ := repo.SumRefunds(context.Background(), 1, payment.RefundPending); pending != 400 {
+		t.Errorf("pending %d, want 400", pending)
+	}
+	if _, err := service.CreateRefund(context.Background(), 42, &money.Money{Amount: 700, Currency: "USD"}, "damaged"); !errors.Is(err, payment.ErrInvalidRefundAmount) {
+		t.Errorf("refunding more than is left: error = %v, want ErrInvalidRefundAmount", err)
+	}
+
+	// the webhook settles the refund
+	provider.webhook = &models.WebhookEvent{Refund: &models.Refund{RefundId: "rfd_9", PaymentId: "pay_1", Amount: 400, Currency: "USD", Status: payment.RefundSucceeded}}
+	updated, err := service.HandlePaymentWebhook(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.refunds) != 1 || repo.refunds[1].RefundId != "rfd_9" || repo.refunds[1].Status != payment.RefundSucceeded {
+		t.Errorf("refunds = %+v, want the pending refund settled", repo.refunds)
+	}
+	if updated == nil || updated.Status != string(payment.PartiallyRefunded) {
+		t.Errorf("updated transaction = %+v, want it partially refunded", updated)
+	}
+}
+
+func TestRejectedRefundsFail(t *testing.T) {
+	repo := newMemoryRepository(paidTransaction())
+	provider := &fakeProvider{refundErr: payment.ErrRefundRejected}
+	service := internal.NewPaymentService(provider, repo, &fakeOrders{})
+
+	if _, err := service.CreateRefund(context.Background(), 42, nil, "returned"); !errors.Is(err, payment.ErrRefundRejected) {
+		t.Fatalf("CreateRefund error = %v, want ErrRefundRejected", err)
+	}
+	if refund := repo.refunds[1]; refund.Status != payment.RefundFailed {
+		t.Errorf("refund status = %q, want failed", refund.Status)
+	}
+
+	// a failed refund leaves the payment to refund again
+	provider.refundErr = nil
+	if _, err := service.CreateRefund(context.Background(), 42, nil, "returned"); err != nil {
+		t.Fatal(err)
+	}
+	if refunded, _ := repo.SumRefunds(context.Background(), 1, payment.RefundSucceeded); refunded != 1000 {
+		t.Errorf("refunded %d, want 1000", refunded)
+	}
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+
+	"github.com/abhiii71/orderStream/payment"
+	"github.com/abhiii71/orderStream/payment/internal"
+	"github.com/abhiii71/orderStream/payment/models"
+	"github.com/abhiii71/orderStream/pkg/outbox"
+)
+
+// memoryRepository keeps products, transactions and refunds in memory. Methods the
+// tests do not need are left to the embedded nil PaymentRepository and panic
+// if called.
+type memoryRepository struct {
+	internal.PaymentRepository
+
+	mu           sync.Mutex
+	locks        sync.Map
+	products     map[string]models.Product
+	transactions map[uint64]models.Transaction
+	refunds      map[uint64]models.Refund
+}
+
+func newMemoryRepository(transactions ...models.Transaction) *memoryRepository {
+	r := &memoryRepository{products: map[string]models.Product{}, transactions: map[uint64]models.Transaction{}, refunds: map[uint64]models.Refund{}}
+	for _, t := range transactions {
+		r.transactions[t.ID] = t
+	}
+	return r
+}
+
+func (r *memoryRepository) GetProductByProductId(_ context.Context, productId string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[productId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &p, nil
+}
+
+func (r *memoryRepository) GetProductsByIds(_ context.Context, productIds []string) ([]*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var products []*models.Product
+	for _, id := range productIds {
+		if p, ok := r.products[id]; ok {
+			products = append(products, &p)
+		}
+	}
+	return products, nil
+}
+
+func (r *memoryRepository) SaveProduct(_ context.Context, product *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.products[product.ProductID] = *product
+	return nil
+}
+
+func (r *memoryRepository) UpdateProduct(ctx context.Context, product *models.Product) error {
+	return r.SaveProduct(ctx, product)
+}
+
+func (r *memoryRepository) DeleteProduct(_ context.Context, productId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.products, productId)
+	return nil
+}
+
+func (r *memoryRepository) find(match func(t models.Transaction) bool) (*models.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.transactions {
+		if match(t) {
+			return &t, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryRepository) GetTransactionByOrderId(_ context.Context, orderId uint64) (*models.Transaction, error) {
+	return r.find(func(t models.Transaction) bool { return t.OrderId == orderId })
+}
+
+func (r *memoryRepository) GetTransactionByPaymentId(_ context.Context, paymentId string) (*models.Transaction, error) {
+	return r.find(func(t models.Transaction) bool { return t.PaymentId == paymentId })
+}
+
+func (r *memoryRepository) UpdateTransactionStatus(_ context.Context, orderId uint64, status string, _ ...outbox.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, t := range r.transactions {
+		if t.OrderId == orderId {
+			t.Status = status
+			r.transactions[id] = t
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) LockTransaction(ctx context.Context, transactionId uint64, fn func(ctx context.Context) error) error {
+	lock, _ := r.locks.LoadOrStore(transactionId, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	return fn(ctx)
+}
+
+func (r *memoryRepository) SavePendingRefund(_ context.Context, refund *models.Refund) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refund.ID = uint64(len(r.refunds) + 1)
+	refund.Status = payment.RefundPending
+	r.refunds[refund.ID] = *refund
+	return nil
+}
+
+func (r *memoryRepository) ClaimPendingRefund(_ context.Context, refund *models.Refund) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claim *models.Refund
+	for _, stored := range r.refunds {
+		if stored.RefundId == refund.RefundId {
+			return false, nil
+		}
+		if stored.TransactionId != refund.TransactionId || stored.RefundId != "" || stored.Status != payment.RefundPending {
+			continue
+		}
+		if claim == nil || stored.ID == refund.ID || (claim.ID != refund.ID && stored.ID < claim.ID) {
+			claim = &stored
+		}
+	}
+	if claim == nil {
+		return false, nil
+	}
+
+	claim.RefundId, claim.Status = refund.RefundId, refund.Status
+	if refund.Amount > 0 {
+		claim.Amount = refund.Amount
+	}
+	r.refunds[claim.ID] = *claim
+	*refund = *claim
+	return true, nil
+}
+
+func (r *memoryRepository) SaveRefund(_ context.Context, refund *models.Refund) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, stored := range r.refunds {
+		if stored.RefundId == refund.RefundId {
+			if stored.Status == payment.RefundPending {
+				stored.Status = refund.Status
+			}
+			r.refunds[id] = stored
+			*refund = stored
+			return nil
+		}
+	}
+	refund.ID = uint64(len(r.refunds) + 1)
+	r.refunds[refund.ID] = *refund
+	return nil
+}
+
+func (r *memoryRepository) UpdateRefundStatus(_ context.Context, id uint64, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.refunds[id]; ok && stored.Status == payment.RefundPending {
+		stored.Status = status
+		r.refunds[id] = stored
+	}
+	return nil
+}
+
+func (r *memoryRepository) SumRefunds(_ context.Context, transactionId uint64, statuses ...string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var total int64
+	for _, refund := range r.refunds {
+		if refund.TransactionId == transactionId && slices.Contains(statuses, refund.Status) {
+			total += refund.Amount
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,154 @@
+// Package events defines the messages services publish to Kafka and how they
+// are encoded. Schemas live in proto/; on the wire events are the protobuf
+// JSON encoding so non-Go consumers can read them without generated code.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/abhiii71/orderStream/pkg/events/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProductEventSchemaVersion is the schema version producers write. Decoders
+// accept it and every earlier version.
+const ProductEventSchemaVersion = 1
+
+// Product event types.
+const (
+	ProductCreated   = "product_created"
+	ProductUpdated   = "product_updated"
+	ProductPublished = "product_published"
+	ProductArchived  = "product_archived"
+	ProductDeleted   = "product_deleted"
+)
+
+// legacyCurrency is the currency of legacy events that carry none.
+const legacyCurrency = "USD"
+
+var (
+	ErrUnsupportedSchema = errors.New("events: unsupported schema version")
+	ErrInvalidEvent      = errors.New("events: invalid event")
+)
+
+// NewProductEvent returns an event of eventType for a change from before to
+// after, either of which may be nil.
+func NewProductEvent(eventType string, before, after *pb.ProductSnapshot) *pb.ProductEvent {
+	event := &pb.ProductEvent{
+		SchemaVersion: ProductEventSchemaVersion,
+		EventId:       uuid.NewString(),
+		Type:          eventType,
+		OccurredAt:    timestamppb.Now(),
+		Before:        before,
+		After:         after,
+	}
+
+	latest := after
+	if latest == nil {
+		latest = before
+	}
+	if latest != nil {
+		event.ProductId, event.ProductVersion = latest.Id, latest.Version
+	}
+	return event
+}
+
+// EncodeProductEvent returns the wire form of event.
+func EncodeProductEvent(event *pb.ProductEvent) ([]byte, error) {
+	return protojson.Marshal(event)
+}
+
+// DecodeProductEvent reads a product event in any schema version, including
+// the untyped JSON events written before schemas were versioned. Unknown
+// fields are ignored so producers can add fields ahead of consumers.
+func DecodeProductEvent(data []byte) (*pb.ProductEvent, error) {
+	var probe struct {
+		SchemaVersion *uint32         `json:"schemaVersion"`
+		Data          json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	var event *pb.ProductEvent
+	switch {
+	case probe.SchemaVersion == nil && probe.Data != nil:
+		legacy, err := decodeLegacyProductEvent(data)
+		if err != nil {
+			return nil, err
+		}
+		event = legacy
+	case probe.SchemaVersion != nil && *probe.SchemaVersion > ProductEventSchemaVersion:
+		return nil, fmt.Errorf("%w %d", ErrUnsupportedSchema, *probe.SchemaVersion)
+	default:
+		event = &pb.ProductEvent{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, event); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+	}
+
+	if event.Type == "" || event.ProductId == "" {
+		return nil, fmt.Errorf("%w: missing type or product id", ErrInvalidEvent)
+	}
+	return event, nil
+}
+
+// legacyProductEvent is the untyped event format used before ProductEvent:
+// a type plus whichever product fields the producer happened to set.
+type legacyProductEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		ProductID   *string  `json:"product_id"`
+		Name        *string  `json:"name"`
+		Description *string  `json:"description"`
+		Price       *float64 `json:"price"`
+		Currency    *string  `json:"currency"`
+		AccountID   *int     `json:"accountID"`
+		Status      *string  `json:"status"`
+	} `json:"data"`
+}
+
+// decodeLegacyProductEvent converts a legacy event into schema version 0 of
+// ProductEvent. Legacy events only describe the product after the change.
+func decodeLegacyProductEvent(data []byte) (*pb.ProductEvent, error) {
+	var legacy legacyProductEvent
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	d := legacy.Data
+	after := &pb.ProductSnapshot{}
+	if d.ProductID != nil {
+		after.Id = *d.ProductID
+	}
+	if d.Name != nil {
+		after.Name = *d.Name
+	}
+	if d.Description != nil {
+		after.Description = *d.Description
+	}
+	if d.Price != nil {
+		currency := legacyCurrency
+		if d.Currency != nil && *d.Currency != "" {
+			currency = *d.Currency
+		}
+		after.Price = money.ToProto(money.FromFloat(*d.Price, currency))
+	}
+	if d.AccountID != nil {
+		after.AccountId = int64(*d.AccountID)
+	}
+	if d.Status != nil {
+		after.Status = *d.Status
+	}
+
+	return &pb.ProductEvent{
+		Type:      legacy.Type,
+		ProductId: after.Id,
+		After:     after,
+	}, nil
+}
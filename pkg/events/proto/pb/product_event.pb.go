@@ -0,0 +1,347 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v3.21.12
+// source: product_event.proto
+
+package pb
+
+import (
+	pb "github.com/abhiii71/orderStream/pkg/money/proto/pb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ProductSnapshot is the full state of a product at one point in time.
+type ProductSnapshot struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price          *pb.Money              `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	PriceOverrides []*pb.Money            `protobuf:"bytes,5,rep,name=priceOverrides,proto3" json:"priceOverrides,omitempty"`
+	AccountId      int64                  `protobuf:"varint,6,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Status         string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	PublishAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=publishAt,proto3" json:"publishAt,omitempty"`
+	DeletedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	Version        int64                  `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	// empty in events written before tax categories, for physical
+	TaxCategory   string `protobuf:"bytes,11,opt,name=taxCategory,proto3" json:"taxCategory,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSnapshot) Reset() {
+	*x = ProductSnapshot{}
+	mi := &file_product_event_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSnapshot) ProtoMessage() {}
+
+func (x *ProductSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_product_event_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSnapshot.ProtoReflect.Descriptor instead.
+func (*ProductSnapshot) Descriptor() ([]byte, []int) {
+	return file_product_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProductSnapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductSnapshot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductSnapshot) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductSnapshot) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductSnapshot) GetPriceOverrides() []*pb.Money {
+	if x != nil {
+		return x.PriceOverrides
+	}
+	return nil
+}
+
+func (x *ProductSnapshot) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ProductSnapshot) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProductSnapshot) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+func (x *ProductSnapshot) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *ProductSnapshot) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProductSnapshot) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+// ProductEvent is published to the product_events topic whenever a product
+// changes. Fields are only ever added; a change that old consumers cannot
+// read bumps schemaVersion.
+type ProductEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion uint32                 `protobuf:"varint,1,opt,name=schemaVersion,proto3" json:"schemaVersion,omitempty"`
+	// unique per event, so consumers can drop duplicates
+	EventId string `protobuf:"bytes,2,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	// product_created, product_updated, product_published, product_archived
+	// or product_deleted
+	Type       string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"`
+	ProductId  string                 `protobuf:"bytes,5,opt,name=productId,proto3" json:"productId,omitempty"`
+	// version of the product after the change
+	ProductVersion int64 `protobuf:"varint,6,opt,name=productVersion,proto3" json:"productVersion,omitempty"`
+	// state before the change; unset for product_created
+	Before *ProductSnapshot `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	// state after the change
+	After         *ProductSnapshot `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductEvent) Reset() {
+	*x = ProductEvent{}
+	mi := &file_product_event_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductEvent) ProtoMessage() {}
+
+func (x *ProductEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_product_event_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductEvent.ProtoReflect.Descriptor instead.
+func (*ProductEvent) Descriptor() ([]byte, []int) {
+	return file_product_event_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductEvent) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *ProductEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ProductEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProductEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *ProductEvent) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductEvent) GetProductVersion() int64 {
+	if x != nil {
+		return x.ProductVersion
+	}
+	return 0
+}
+
+func (x *ProductEvent) GetBefore() *ProductSnapshot {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *ProductEvent) GetAfter() *ProductSnapshot {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+var File_product_event_proto protoreflect.FileDescriptor
+
+const file_product_event_proto_rawDesc = "" +
+	"\n" +
+	"\x13product_event.proto\x12\x06events\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\vmoney.proto\"\x97\x03\n" +
+	"\x0fProductSnapshot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\x04 \x01(\v2\f.money.MoneyR\x05price\x124\n" +
+	"\x0epriceOverrides\x18\x05 \x03(\v2\f.money.MoneyR\x0epriceOverrides\x12\x1c\n" +
+	"\taccountId\x18\x06 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x128\n" +
+	"\tpublishAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x128\n" +
+	"\tdeletedAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\x03R\aversion\x12 \n" +
+	"\vtaxCategory\x18\v \x01(\tR\vtaxCategory\"\xc4\x02\n" +
+	"\fProductEvent\x12$\n" +
+	"\rschemaVersion\x18\x01 \x01(\rR\rschemaVersion\x12\x18\n" +
+	"\aeventId\x18\x02 \x01(\tR\aeventId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12:\n" +
+	"\n" +
+	"occurredAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x1c\n" +
+	"\tproductId\x18\x05 \x01(\tR\tproductId\x12&\n" +
+	"\x0eproductVersion\x18\x06 \x01(\x03R\x0eproductVersion\x12/\n" +
+	"\x06before\x18\a \x01(\v2\x17.events.ProductSnapshotR\x06before\x12-\n" +
+	"\x05after\x18\b \x01(\v2\x17.events.ProductSnapshotR\x05afterB5Z3github.com/abhiii71/orderStream/pkg/events/proto/pbb\x06proto3"
+
+var (
+	file_product_event_proto_rawDescOnce sync.Once
+	file_product_event_proto_rawDescData []byte
+)
+
+func file_product_event_proto_rawDescGZIP() []byte {
+	file_product_event_proto_rawDescOnce.Do(func() {
+		file_product_event_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_product_event_proto_rawDesc), len(file_product_event_proto_rawDesc)))
+	})
+	return file_product_event_proto_rawDescData
+}
+
+var file_product_event_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_product_event_proto_goTypes = []any{
+	(*ProductSnapshot)(nil),       // 0: events.ProductSnapshot
+	(*ProductEvent)(nil),          // 1: events.ProductEvent
+	(*pb.Money)(nil),              // 2: money.Money
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_product_event_proto_depIdxs = []int32{
+	2, // 0: events.ProductSnapshot.price:type_name -> money.Money
+	2, // 1: events.ProductSnapshot.priceOverrides:type_name -> money.Money
+	3, // 2: events.ProductSnapshot.publishAt:type_name -> google.protobuf.Timestamp
+	3, // 3: events.ProductSnapshot.deletedAt:type_name -> google.protobuf.Timestamp
+	3, // 4: events.ProductEvent.occurredAt:type_name -> google.protobuf.Timestamp
+	0, // 5: events.ProductEvent.before:type_name -> events.ProductSnapshot
+	0, // 6: events.ProductEvent.after:type_name -> events.ProductSnapshot
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_product_event_proto_init() }
+func file_product_event_proto_init() {
+	if File_product_event_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_product_event_proto_rawDesc), len(file_product_event_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_product_event_proto_goTypes,
+		DependencyIndexes: file_product_event_proto_depIdxs,
+		MessageInfos:      file_product_event_proto_msgTypes,
+	}.Build()
+	File_product_event_proto = out.File
+	file_product_event_proto_goTypes = nil
+	file_product_event_proto_depIdxs = nil
+}
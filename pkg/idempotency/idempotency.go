@@ -0,0 +1,157 @@
+// Package idempotency lets clients retry requests safely. A client sends an
+// Idempotency-Key with a request; the first request with that key runs and
+// its response is stored, and retries with the same key get the stored
+// response instead of running the request again.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Header is the HTTP header clients send the key in.
+	Header = "Idempotency-Key"
+	// MetadataKey is the gRPC metadata key the key is passed on in.
+	MetadataKey = "idempotency-key"
+	// DefaultTTL is how long responses are replayed.
+	DefaultTTL = 24 * time.Hour
+
+	maxKeyLength = 255
+)
+
+var (
+	ErrInvalidKey = errors.New("idempotency key must be at most 255 characters")
+	ErrKeyReused  = errors.New("idempotency key was already used for a different request")
+	ErrInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// Record is a stored key. Response is nil while the request is running.
+type Record struct {
+	Fingerprint []byte
+	Response    []byte
+	CreatedAt   time.Time
+}
+
+// Store keeps idempotency keys with the responses of their requests. Keys
+// are unique within a scope, such as an RPC and the account calling it.
+type Store interface {
+	// Claim takes key for a request with fingerprint and returns nil, or
+	// returns the record of the request that holds the key.
+	Claim(ctx context.Context, scope, key string, fingerprint []byte) (*Record, error)
+	// Complete stores the response of the request holding key.
+	Complete(ctx context.Context, scope, key string, response []byte) error
+	// Release gives up key, so the request can be retried.
+	Release(ctx context.Context, scope, key string) error
+}
+
+// Do runs fn unless a request with key ran before, in which case it returns
+// that request's response. Retries must send the same request; reusing a key
+// for another request fails with ErrKeyReused. If fn fails the key is
+// released, so failed requests can be retried. Without a key fn just runs.
+func Do[T proto.Message](ctx context.Context, store Store, scope, key string, request proto.Message, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if key == "" {
+		return fn(ctx)
+	}
+	if len(key) > maxKeyLength {
+		return zero, ErrInvalidKey
+	}
+
+	fingerprint, err := Fingerprint(request)
+	if err != nil {
+		return zero, err
+	}
+	record, err := store.Claim(ctx, scope, key, fingerprint)
+	if err != nil {
+		return zero, err
+	}
+	if record != nil {
+		return replay[T](record, fingerprint)
+	}
+
+	response, err := fn(ctx)
+	if err != nil {
+		if err := store.Release(context.WithoutCancel(ctx), scope, key); err != nil {
+			log.Printf("Failed to release idempotency key %s: %v", key, err)
+		}
+		return zero, err
+	}
+
+	data, err := proto.Marshal(response)
+	if err == nil {
+		err = store.Complete(context.WithoutCancel(ctx), scope, key, data)
+	}
+	if err != nil {
+		// the request went through; a retry will run it again
+		log.Printf("Failed to store response for idempotency key %s: %v", key, err)
+		if err := store.Release(context.WithoutCancel(ctx), scope, key); err != nil {
+			log.Printf("Failed to release idempotency key %s: %v", key, err)
+		}
+	}
+	return response, nil
+}
+
+func replay[T proto.Message](record *Record, fingerprint []byte) (T, error) {
+	var zero T
+	if string(record.Fingerprint) != string(fingerprint) {
+		return zero, ErrKeyReused
+	}
+	if record.Response == nil {
+		return zero, ErrInProgress
+	}
+
+	response := zero.ProtoReflect().New().Interface().(T)
+	if err := proto.Unmarshal(record.Response, response); err != nil {
+		return zero, err
+	}
+	return response, nil
+}
+
+// Fingerprint identifies a request by the hash of its deterministic
+// encoding.
+func Fingerprint(request proto.Message) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+type keyCtx struct{}
+
+// WithKey returns ctx carrying the key a client sent.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyCtx{}, key)
+}
+
+// KeyFromContext returns the key stored by WithKey, or "".
+func KeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(keyCtx{}).(string)
+	return key
+}
+
+// OutgoingContext passes the key in ctx on in the metadata of outgoing gRPC
+// calls.
+func OutgoingContext(ctx context.Context) context.Context {
+	if key := KeyFromContext(ctx); key != "" {
+		return metadata.AppendToOutgoingContext(ctx, MetadataKey, key)
+	}
+	return ctx
+}
+
+// IncomingKey returns the key in the metadata of an incoming gRPC call, or
+// "".
+func IncomingKey(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps keys in memory, for services that run without a
+// database. Keys are lost on restart.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, records: make(map[string]*Record)}
+}
+
+func (s *MemoryStore) Claim(_ context.Context, scope, key string, fingerprint []byte) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	record, ok := s.records[scope+"/"+key]
+	if ok && record.CreatedAt.After(now.Add(-s.ttl)) &&
+		(record.Response != nil || record.CreatedAt.After(now.Add(-staleAfter))) {
+		copied := *record
+		return &copied, nil
+	}
+	s.records[scope+"/"+key] = &Record{Fingerprint: fingerprint, CreatedAt: now}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, scope, key string, response []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[scope+"/"+key]; ok {
+		record.Response = response
+	}
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[scope+"/"+key]; ok && record.Response == nil {
+		delete(s.records, scope+"/"+key)
+	}
+	return nil
+}
+
+// Prune deletes expired keys.
+func (s *MemoryStore) Prune(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int64
+	for id, record := range s.records {
+		if record.CreatedAt.Before(time.Now().Add(-s.ttl)) {
+			delete(s.records, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// staleAfter is how long a request may hold a key without completing before
+// the key is handed to a retry. Requests time out well before that.
+const staleAfter = time.Minute
+
+// PostgresStore keeps keys in the idempotency_keys table. Keys expire after
+// ttl and may be used again.
+type PostgresStore struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+func NewPostgresStore(db *sql.DB, ttl time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, ttl: ttl}
+}
+
+func (s *PostgresStore) Claim(ctx context.Context, scope, key string, fingerprint []byte) (*Record, error) {
+	now := time.Now().UTC()
+	result, err := s.db.ExecContext(ctx, `INSERT INTO idempotency_keys (scope, idempotency_key, fingerprint, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, response = NULL, created_at = EXCLUDED.created_at
+		WHERE idempotency_keys.created_at < $5
+			OR (idempotency_keys.response IS NULL AND idempotency_keys.created_at < $6)`,
+		scope, key, fingerprint, now, now.Add(-s.ttl), now.Add(-staleAfter))
+	if err != nil {
+		return nil, err
+	}
+	if claimed, err := result.RowsAffected(); err != nil || claimed > 0 {
+		return nil, err
+	}
+
+	var record Record
+	err = s.db.QueryRowContext(ctx, `SELECT fingerprint, response, created_at FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2`, scope, key).Scan(&record.Fingerprint, &record.Response, &record.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// released in the meantime
+		return s.Claim(ctx, scope, key, fingerprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, scope, key string, response []byte) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE idempotency_keys SET response = $1
+		WHERE scope = $2 AND idempotency_key = $3`, response, scope, key)
+	return err
+}
+
+func (s *PostgresStore) Release(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND response IS NULL`, scope, key)
+	return err
+}
+
+// Prune deletes expired keys.
+func (s *PostgresStore) Prune(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, time.Now().UTC().Add(-s.ttl))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,82 @@
+// Package inbox deduplicates events delivered more than once. A consumer
+// records the id of every event it handles in the inbox table, in the same
+// transaction as the changes the event causes, and skips events whose id is
+// already recorded.
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DBTX runs queries on a *sql.DB or a *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction of the event being processed in ctx, or db
+// outside of Process. Repositories use it so the writes of a handler commit
+// together with the event id.
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// WithTx returns a copy of ctx in which Conn returns tx, for repositories
+// that run several calls in one transaction outside of Process.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// Inbox records the events processed by one consumer.
+type Inbox struct {
+	db       *sql.DB
+	consumer string
+}
+
+func New(db *sql.DB, consumer string) *Inbox {
+	return &Inbox{db: db, consumer: consumer}
+}
+
+// Process runs handle in a transaction unless the event was processed
+// before, and reports whether it ran. The event id is recorded in the same
+// transaction, so it is only kept if handle succeeds. Concurrent deliveries
+// of the same event wait for each other, and only the first one runs.
+func (i *Inbox) Process(ctx context.Context, eventID string, handle func(ctx context.Context) error) (bool, error) {
+	tx, err := i.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO inbox (consumer, event_id, processed_at)
+		VALUES ($1, $2, NOW()) ON CONFLICT DO NOTHING`, i.consumer, eventID)
+	if err != nil {
+		return false, err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		return false, err
+	}
+
+	if err := handle(WithTx(ctx, tx)); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Prune forgets events processed before t. Deliveries of pruned events are
+// processed again.
+func (i *Inbox) Prune(ctx context.Context, t time.Time) (int64, error) {
+	result, err := i.db.ExecContext(ctx, `DELETE FROM inbox WHERE consumer = $1 AND processed_at < $2`, i.consumer, t)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,221 @@
+package kafka
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// deadLetterGroup is the consumer group whose offsets mark how far the dead
+// letters of a topic have been dealt with.
+const deadLetterGroup = "dlq-admin"
+
+// DeadLetter is a message in a dead letter topic.
+type DeadLetter struct {
+	Partition int32
+	Offset    int64
+	Timestamp time.Time
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+}
+
+// OriginalTopic is the topic the message was first published to.
+func (l DeadLetter) OriginalTopic() string {
+	return l.Headers[HeaderOriginalTopic]
+}
+
+// DeadLetters reads the dead letter topic of a topic. Dead letters are
+// pending until they are replayed or discarded, which acknowledges them by
+// moving the dlq-admin group past them; Kafka itself keeps them until they
+// expire.
+type DeadLetters struct {
+	client     sarama.Client
+	topic      string
+	offsets    sarama.OffsetManager
+	partitions map[int32]sarama.PartitionOffsetManager
+}
+
+func OpenDeadLetters(brokers, topic string) (*DeadLetters, error) {
+	client, err := sarama.NewClient([]string{brokers}, sarama.NewConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	offsets, err := sarama.NewOffsetManagerFromClient(deadLetterGroup, client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &DeadLetters{
+		client:     client,
+		topic:      DLQTopic(topic),
+		offsets:    offsets,
+		partitions: make(map[int32]sarama.PartitionOffsetManager),
+	}, nil
+}
+
+// Pending returns up to limit unacknowledged dead letters, oldest first.
+func (d *DeadLetters) Pending(limit int) ([]DeadLetter, error) {
+	partitions, err := d.client.Partitions(d.topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var letters []DeadLetter
+	for _, partition := range partitions {
+		from, err := d.nextOffset(partition)
+		if err != nil {
+			return nil, err
+		}
+		read, err := d.read(partition, from, limit)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, read...)
+	}
+
+	slices.SortFunc(letters, func(a, b DeadLetter) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return letters[:min(limit, len(letters))], nil
+}
+
+// Get returns the dead letter at offset of partition.
+func (d *DeadLetters) Get(partition int32, offset int64) (DeadLetter, error) {
+	letters, err := d.read(partition, offset, 1)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	if len(letters) == 0 || letters[0].Offset != offset {
+		return DeadLetter{}, fmt.Errorf("no message at %s/%d offset %d", d.topic, partition, offset)
+	}
+	return letters[0], nil
+}
+
+// Replay publishes letters to their original topic again, without the retry
+// headers, so they are handled with a fresh set of retries.
+func (d *DeadLetters) Replay(producer sarama.SyncProducer, letters ...DeadLetter) error {
+	for _, letter := range letters {
+		msg := &sarama.ProducerMessage{
+			Topic: letter.OriginalTopic(),
+			Value: sarama.ByteEncoder(letter.Value),
+		}
+		if msg.Topic == "" {
+			return fmt.Errorf("dead letter %d/%d has no original topic", letter.Partition, letter.Offset)
+		}
+		if letter.Key != nil {
+			msg.Key = sarama.ByteEncoder(letter.Key)
+		}
+		for key, value := range letter.Headers {
+			if !isRetryHeader(key) {
+				msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+			}
+		}
+
+		if _, _, err := producer.SendMessage(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Acknowledge marks letters, and every pending letter before them in their
+// partitions, as dealt with.
+func (d *DeadLetters) Acknowledge(letters ...DeadLetter) error {
+	next := make(map[int32]int64)
+	for _, letter := range letters {
+		next[letter.Partition] = max(next[letter.Partition], letter.Offset+1)
+	}
+
+	for partition, offset := range next {
+		pom, err := d.partition(partition)
+		if err != nil {
+			return err
+		}
+		pom.MarkOffset(offset, "")
+	}
+	d.offsets.Commit()
+	return nil
+}
+
+// Close commits acknowledgements still in flight and disconnects.
+func (d *DeadLetters) Close() error {
+	if err := d.offsets.Close(); err != nil {
+		return err
+	}
+	return d.client.Close()
+}
+
+func (d *DeadLetters) partition(partition int32) (sarama.PartitionOffsetManager, error) {
+	if pom, ok := d.partitions[partition]; ok {
+		return pom, nil
+	}
+
+	pom, err := d.offsets.ManagePartition(d.topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	d.partitions[partition] = pom
+	return pom, nil
+}
+
+// nextOffset returns the first unacknowledged offset of partition.
+func (d *DeadLetters) nextOffset(partition int32) (int64, error) {
+	pom, err := d.partition(partition)
+	if err != nil {
+		return 0, err
+	}
+
+	// letters that expired before they were acknowledged are skipped
+	oldest, err := d.client.GetOffset(d.topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, err
+	}
+	offset, _ := pom.NextOffset()
+	return max(offset, oldest), nil
+}
+
+// read returns up to limit messages of partition starting at offset.
+func (d *DeadLetters) read(partition int32, offset int64, limit int) ([]DeadLetter, error) {
+	newest, err := d.client.GetOffset(d.topic, partition, sarama.OffsetNewest)
+	if err != nil || offset >= newest {
+		return nil, err
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(d.client)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	pc, err := consumer.ConsumePartition(d.topic, partition, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer pc.Close()
+
+	var letters []DeadLetter
+	for len(letters) < limit {
+		msg := <-pc.Messages()
+		letter := DeadLetter{
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			Timestamp: msg.Timestamp,
+			Key:       msg.Key,
+			Value:     msg.Value,
+			Headers:   make(map[string]string),
+		}
+		for _, h := range msg.Headers {
+			letter.Headers[string(h.Key)] = string(h.Value)
+		}
+		letters = append(letters, letter)
+
+		if msg.Offset >= newest-1 {
+			break
+		}
+	}
+	return letters, nil
+}
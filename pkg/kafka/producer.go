@@ -1,40 +1,14 @@
 package kafka
 
 import (
-	"encoding/json"
-	"log"
-
 	"github.com/IBM/sarama"
 )
 
-var done = make(chan bool)
-
-type ProducerService interface {
-	GetProducer() sarama.AsyncProducer
-}
-
-func SendMessageToRecommender(service ProducerService, event any, topic string) error {
-	jsonMessage, err := json.Marshal(event)
-	if err != nil {
-		log.Println("failed to marshal event: ", err)
-		return err
-	}
-
-	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.StringEncoder(jsonMessage),
-	}
-
-	// send the message asynchronously
-	service.GetProducer().Input() <- msg
-
-	return nil
-}
-
-func CloseProducer(service ProducerService) {
-	if err := service.GetProducer().Close(); err != nil {
-		log.Printf("failed to close producer: %v\n", err)
-	} else {
-		done <- true
-	}
+// NewSyncProducer returns a producer that waits until every replica of the
+// partition has the message, for callers that must know it was delivered.
+func NewSyncProducer(brokers string) (sarama.SyncProducer, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	return sarama.NewSyncProducer([]string{brokers}, config)
 }
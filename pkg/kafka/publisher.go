@@ -0,0 +1,259 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/IBM/sarama"
+)
+
+var ErrPublisherClosed = errors.New("kafka publisher is closed")
+
+// Message is a message to publish. Messages with the same key go to the
+// same partition and are delivered in the order they were published, so
+// events of one aggregate should share a key.
+type Message struct {
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// PublisherConfig configures a Publisher.
+type PublisherConfig struct {
+	// Acks is how many replicas must have a message before it counts as
+	// delivered. It defaults to sarama.WaitForAll.
+	Acks sarama.RequiredAcks
+	// Idempotent makes the broker discard duplicates caused by the
+	// producer's own retries, keeping the order of keyed messages. It
+	// requires Acks to be sarama.WaitForAll.
+	Idempotent bool
+	// OnError is called with every message that could not be delivered. It
+	// defaults to logging the failure.
+	OnError func(m Message, err error)
+}
+
+// ParseAcks parses "all", "leader" or "none" as required acks.
+func ParseAcks(s string) (sarama.RequiredAcks, error) {
+	switch strings.ToLower(s) {
+	case "", "all":
+		return sarama.WaitForAll, nil
+	case "leader":
+		return sarama.WaitForLocal, nil
+	case "none":
+		return sarama.NoResponse, nil
+	}
+	return 0, fmt.Errorf("invalid kafka acks %q", s)
+}
+
+// PublisherMetrics counts the messages handled by a Publisher.
+type PublisherMetrics struct {
+	Published uint64
+	Delivered uint64
+	Failed    uint64
+	InFlight  int64
+}
+
+// Publisher publishes messages asynchronously. Publish returns once the
+// message is queued; delivery results are tracked in the background, failed
+// messages are passed to OnError and Flush waits for messages in flight.
+type Publisher struct {
+	producer sarama.AsyncProducer
+	onError  func(Message, error)
+
+	// mu guards sends to the producer against Close
+	mu     sync.RWMutex
+	closed bool
+
+	flight   sync.Mutex
+	inFlight int64
+	idle     chan struct{}
+
+	published, delivered, failed atomic.Uint64
+	// drained is closed once the producer has shut down and every result
+	// was handled
+	drained chan struct{}
+}
+
+func NewPublisher(brokers string, cfg PublisherConfig) (*Publisher, error) {
+	config, err := publisherConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewAsyncProducer([]string{brokers}, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewPublisherFromProducer(producer, cfg), nil
+}
+
+// NewPublisherFromProducer publishes with an existing producer, which must
+// be configured to return successes and errors.
+func NewPublisherFromProducer(producer sarama.AsyncProducer, cfg PublisherConfig) *Publisher {
+	p := &Publisher{producer: producer, onError: cfg.OnError, drained: make(chan struct{})}
+	if p.onError == nil {
+		p.onError = func(m Message, err error) {
+			log.Printf("failed to publish message to %s: %v", m.Topic, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range producer.Successes() {
+			p.delivered.Add(1)
+			p.done()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for err := range producer.Errors() {
+			p.failed.Add(1)
+			m, _ := err.Msg.Metadata.(Message)
+			p.onError(m, err.Err)
+			p.done()
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(p.drained)
+	}()
+	return p
+}
+
+func publisherConfig(cfg PublisherConfig) (*sarama.Config, error) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	if cfg.Acks != 0 {
+		config.Producer.RequiredAcks = cfg.Acks
+	}
+
+	if cfg.Idempotent {
+		if config.Producer.RequiredAcks != sarama.WaitForAll {
+			return nil, errors.New("idempotent kafka publisher requires acks from all replicas")
+		}
+		config.Version = sarama.V2_1_0_0
+		config.Producer.Idempotent = true
+		config.Net.MaxOpenRequests = 1
+	}
+	return config, nil
+}
+
+// Publish queues m for delivery. It only blocks while the producer's buffer
+// is full, until ctx is done.
+func (p *Publisher) Publish(ctx context.Context, m Message) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic:    m.Topic,
+		Value:    sarama.ByteEncoder(m.Value),
+		Metadata: m,
+	}
+	if m.Key != "" {
+		msg.Key = sarama.StringEncoder(m.Key)
+	}
+	for key, value := range m.Headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	p.start()
+	select {
+	case p.producer.Input() <- msg:
+		p.published.Add(1)
+		return nil
+	case <-ctx.Done():
+		p.done()
+		return ctx.Err()
+	}
+}
+
+// PublishJSON publishes v encoded as JSON.
+func (p *Publisher) PublishJSON(ctx context.Context, topic, key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return p.Publish(ctx, Message{Topic: topic, Key: key, Value: value})
+}
+
+// Flush waits until every queued message was delivered or failed.
+func (p *Publisher) Flush(ctx context.Context) error {
+	p.flight.Lock()
+	if p.inFlight == 0 {
+		p.flight.Unlock()
+		return nil
+	}
+	idle := p.idle
+	p.flight.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting messages and shuts the producer down once the
+// messages in flight are delivered or failed. If ctx is done first, Close
+// returns while the remaining results are still handled in the background.
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		p.producer.AsyncClose()
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Publisher) Metrics() PublisherMetrics {
+	p.flight.Lock()
+	inFlight := p.inFlight
+	p.flight.Unlock()
+
+	return PublisherMetrics{
+		Published: p.published.Load(),
+		Delivered: p.delivered.Load(),
+		Failed:    p.failed.Load(),
+		InFlight:  inFlight,
+	}
+}
+
+func (p *Publisher) start() {
+	p.flight.Lock()
+	defer p.flight.Unlock()
+	if p.inFlight == 0 {
+		p.idle = make(chan struct{})
+	}
+	p.inFlight++
+}
+
+func (p *Publisher) done() {
+	p.flight.Lock()
+	defer p.flight.Unlock()
+	p.inFlight--
+	if p.inFlight == 0 {
+		close(p.idle)
+	}
+}
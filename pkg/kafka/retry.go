@@ -0,0 +1,138 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Headers added to messages sent to retry and dead letter topics.
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderAttempts          = "x-attempts"
+	HeaderError             = "x-error"
+	HeaderFailedAt          = "x-failed-at"
+	HeaderRetryAt           = "x-retry-at"
+)
+
+// RetryPolicy sends messages whose handler failed through delay topics. A
+// message failing for the n-th time goes to RetryTopic(topic, n) and is
+// handled again once Delays[n-1] has passed; after the last delay it goes
+// to DLQTopic(topic).
+type RetryPolicy struct {
+	Delays []time.Duration
+}
+
+// DefaultRetryPolicy retries after 30 seconds, 5 minutes and 30 minutes.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Delays: []time.Duration{30 * time.Second, 5 * time.Minute, 30 * time.Minute}}
+}
+
+func RetryTopic(topic string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, attempt)
+}
+
+func DLQTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+// RetryTopics returns the delay topics used for topic.
+func (p RetryPolicy) RetryTopics(topic string) []string {
+	topics := make([]string, len(p.Delays))
+	for i := range p.Delays {
+		topics[i] = RetryTopic(topic, i+1)
+	}
+	return topics
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks a handler error that retrying cannot fix, such as a
+// malformed message. The message goes straight to the dead letter topic.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Failed returns the message to publish after msg failed with cause: the
+// same key, value and headers sent to the next retry topic, or to the dead
+// letter topic once the retries are used up.
+func (p RetryPolicy) Failed(msg *sarama.ConsumerMessage, cause error, now time.Time) *sarama.ProducerMessage {
+	headers := make(map[string]string)
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+
+	if headers[HeaderOriginalTopic] == "" {
+		headers[HeaderOriginalTopic] = msg.Topic
+		headers[HeaderOriginalPartition] = strconv.Itoa(int(msg.Partition))
+		headers[HeaderOriginalOffset] = strconv.FormatInt(msg.Offset, 10)
+	}
+	attempts, _ := strconv.Atoi(headers[HeaderAttempts])
+	attempts++
+	headers[HeaderAttempts] = strconv.Itoa(attempts)
+	headers[HeaderError] = cause.Error()
+	headers[HeaderFailedAt] = now.UTC().Format(time.RFC3339)
+	delete(headers, HeaderRetryAt)
+
+	original := headers[HeaderOriginalTopic]
+	topic := DLQTopic(original)
+	var permanent permanentError
+	if attempts <= len(p.Delays) && !errors.As(cause, &permanent) {
+		topic = RetryTopic(original, attempts)
+		headers[HeaderRetryAt] = now.Add(p.Delays[attempts-1]).UTC().Format(time.RFC3339Nano)
+	}
+
+	out := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(msg.Value),
+	}
+	if msg.Key != nil {
+		out.Key = sarama.ByteEncoder(msg.Key)
+	}
+	for key, value := range headers {
+		out.Headers = append(out.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	return out
+}
+
+// OriginalTopic returns the topic a message was first published to, also for
+// messages read from retry topics.
+func OriginalTopic(msg *sarama.ConsumerMessage) string {
+	for _, h := range msg.Headers {
+		if string(h.Key) == HeaderOriginalTopic {
+			return string(h.Value)
+		}
+	}
+	return msg.Topic
+}
+
+// retryAt returns when a message read from a retry topic is due.
+func retryAt(msg *sarama.ConsumerMessage) time.Time {
+	for _, h := range msg.Headers {
+		if string(h.Key) == HeaderRetryAt {
+			at, _ := time.Parse(time.RFC3339Nano, string(h.Value))
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// isRetryHeader reports whether a header was added by the retry policy.
+func isRetryHeader(key string) bool {
+	switch key {
+	case HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset,
+		HeaderAttempts, HeaderError, HeaderFailedAt, HeaderRetryAt:
+		return true
+	}
+	return false
+}
@@ -2,43 +2,155 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/IBM/sarama"
 )
 
-type ConsumerService interface {
-	GetConsumer() sarama.Consumer
+// Handler processes one message. The message counts as consumed once the
+// handler returns nil. When it returns an error, the message is handed on to
+// its retry topic if retries are enabled, and read again otherwise.
+type Handler func(ctx context.Context, msg *sarama.ConsumerMessage) error
+
+// Consumer reads topics as a member of a consumer group. Partitions are
+// spread across the replicas in the group and rebalanced when replicas come
+// and go. Offsets are committed for handled messages only, so a restarted
+// replica continues where the group left off; a new group starts from the
+// oldest retained message.
+type Consumer struct {
+	group   sarama.ConsumerGroup
+	groupID string
+
+	retry    *RetryPolicy
+	producer sarama.SyncProducer
 }
 
-// StartEventConsumer starts a simple Kafka consumer that listen to given topic
-func StartEventConsumer(ctx context.Context, service ConsumerService, topic string, OnEvent func(p int32, pc sarama.PartitionConsumer)) error {
-	partitions, err := service.GetConsumer().Partitions(topic)
+func NewConsumer(brokers, groupID string) (*Consumer, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
+
+	group, err := sarama.NewConsumerGroup([]string{brokers}, groupID, config)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return NewConsumerFromGroup(group, groupID), nil
+}
+
+// NewConsumerFromGroup consumes as a member of an existing consumer group,
+// which must be configured to return errors.
+func NewConsumerFromGroup(group sarama.ConsumerGroup, groupID string) *Consumer {
+	return &Consumer{group: group, groupID: groupID}
+}
+
+// EnableRetries sends messages whose handler failed through the retry
+// topics of policy, publishing them with producer. The consumer reads the
+// retry topics alongside the topics it is asked to consume.
+func (c *Consumer) EnableRetries(policy RetryPolicy, producer sarama.SyncProducer) {
+	c.retry, c.producer = &policy, producer
+}
+
+// Consume passes messages of topics to handler until ctx is cancelled.
+// Messages of a partition are handled one at a time, in order.
+func (c *Consumer) Consume(ctx context.Context, topics []string, handler Handler) error {
+	if c.retry != nil {
+		for _, topic := range topics {
+			topics = append(topics, c.retry.RetryTopics(topic)...)
+		}
 	}
 
-	log.Printf("payment kafka consumer starting; topic=%s partitions=%v", topic, partitions)
+	go func() {
+		for err := range c.group.Errors() {
+			log.Printf("kafka consumer group %s error: %v", c.groupID, err)
+		}
+	}()
 
-	done := make(chan struct{})
-	for _, partition := range partitions {
-		pc, err := service.GetConsumer().ConsumePartition(topic, partition, sarama.OffsetNewest)
+	log.Printf("kafka consumer starting; group=%s topics=%v", c.groupID, topics)
+	for {
+		// Consume returns on every rebalance and has to be called again
+		err := c.group.Consume(ctx, topics, &groupHandler{consumer: c, handler: handler})
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) || ctx.Err() != nil {
+			return nil
+		}
 		if err != nil {
-			log.Printf("error starting partition consumer p=%d: %v", partition, err)
-			continue
+			return err
+		}
+	}
+}
+
+// Close leaves the group, committing the offsets of handled messages.
+func (c *Consumer) Close() error {
+	return c.group.Close()
+}
+
+type groupHandler struct {
+	consumer *Consumer
+	handler  Handler
+}
+
+func (h *groupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *groupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if !waitUntil(session.Context(), retryAt(msg)) {
+				return nil
+			}
+			if err := h.handle(session.Context(), msg); err != nil {
+				// ends the session without marking the message, so it is
+				// read again
+				return err
+			}
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
 		}
-		go OnEvent(partition, pc)
 	}
-	<-ctx.Done()
-	close(done)
+}
+
+// handle runs the handler and, if retries are enabled, hands a failed
+// message on to its next retry or dead letter topic. Without retries the
+// handler's error is returned, so the message is not marked.
+func (h *groupHandler) handle(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	err := h.handler(ctx, msg)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("failed to handle message from %s/%d at offset %d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+	if h.consumer.retry == nil {
+		return err
+	}
 
+	next := h.consumer.retry.Failed(msg, err, time.Now())
+	if _, _, err := h.consumer.producer.SendMessage(next); err != nil {
+		return fmt.Errorf("failed to send message to %s: %w", next.Topic, err)
+	}
 	return nil
 }
 
-func CloseConsumer(service ConsumerService) {
-	if err := service.GetConsumer().Close(); err != nil {
-		log.Printf("failed to close consumer: %v\n", err)
-	} else {
-		done <- true
+// waitUntil blocks until t or until ctx is done, reporting which came first.
+func waitUntil(ctx context.Context, t time.Time) bool {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
 }
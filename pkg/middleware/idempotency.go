@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKey puts the Idempotency-Key header of a request into its
+// context, from where it is passed on to the services that honour it.
+func IdempotencyKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(idempotency.Header); key != "" {
+			c.Request = c.Request.WithContext(idempotency.WithKey(c.Request.Context(), key))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,213 @@
+// Package money represents amounts of money exactly, as an integer number of
+// minor units (cents, paise, ...) of a currency.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+)
+
+// RoundingMode decides how fractions of a minor unit are resolved.
+type RoundingMode int
+
+const (
+	// HalfUp rounds halves away from zero: 0.125 -> 0.13, -0.125 -> -0.13.
+	HalfUp RoundingMode = iota
+	// HalfEven rounds halves to the nearest even minor unit (banker's
+	// rounding): 0.125 -> 0.12, 0.135 -> 0.14.
+	HalfEven
+	// Down truncates towards zero.
+	Down
+)
+
+// zeroDecimalCurrencies have no minor unit.
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+	"VND": true,
+}
+
+// Money is an amount in minor units of Currency.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero returns no money in currency.
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+// Decimals returns the number of minor unit digits of currency.
+func Decimals(currency string) int {
+	if zeroDecimalCurrencies[currency] {
+		return 0
+	}
+	return 2
+}
+
+func scale(currency string) float64 {
+	return math.Pow10(Decimals(currency))
+}
+
+// FromFloat converts a decimal amount such as 19.99 to Money, rounding to the
+// nearest minor unit. Plain truncation (int64(price*100)) turns 0.29 into 28
+// cents because 0.29*100 is 28.999999999999996.
+func FromFloat(amount float64, currency string) Money {
+	return Money{Amount: int64(math.Round(amount * scale(currency))), Currency: currency}
+}
+
+// Parse reads a decimal string such as "19.99" or "-3.5" exactly. More
+// fraction digits than the currency has minor units is an error.
+func Parse(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	decimals := Decimals(currency)
+	if whole == "" && frac == "" || len(frac) > decimals {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+
+	digits := whole + frac
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Float returns the amount as a decimal number, e.g. for JSON APIs that still
+// expect floats. It must not be used for further arithmetic.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / scale(m.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// Add returns m+o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m-o. Both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by a whole quantity.
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// MulRate returns m multiplied by a fractional factor, such as a tax rate or
+// a discount, rounded to a whole minor unit with mode.
+func (m Money) MulRate(rate float64, mode RoundingMode) Money {
+	return Money{Amount: round(float64(m.Amount)*rate, mode), Currency: m.Currency}
+}
+
+// Convert returns m in currency to using an exchange rate (units of to per
+// unit of m's currency), rounded to a whole minor unit of to with mode.
+func (m Money) Convert(to string, rate float64, mode RoundingMode) Money {
+	amount := m.Float() * rate * scale(to)
+	return Money{Amount: round(amount, mode), Currency: to}
+}
+
+// Cmp compares m and o, returning -1, 0 or +1. Both must be in the same
+// currency.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Sum adds up amounts in currency. An empty list sums to zero.
+func Sum(currency string, amounts ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, m := range amounts {
+		var err error
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Decimal formats the amount without currency, e.g. "1234.50".
+func (m Money) Decimal() string {
+	decimals := Decimals(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	if decimals == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	unit := int64(scale(m.Currency))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, decimals, amount%unit)
+}
+
+// String formats m as "1234.50 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func round(x float64, mode RoundingMode) int64 {
+	switch mode {
+	case HalfEven:
+		return int64(math.RoundToEven(x))
+	case Down:
+		return int64(math.Trunc(x))
+	default:
+		return int64(math.Round(x))
+	}
+}
@@ -0,0 +1,14 @@
+package money
+
+import "github.com/abhiii71/orderStream/pkg/money/proto/pb"
+
+// ToProto converts m to its protobuf message.
+func ToProto(m Money) *pb.Money {
+	return &pb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+// FromProto converts a protobuf message to Money. A nil message is zero with
+// no currency.
+func FromProto(m *pb.Money) Money {
+	return Money{Amount: m.GetAmount(), Currency: m.GetCurrency()}
+}
@@ -0,0 +1,30 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+)
+
+type kafkaPublisher struct {
+	producer sarama.SyncProducer
+}
+
+// NewKafkaPublisher publishes messages with a synchronous producer, which
+// waits for the broker to acknowledge each message.
+func NewKafkaPublisher(producer sarama.SyncProducer) Publisher {
+	return &kafkaPublisher{producer: producer}
+}
+
+func (p *kafkaPublisher) Publish(_ context.Context, m Message) error {
+	msg := &sarama.ProducerMessage{
+		Topic: m.Topic,
+		Value: sarama.ByteEncoder(m.Payload),
+	}
+	if m.Key != "" {
+		msg.Key = sarama.StringEncoder(m.Key)
+	}
+
+	_, _, err := p.producer.SendMessage(msg)
+	return err
+}
@@ -0,0 +1,45 @@
+// Package outbox implements the transactional outbox pattern: events are
+// stored alongside the state change that produced them and a Relay publishes
+// them afterwards, so an event is never lost once the change is committed.
+//
+// Delivery is at least once. Messages with the same topic and key are
+// published in the order they were stored; a message that cannot be
+// published holds back the messages queued after it for the same key.
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Message is an event waiting to be published.
+type Message struct {
+	ID      string
+	Topic   string
+	Key     string
+	Payload []byte
+
+	// Attempts counts failed publish attempts; the message is not retried
+	// before NextAttemptAt.
+	Attempts      int
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+// Store holds the messages waiting to be published.
+type Store interface {
+	// Pending returns up to limit unpublished messages, oldest first. It may
+	// leave out messages that are not due yet, along with the messages
+	// queued after them for the same key.
+	Pending(ctx context.Context, limit int) ([]Message, error)
+	// MarkPublished removes a published message.
+	MarkPublished(ctx context.Context, m Message) error
+	// MarkFailed records a failed attempt and when to try again.
+	MarkFailed(ctx context.Context, m Message, cause error, retryAt time.Time) error
+}
+
+// Publisher delivers messages to the broker. Publish must only return nil
+// once the broker has acknowledged the message.
+type Publisher interface {
+	Publish(ctx context.Context, m Message) error
+}
@@ -0,0 +1,131 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// lockID is the Postgres advisory lock held by the relaying replica.
+const lockID = 0x6f7574626f78
+
+// Execer is implemented by *sql.DB and *sql.Tx.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Enqueue stores messages in the outbox table. Pass the transaction that
+// writes the state change so both are committed or rolled back together.
+func Enqueue(ctx context.Context, tx Execer, messages ...Message) error {
+	for _, m := range messages {
+		_, err := tx.ExecContext(ctx, `INSERT INTO outbox (topic, message_key, payload) VALUES ($1, $2, $3)`,
+			m.Topic, m.Key, m.Payload)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PostgresStore reads the outbox table written by Enqueue. Only one replica
+// relays at a time: Pending returns nothing unless this store holds the
+// advisory lock, which it keeps on a dedicated connection.
+type PostgresStore struct {
+	db *sql.DB
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Pending(ctx context.Context, limit int) ([]Message, error) {
+	leader, err := s.acquire(ctx)
+	if err != nil || !leader {
+		return nil, err
+	}
+
+	// a message is due once its next attempt is, and nothing queued before
+	// it under the same key is waiting for a later attempt
+	rows, err := s.db.QueryContext(ctx, `SELECT id, topic, message_key, payload, attempts, next_attempt_at, created_at
+		FROM outbox o
+		WHERE next_attempt_at <= NOW()
+		AND (message_key = '' OR NOT EXISTS (
+			SELECT 1 FROM outbox b
+			WHERE b.topic = o.topic AND b.message_key = o.message_key AND b.id < o.id AND b.next_attempt_at > NOW()))
+		ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var (
+			m  Message
+			id int64
+		)
+		if err := rows.Scan(&id, &m.Topic, &m.Key, &m.Payload, &m.Attempts, &m.NextAttemptAt, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.ID = strconv.FormatInt(id, 10)
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (s *PostgresStore) MarkPublished(ctx context.Context, m Message) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM outbox WHERE id = $1`, m.ID)
+	return err
+}
+
+func (s *PostgresStore) MarkFailed(ctx context.Context, m Message, cause error, retryAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3`,
+		cause.Error(), retryAt, m.ID)
+	return err
+}
+
+// Close releases the relay lock.
+func (s *PostgresStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// acquire reports whether this store holds the relay lock, taking it if it
+// is free. The lock is lost with its connection and taken again later.
+func (s *PostgresStore) acquire(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		if err := s.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, int64(lockID)).Scan(&locked); err != nil || !locked {
+		conn.Close()
+		return false, err
+	}
+	s.conn = conn
+	return true, nil
+}
@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Relay moves messages from a Store to a Publisher.
+type Relay struct {
+	store     Store
+	publisher Publisher
+
+	// Interval is how often the store is polled.
+	Interval time.Duration
+	// BatchSize is the number of messages read per poll.
+	BatchSize int
+	// Failed messages are retried after MinBackoff, doubling on every
+	// further failure up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func NewRelay(store Store, publisher Publisher) *Relay {
+	return &Relay{
+		store:      store,
+		publisher:  publisher,
+		Interval:   time.Second,
+		BatchSize:  100,
+		MinBackoff: time.Second,
+		MaxBackoff: 5 * time.Minute,
+	}
+}
+
+// Run relays messages until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayOnce(ctx); err != nil {
+			log.Println("outbox relay failed:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of pending messages and returns how many
+// were published.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	messages, err := r.store.Pending(ctx, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	blocked := make(map[string]bool)
+	published := 0
+	for _, m := range messages {
+		key := orderingKey(m)
+		if blocked[key] {
+			continue
+		}
+		if m.NextAttemptAt.After(now) {
+			blocked[key] = true
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, m); err != nil {
+			blocked[key] = true
+			log.Printf("failed to publish outbox message %s to %s (attempt %d): %v", m.ID, m.Topic, m.Attempts+1, err)
+			if err := r.store.MarkFailed(ctx, m, err, now.Add(r.backoff(m.Attempts+1))); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		// if this fails the message is published again on the next poll
+		if err := r.store.MarkPublished(ctx, m); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.MinBackoff
+	for i := 1; i < attempts && backoff < r.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, r.MaxBackoff)
+}
+
+// orderingKey groups the messages that must be published in order. Messages
+// without a key are independent of each other.
+func orderingKey(m Message) string {
+	if m.Key == "" {
+		return "id:" + m.ID
+	}
+	return m.Topic + "/" + m.Key
+}
@@ -0,0 +1,58 @@
+// Package pricing computes order prices. Every service that shows or charges
+// an order total uses it, so they cannot disagree about the amount.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+var (
+	ErrNoLines         = errors.New("pricing: no lines")
+	ErrInvalidQuantity = errors.New("pricing: quantity must be positive")
+	ErrNegativePrice   = errors.New("pricing: negative unit price")
+)
+
+// Line is a quantity of one product at a unit price.
+type Line struct {
+	UnitPrice money.Money
+	Quantity  uint32
+}
+
+// Total returns the price of the line.
+func (l Line) Total() money.Money {
+	return l.UnitPrice.Mul(int64(l.Quantity))
+}
+
+// Validate checks that the line can be priced.
+func (l Line) Validate() error {
+	if l.Quantity == 0 {
+		return ErrInvalidQuantity
+	}
+	if l.UnitPrice.IsNegative() {
+		return fmt.Errorf("%w: %s", ErrNegativePrice, l.UnitPrice)
+	}
+	return nil
+}
+
+// Total adds up the totals of lines. All lines must be valid and in the same
+// currency.
+func Total(lines ...Line) (money.Money, error) {
+	if len(lines) == 0 {
+		return money.Money{}, ErrNoLines
+	}
+
+	total := money.Zero(lines[0].UnitPrice.Currency)
+	for _, l := range lines {
+		if err := l.Validate(); err != nil {
+			return money.Money{}, err
+		}
+		var err error
+		if total, err = total.Add(l.Total()); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,265 @@
+// Package promotions applies coupons to orders. The order service prices
+// orders with it, so a coupon discounts the same lines wherever it is used.
+package promotions
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+// Coupon types.
+const (
+	// TypePercentage takes PercentOff off every line the coupon applies to.
+	TypePercentage = "percentage"
+	// TypeFixed takes AmountOff off the lines the coupon applies to, once
+	// per order.
+	TypeFixed = "fixed"
+)
+
+// Coupon scopes: which lines of an order a coupon discounts.
+const (
+	ScopeCart     = "cart"
+	ScopeProduct  = "product"
+	ScopeCategory = "category"
+)
+
+// MaxPercentOff is 100% in basis points.
+const MaxPercentOff = 10000
+
+var (
+	ErrNotFound          = errors.New("promotions: coupon not found")
+	ErrCodeTaken         = errors.New("promotions: coupon code is already in use")
+	ErrInvalidCoupon     = errors.New("promotions: invalid coupon")
+	ErrInactive          = errors.New("promotions: coupon is not active")
+	ErrNotApplicable     = errors.New("promotions: coupon does not apply to this order")
+	ErrMinOrderValue     = errors.New("promotions: order does not reach the coupon's minimum value")
+	ErrUsageLimit        = errors.New("promotions: coupon has been used up")
+	ErrAccountUsageLimit = errors.New("promotions: coupon was already used by this account")
+)
+
+// Coupon is a discount customers redeem with a code.
+type Coupon struct {
+	ID          uint64
+	Code        string
+	Description string
+	// SellerId is the seller who issued the coupon, which only discounts
+	// their products. It is 0 for coupons issued by admins, which discount
+	// any product.
+	SellerId uint64
+	Type     string
+	// PercentOff is the discount of percentage coupons in basis points:
+	// 1250 is 12.5%.
+	PercentOff int64
+	// AmountOff is the discount of fixed coupons.
+	AmountOff money.Money
+	Scope     string
+	// ProductIds and Categories are the products and categories that
+	// product and category coupons discount.
+	ProductIds []string
+	Categories []string
+	// MinOrderValue, if set, is how much the products of the coupon's seller
+	// (of any seller for admin coupons) must cost before the discount.
+	MinOrderValue *money.Money
+	// MaxRedemptions and MaxRedemptionsPerAccount limit how often the
+	// coupon is used in total and by one account; 0 is unlimited.
+	MaxRedemptions           int
+	MaxRedemptionsPerAccount int
+	// Redemptions counts the orders placed with the coupon that were not
+	// cancelled.
+	Redemptions int
+	// StartsAt and EndsAt, if set, bound when the coupon can be used.
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	Active    bool
+	CreatedAt time.Time
+}
+
+// NormalizeCode uppercases a coupon code; codes are not case sensitive.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks that the coupon can be stored.
+func (c *Coupon) Validate() error {
+	if len(c.Code) < 3 || len(c.Code) > 32 || c.Code != NormalizeCode(c.Code) {
+		return fmt.Errorf("%w: code must be 3 to 32 characters", ErrInvalidCoupon)
+	}
+	for _, r := range c.Code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return fmt.Errorf("%w: code may only contain letters, digits, dashes and underscores", ErrInvalidCoupon)
+		}
+	}
+
+	switch c.Type {
+	case TypePercentage:
+		if c.PercentOff <= 0 || c.PercentOff > MaxPercentOff {
+			return fmt.Errorf("%w: percentage must be between 0.01%% and 100%%", ErrInvalidCoupon)
+		}
+	case TypeFixed:
+		if c.AmountOff.Amount <= 0 || c.AmountOff.Currency == "" {
+			return fmt.Errorf("%w: fixed discount must be a positive amount", ErrInvalidCoupon)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidCoupon, c.Type)
+	}
+
+	switch c.Scope {
+	case ScopeCart:
+	case ScopeProduct:
+		if len(c.ProductIds) == 0 {
+			return fmt.Errorf("%w: product coupons need products", ErrInvalidCoupon)
+		}
+	case ScopeCategory:
+		if len(c.Categories) == 0 {
+			return fmt.Errorf("%w: category coupons need categories", ErrInvalidCoupon)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope %q", ErrInvalidCoupon, c.Scope)
+	}
+
+	if c.MinOrderValue != nil {
+		if c.MinOrderValue.IsNegative() || c.MinOrderValue.Currency == "" {
+			return fmt.Errorf("%w: invalid minimum order value", ErrInvalidCoupon)
+		}
+		if c.Type == TypeFixed && c.MinOrderValue.Currency != c.AmountOff.Currency {
+			return fmt.Errorf("%w: minimum order value and discount must be in the same currency", ErrInvalidCoupon)
+		}
+	}
+	if c.MaxRedemptions < 0 || c.MaxRedemptionsPerAccount < 0 {
+		return fmt.Errorf("%w: usage limits cannot be negative", ErrInvalidCoupon)
+	}
+	if c.StartsAt != nil && c.EndsAt != nil && !c.EndsAt.After(*c.StartsAt) {
+		return fmt.Errorf("%w: coupon must end after it starts", ErrInvalidCoupon)
+	}
+	return nil
+}
+
+// ActiveAt reports whether the coupon can be used at t.
+func (c *Coupon) ActiveAt(t time.Time) bool {
+	return c.Active && (c.StartsAt == nil || !t.Before(*c.StartsAt)) && (c.EndsAt == nil || t.Before(*c.EndsAt))
+}
+
+// currency is the currency of the orders the coupon applies to, or "" if it
+// applies to orders in any currency.
+func (c *Coupon) currency() string {
+	if c.Type == TypeFixed {
+		return c.AmountOff.Currency
+	}
+	if c.MinOrderValue != nil {
+		return c.MinOrderValue.Currency
+	}
+	return ""
+}
+
+// Line is a line of an order as far as coupons are concerned.
+type Line struct {
+	ProductId string
+	SellerId  uint64
+	Category  string
+	Total     money.Money
+}
+
+// Discount is the part of a coupon taken off one line.
+type Discount struct {
+	ProductId string
+	Amount    money.Money
+}
+
+// Apply returns the discounts of a coupon on the lines of an order placed
+// at now, one per discounted line. Usage limits are not checked here; they
+// depend on the orders placed with the coupon.
+func Apply(c *Coupon, lines []Line, now time.Time) ([]Discount, error) {
+	if !c.ActiveAt(now) {
+		return nil, ErrInactive
+	}
+	if len(lines) == 0 {
+		return nil, ErrNotApplicable
+	}
+	currency := lines[0].Total.Currency
+	if cc := c.currency(); cc != "" && cc != currency {
+		return nil, fmt.Errorf("%w: coupon is for orders in %s", ErrNotApplicable, cc)
+	}
+
+	var (
+		eligible    []Line
+		sellerTotal = money.Zero(currency)
+		eligibleSum = money.Zero(currency)
+		err         error
+	)
+	for _, l := range lines {
+		if c.SellerId != 0 && l.SellerId != c.SellerId {
+			continue
+		}
+		if sellerTotal, err = sellerTotal.Add(l.Total); err != nil {
+			return nil, err
+		}
+		if c.covers(l) {
+			eligible = append(eligible, l)
+			if eligibleSum, err = eligibleSum.Add(l.Total); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if c.MinOrderValue != nil && sellerTotal.Amount < c.MinOrderValue.Amount {
+		return nil, fmt.Errorf("%w of %s", ErrMinOrderValue, c.MinOrderValue)
+	}
+	if len(eligible) == 0 || eligibleSum.IsZero() {
+		return nil, ErrNotApplicable
+	}
+
+	var discounts []Discount
+	switch c.Type {
+	case TypePercentage:
+		for _, l := range eligible {
+			amount := l.Total.MulRate(float64(c.PercentOff)/MaxPercentOff, money.Down)
+			discounts = append(discounts, Discount{ProductId: l.ProductId, Amount: amount})
+		}
+	case TypeFixed:
+		discounts = spread(min(c.AmountOff.Amount, eligibleSum.Amount), eligible, eligibleSum.Amount)
+	}
+
+	return slices.DeleteFunc(discounts, func(d Discount) bool { return d.Amount.IsZero() }), nil
+}
+
+// covers reports whether the coupon's scope includes the line.
+func (c *Coupon) covers(l Line) bool {
+	switch c.Scope {
+	case ScopeProduct:
+		return slices.Contains(c.ProductIds, l.ProductId)
+	case ScopeCategory:
+		return l.Category != "" && slices.Contains(c.Categories, l.Category)
+	}
+	return true
+}
+
+// spread splits amount over lines in proportion to their totals, which add
+// up to total. Minor units left over by rounding go to the first lines.
+func spread(amount int64, lines []Line, total int64) []Discount {
+	discounts := make([]Discount, len(lines))
+	left := amount
+	for i, l := range lines {
+		share := amount * l.Total.Amount / total
+		discounts[i] = Discount{ProductId: l.ProductId, Amount: money.New(share, l.Total.Currency)}
+		left -= share
+	}
+	for i := 0; left > 0; i++ {
+		discounts[i].Amount.Amount++
+		left--
+	}
+	return discounts
+}
+
+// Total adds up discounts in currency.
+func Total(currency string, discounts ...Discount) (money.Money, error) {
+	amounts := make([]money.Money, len(discounts))
+	for i, d := range discounts {
+		amounts[i] = d.Amount
+	}
+	return money.Sum(currency, amounts...)
+}
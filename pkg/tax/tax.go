@@ -0,0 +1,46 @@
+// Package tax names the tax categories products are sold under. The order
+// service charges tax by them and the payment service registers products
+// with the payment provider under them.
+package tax
+
+import "slices"
+
+// Tax categories.
+const (
+	// Physical is tangible goods taxed at the standard rate.
+	Physical = "physical"
+	// PhysicalReduced is tangible goods taxed at a reduced rate where there
+	// is one, such as food and printed books.
+	PhysicalReduced = "physical_reduced"
+	Digital         = "digital"
+	EBook           = "e_book"
+	Saas            = "saas"
+	Edtech          = "edtech"
+	// Exempt is never taxed.
+	Exempt = "exempt"
+)
+
+// DefaultCategory is the category of products sold without one.
+const DefaultCategory = Physical
+
+// Categories are the tax categories products can be sold under.
+var Categories = []string{Physical, PhysicalReduced, Digital, EBook, Saas, Edtech, Exempt}
+
+// Valid reports whether category is a tax category.
+func Valid(category string) bool {
+	return slices.Contains(Categories, category)
+}
+
+// CategoryOf returns category, or DefaultCategory if it is empty.
+func CategoryOf(category string) string {
+	if category == "" {
+		return DefaultCategory
+	}
+	return category
+}
+
+// IsPhysical reports whether products of category are shipped.
+func IsPhysical(category string) bool {
+	category = CategoryOf(category)
+	return category == Physical || category == PhysicalReduced
+}
@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+)
+
+// fakeGroup is a consumer group owning a single partition. Every session
+// delivers the messages from the committed offset on, like a replica
+// rejoining the group after a rebalance or a restart. Methods the consumer
+// does not use are left to the embedded nil ConsumerGroup.
+type fakeGroup struct {
+	sarama.ConsumerGroup
+
+	mu       sync.Mutex
+	messages []*sarama.ConsumerMessage
+	// committed is the offset of the next message to deliver.
+	committed int64
+	sessions  [][]string
+	closed    bool
+	errors    chan error
+	// drained is called once every message is committed.
+	drained func()
+}
+
+func newFakeGroup(values ...string) *fakeGroup {
+	g := &fakeGroup{errors: make(chan error, 16), drained: func() {}}
+	for i, value := range values {
+		g.messages = append(g.messages, &sarama.ConsumerMessage{Topic: "product_events", Offset: int64(i), Value: []byte(value)})
+	}
+	return g
+}
+
+func (g *fakeGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return sarama.ErrClosedConsumerGroup
+	}
+	g.sessions = append(g.sessions, topics)
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(g.messages))}
+	for _, msg := range g.messages[g.committed:] {
+		claim.messages <- msg
+	}
+	g.mu.Unlock()
+
+	session := &fakeSession{ctx: ctx, group: g}
+	if err := handler.Setup(session); err != nil {
+		return err
+	}
+	// like sarama, a failed claim ends the session and is reported on the
+	// errors channel
+	if err := handler.ConsumeClaim(session, claim); err != nil {
+		g.errors <- err
+	}
+	return handler.Cleanup(session)
+}
+
+func (g *fakeGroup) Errors() <-chan error { return g.errors }
+
+func (g *fakeGroup) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.closed {
+		g.closed = true
+		close(g.errors)
+	}
+	return nil
+}
+
+func (g *fakeGroup) state() (committed int64, sessions [][]string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.committed, slices.Clone(g.sessions)
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx   context.Context
+	group *fakeGroup
+}
+
+func (s *fakeSession) Context() context.Context { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	g := s.group
+	g.mu.Lock()
+	g.committed = msg.Offset + 1
+	drained := g.committed == int64(len(g.messages))
+	g.mu.Unlock()
+
+	if drained {
+		g.drained()
+	}
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// consume runs the consumer until the group committed every message.
+func consume(t *testing.T, consumer *kafka.Consumer, group *fakeGroup, handler kafka.Handler) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	group.drained = cancel
+
+	if err := consumer.Consume(ctx, []string{"product_events"}, handler); err != nil {
+		t.Fatalf("Consume error = %v", err)
+	}
+	if committed, _ := group.state(); committed != int64(len(group.messages)) {
+		t.Fatalf("committed offset = %d, want %d", committed, len(group.messages))
+	}
+}
+
+func TestConsumerCommitsHandledMessages(t *testing.T) {
+	group := newFakeGroup("created", "published", "updated")
+	consumer := kafka.NewConsumerFromGroup(group, "payment-service")
+
+	var handled []string
+	consume(t, consumer, group, func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		handled = append(handled, string(msg.Value))
+		return nil
+	})
+
+	if want := []string{"created", "published", "updated"}; !slices.Equal(handled, want) {
+		t.Errorf("handled = %v, want %v", handled, want)
+	}
+	if _, sessions := group.state(); len(sessions) != 1 || !slices.Equal(sessions[0], []string{"product_events"}) {
+		t.Errorf("sessions = %v, want one session of product_events", sessions)
+	}
+}
+
+func TestConsumerDoesNotCommitFailedMessages(t *testing.T) {
+	group := newFakeGroup("created", "published", "updated")
+	consumer := kafka.NewConsumerFromGroup(group, "payment-service")
+
+	var handled []string
+	var committedOnRetry int64
+	failures := 1
+	consume(t, consumer, group, func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		handled = append(handled, string(msg.Value))
+		if string(msg.Value) != "published" {
+			return nil
+		}
+		if failures > 0 {
+			failures--
+			return errors.New("provider unavailable")
+		}
+		committedOnRetry, _ = group.state()
+		return nil
+	})
+
+	// without retries the failed message is read again in the next session
+	if want := []string{"created", "published", "published", "updated"}; !slices.Equal(handled, want) {
+		t.Errorf("handled = %v, want %v", handled, want)
+	}
+	if committedOnRetry != 1 {
+		t.Errorf("committed offset when redelivered = %d, want 1, before the failed message", committedOnRetry)
+	}
+	if _, sessions := group.state(); len(sessions) != 2 {
+		t.Errorf("sessions = %d, want the failed one and the next", len(sessions))
+	}
+}
+
+func TestConsumerRedeliversMessagesItCouldNotRetry(t *testing.T) {
+	group := newFakeGroup("created", "published", "updated")
+	consumer := kafka.NewConsumerFromGroup(group, "payment-service")
+
+	producer := mocks.NewSyncProducer(t, mocks.NewTestConfig())
+	defer producer.Close()
+	toRetryTopic := func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "product_events.retry.1" {
+			return errors.New("sent to " + msg.Topic)
+		}
+		return nil
+	}
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndFail(toRetryTopic, sarama.ErrOutOfBrokers)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(toRetryTopic)
+	consumer.EnableRetries(kafka.RetryPolicy{Delays: []time.Duration{time.Minute}}, producer)
+
+	var handled []string
+	consume(t, consumer, group, func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+		handled = append(handled, string(msg.Value))
+		if string(msg.Value) == "published" {
+			return errors.New("provider unavailable")
+		}
+		return nil
+	})
+
+	// the message is read again once the retry topic can take it, and the
+	// messages before it are not
+	if want := []string{"created", "published", "published", "updated"}; !slices.Equal(handled, want) {
+		t.Errorf("handled = %v, want %v", handled, want)
+	}
+	_, sessions := group.state()
+	if len(sessions) != 2 {
+		t.Fatalf("sessions = %d, want the failed one and the next", len(sessions))
+	}
+	if want := []string{"product_events", "product_events.retry.1"}; !slices.Equal(sessions[0], want) {
+		t.Errorf("topics = %v, want %v", sessions[0], want)
+	}
+}
+
+func TestConsumerStopsWhenTheGroupCloses(t *testing.T) {
+	group := newFakeGroup()
+	consumer := kafka.NewConsumerFromGroup(group, "payment-service")
+	if err := consumer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := consumer.Consume(context.Background(), []string{"product_events"}, func(context.Context, *sarama.ConsumerMessage) error {
+		t.Error("handler called after Close")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Consume error = %v, want nil after Close", err)
+	}
+}
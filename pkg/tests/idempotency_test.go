@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abhiii71/orderStream/pkg/idempotency"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	keys := idempotency.NewMemoryStore(idempotency.DefaultTTL)
+	calls := 0
+	run := func(ctx context.Context) (*wrapperspb.StringValue, error) {
+		calls++
+		return wrapperspb.String("order-1"), nil
+	}
+
+	ctx := context.Background()
+	request := wrapperspb.String("two headphones")
+	for range 2 {
+		res, err := idempotency.Do(ctx, keys, "PostOrder", "key-1", request, run)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.GetValue() != "order-1" {
+			t.Errorf("response = %q, want order-1", res.GetValue())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("ran %d times, want once", calls)
+	}
+
+	_, err := idempotency.Do(ctx, keys, "PostOrder", "key-1", wrapperspb.String("one headphone"), run)
+	if !errors.Is(err, idempotency.ErrKeyReused) {
+		t.Errorf("different request error = %v, want ErrKeyReused", err)
+	}
+
+	if _, err := idempotency.Do(ctx, keys, "PostOrder", "", request, run); err != nil || calls != 2 {
+		t.Errorf("without a key: err = %v, calls = %d, want a new call", err, calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnFailure(t *testing.T) {
+	keys := idempotency.NewMemoryStore(idempotency.DefaultTTL)
+	ctx := context.Background()
+	request := wrapperspb.String("checkout")
+
+	failure := errors.New("provider unavailable")
+	_, err := idempotency.Do(ctx, keys, "Checkout", "key-2", request, func(ctx context.Context) (*wrapperspb.StringValue, error) {
+		return nil, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("error = %v, want %v", err, failure)
+	}
+
+	res, err := idempotency.Do(ctx, keys, "Checkout", "key-2", request, func(ctx context.Context) (*wrapperspb.StringValue, error) {
+		return wrapperspb.String("https://checkout"), nil
+	})
+	if err != nil || res.GetValue() != "https://checkout" {
+		t.Errorf("retry = %v, %v; want the retry to run", res, err)
+	}
+}
+
+func TestIdempotencyKeyInMetadata(t *testing.T) {
+	ctx := idempotency.OutgoingContext(idempotency.WithKey(context.Background(), "key-3"))
+	md, _ := metadata.FromOutgoingContext(ctx)
+	incoming := metadata.NewIncomingContext(context.Background(), md)
+	if got := idempotency.IncomingKey(incoming); got != "key-3" {
+		t.Errorf("IncomingKey = %q, want key-3", got)
+	}
+}
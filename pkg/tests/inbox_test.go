@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abhiii71/orderStream/pkg/inbox"
+)
+
+// inboxDB is an in-memory database/sql driver that understands the queries
+// of the inbox and of the tests' handlers. Like Postgres, an insert of a key
+// another transaction inserted but has not committed waits for that
+// transaction to end.
+type inboxDB struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	// rows are the committed inbox rows; pending the ones transactions
+	// inserted and have not committed, by transaction.
+	rows    map[string]bool
+	pending map[string]*inboxTx
+	// effects are what handlers wrote, once committed.
+	effects []string
+	// waits is told whenever an insert starts waiting for another
+	// transaction.
+	waits chan struct{}
+}
+
+type inboxTx struct {
+	keys    []string
+	effects []string
+}
+
+var (
+	inboxDBsMu sync.Mutex
+	inboxDBs   = map[string]*inboxDB{}
+)
+
+func init() {
+	sql.Register("inboxtest", inboxDriver{})
+}
+
+// openInboxDB returns a database of its own for the test.
+func openInboxDB(t *testing.T) (*sql.DB, *inboxDB) {
+	t.Helper()
+
+	state := &inboxDB{rows: map[string]bool{}, pending: map[string]*inboxTx{}, waits: make(chan struct{}, 16)}
+	state.cond = sync.NewCond(&state.mu)
+	inboxDBsMu.Lock()
+	inboxDBs[t.Name()] = state
+	inboxDBsMu.Unlock()
+
+	db, err := sql.Open("inboxtest", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, state
+}
+
+func (s *inboxDB) committedEffects() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.effects...)
+}
+
+type inboxDriver struct{}
+
+func (inboxDriver) Open(name string) (driver.Conn, error) {
+	inboxDBsMu.Lock()
+	defer inboxDBsMu.Unlock()
+
+	state, ok := inboxDBs[name]
+	if !ok {
+		return nil, fmt.Errorf("no database %q", name)
+	}
+	return &inboxConn{db: state}, nil
+}
+
+type inboxConn struct {
+	db *inboxDB
+	tx *inboxTx
+}
+
+func (c *inboxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *inboxConn) Close() error { return nil }
+
+func (c *inboxConn) Begin() (driver.Tx, error) {
+	c.tx = &inboxTx{}
+	return c, nil
+}
+
+func (c *inboxConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	for _, key := range c.tx.keys {
+		c.db.rows[key] = true
+		delete(c.db.pending, key)
+	}
+	c.db.effects = append(c.db.effects, c.tx.effects...)
+	c.tx = nil
+	c.db.cond.Broadcast()
+	return nil
+}
+
+func (c *inboxConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	for _, key := range c.tx.keys {
+		delete(c.db.pending, key)
+	}
+	c.tx = nil
+	c.db.cond.Broadcast()
+	return nil
+}
+
+func (c *inboxConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.tx == nil {
+		return nil, errors.New("the tests only write in transactions")
+	}
+
+	switch {
+	case strings.Contains(query, "INSERT INTO inbox"):
+		return c.insertEvent(fmt.Sprintf("%v/%v", args[0].Value, args[1].Value))
+	case strings.Contains(query, "INSERT INTO effects"):
+		c.tx.effects = append(c.tx.effects, fmt.Sprint(args[0].Value))
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+// insertEvent inserts an inbox row unless it exists, waiting for the
+// transaction that inserted it to end first.
+func (c *inboxConn) insertEvent(key string) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	for {
+		if c.db.rows[key] {
+			return driver.RowsAffected(0), nil
+		}
+		owner, ok := c.db.pending[key]
+		if !ok || owner == c.tx {
+			break
+		}
+		select {
+		case c.db.waits <- struct{}{}:
+		default:
+		}
+		c.db.cond.Wait()
+	}
+
+	c.db.pending[key] = c.tx
+	c.tx.keys = append(c.tx.keys, key)
+	return driver.RowsAffected(1), nil
+}
+
+// writeEffect is a handler writing to the database in the event's
+// transaction.
+func writeEffect(db *sql.DB, effect string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := inbox.Conn(ctx, db).ExecContext(ctx, `INSERT INTO effects (name) VALUES ($1)`, effect)
+		return err
+	}
+}
+
+func TestInboxSkipsDuplicateDeliveries(t *testing.T) {
+	db, state := openInboxDB(t)
+	events := inbox.New(db, "payment")
+
+	for i, want := range []bool{true, false} {
+		processed, err := events.Process(context.Background(), "evt-1", writeEffect(db, "refund order 42"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if processed != want {
+			t.Errorf("delivery %d: processed = %t, want %t", i+1, processed, want)
+		}
+	}
+	if got := state.committedEffects(); len(got) != 1 {
+		t.Errorf("effects = %v, want the event applied once", got)
+	}
+
+	// other consumers keep their own inbox
+	processed, err := inbox.New(db, "recommender").Process(context.Background(), "evt-1", writeEffect(db, "record purchase"))
+	if err != nil || !processed {
+		t.Errorf("another consumer: processed = %t, err = %v, want the event processed", processed, err)
+	}
+}
+
+func TestInboxRollsBackFailedHandlers(t *testing.T) {
+	db, state := openInboxDB(t)
+	events := inbox.New(db, "payment")
+
+	failure := errors.New("provider unavailable")
+	processed, err := events.Process(context.Background(), "evt-1", func(ctx context.Context) error {
+		if err := writeEffect(db, "half a refund")(ctx); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) || processed {
+		t.Fatalf("Process = %t, %v, want false, %v", processed, err, failure)
+	}
+	if got := state.committedEffects(); len(got) != 0 {
+		t.Errorf("effects = %v, want the failed handler's writes rolled back", got)
+	}
+
+	// the event id was rolled back too, so a redelivery runs the handler
+	processed, err = events.Process(context.Background(), "evt-1", writeEffect(db, "refund order 42"))
+	if err != nil || !processed {
+		t.Fatalf("redelivery: processed = %t, err = %v, want the event processed", processed, err)
+	}
+	if got := state.committedEffects(); len(got) != 1 || got[0] != "refund order 42" {
+		t.Errorf("effects = %v, want only the redelivery's", got)
+	}
+}
+
+func TestInboxRunsConcurrentDeliveriesOnce(t *testing.T) {
+	db, state := openInboxDB(t)
+	events := inbox.New(db, "payment")
+
+	started, release := make(chan struct{}), make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		_, err := events.Process(context.Background(), "evt-1", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return writeEffect(db, "refund order 42")(ctx)
+		})
+		first <- err
+	}()
+	<-started
+
+	type result struct {
+		processed bool
+		err       error
+	}
+	second := make(chan result, 1)
+	go func() {
+		processed, err := events.Process(context.Background(), "evt-1", writeEffect(db, "refund order 42 again"))
+		second <- result{processed, err}
+	}()
+
+	// the second delivery waits for the first one's transaction
+	<-state.waits
+	close(release)
+
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	if r := <-second; r.err != nil || r.processed {
+		t.Errorf("second delivery: processed = %t, err = %v, want it skipped", r.processed, r.err)
+	}
+	if got := state.committedEffects(); len(got) != 1 || got[0] != "refund order 42" {
+		t.Errorf("effects = %v, want the event applied once", got)
+	}
+}
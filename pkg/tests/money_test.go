@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/money/proto/pb"
+)
+
+func TestFromFloatRoundsToMinorUnits(t *testing.T) {
+	cases := []struct {
+		amount   float64
+		currency string
+		want     int64
+	}{
+		{0.29, "USD", 29},
+		{19.99, "USD", 1999},
+		{0.125, "EUR", 13},
+		{-0.29, "USD", -29},
+		{1500, "JPY", 1500},
+		{1499.6, "JPY", 1500},
+	}
+	for _, c := range cases {
+		if got := money.FromFloat(c.amount, c.currency); got.Amount != c.want || got.Currency != c.currency {
+			t.Errorf("FromFloat(%v, %s) = %+v, want %d", c.amount, c.currency, got, c.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in       string
+		currency string
+		want     int64
+	}{
+		{"19.99", "USD", 1999},
+		{"0.29", "USD", 29},
+		{"5", "USD", 500},
+		{"5.5", "USD", 550},
+		{"-3.50", "EUR", -350},
+		{"1200", "JPY", 1200},
+	}
+	for _, c := range cases {
+		got, err := money.Parse(c.in, c.currency)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.in, err)
+		}
+		if got.Amount != c.want {
+			t.Errorf("Parse(%q) = %d, want %d", c.in, got.Amount, c.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.234", "1.5.0", "12.5"} {
+		currency := "USD"
+		if in == "12.5" {
+			currency = "JPY"
+		}
+		if _, err := money.Parse(in, currency); !errors.Is(err, money.ErrInvalidAmount) {
+			t.Errorf("Parse(%q, %s): expected ErrInvalidAmount, got %v", in, currency, err)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	price := money.New(29, "USD")
+
+	total, err := money.Sum("USD", price.Mul(3), money.New(1999, "USD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != money.New(2086, "USD") {
+		t.Fatalf("Sum = %v, want 20.86 USD", total)
+	}
+
+	diff, err := total.Sub(money.New(2100, "USD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.IsNegative() || diff.Amount != -14 {
+		t.Fatalf("Sub = %v, want -0.14 USD", diff)
+	}
+
+	if _, err := price.Add(money.New(1, "EUR")); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := money.Sum("USD", money.New(1, "EUR")); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+
+	if cmp, _ := price.Cmp(money.New(30, "USD")); cmp != -1 {
+		t.Fatalf("Cmp = %d, want -1", cmp)
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	// 12.5 and 13.5 cents
+	cases := []struct {
+		amount int64
+		mode   money.RoundingMode
+		want   int64
+	}{
+		{125, money.HalfUp, 13},
+		{125, money.HalfEven, 12},
+		{135, money.HalfEven, 14},
+		{125, money.Down, 12},
+		{-125, money.HalfUp, -13},
+		{-125, money.Down, -12},
+	}
+	for _, c := range cases {
+		if got := money.New(c.amount, "USD").MulRate(0.1, c.mode); got.Amount != c.want {
+			t.Errorf("MulRate(%d, %v) = %d, want %d", c.amount, c.mode, got.Amount, c.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	got := money.New(1000, "USD").Convert("JPY", 149.5, money.HalfUp)
+	if got != money.New(1495, "JPY") {
+		t.Fatalf("Convert to JPY = %v, want 1495 JPY", got)
+	}
+
+	got = money.New(1999, "USD").Convert("EUR", 0.9, money.HalfUp)
+	if got != money.New(1799, "EUR") {
+		t.Fatalf("Convert to EUR = %v, want 17.99 EUR", got)
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	cases := []struct {
+		m    money.Money
+		want string
+	}{
+		{money.New(123450, "USD"), "1234.50 USD"},
+		{money.New(5, "USD"), "0.05 USD"},
+		{money.New(-29, "EUR"), "-0.29 EUR"},
+		{money.New(1500, "JPY"), "1500 JPY"},
+	}
+	for _, c := range cases {
+		if got := c.m.String(); got != c.want {
+			t.Errorf("String() = %q, want %q", got, c.want)
+		}
+	}
+
+	if got := money.New(29, "USD").Float(); got != 0.29 {
+		t.Errorf("Float() = %v, want 0.29", got)
+	}
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	m := money.New(1999, "INR")
+	if got := money.FromProto(money.ToProto(m)); got != m {
+		t.Fatalf("round trip = %v, want %v", got, m)
+	}
+	if got := money.FromProto((*pb.Money)(nil)); got != (money.Money{}) {
+		t.Fatalf("FromProto(nil) = %v, want zero", got)
+	}
+}
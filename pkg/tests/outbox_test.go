@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/outbox"
+)
+
+type memoryStore struct {
+	messages []outbox.Message
+}
+
+func (s *memoryStore) Pending(_ context.Context, limit int) ([]outbox.Message, error) {
+	return append([]outbox.Message(nil), s.messages[:min(limit, len(s.messages))]...), nil
+}
+
+func (s *memoryStore) MarkPublished(_ context.Context, m outbox.Message) error {
+	for i := range s.messages {
+		if s.messages[i].ID == m.ID {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(_ context.Context, m outbox.Message, _ error, retryAt time.Time) error {
+	for i := range s.messages {
+		if s.messages[i].ID == m.ID {
+			s.messages[i].Attempts++
+			s.messages[i].NextAttemptAt = retryAt
+		}
+	}
+	return nil
+}
+
+type recordingPublisher struct {
+	published []string
+	failing   map[string]bool
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, m outbox.Message) error {
+	if p.failing[m.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, m.ID)
+	return nil
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	store := &memoryStore{messages: []outbox.Message{
+		{ID: "1", Topic: "orders", Key: "a"},
+		{ID: "2", Topic: "orders", Key: "b"},
+		{ID: "3", Topic: "orders", Key: "a"},
+	}}
+	publisher := &recordingPublisher{}
+
+	n, err := outbox.NewRelay(store, publisher).RelayOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || len(store.messages) != 0 {
+		t.Fatalf("published %d, %d left", n, len(store.messages))
+	}
+	if got := publisher.published; got[0] != "1" || got[1] != "2" || got[2] != "3" {
+		t.Fatalf("published %v, want [1 2 3]", got)
+	}
+}
+
+func TestRelayHoldsBackKeyAfterFailure(t *testing.T) {
+	store := &memoryStore{messages: []outbox.Message{
+		{ID: "1", Topic: "orders", Key: "a"},
+		{ID: "2", Topic: "orders", Key: "b"},
+		{ID: "3", Topic: "orders", Key: "a"},
+		{ID: "4", Topic: "orders"},
+	}}
+	publisher := &recordingPublisher{failing: map[string]bool{"1": true}}
+	relay := outbox.NewRelay(store, publisher)
+
+	if _, err := relay.RelayOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := publisher.published; len(got) != 2 || got[0] != "2" || got[1] != "4" {
+		t.Fatalf("published %v, want [2 4]", got)
+	}
+	if len(store.messages) != 2 || store.messages[0].Attempts != 1 {
+		t.Fatalf("unexpected outbox after failure: %+v", store.messages)
+	}
+
+	// the failed message is not retried before its backoff has passed
+	delete(publisher.failing, "1")
+	if n, _ := relay.RelayOnce(context.Background()); n != 0 {
+		t.Fatalf("published %d messages during backoff", n)
+	}
+
+	store.messages[0].NextAttemptAt = time.Time{}
+	if n, _ := relay.RelayOnce(context.Background()); n != 2 {
+		t.Fatalf("published %d messages after backoff, want 2", n)
+	}
+	if got := publisher.published[2:]; got[0] != "1" || got[1] != "3" {
+		t.Fatalf("published %v, want [1 3]", got)
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/pricing"
+)
+
+func TestPricingTotal(t *testing.T) {
+	total, err := pricing.Total(
+		pricing.Line{UnitPrice: money.New(1999, "USD"), Quantity: 3},
+		pricing.Line{UnitPrice: money.New(29, "USD"), Quantity: 1},
+		pricing.Line{UnitPrice: money.New(0, "USD"), Quantity: 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := money.New(6026, "USD"); total != want {
+		t.Errorf("Total = %v, want %v", total, want)
+	}
+}
+
+func TestPricingLineTotal(t *testing.T) {
+	line := pricing.Line{UnitPrice: money.New(1500, "JPY"), Quantity: 4}
+	if got, want := line.Total(), money.New(6000, "JPY"); got != want {
+		t.Errorf("Line.Total = %v, want %v", got, want)
+	}
+}
+
+func TestPricingTotalErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		lines []pricing.Line
+		want  error
+	}{
+		{"no lines", nil, pricing.ErrNoLines},
+		{"zero quantity", []pricing.Line{{UnitPrice: money.New(100, "USD")}}, pricing.ErrInvalidQuantity},
+		{"negative price", []pricing.Line{{UnitPrice: money.New(-1, "USD"), Quantity: 1}}, pricing.ErrNegativePrice},
+		{"mixed currencies", []pricing.Line{
+			{UnitPrice: money.New(100, "USD"), Quantity: 1},
+			{UnitPrice: money.New(100, "EUR"), Quantity: 1},
+		}, money.ErrCurrencyMismatch},
+	}
+	for _, c := range cases {
+		if _, err := pricing.Total(c.lines...); !errors.Is(err, c.want) {
+			t.Errorf("%s: Total error = %v, want %v", c.name, err, c.want)
+		}
+	}
+}
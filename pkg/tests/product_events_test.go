@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abhiii71/orderStream/pkg/events"
+	"github.com/abhiii71/orderStream/pkg/events/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"google.golang.org/protobuf/proto"
+)
+
+func readGolden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecodeProductEventV1(t *testing.T) {
+	event, err := events.DecodeProductEvent(readGolden(t, "product_updated_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Type != events.ProductUpdated || event.ProductId != "prod-1" || event.ProductVersion != 4 {
+		t.Fatalf("unexpected envelope: %v", event)
+	}
+	if got := money.FromProto(event.Before.Price); got != money.New(1299, "USD") {
+		t.Errorf("before price = %v, want 12.99 USD", got)
+	}
+	if got := money.FromProto(event.After.Price); got != money.New(1499, "USD") {
+		t.Errorf("after price = %v, want 14.99 USD", got)
+	}
+	if len(event.After.PriceOverrides) != 1 || event.After.AccountId != 7 || event.After.PublishAt == nil {
+		t.Errorf("unexpected after snapshot: %v", event.After)
+	}
+}
+
+func TestDecodeLegacyProductEvents(t *testing.T) {
+	event, err := events.DecodeProductEvent(readGolden(t, "product_published_legacy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.SchemaVersion != 0 || event.Type != events.ProductPublished || event.ProductId != "prod-2" {
+		t.Fatalf("unexpected envelope: %v", event)
+	}
+	if got := money.FromProto(event.After.Price); got != money.New(2450, "EUR") {
+		t.Errorf("price = %v, want 24.50 EUR", got)
+	}
+	if event.After.Name != "Lamp" || event.After.AccountId != 9 || event.Before != nil {
+		t.Errorf("unexpected snapshots: before %v, after %v", event.Before, event.After)
+	}
+
+	event, err = events.DecodeProductEvent(readGolden(t, "product_deleted_legacy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != events.ProductDeleted || event.ProductId != "prod-3" {
+		t.Fatalf("unexpected envelope: %v", event)
+	}
+}
+
+func TestProductEventRoundTrip(t *testing.T) {
+	before := &pb.ProductSnapshot{Id: "prod-1", Name: "Mug", Price: money.ToProto(money.New(1299, "USD")), Status: "draft", Version: 1}
+	after := &pb.ProductSnapshot{Id: "prod-1", Name: "Mug", Price: money.ToProto(money.New(1299, "USD")), Status: "published", Version: 2}
+
+	event := events.NewProductEvent(events.ProductPublished, before, after)
+	if event.EventId == "" || event.ProductId != "prod-1" || event.ProductVersion != 2 {
+		t.Fatalf("unexpected envelope: %v", event)
+	}
+
+	data, err := events.EncodeProductEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := events.DecodeProductEvent(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(event, decoded) {
+		t.Fatalf("round trip = %v, want %v", decoded, event)
+	}
+}
+
+func TestDecodeProductEventRejects(t *testing.T) {
+	if _, err := events.DecodeProductEvent([]byte(`{"schemaVersion":2,"type":"product_updated","productId":"prod-1"}`)); !errors.Is(err, events.ErrUnsupportedSchema) {
+		t.Errorf("expected ErrUnsupportedSchema, got %v", err)
+	}
+	for _, data := range []string{`not json`, `{"schemaVersion":1,"type":"product_updated"}`, `{"type":"product_deleted","data":{}}`} {
+		if _, err := events.DecodeProductEvent([]byte(data)); !errors.Is(err, events.ErrInvalidEvent) {
+			t.Errorf("DecodeProductEvent(%s): expected ErrInvalidEvent, got %v", data, err)
+		}
+	}
+}
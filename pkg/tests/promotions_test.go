@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/promotions"
+)
+
+var couponNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func couponLines() []promotions.Line {
+	return []promotions.Line{
+		{ProductId: "mug", SellerId: 1, Category: "kitchen", Total: money.New(1000, "USD")},
+		{ProductId: "tea", SellerId: 1, Category: "grocery", Total: money.New(2000, "USD")},
+		{ProductId: "lamp", SellerId: 2, Category: "home", Total: money.New(3001, "USD")},
+	}
+}
+
+func TestPromotionsPercentage(t *testing.T) {
+	coupon := &promotions.Coupon{Type: promotions.TypePercentage, PercentOff: 1250, Scope: promotions.ScopeCart, Active: true}
+
+	discounts, err := promotions.Apply(coupon, couponLines(), couponNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []promotions.Discount{
+		{ProductId: "mug", Amount: money.New(125, "USD")},
+		{ProductId: "tea", Amount: money.New(250, "USD")},
+		// 375.125 is rounded down
+		{ProductId: "lamp", Amount: money.New(375, "USD")},
+	}
+	if len(discounts) != len(want) {
+		t.Fatalf("Apply = %v, want %v", discounts, want)
+	}
+	for i := range want {
+		if discounts[i] != want[i] {
+			t.Errorf("discount %d = %v, want %v", i, discounts[i], want[i])
+		}
+	}
+}
+
+func TestPromotionsFixedIsSpreadOverLines(t *testing.T) {
+	coupon := &promotions.Coupon{Type: promotions.TypeFixed, AmountOff: money.New(1000, "USD"), Scope: promotions.ScopeCart, Active: true}
+
+	discounts, err := promotions.Apply(coupon, couponLines(), couponNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, err := promotions.Total("USD", discounts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := money.New(1000, "USD"); total != want {
+		t.Errorf("discount total = %v, want %v", total, want)
+	}
+	if discounts[0].Amount.Amount != 167 || discounts[1].Amount.Amount != 333 || discounts[2].Amount.Amount != 500 {
+		t.Errorf("discounts = %v, want 167, 333 and 500", discounts)
+	}
+
+	// the discount never exceeds what the lines cost
+	coupon.AmountOff = money.New(100000, "USD")
+	discounts, _ = promotions.Apply(coupon, couponLines(), couponNow)
+	if total, _ := promotions.Total("USD", discounts...); total != money.New(6001, "USD") {
+		t.Errorf("discount total = %v, want the order total", total)
+	}
+}
+
+func TestPromotionsScopes(t *testing.T) {
+	cases := []struct {
+		name   string
+		coupon promotions.Coupon
+		want   []string
+	}{
+		{"seller", promotions.Coupon{SellerId: 1, Scope: promotions.ScopeCart}, []string{"mug", "tea"}},
+		{"product", promotions.Coupon{Scope: promotions.ScopeProduct, ProductIds: []string{"lamp"}}, []string{"lamp"}},
+		{"category", promotions.Coupon{Scope: promotions.ScopeCategory, Categories: []string{"kitchen", "home"}}, []string{"mug", "lamp"}},
+		{"other seller's product", promotions.Coupon{SellerId: 1, Scope: promotions.ScopeProduct, ProductIds: []string{"mug", "lamp"}}, []string{"mug"}},
+	}
+	for _, c := range cases {
+		c.coupon.Type, c.coupon.PercentOff, c.coupon.Active = promotions.TypePercentage, 1000, true
+		discounts, err := promotions.Apply(&c.coupon, couponLines(), couponNow)
+		if err != nil {
+			t.Errorf("%s: Apply error = %v", c.name, err)
+			continue
+		}
+		var got []string
+		for _, d := range discounts {
+			got = append(got, d.ProductId)
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%s: discounted %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: discounted %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPromotionsRejections(t *testing.T) {
+	yesterday, tomorrow := couponNow.Add(-24*time.Hour), couponNow.Add(24*time.Hour)
+	minValue := money.New(3500, "USD")
+	cases := []struct {
+		name   string
+		coupon promotions.Coupon
+		want   error
+	}{
+		{"inactive", promotions.Coupon{}, promotions.ErrInactive},
+		{"not started", promotions.Coupon{Active: true, StartsAt: &tomorrow}, promotions.ErrInactive},
+		{"ended", promotions.Coupon{Active: true, EndsAt: &yesterday}, promotions.ErrInactive},
+		// seller 1 only sells 30.00 of the order
+		{"minimum order value", promotions.Coupon{Active: true, SellerId: 1, MinOrderValue: &minValue}, promotions.ErrMinOrderValue},
+		{"no matching lines", promotions.Coupon{Active: true, Scope: promotions.ScopeCategory, Categories: []string{"toys"}}, promotions.ErrNotApplicable},
+		{"other currency", promotions.Coupon{Active: true, Type: promotions.TypeFixed, AmountOff: money.New(500, "EUR")}, promotions.ErrNotApplicable},
+	}
+	for _, c := range cases {
+		if c.coupon.Type == "" {
+			c.coupon.Type, c.coupon.PercentOff = promotions.TypePercentage, 1000
+		}
+		if c.coupon.Scope == "" {
+			c.coupon.Scope = promotions.ScopeCart
+		}
+		if _, err := promotions.Apply(&c.coupon, couponLines(), couponNow); !errors.Is(err, c.want) {
+			t.Errorf("%s: Apply error = %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestPromotionsValidate(t *testing.T) {
+	valid := func() promotions.Coupon {
+		return promotions.Coupon{Code: "SPRING-10", Type: promotions.TypePercentage, PercentOff: 1000, Scope: promotions.ScopeCart}
+	}
+	if c := valid(); c.Validate() != nil {
+		t.Fatalf("Validate = %v, want nil", c.Validate())
+	}
+
+	cases := []struct {
+		name   string
+		modify func(*promotions.Coupon)
+	}{
+		{"lowercase code", func(c *promotions.Coupon) { c.Code = "spring" }},
+		{"short code", func(c *promotions.Coupon) { c.Code = "AB" }},
+		{"over 100%", func(c *promotions.Coupon) { c.PercentOff = promotions.MaxPercentOff + 1 }},
+		{"fixed without amount", func(c *promotions.Coupon) { c.Type = promotions.TypeFixed }},
+		{"product scope without products", func(c *promotions.Coupon) { c.Scope = promotions.ScopeProduct }},
+		{"unknown scope", func(c *promotions.Coupon) { c.Scope = "everything" }},
+		{"negative limit", func(c *promotions.Coupon) { c.MaxRedemptions = -1 }},
+	}
+	for _, c := range cases {
+		coupon := valid()
+		c.modify(&coupon)
+		if err := coupon.Validate(); !errors.Is(err, promotions.ErrInvalidCoupon) {
+			t.Errorf("%s: Validate error = %v, want ErrInvalidCoupon", c.name, err)
+		}
+	}
+}
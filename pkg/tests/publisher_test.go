@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+)
+
+func TestPublisherTracksDeliveriesAndFlushes(t *testing.T) {
+	config := mocks.NewTestConfig()
+	config.Producer.Return.Successes = true
+	producer := mocks.NewAsyncProducer(t, config)
+
+	keyed := func(msg *sarama.ProducerMessage) error {
+		key, _ := msg.Key.Encode()
+		if string(key) != "product-1" || len(msg.Headers) != 1 {
+			return errors.New("key or headers missing")
+		}
+		return nil
+	}
+	producer.ExpectInputWithMessageCheckerFunctionAndSucceed(keyed)
+	producer.ExpectInputAndFail(sarama.ErrOutOfBrokers)
+
+	var failed []kafka.Message
+	publisher := kafka.NewPublisherFromProducer(producer, kafka.PublisherConfig{
+		OnError: func(m kafka.Message, err error) {
+			failed = append(failed, m)
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := publisher.Publish(ctx, kafka.Message{
+		Topic:   "interaction_events",
+		Key:     "product-1",
+		Value:   []byte(`{}`),
+		Headers: map[string]string{"trace-id": "abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.PublishJSON(ctx, "interaction_events", "product-2", map[string]string{"type": "product_retrieved"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := publisher.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	m := publisher.Metrics()
+	if m.Published != 2 || m.Delivered != 1 || m.Failed != 1 || m.InFlight != 0 {
+		t.Fatalf("metrics %+v", m)
+	}
+	if len(failed) != 1 || failed[0].Key != "product-2" {
+		t.Fatalf("error callback got %+v", failed)
+	}
+
+	if err := publisher.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.Publish(ctx, kafka.Message{Topic: "interaction_events"}); !errors.Is(err, kafka.ErrPublisherClosed) {
+		t.Fatalf("publish after close returned %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+)
+
+func headers(msg *sarama.ProducerMessage) map[string]string {
+	h := make(map[string]string)
+	for _, header := range msg.Headers {
+		h[string(header.Key)] = string(header.Value)
+	}
+	return h
+}
+
+// redeliver turns a produced message into the message a consumer reads.
+func redeliver(msg *sarama.ProducerMessage, offset int64) *sarama.ConsumerMessage {
+	value, _ := msg.Value.Encode()
+	consumed := &sarama.ConsumerMessage{Topic: msg.Topic, Value: value, Offset: offset}
+	for _, h := range msg.Headers {
+		consumed.Headers = append(consumed.Headers, &sarama.RecordHeader{Key: h.Key, Value: h.Value})
+	}
+	return consumed
+}
+
+func TestRetryPolicyRoutesToRetryTopicsThenDLQ(t *testing.T) {
+	policy := kafka.RetryPolicy{Delays: []time.Duration{time.Minute, time.Hour}}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	msg := &sarama.ConsumerMessage{
+		Topic:     "product_events",
+		Partition: 2,
+		Offset:    41,
+		Value:     []byte(`{"type":"product_published"}`),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("trace-id"), Value: []byte("abc")}},
+	}
+
+	first := policy.Failed(msg, errors.New("dodo unavailable"), now)
+	h := headers(first)
+	if first.Topic != "product_events.retry.1" || h[kafka.HeaderAttempts] != "1" {
+		t.Fatalf("first failure went to %s with %v", first.Topic, h)
+	}
+	if h[kafka.HeaderOriginalPartition] != "2" || h[kafka.HeaderOriginalOffset] != "41" || h["trace-id"] != "abc" {
+		t.Fatalf("headers not carried over: %v", h)
+	}
+	if h[kafka.HeaderRetryAt] != now.Add(time.Minute).Format(time.RFC3339Nano) {
+		t.Fatalf("retry at %s", h[kafka.HeaderRetryAt])
+	}
+
+	if topic := kafka.OriginalTopic(redeliver(first, 0)); topic != "product_events" {
+		t.Fatalf("retried message came from %s", topic)
+	}
+
+	second := policy.Failed(redeliver(first, 0), errors.New("dodo unavailable"), now)
+	if second.Topic != "product_events.retry.2" {
+		t.Fatalf("second failure went to %s", second.Topic)
+	}
+
+	third := policy.Failed(redeliver(second, 0), errors.New("dodo unavailable"), now)
+	h = headers(third)
+	if third.Topic != "product_events.dlq" || h[kafka.HeaderAttempts] != "3" || h[kafka.HeaderRetryAt] != "" {
+		t.Fatalf("third failure went to %s with %v", third.Topic, h)
+	}
+	if h[kafka.HeaderOriginalTopic] != "product_events" || h[kafka.HeaderOriginalOffset] != "41" || h[kafka.HeaderError] != "dodo unavailable" {
+		t.Fatalf("dead letter lost its origin: %v", h)
+	}
+}
+
+func TestRetryPolicyPermanentErrorsSkipRetries(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: "product_events", Value: []byte("not json")}
+
+	out := kafka.DefaultRetryPolicy().Failed(msg, kafka.Permanent(errors.New("malformed")), time.Now())
+	if out.Topic != "product_events.dlq" {
+		t.Fatalf("permanent failure went to %s", out.Topic)
+	}
+}
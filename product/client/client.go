@@ -3,12 +3,15 @@ package client
 import (
 	"context"
 	"log"
+	"time"
 
+	"github.com/abhiii71/orderStream/pkg/money"
+	moneypb "github.com/abhiii71/orderStream/pkg/money/proto/pb"
 	"github.com/abhiii71/orderStream/product/models"
 	"github.com/abhiii71/orderStream/product/proto/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/protobuf/types/known/wrapperspb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Client struct {
@@ -32,27 +35,26 @@ func (c *Client) Close() {
 	}
 }
 
-func (c *Client) GetProduct(ctx context.Context, id string) (*models.Product, error) {
-	res, err := c.service.GetProduct(ctx, &wrapperspb.StringValue{Value: id})
+func (c *Client) GetProduct(ctx context.Context, id string, viewerAccountId int64, displayCurrency string) (*models.Product, error) {
+	res, err := c.service.GetProduct(ctx, &pb.GetProductRequest{Id: id, ViewerAccountId: viewerAccountId, DisplayCurrency: displayCurrency})
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.Product{
-		Id:         res.Product.Id,
-		Name:       res.Product.Name,
-		Description: res.Product.Description,
-		Price:      res.Product.Price,
-		AccountId:  int(res.Product.GetAccountId()),
-	}, nil
+	return productFromProto(res.Product), nil
 }
 
-func (c *Client) GetProducts(ctx context.Context, skip, take uint64, ids []string, query string) ([]models.Product, error) {
+func (c *Client) GetProducts(ctx context.Context, skip, take uint64, ids []string, query string, filter models.ProductFilter) ([]models.Product, error) {
 	res, err := c.service.GetProducts(ctx, &pb.GetProductsRequest{
-		Skip:  skip,
-		Take:  take,
-		Ids:   ids,
-		Query: query,
+		Skip:            skip,
+		Take:            take,
+		Ids:             ids,
+		Query:           query,
+		ViewerAccountId: int64(filter.ViewerId),
+		MinRating:       filter.MinRating,
+		SortBy:          filter.SortBy,
+		DisplayCurrency: filter.DisplayCurrency,
+		IncludeHidden:   filter.IncludeHidden,
 	})
 	if err != nil {
 		return nil, err
@@ -60,59 +62,367 @@ func (c *Client) GetProducts(ctx context.Context, skip, take uint64, ids []strin
 
 	var products []models.Product
 	for _, p := range res.Products {
-		products = append(products, models.Product{
-			Id:          p.Id,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			AccountId:   int(p.AccountId),
-		})
+		products = append(products, *productFromProto(p))
 	}
 	return products, nil
 }
 
-func (c *Client) PostProduct(ctx context.Context, name, description string, price float64, acccountId int64) (*models.Product, error) {
-	res, err := c.service.PostProduct(ctx, &pb.CreateProductRequest{
-		Name:        name,
-		Description: description,
-		Price:       price,
-		AccountId:   acccountId,
+// ListProductsByAccount returns the seller's own products, including drafts,
+// archived products and owner-only fields.
+func (c *Client) ListProductsByAccount(ctx context.Context, accountId int64, statuses []string, skip, take uint64) ([]models.Product, error) {
+	res, err := c.service.ListProductsByAccount(ctx, &pb.ListProductsByAccountRequest{
+		AccountId: accountId,
+		Statuses:  statuses,
+		Skip:      skip,
+		Take:      take,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.Product{
-		Id:         res.Product.Id,
-		Name:       res.Product.Name,
-		Description: res.Product.Description,
-		Price:      res.Product.Price,
-		AccountId:  int(res.Product.GetAccountId()),
-	}, nil
+	var products []models.Product
+	for _, p := range res.Products {
+		products = append(products, *productFromProto(p))
+	}
+	return products, nil
+}
+
+func (c *Client) PostProduct(ctx context.Context, name, description, sku, category, taxCategory string, weightGrams int64, price money.Money, overrides []money.Money, stock *int, acccountId int64, status string, publishAt *time.Time) (*models.Product, error) {
+	request := &pb.CreateProductRequest{
+		Name:           name,
+		Description:    description,
+		Sku:            sku,
+		Category:       category,
+		TaxCategory:    taxCategory,
+		WeightGrams:    weightGrams,
+		Price:          money.ToProto(price),
+		PriceOverrides: priceOverridesToProto(overrides),
+		Stock:          stockToProto(stock),
+		AccountId:      acccountId,
+		Status:         status,
+	}
+	if publishAt != nil {
+		request.PublishAt = timestamppb.New(*publishAt)
+	}
+
+	res, err := c.service.PostProduct(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return productFromProto(res.Product), nil
 }
 
-func (c *Client) UpdateProduct(ctx context.Context, id, name, description string, price float64, accountId int64) (*models.Product, error) {
+func (c *Client) UpdateProduct(ctx context.Context, id, name, description string, sku, category, taxCategory *string, weightGrams *int64, price money.Money, overrides []money.Money, stock *int, untrackStock bool, accountId, version int64) (*models.Product, error) {
 	res, err := c.service.UpdateProduct(ctx, &pb.UpdateProductRequest{
-		Id:          id,
-		Name:        name,
-		Description: description,
-		Price:       price,
-		AccountId:   accountId,
+		Id:             id,
+		Name:           name,
+		Description:    description,
+		Sku:            sku,
+		Category:       category,
+		TaxCategory:    taxCategory,
+		WeightGrams:    weightGrams,
+		Price:          money.ToProto(price),
+		PriceOverrides: priceOverridesToProto(overrides),
+		Stock:          stockToProto(stock),
+		UntrackStock:   untrackStock,
+		AccountId:      accountId,
+		Version:        version,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.Product{
-		Id:         res.Product.Id,
-		Name:       res.Product.Name,
-		Description: res.Product.Description,
-		Price:      res.Product.Price,
-		AccountId:  int(res.Product.GetAccountId()),
-	}, nil
+	return productFromProto(res.Product), nil
 }
 
 func (c *Client) DeleteProduct(ctx context.Context, productId string, accountId int64) error {
 	_, err := c.service.DeleteProduct(ctx, &pb.DeleteProductRequest{ProductId: productId, AccountId: accountId})
 	return err
 }
+
+func (c *Client) RestoreProduct(ctx context.Context, productId string, accountId int64) (*models.Product, error) {
+	res, err := c.service.RestoreProduct(ctx, &pb.RestoreProductRequest{ProductId: productId, AccountId: accountId})
+	if err != nil {
+		return nil, err
+	}
+
+	return productFromProto(res.Product), nil
+}
+
+func (c *Client) ListDeletedProducts(ctx context.Context, accountId int64, skip, take uint64) ([]models.Product, error) {
+	res, err := c.service.ListDeletedProducts(ctx, &pb.ListDeletedProductsRequest{AccountId: accountId, Skip: skip, Take: take})
+	if err != nil {
+		return nil, err
+	}
+
+	var products []models.Product
+	for _, p := range res.Products {
+		products = append(products, *productFromProto(p))
+	}
+	return products, nil
+}
+
+func (c *Client) UploadProductImage(ctx context.Context, productId string, accountId int64, contentType string, data []byte) (*models.Product, error) {
+	res, err := c.service.UploadProductImage(ctx, &pb.UploadProductImageRequest{
+		ProductId:   productId,
+		AccountId:   accountId,
+		ContentType: contentType,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return productFromProto(res.Product), nil
+}
+
+func (c *Client) PublishProduct(ctx context.Context, productId string, accountId int64, publishAt *time.Time) (*models.Product, error) {
+	request := &pb.PublishProductRequest{ProductId: productId, AccountId: accountId}
+	if publishAt != nil {
+		request.PublishAt = timestamppb.New(*publishAt)
+	}
+
+	res, err := c.service.PublishProduct(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return productFromProto(res.Product), nil
+}
+
+func (c *Client) ArchiveProduct(ctx context.Context, productId string, accountId int64) (*models.Product, error) {
+	res, err := c.service.ArchiveProduct(ctx, &pb.ArchiveProductRequest{ProductId: productId, AccountId: accountId})
+	if err != nil {
+		return nil, err
+	}
+
+	return productFromProto(res.Product), nil
+}
+
+func (c *Client) CreateReview(ctx context.Context, productId string, accountId int64, rating int, title, body string) (*models.Review, error) {
+	res, err := c.service.CreateReview(ctx, &pb.CreateReviewRequest{
+		ProductId: productId,
+		AccountId: accountId,
+		Rating:    int32(rating),
+		Title:     title,
+		Body:      body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewFromProto(res.Review), nil
+}
+
+func (c *Client) ListReviews(ctx context.Context, productId string, skip, take uint64) ([]models.Review, error) {
+	res, err := c.service.ListReviews(ctx, &pb.ListReviewsRequest{ProductId: productId, Skip: skip, Take: take})
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []models.Review
+	for _, r := range res.Reviews {
+		reviews = append(reviews, *reviewFromProto(r))
+	}
+	return reviews, nil
+}
+
+func (c *Client) UpdateReview(ctx context.Context, reviewId string, accountId int64, rating int, title, body string) (*models.Review, error) {
+	res, err := c.service.UpdateReview(ctx, &pb.UpdateReviewRequest{
+		ReviewId:  reviewId,
+		AccountId: accountId,
+		Rating:    int32(rating),
+		Title:     title,
+		Body:      body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewFromProto(res.Review), nil
+}
+
+func (c *Client) DeleteReview(ctx context.Context, reviewId string, accountId int64) error {
+	_, err := c.service.DeleteReview(ctx, &pb.DeleteReviewRequest{ReviewId: reviewId, AccountId: accountId})
+	return err
+}
+
+func (c *Client) ReplyToReview(ctx context.Context, reviewId string, accountId int64, body string) (*models.Review, error) {
+	res, err := c.service.ReplyToReview(ctx, &pb.ReplyToReviewRequest{ReviewId: reviewId, AccountId: accountId, Body: body})
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewFromProto(res.Review), nil
+}
+
+func (c *Client) GetPriceHistory(ctx context.Context, productId string, skip, take uint64) ([]models.PriceHistoryEntry, error) {
+	res, err := c.service.GetPriceHistory(ctx, &pb.GetPriceHistoryRequest{ProductId: productId, Skip: skip, Take: take})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.PriceHistoryEntry
+	for _, entry := range res.Entries {
+		entries = append(entries, models.PriceHistoryEntry{
+			ProductId:     productId,
+			Price:         money.FromProto(entry.GetPrice()),
+			PreviousPrice: money.FromProto(entry.GetPreviousPrice()),
+			Reason:        entry.GetReason(),
+			ChangedAt:     entry.GetChangedAt().AsTime(),
+		})
+	}
+	return entries, nil
+}
+
+func (c *Client) SchedulePriceChange(ctx context.Context, productId string, accountId int64, price money.Money, startAt *time.Time, endAt *time.Time) (*models.ScheduledPrice, error) {
+	request := &pb.SchedulePriceChangeRequest{ProductId: productId, AccountId: accountId, Price: money.ToProto(price)}
+	if startAt != nil {
+		request.StartAt = timestamppb.New(*startAt)
+	}
+	if endAt != nil {
+		request.EndAt = timestamppb.New(*endAt)
+	}
+
+	res, err := c.service.SchedulePriceChange(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledPriceFromProto(res.Change), nil
+}
+
+func (c *Client) ListScheduledPrices(ctx context.Context, productId string, accountId int64) ([]models.ScheduledPrice, error) {
+	res, err := c.service.ListScheduledPrices(ctx, &pb.ListScheduledPricesRequest{ProductId: productId, AccountId: accountId})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []models.ScheduledPrice
+	for _, change := range res.Changes {
+		changes = append(changes, *scheduledPriceFromProto(change))
+	}
+	return changes, nil
+}
+
+func (c *Client) CancelPriceChange(ctx context.Context, changeId string, accountId int64) error {
+	_, err := c.service.CancelPriceChange(ctx, &pb.CancelPriceChangeRequest{ChangeId: changeId, AccountId: accountId})
+	return err
+}
+
+// ReserveStock takes stock for the items of an order under reservationId.
+// It fails with codes.FailedPrecondition if a product is out of stock.
+func (c *Client) ReserveStock(ctx context.Context, reservationId string, items []models.ReservedItem) error {
+	request := &pb.ReserveStockRequest{ReservationId: reservationId}
+	for _, item := range items {
+		request.Items = append(request.Items, &pb.ReservedItem{ProductId: item.ProductId, Quantity: int32(item.Quantity)})
+	}
+
+	_, err := c.service.ReserveStock(ctx, request)
+	return err
+}
+
+func (c *Client) ReleaseStock(ctx context.Context, reservationId string) error {
+	_, err := c.service.ReleaseStock(ctx, &pb.ReleaseStockRequest{ReservationId: reservationId})
+	return err
+}
+
+func productFromProto(p *pb.Product) *models.Product {
+	product := &models.Product{
+		Id:          p.GetId(),
+		Name:        p.GetName(),
+		Description: p.GetDescription(),
+		Sku:         p.GetSku(),
+		Category:    p.GetCategory(),
+		TaxCategory: p.GetTaxCategory(),
+		WeightGrams: p.GetWeightGrams(),
+		Price:       money.FromProto(p.GetPrice()),
+		AccountId:   int(p.GetAccountId()),
+		Status:      p.GetStatus(),
+		Version:     p.GetVersion(),
+
+		RatingAverage: p.GetRatingAverage(),
+		RatingCount:   int(p.GetRatingCount()),
+
+		DisplayPrice: money.FromProto(p.GetDisplayPrice()),
+	}
+	for _, override := range p.GetPriceOverrides() {
+		product.PriceOverrides = append(product.PriceOverrides, money.FromProto(override))
+	}
+	if p.PublishAt != nil {
+		t := p.PublishAt.AsTime()
+		product.PublishAt = &t
+	}
+	if p.DeletedAt != nil {
+		t := p.DeletedAt.AsTime()
+		product.DeletedAt = &t
+	}
+	if p.Stock != nil {
+		stock := int(*p.Stock)
+		product.Stock = &stock
+	}
+
+	for _, image := range p.GetImages() {
+		product.Images = append(product.Images, models.Image{
+			URL:          image.GetUrl(),
+			ThumbnailURL: image.GetThumbnailUrl(),
+			ContentType:  image.GetContentType(),
+			Size:         image.GetSize(),
+			Position:     int(image.GetPosition()),
+		})
+	}
+	return product
+}
+
+func reviewFromProto(r *pb.Review) *models.Review {
+	review := &models.Review{
+		Id:        r.Id,
+		ProductId: r.ProductId,
+		AccountId: int(r.AccountId),
+		Rating:    int(r.Rating),
+		Title:     r.Title,
+		Body:      r.Body,
+		CreatedAt: r.CreatedAt.AsTime(),
+		UpdatedAt: r.UpdatedAt.AsTime(),
+	}
+	if r.Reply != nil {
+		review.Reply = &models.SellerReply{
+			AccountId: int(r.Reply.AccountId),
+			Body:      r.Reply.Body,
+			CreatedAt: r.Reply.CreatedAt.AsTime(),
+		}
+	}
+	return review
+}
+
+func scheduledPriceFromProto(c *pb.ScheduledPrice) *models.ScheduledPrice {
+	change := &models.ScheduledPrice{
+		Id:        c.GetId(),
+		ProductId: c.GetProductId(),
+		Price:     money.FromProto(c.GetPrice()),
+		StartAt:   c.GetStartAt().AsTime(),
+		Status:    c.GetStatus(),
+	}
+	if c.EndAt != nil {
+		t := c.EndAt.AsTime()
+		change.EndAt = &t
+	}
+	return change
+}
+
+func stockToProto(stock *int) *int64 {
+	if stock == nil {
+		return nil
+	}
+	v := int64(*stock)
+	return &v
+}
+
+func priceOverridesToProto(overrides []money.Money) []*moneypb.Money {
+	var res []*moneypb.Money
+	for _, override := range overrides {
+		res = append(res, money.ToProto(override))
+	}
+	return res
+}
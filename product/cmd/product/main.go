@@ -1,28 +1,42 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/IBM/sarama"
+	order "github.com/abhiii71/orderStream/order/client"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/pkg/outbox"
 	"github.com/abhiii71/orderStream/product/config"
 	"github.com/abhiii71/orderStream/product/internal"
 	"github.com/tinrab/retry"
 )
 
 func main() {
-	var repo internal.Repository
+	var (
+		repo      internal.Repository
+		publisher *kafka.Publisher
+	)
 
-	producer, err := sarama.NewAsyncProducer([]string{config.BootstrapServers}, nil)
+	acks, err := kafka.ParseAcks(config.KafkaAcks)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
 	}
-	defer func(producer sarama.AsyncProducer) {
-		err := producer.Close()
+	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+		publisher, err = kafka.NewPublisher(config.BootstrapServers, kafka.PublisherConfig{
+			Acks:       acks,
+			Idempotent: config.KafkaIdempotent,
+		})
 		if err != nil {
 			log.Println(err)
 		}
-	}(producer)
+		return
+	})
 
 	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
 		repo, err = internal.NewElasticRepository(config.ElasticsearchURL)
@@ -33,7 +47,100 @@ func main() {
 	})
 	defer repo.Close()
 
-	log.Println("listening on port 8080...")
-	service := internal.NewProductService(repo, producer)
-	log.Fatal(internal.ListenGRPC(service, 8080))
+	var media internal.MediaStore
+	switch config.MediaStore {
+	case "s3":
+		media = internal.NewS3MediaStore(config.S3Endpoint, config.S3Region, config.S3Bucket, config.S3AccessKey, config.S3SecretKey, config.MediaBaseURL)
+	default:
+		media, err = internal.NewLocalMediaStore(config.MediaDir, config.MediaBaseURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		go func() {
+			log.Printf("serving media on port %d...", config.MediaPort)
+			if err := internal.ServeLocalMedia(config.MediaDir, config.MediaPort); err != nil {
+				log.Println("media server error:", err)
+			}
+		}()
+	}
+
+	orderClient, err := order.NewClient(config.OrderURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer orderClient.Close()
+
+	var rates internal.RateProvider
+	switch config.ExchangeRatesProvider {
+	case "static":
+		rates, err = internal.NewStaticRateProvider(config.ExchangeRatesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "http":
+		rates = internal.NewHTTPRateProvider(config.ExchangeRatesURL, config.ExchangeRatesTTL)
+	}
+
+	service := internal.NewProductService(repo, publisher, media, orderClient, rates)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go internal.StartPublishScheduler(ctx, service, config.PublishSchedulerInterval)
+	go internal.StartPriceScheduler(ctx, service, config.PriceSchedulerInterval)
+	go internal.StartPurgeScheduler(ctx, service, config.PurgeInterval)
+	go internal.BackfillPriceAmounts(ctx, repo)
+
+	// product events are staged in Elasticsearch and relayed to Kafka once
+	// the change they describe is written. Order events release the stock
+	// of cancelled orders; failures go through the retry topics.
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+
+		var producer sarama.SyncProducer
+		retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+			producer, err = kafka.NewSyncProducer(config.BootstrapServers)
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		})
+		defer producer.Close()
+
+		go outbox.NewRelay(internal.NewProductOutbox(repo), outbox.NewKafkaPublisher(producer)).Run(ctx)
+
+		var consumer *kafka.Consumer
+		retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+			consumer, err = kafka.NewConsumer(config.BootstrapServers, config.ConsumerGroup)
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		})
+		defer consumer.Close()
+		consumer.EnableRetries(kafka.DefaultRetryPolicy(), producer)
+
+		if err := internal.NewEventConsumer(consumer, service).StartOrderEventConsumer(ctx); err != nil {
+			log.Println("kafka consumer error:", err)
+		}
+	}()
+
+	go func() {
+		log.Printf("listening on port %d...", config.GrpcPort)
+		log.Fatal(internal.ListenGRPC(service, config.GrpcPort))
+	}()
+
+	<-ctx.Done()
+	flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	select {
+	case <-consumerDone:
+	case <-flushCtx.Done():
+	}
+	if err := publisher.Close(flushCtx); err != nil {
+		log.Println("failed to flush kafka publisher:", err)
+	}
+	m := publisher.Metrics()
+	log.Printf("kafka publisher closed; published=%d delivered=%d failed=%d", m.Published, m.Delivered, m.Failed)
 }
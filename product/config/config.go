@@ -1,13 +1,141 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 var (
 	ElasticsearchURL string
 	BootstrapServers string
+	// KafkaAcks is "all", "leader" or "none"; KafkaIdempotent makes the
+	// broker drop duplicates from producer retries and needs "all".
+	KafkaAcks       string
+	KafkaIdempotent bool
+	// ConsumerGroup is the Kafka consumer group shared by all replicas;
+	// OrderEventsTopic is read to release the stock of cancelled orders.
+	ConsumerGroup    string
+	OrderEventsTopic string
+	// OrderURL is used to check that reviewers actually bought the product.
+	OrderURL string
+
+	// Media storage. MediaStore selects the backend: "local" (default) or "s3".
+	MediaStore    string
+	MediaDir      string
+	MediaBaseURL  string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	MaxImageSize  int64
+	ThumbnailSize int
+	// MaxImagePixels bounds width x height of uploads, which are decoded in
+	// full to make thumbnails.
+	MaxImagePixels int
+
+	// SupportedCurrencies are the currencies products may be priced in.
+	SupportedCurrencies []string
+	// Exchange rates for display conversion. ExchangeRatesProvider is
+	// "static" (rates from ExchangeRatesFile), "http" (rates fetched from
+	// ExchangeRatesURL) or empty to disable conversion.
+	ExchangeRatesProvider string
+	ExchangeRatesFile     string
+	ExchangeRatesURL      string
+	ExchangeRatesTTL      time.Duration
+
+	// PublishSchedulerInterval is how often scheduled drafts are checked.
+	PublishSchedulerInterval time.Duration
+	// PriceSchedulerInterval is how often scheduled price changes are applied.
+	PriceSchedulerInterval time.Duration
+	// TrashRetention is how long deleted products can be restored before
+	// the purge job removes them; PurgeInterval is how often it runs.
+	TrashRetention time.Duration
+	PurgeInterval  time.Duration
+)
+
+const (
+	GrpcPort  int = 8080
+	MediaPort int = 8081
 )
 
 func init() {
 	ElasticsearchURL = os.Getenv("ELASTICSEARCH_URL")
 	BootstrapServers = os.Getenv("KAFKA_BOOTSTRAP_SERVERS")
+	KafkaAcks = os.Getenv("KAFKA_ACKS")
+	KafkaIdempotent = os.Getenv("KAFKA_IDEMPOTENT") != "false"
+	ConsumerGroup = os.Getenv("KAFKA_CONSUMER_GROUP")
+	if ConsumerGroup == "" {
+		ConsumerGroup = "product-service"
+	}
+	OrderEventsTopic = os.Getenv("ORDER_EVENTS_TOPIC")
+	if OrderEventsTopic == "" {
+		OrderEventsTopic = "order_events"
+	}
+	OrderURL = os.Getenv("ORDER_URL")
+
+	MediaStore = os.Getenv("MEDIA_STORE")
+	if MediaStore == "" {
+		MediaStore = "local"
+	}
+	MediaDir = os.Getenv("MEDIA_DIR")
+	if MediaDir == "" {
+		MediaDir = "/var/lib/product/media"
+	}
+	MediaBaseURL = os.Getenv("MEDIA_BASE_URL")
+	if MediaBaseURL == "" && MediaStore == "local" {
+		MediaBaseURL = "http://localhost:8081/media"
+	}
+	S3Endpoint = os.Getenv("S3_ENDPOINT")
+	S3Region = os.Getenv("S3_REGION")
+	if S3Region == "" {
+		S3Region = "us-east-1"
+	}
+	S3Bucket = os.Getenv("S3_BUCKET")
+	S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	S3SecretKey = os.Getenv("S3_SECRET_KEY")
+
+	MaxImageSize = 5 << 20
+	if v, err := strconv.ParseInt(os.Getenv("MAX_IMAGE_SIZE"), 10, 64); err == nil && v > 0 {
+		MaxImageSize = v
+	}
+	ThumbnailSize = 256
+	if v, err := strconv.Atoi(os.Getenv("THUMBNAIL_SIZE")); err == nil && v > 0 {
+		ThumbnailSize = v
+	}
+	MaxImagePixels = 40_000_000
+	if v, err := strconv.Atoi(os.Getenv("MAX_IMAGE_PIXELS")); err == nil && v > 0 {
+		MaxImagePixels = v
+	}
+
+	SupportedCurrencies = []string{"USD", "EUR", "INR"}
+	if v := os.Getenv("SUPPORTED_CURRENCIES"); v != "" {
+		SupportedCurrencies = strings.Split(strings.ToUpper(v), ",")
+	}
+	ExchangeRatesProvider = os.Getenv("EXCHANGE_RATES_PROVIDER")
+	ExchangeRatesFile = os.Getenv("EXCHANGE_RATES_FILE")
+	ExchangeRatesURL = os.Getenv("EXCHANGE_RATES_URL")
+	ExchangeRatesTTL = time.Hour
+	if v, err := time.ParseDuration(os.Getenv("EXCHANGE_RATES_TTL")); err == nil && v > 0 {
+		ExchangeRatesTTL = v
+	}
+
+	PublishSchedulerInterval = time.Minute
+	if v, err := time.ParseDuration(os.Getenv("PUBLISH_SCHEDULER_INTERVAL")); err == nil && v > 0 {
+		PublishSchedulerInterval = v
+	}
+	PriceSchedulerInterval = time.Minute
+	if v, err := time.ParseDuration(os.Getenv("PRICE_SCHEDULER_INTERVAL")); err == nil && v > 0 {
+		PriceSchedulerInterval = v
+	}
+	TrashRetention = 30 * 24 * time.Hour
+	if v, err := time.ParseDuration(os.Getenv("TRASH_RETENTION")); err == nil && v > 0 {
+		TrashRetention = v
+	}
+	PurgeInterval = time.Hour
+	if v, err := time.ParseDuration(os.Getenv("PURGE_INTERVAL")); err == nil && v > 0 {
+		PurgeInterval = v
+	}
 }
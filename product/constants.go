@@ -2,6 +2,71 @@ package product
 
 import "errors"
 
+// Product lifecycle statuses. Documents indexed before statuses existed have
+// no status and are treated as published.
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// DefaultCurrency is the currency of products created before currencies
+// existed and of products created without one.
+const DefaultCurrency = "USD"
+
+// Scheduled price change statuses.
+const (
+	PriceChangePending   = "pending"
+	PriceChangeActive    = "active"
+	PriceChangeDone      = "done"
+	PriceChangeCancelled = "cancelled"
+)
+
+// Price history reasons.
+const (
+	PriceReasonManual    = "manual"
+	PriceReasonScheduled = "scheduled"
+	PriceReasonSaleEnded = "sale_ended"
+)
+
+// Stock reservation statuses.
+const (
+	ReservationReserved = "reserved"
+	ReservationReleased = "released"
+)
+
+// SortByRating orders product listings by average review rating.
+const SortByRating = "rating"
+
+// Review rating bounds.
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
 var (
-	ErrNotFound = errors.New("entity not found")
+	ErrNotFound            = errors.New("entity not found")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrInvalidStatus       = errors.New("invalid product status")
+	ErrAlreadyPublished    = errors.New("product is already published")
+	ErrVersionConflict     = errors.New("product was modified concurrently")
+	ErrInvalidPrice        = errors.New("price must be greater than zero")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrNoExchangeRate      = errors.New("no exchange rate for currency")
+	ErrInvalidSchedule     = errors.New("price change must end after it starts")
+	ErrScheduleOverlap     = errors.New("price change overlaps another scheduled change")
+	ErrScheduleStarted     = errors.New("price change has already been applied")
+	ErrInvalidRating       = errors.New("rating must be between 1 and 5")
+	ErrNotPurchased        = errors.New("only customers who bought this product can review it")
+	ErrAlreadyReviewed     = errors.New("you have already reviewed this product")
+	ErrImageTooLarge       = errors.New("image exceeds maximum allowed size")
+	ErrImageDimensions     = errors.New("image exceeds maximum allowed dimensions")
+	ErrUnsupportedImage    = errors.New("unsupported image content type")
+	ErrContentTypeMismatch = errors.New("image content does not match declared content type")
+	ErrRestoreExpired      = errors.New("product was deleted too long ago to be restored")
+	ErrInvalidStock        = errors.New("stock cannot be negative")
+	ErrOutOfStock          = errors.New("not enough stock")
+	ErrInvalidCategory     = errors.New("category must be a slug of lowercase letters, digits and dashes")
+	ErrInvalidTaxCategory  = errors.New("unknown tax category")
+	ErrInvalidWeight       = errors.New("weight cannot be negative")
 )
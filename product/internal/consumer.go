@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/IBM/sarama"
+	"github.com/abhiii71/orderStream/order"
+	orderModels "github.com/abhiii71/orderStream/order/models"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/product/config"
+)
+
+// EventConsumer puts back the stock reserved for orders that are cancelled.
+type EventConsumer struct {
+	consumer *kafka.Consumer
+	service  Service
+}
+
+func NewEventConsumer(consumer *kafka.Consumer, service Service) *EventConsumer {
+	return &EventConsumer{consumer: consumer, service: service}
+}
+
+// StartOrderEventConsumer consumes order events until ctx is cancelled.
+func (ec *EventConsumer) StartOrderEventConsumer(ctx context.Context) error {
+	return ec.consumer.Consume(ctx, []string{config.OrderEventsTopic}, ec.handleOrderEvent)
+}
+
+func (ec *EventConsumer) handleOrderEvent(ctx context.Context, message *sarama.ConsumerMessage) error {
+	var event orderModels.OrderStatusChangedEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("failed to decode order event: %w", err))
+	}
+	if event.Type != orderModels.OrderStatusChanged || event.Data.To != order.StatusCancelled || event.Data.StockReservationId == "" {
+		return nil
+	}
+
+	// releasing is idempotent, so redelivered events do no harm
+	log.Printf("Releasing stock of cancelled order %d", event.Data.OrderId)
+	if err := ec.service.ReleaseStock(ctx, event.Data.StockReservationId); err != nil {
+		return fmt.Errorf("failed to release stock of order %d: %w", event.Data.OrderId, err)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/events"
+	eventspb "github.com/abhiii71/orderStream/pkg/events/proto/pb"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/outbox"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/models"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	productEventsTopic     = "product_events"
+	interactionEventsTopic = "interaction_events"
+)
+
+// stagedWriteTimeout is how long the relay waits for the write behind an
+// outbox entry to show up before deciding it never happened.
+const stagedWriteTimeout = time.Minute
+
+var lastSequence atomic.Int64
+
+// nextSequence returns increasing numbers that order outbox entries.
+func nextSequence() int64 {
+	for {
+		last, next := lastSequence.Load(), time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if lastSequence.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// writeWithEvents makes a write to a product that produces events of the
+// given types. Elasticsearch cannot store the events and the change
+// atomically, so the events are staged in the outbox first, and the relay
+// only publishes them once the product shows the change. before is the
+// product as it was read for the change, nil for new products.
+func (s *productService) writeWithEvents(ctx context.Context, productId string, before *models.Product, write func() error, eventTypes ...string) error {
+	var snapshot []byte
+	if before != nil {
+		var err error
+		if snapshot, err = protojson.Marshal(productSnapshot(before)); err != nil {
+			return err
+		}
+	}
+
+	var staged []string
+	for _, eventType := range eventTypes {
+		entry := &models.OutboxEntry{
+			ProductId:     productId,
+			Type:          eventType,
+			Sequence:      nextSequence(),
+			Before:        snapshot,
+			StagedAt:      time.Now().UTC(),
+			NextAttemptAt: time.Now().UTC(),
+		}
+		if err := s.repo.PutOutboxEntry(ctx, entry); err != nil {
+			s.discardEvents(staged)
+			return err
+		}
+		staged = append(staged, entry.Id)
+	}
+
+	if err := write(); err != nil {
+		s.discardEvents(staged)
+		return err
+	}
+	return nil
+}
+
+// discardEvents removes the outbox entries of a write that failed. Entries
+// left behind are dropped by the relay after stagedWriteTimeout.
+func (s *productService) discardEvents(ids []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, id := range ids {
+		if err := s.repo.DeleteOutboxEntry(ctx, id); err != nil {
+			log.Printf("failed to discard outbox entry %s: %v", id, err)
+		}
+	}
+}
+
+// productOutbox is the outbox.Store of the product service. It turns staged
+// entries into product events once their write is visible on the product:
+// the product must exist and, for changes to an existing product, its
+// version must have moved past the staged before snapshot. The after
+// snapshot of an event is the before snapshot of the next entry of the
+// product, or the product as it is now.
+type productOutbox struct {
+	repo Repository
+}
+
+func NewProductOutbox(repo Repository) outbox.Store {
+	return &productOutbox{repo: repo}
+}
+
+func (o *productOutbox) Pending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	entries, err := o.repo.ListOutboxEntries(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var productIds []string
+	byProduct := make(map[string][]models.OutboxEntry)
+	for _, entry := range entries {
+		if _, ok := byProduct[entry.ProductId]; !ok {
+			productIds = append(productIds, entry.ProductId)
+		}
+		byProduct[entry.ProductId] = append(byProduct[entry.ProductId], entry)
+	}
+
+	var messages []outbox.Message
+	for _, productId := range productIds {
+		pending, err := o.productMessages(ctx, productId, byProduct[productId])
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, pending...)
+	}
+	return messages, nil
+}
+
+func (o *productOutbox) productMessages(ctx context.Context, productId string, entries []models.OutboxEntry) ([]outbox.Message, error) {
+	current, err := o.repo.GetProductsByID(ctx, productId)
+	if err != nil && !errors.Is(err, product.ErrNotFound) {
+		return nil, err
+	}
+
+	befores := make([]*eventspb.ProductSnapshot, len(entries))
+	for i, entry := range entries {
+		if len(entry.Before) == 0 {
+			continue
+		}
+		befores[i] = &eventspb.ProductSnapshot{}
+		if err := protojson.Unmarshal(entry.Before, befores[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var messages []outbox.Message
+	for i, entry := range entries {
+		before := befores[i]
+		written := current != nil && (before == nil || current.Version > before.Version)
+		if !written {
+			if time.Since(entry.StagedAt) < stagedWriteTimeout {
+				// the write may still be in flight; later entries wait for it
+				break
+			}
+			log.Printf("dropping %s event for product %s: the change was never written", entry.Type, productId)
+			if err := o.repo.DeleteOutboxEntry(ctx, entry.Id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		after := productSnapshot(current)
+		for _, next := range befores[i+1:] {
+			if next != nil {
+				after = next
+				break
+			}
+		}
+
+		event := events.NewProductEvent(entry.Type, before, after)
+		event.EventId, event.OccurredAt = entry.Id, timestamppb.New(entry.StagedAt)
+		payload, err := events.EncodeProductEvent(event)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, outbox.Message{
+			ID:            entry.Id,
+			Topic:         productEventsTopic,
+			Key:           productId,
+			Payload:       payload,
+			Attempts:      entry.Attempts,
+			NextAttemptAt: entry.NextAttemptAt,
+			CreatedAt:     entry.StagedAt,
+		})
+	}
+	return messages, nil
+}
+
+func (o *productOutbox) MarkPublished(ctx context.Context, m outbox.Message) error {
+	return o.repo.DeleteOutboxEntry(ctx, m.ID)
+}
+
+func (o *productOutbox) MarkFailed(ctx context.Context, m outbox.Message, cause error, retryAt time.Time) error {
+	return o.repo.UpdateOutboxEntryAttempts(ctx, m.ID, m.Attempts+1, cause.Error(), retryAt.UTC())
+}
+
+func productSnapshot(p *models.Product) *eventspb.ProductSnapshot {
+	if p == nil {
+		return nil
+	}
+
+	snapshot := &eventspb.ProductSnapshot{
+		Id:          p.Id,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       money.ToProto(p.Price),
+		AccountId:   int64(p.AccountId),
+		Status:      p.Status,
+		Version:     p.Version,
+		TaxCategory: p.TaxCategory,
+	}
+	for _, override := range p.PriceOverrides {
+		snapshot.PriceOverrides = append(snapshot.PriceOverrides, money.ToProto(override))
+	}
+	if p.PublishAt != nil {
+		snapshot.PublishAt = timestamppb.New(*p.PublishAt)
+	}
+	if p.DeletedAt != nil {
+		snapshot.DeletedAt = timestamppb.New(*p.DeletedAt)
+	}
+	return snapshot
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/abhiii71/orderStream/product"
+)
+
+// allowedImageTypes maps accepted upload content types to file extensions.
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ValidateImage checks the declared content type against the allow list and
+// against the sniffed content, and enforces the size limit. It returns the
+// content type that should be stored.
+func ValidateImage(data []byte, declaredType string, maxSize int64) (string, error) {
+	if int64(len(data)) > maxSize {
+		return "", product.ErrImageTooLarge
+	}
+
+	sniffed := http.DetectContentType(data)
+	if _, ok := allowedImageTypes[sniffed]; !ok {
+		return "", product.ErrUnsupportedImage
+	}
+	if declaredType != "" && declaredType != "application/octet-stream" && declaredType != sniffed {
+		return "", product.ErrContentTypeMismatch
+	}
+	return sniffed, nil
+}
+
+// MakeThumbnail scales the image down so that it fits in a size x size box,
+// keeping the aspect ratio. PNG and GIF thumbnails are encoded as PNG to keep
+// transparency, everything else as JPEG. Images of more than maxPixels are
+// rejected before they are decoded.
+func MakeThumbnail(data []byte, contentType string, size, maxPixels int) ([]byte, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > maxPixels/cfg.Height {
+		return nil, "", product.ErrImageDimensions
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	dst := scaleToFit(src, size)
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", err
+	}
+	err = png.Encode(&buf, dst)
+	return buf.Bytes(), "image/png", err
+}
+
+// scaleToFit box-filters src into an image no larger than size x size.
+func scaleToFit(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	tw, th := size, size
+	if w > h {
+		th = max(1, h*size/w)
+	} else {
+		tw = max(1, w*size/h)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0 := b.Min.Y + y*h/th
+		y1 := max(y0+1, b.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			x0 := b.Min.X + x*w/tw
+			x1 := max(x0+1, b.Min.X+(x+1)*w/tw)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MediaStore persists uploaded product media and hands back the public URL
+// clients use to fetch it.
+type MediaStore interface {
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type localMediaStore struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalMediaStore stores media under root on the local filesystem. URLs are
+// built by joining baseURL and the object key, so baseURL should point at
+// whatever serves root (see ServeLocalMedia).
+func NewLocalMediaStore(root, baseURL string) (MediaStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &localMediaStore{root: root, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (s *localMediaStore) path(key string) (string, error) {
+	p := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, filepath.Clean(s.root)+string(os.PathSeparator)) {
+		return "", errors.New("invalid media key")
+	}
+	return p, nil
+}
+
+func (s *localMediaStore) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) (string, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return "", err
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *localMediaStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ServeLocalMedia exposes a local media directory over HTTP under /media/.
+func ServeLocalMedia(root string, port int) error {
+	mux := http.NewServeMux()
+	mux.Handle("/media/", http.StripPrefix("/media/", http.FileServer(http.Dir(root))))
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/abhiii71/orderStream/product"
+)
+
+// RateProvider converts between currencies for display. Checkout always uses
+// the seller's own prices; rates are only used to show an approximate price.
+type RateProvider interface {
+	// Rate returns how many units of to one unit of from is worth.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// rateTable is the on-disk and over-the-wire format of exchange rates: every
+// rate is the value of one unit of Base.
+type rateTable struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func (t *rateTable) rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	fromRate, err := t.baseRate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := t.baseRate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+func (t *rateTable) baseRate(currency string) (float64, error) {
+	if currency == t.Base {
+		return 1, nil
+	}
+	rate, ok := t.Rates[currency]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("%w %s", product.ErrNoExchangeRate, currency)
+	}
+	return rate, nil
+}
+
+func decodeRateTable(r io.Reader) (*rateTable, error) {
+	table := &rateTable{}
+	if err := json.NewDecoder(r).Decode(table); err != nil {
+		return nil, err
+	}
+	if table.Base == "" {
+		return nil, fmt.Errorf("exchange rates: missing base currency")
+	}
+	return table, nil
+}
+
+type staticRateProvider struct {
+	table *rateTable
+}
+
+// NewStaticRateProvider loads fixed exchange rates from a JSON file of the
+// form {"base": "USD", "rates": {"EUR": 0.92, "INR": 83.1}}.
+func NewStaticRateProvider(path string) (RateProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	table, err := decodeRateTable(f)
+	if err != nil {
+		return nil, err
+	}
+	return &staticRateProvider{table}, nil
+}
+
+func (p *staticRateProvider) Rate(_ context.Context, from, to string) (float64, error) {
+	return p.table.rate(from, to)
+}
+
+type httpRateProvider struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	table     *rateTable
+	fetchedAt time.Time
+}
+
+// NewHTTPRateProvider fetches exchange rates in the same format as the static
+// provider from url, caching them for ttl. If a refresh fails the previous
+// rates keep being used.
+func NewHTTPRateProvider(url string, ttl time.Duration) RateProvider {
+	return &httpRateProvider{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *httpRateProvider) Rate(ctx context.Context, from, to string) (float64, error) {
+	table, err := p.rates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return table.rate(from, to)
+}
+
+func (p *httpRateProvider) rates(ctx context.Context) (*rateTable, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.table != nil && time.Since(p.fetchedAt) < p.ttl {
+		return p.table, nil
+	}
+
+	table, err := p.fetch(ctx)
+	if err != nil {
+		if p.table != nil {
+			return p.table, nil
+		}
+		return nil, err
+	}
+	p.table, p.fetchedAt = table, time.Now()
+	return table, nil
+}
+
+func (p *httpRateProvider) fetch(ctx context.Context) (*rateTable, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rates: unexpected status %s", res.Status)
+	}
+	return decodeRateTable(res.Body)
+}
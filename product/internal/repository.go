@@ -3,8 +3,12 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/tax"
 	"github.com/abhiii71/orderStream/product"
 	"github.com/abhiii71/orderStream/product/models"
 	"gopkg.in/olivere/elastic.v5"
@@ -14,11 +18,133 @@ type Repository interface {
 	Close()
 	PutProduct(ctx context.Context, p *models.Product) error
 	GetProductsByID(ctx context.Context, id string) (*models.Product, error)
-	ListProducts(ctx context.Context, skip, take uint64) ([]models.Product, error)
+	ListProducts(ctx context.Context, skip, take uint64, filter models.ProductFilter) ([]models.Product, error)
 	ListProductsWithIDs(ctx context.Context, ids []string) ([]models.Product, error)
-	SearchProducts(ctx context.Context, query string, skip, take uint64) ([]models.Product, error)
+	ListProductsByAccount(ctx context.Context, accountId int, statuses []string, skip, take uint64) ([]models.Product, error)
+	SearchProducts(ctx context.Context, query string, skip, take uint64, filter models.ProductFilter) ([]models.Product, error)
+	ListScheduledProducts(ctx context.Context, before time.Time) ([]models.Product, error)
 	UpdateProduct(ctx context.Context, updateProduct *models.Product) error
-	DeleteProduct(ctx context.Context, productId string) error
+	UpdateProductImages(ctx context.Context, productId string, images []models.Image, version int64) (int64, error)
+	UpdateProductStatus(ctx context.Context, productId, status string, publishAt *time.Time, version int64) (int64, error)
+	UpdateProductDeletedAt(ctx context.Context, productId string, deletedAt *time.Time, version int64) (int64, error)
+	ListDeletedProducts(ctx context.Context, accountId int, skip, take uint64) ([]models.Product, error)
+	ListPurgeableProducts(ctx context.Context, deletedBefore time.Time) ([]models.Product, error)
+	PurgeProduct(ctx context.Context, productId string) error
+	UpdateProductPrice(ctx context.Context, productId string, price money.Money, version int64) (int64, error)
+	ConvertLegacyPrices(ctx context.Context, limit int) (int, error)
+	UpdateProductRating(ctx context.Context, productId string, average float64, count int) error
+
+	PutReview(ctx context.Context, review *models.Review) error
+	GetReview(ctx context.Context, id string) (*models.Review, error)
+	ListReviews(ctx context.Context, productId string, skip, take uint64) ([]models.Review, error)
+	UpdateReview(ctx context.Context, review *models.Review) error
+	DeleteReview(ctx context.Context, id string) error
+	ReviewStats(ctx context.Context, productId string) (float64, int, error)
+
+	PutPriceHistory(ctx context.Context, entry *models.PriceHistoryEntry) error
+	ListPriceHistory(ctx context.Context, productId string, skip, take uint64) ([]models.PriceHistoryEntry, error)
+	PutScheduledPrice(ctx context.Context, change *models.ScheduledPrice) error
+	GetScheduledPrice(ctx context.Context, id string) (*models.ScheduledPrice, error)
+	ListScheduledPrices(ctx context.Context, productId string) ([]models.ScheduledPrice, error)
+	ListDueScheduledPrices(ctx context.Context, now time.Time) ([]models.ScheduledPrice, error)
+	UpdateScheduledPrice(ctx context.Context, change *models.ScheduledPrice) error
+
+	AdjustStock(ctx context.Context, productId string, delta int) error
+	PutStockReservation(ctx context.Context, reservation *models.StockReservation) error
+	GetStockReservation(ctx context.Context, id string) (*models.StockReservation, error)
+	UpdateStockReservationStatus(ctx context.Context, reservation *models.StockReservation, status string) error
+
+	PutOutboxEntry(ctx context.Context, entry *models.OutboxEntry) error
+	ListOutboxEntries(ctx context.Context, limit int) ([]models.OutboxEntry, error)
+	UpdateOutboxEntryAttempts(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error
+	DeleteOutboxEntry(ctx context.Context, id string) error
+}
+
+// moneyMapping stores money.Money values as exact minor units.
+const moneyMapping = `{"properties": {"amount": {"type": "long"}, "currency": {"type": "keyword"}}}`
+
+// indexMappings lists the auxiliary indices created on startup. Product ids
+// are keywords so documents can be filtered and aggregated per product
+// without relying on dynamic mapping.
+var indexMappings = map[string]string{
+	"reviews": `{
+		"mappings": {
+			"review": {
+				"properties": {
+					"productId": {"type": "keyword"},
+					"accountId": {"type": "long"},
+					"rating":    {"type": "integer"},
+					"title":     {"type": "text"},
+					"body":      {"type": "text"},
+					"createdAt": {"type": "date"},
+					"updatedAt": {"type": "date"}
+				}
+			}
+		}
+	}`,
+	"price_history": `{
+		"mappings": {
+			"entry": {
+				"properties": {
+					"productId":     {"type": "keyword"},
+					"price":         ` + moneyMapping + `,
+					"previousPrice": ` + moneyMapping + `,
+					"reason":        {"type": "keyword"},
+					"changedAt":     {"type": "date"}
+				}
+			}
+		}
+	}`,
+	"price_changes": `{
+		"mappings": {
+			"change": {
+				"properties": {
+					"productId":     {"type": "keyword"},
+					"price":         ` + moneyMapping + `,
+					"startAt":       {"type": "date"},
+					"endAt":         {"type": "date"},
+					"status":        {"type": "keyword"},
+					"previousPrice": ` + moneyMapping + `,
+					"createdAt":     {"type": "date"}
+				}
+			}
+		}
+	}`,
+	"stock_reservations": `{
+		"mappings": {
+			"reservation": {
+				"properties": {
+					"items": {
+						"properties": {
+							"productId": {"type": "keyword"},
+							"quantity":  {"type": "integer"}
+						}
+					},
+					"status":    {"type": "keyword"},
+					"createdAt": {"type": "date"}
+				}
+			}
+		}
+	}`,
+	// a single shard makes staged entries searchable in the order they
+	// were written
+	"product_outbox": `{
+		"settings": {"number_of_shards": 1},
+		"mappings": {
+			"entry": {
+				"properties": {
+					"productId":     {"type": "keyword"},
+					"type":          {"type": "keyword"},
+					"sequence":      {"type": "long"},
+					"before":        {"type": "object", "enabled": false},
+					"stagedAt":      {"type": "date"},
+					"attempts":      {"type": "integer"},
+					"lastError":     {"type": "text", "index": false},
+					"nextAttemptAt": {"type": "date"}
+				}
+			}
+		}
+	}`,
 }
 
 type elasticRepository struct {
@@ -33,6 +159,21 @@ func NewElasticRepository(url string) (Repository, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	ctx := context.Background()
+	for index, mapping := range indexMappings {
+		exists, err := client.IndexExists(index).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+		if _, err := client.CreateIndex(index).BodyString(mapping).Do(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	return &elasticRepository{client}, nil
 }
 
@@ -40,11 +181,24 @@ func (r *elasticRepository) Close() {
 	r.client.Stop()
 }
 
+// PutProduct stores a new product under p.Id.
 func (r *elasticRepository) PutProduct(ctx context.Context, p *models.Product) error {
-	res, err := r.client.Index().Index("catalog").Type("product").BodyJson(models.ProductDocument{
+	res, err := r.client.Index().Index("catalog").Type("product").Id(p.Id).OpType("create").BodyJson(models.ProductDocument{
 		Name:        p.Name,
 		Description: p.Description,
-		Price:       p.Price,
+		PriceAmount: p.Price.Amount,
+		Currency:    p.Price.Currency,
+		AccountId:   p.AccountId,
+		Status:      p.Status,
+		PublishAt:   p.PublishAt,
+		Stock:       p.Stock,
+		Sku:         p.Sku,
+		Category:    p.Category,
+		TaxCategory: p.TaxCategory,
+		WeightGrams: p.WeightGrams,
+
+		PriceOverrides: toPriceOverrides(p.PriceOverrides),
+		Revision:       1,
 	}).Do(ctx)
 	if err != nil {
 		log.Println(err)
@@ -52,49 +206,38 @@ func (r *elasticRepository) PutProduct(ctx context.Context, p *models.Product) e
 	}
 
 	p.Id = res.Id
+	p.Version = 1
 	return nil
 }
 
 func (r *elasticRepository) GetProductsByID(ctx context.Context, id string) (*models.Product, error) {
-	res, _ := r.client.Get().Index("catalog").Type("product").Id(id).Do(ctx)
-	if !res.Found {
+	res, err := r.client.Get().Index("catalog").Type("product").Id(id).Do(ctx)
+	if elastic.IsNotFound(err) || (err == nil && !res.Found) {
 		return nil, product.ErrNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	product := models.ProductDocument{}
-	if err := json.Unmarshal(*res.Source, &product); err != nil {
+	doc := models.ProductDocument{}
+	if err := json.Unmarshal(*res.Source, &doc); err != nil {
 		return nil, err
 	}
 
-	return &models.Product{
-		Id:          id,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-	}, nil
+	p := toProduct(id, doc)
+	return &p, nil
 }
 
-func (r *elasticRepository) ListProducts(ctx context.Context, skip, take uint64) ([]models.Product, error) {
-	res, err := r.client.Search().Index("catalog").Type("product").Query(elastic.MatchAllQuery{}).From(int(skip)).Size(int(take)).Do(ctx)
+func (r *elasticRepository) ListProducts(ctx context.Context, skip, take uint64, filter models.ProductFilter) ([]models.Product, error) {
+	query := elastic.NewBoolQuery().Filter(filterQueries(filter)...)
+	search := r.client.Search().Index("catalog").Type("product").Query(query).From(int(skip)).Size(int(take))
+	res, err := sortBy(search, filter).Do(ctx)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	var products []models.Product
-
-	for _, hit := range res.Hits.Hits {
-		product := models.ProductDocument{}
-		if err = json.Unmarshal(*hit.Source, &product); err == nil {
-			products = append(products, models.Product{
-				Id:          hit.Id,
-				Name:        product.Name,
-				Description: product.Description,
-				Price:       product.Price,
-			})
-		}
-	}
-	return products, nil
+	return hitsToProducts(res), nil
 }
 
 func (r *elasticRepository) ListProductsWithIDs(ctx context.Context, ids []string) ([]models.Product, error) {
@@ -111,52 +254,705 @@ func (r *elasticRepository) ListProductsWithIDs(ctx context.Context, ids []strin
 
 	var products []models.Product
 	for _, doc := range res.Docs {
+		if !doc.Found {
+			continue
+		}
 		product := models.ProductDocument{}
 		if err = json.Unmarshal(*doc.Source, &product); err == nil {
-			products = append(products, models.Product{
-				Id:          doc.Id,
-				Name:        product.Name,
-				Description: product.Description,
-				Price:       product.Price,
-			})
+			products = append(products, toProduct(doc.Id, product))
 		}
 	}
 	return products, err
 }
 
-func (r *elasticRepository) SearchProducts(ctx context.Context, query string, skip, take uint64) ([]models.Product, error) {
-	res, err := r.client.Search().Index("catalog").Type("product").Query(elastic.NewMultiMatchQuery(query, "name", "description")).From(int(skip)).Size(int(take)).Do(ctx)
+// ListProductsByAccount returns the catalog of a seller, drafts and archived
+// products included, ordered by name. Products in the trash are left out.
+func (r *elasticRepository) ListProductsByAccount(ctx context.Context, accountId int, statuses []string, skip, take uint64) ([]models.Product, error) {
+	query := elastic.NewBoolQuery().Filter(elastic.NewTermQuery("accountId", accountId), notDeleted())
+	if len(statuses) != 0 {
+		query = query.Filter(hasStatus(statuses))
+	}
+
+	res, err := r.client.Search().Index("catalog").Type("product").Query(query).
+		SortBy(elastic.NewFieldSort("name.keyword").UnmappedType("keyword")).
+		From(int(skip)).Size(int(take)).Do(ctx)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	var products []models.Product
-	for _, hit := range res.Hits.Hits {
-		product := models.ProductDocument{}
-		if err = json.Unmarshal(*hit.Source, &product); err == nil {
-			products = append(products, models.Product{
-				Id:          hit.Id,
-				Name:        product.Name,
-				Description: product.Description,
-				Price:       product.Price,
-			})
-		}
+	return hitsToProducts(res), nil
+}
+
+func (r *elasticRepository) SearchProducts(ctx context.Context, query string, skip, take uint64, filter models.ProductFilter) ([]models.Product, error) {
+	q := elastic.NewBoolQuery().
+		Must(elastic.NewMultiMatchQuery(query, "name", "description")).
+		Filter(filterQueries(filter)...)
+	search := r.client.Search().Index("catalog").Type("product").Query(q).From(int(skip)).Size(int(take))
+	res, err := sortBy(search, filter).Do(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
 	}
-	return products, nil
+
+	return hitsToProducts(res), nil
 }
 
+func (r *elasticRepository) ListScheduledProducts(ctx context.Context, before time.Time) ([]models.Product, error) {
+	query := elastic.NewBoolQuery().Filter(
+		elastic.NewMatchQuery("status", product.StatusDraft),
+		elastic.NewRangeQuery("publishAt").Lte(before),
+		notDeleted(),
+	)
+	res, err := r.client.Search().Index("catalog").Type("product").Query(query).Size(100).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return hitsToProducts(res), nil
+}
+
+// updateRevisionScript writes params.doc to a product that is still at
+// params.revision and moves it to the next revision; a product at any other
+// revision is left alone, which is a noop.
+const updateRevisionScript = `
+long revision = ctx._source.revision == null ? 0 : ((Number) ctx._source.revision).longValue();
+if (revision != ((Number) params.revision).longValue()) {
+	ctx.op = 'none';
+} else {
+	for (entry in params.doc.entrySet()) {
+		ctx._source[entry.getKey()] = entry.getValue();
+	}
+	ctx._source.revision = revision + 1;
+}`
+
+// updateRevision writes the fields of doc to a product if it is still at
+// revision and returns its new revision. It fails with
+// product.ErrVersionConflict if the product was edited in the meantime.
+// Rating and stock updates do not move the revision, so they only make the
+// update retry.
+func (r *elasticRepository) updateRevision(ctx context.Context, productId string, revision int64, doc interface{}) (int64, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return 0, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, err
+	}
+
+	script := elastic.NewScript(updateRevisionScript).Lang("painless").Param("revision", revision).Param("doc", fields)
+	res, err := r.client.Update().Index("catalog").Type("product").Id(productId).Script(script).RetryOnConflict(3).Do(ctx)
+	if elastic.IsNotFound(err) {
+		return 0, product.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if res.Result == "noop" {
+		return 0, product.ErrVersionConflict
+	}
+	return revision + 1, nil
+}
+
+// UpdateProduct overwrites the editable fields of a product, but only if the
+// stored document is still at updateProduct.Version. On success the new
+// version is written back to updateProduct.
 func (r *elasticRepository) UpdateProduct(ctx context.Context, updateProduct *models.Product) error {
-	_, err := r.client.Update().Index("catalog").Type("product").Id(updateProduct.Id).Doc(models.ProductDocument{
+	version, err := r.updateRevision(ctx, updateProduct.Id, updateProduct.Version, models.ProductDocument{
 		Name:        updateProduct.Name,
 		Description: updateProduct.Description,
-		Price:       updateProduct.Price,
-	}).Do(ctx)
+		PriceAmount: updateProduct.Price.Amount,
+		Currency:    updateProduct.Price.Currency,
+		AccountId:   updateProduct.AccountId,
+		Stock:       updateProduct.Stock,
+		Sku:         updateProduct.Sku,
+		Category:    updateProduct.Category,
+		TaxCategory: updateProduct.TaxCategory,
+		WeightGrams: updateProduct.WeightGrams,
+
+		PriceOverrides: toPriceOverrides(updateProduct.PriceOverrides),
+	})
+	if err != nil {
+		return err
+	}
 
+	updateProduct.Version = version
+	return nil
+}
+
+// UpdateProductImages replaces the images of a product if it is still at
+// version, and returns its new version.
+func (r *elasticRepository) UpdateProductImages(ctx context.Context, productId string, images []models.Image, version int64) (int64, error) {
+	return r.updateRevision(ctx, productId, version, map[string]interface{}{
+		"images": images,
+	})
+}
+
+// UpdateProductStatus sets the status of a product if it is still at
+// version, and returns its new version.
+func (r *elasticRepository) UpdateProductStatus(ctx context.Context, productId, status string, publishAt *time.Time, version int64) (int64, error) {
+	return r.updateRevision(ctx, productId, version, map[string]interface{}{
+		"status":    status,
+		"publishAt": publishAt,
+	})
+}
+
+// UpdateProductPrice sets the price of a product if it is still at version,
+// and returns its new version.
+func (r *elasticRepository) UpdateProductPrice(ctx context.Context, productId string, price money.Money, version int64) (int64, error) {
+	return r.updateRevision(ctx, productId, version, map[string]interface{}{
+		"priceAmount": price.Amount,
+		"currency":    price.Currency,
+	})
+}
+
+// convertPricesScript writes the minor unit prices of a legacy product and
+// drops its decimal price. A product written since it was read is left
+// alone; if it still has no minor unit price the next run converts it.
+const convertPricesScript = `
+long revision = ctx._source.revision == null ? 0 : ((Number) ctx._source.revision).longValue();
+if (revision != ((Number) params.revision).longValue() || ctx._source.priceAmount != null) {
+	ctx.op = 'none';
+} else {
+	ctx._source.priceAmount = params.priceAmount;
+	ctx._source.priceOverrides = params.priceOverrides;
+	ctx._source.remove('price');
+}`
+
+// ConvertLegacyPrices stores the prices of up to limit products that still
+// have decimal prices in minor units, and returns how many it converted.
+// The version of the products is left alone: their price does not change.
+func (r *elasticRepository) ConvertLegacyPrices(ctx context.Context, limit int) (int, error) {
+	query := elastic.NewBoolQuery().
+		Filter(elastic.NewExistsQuery("price")).
+		MustNot(elastic.NewExistsQuery("priceAmount"))
+	res, err := r.client.Search().Index("catalog").Type("product").Query(query).Size(limit).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	for _, hit := range res.Hits.Hits {
+		doc := models.ProductDocument{}
+		if err := json.Unmarshal(*hit.Source, &doc); err != nil {
+			return converted, err
+		}
+		p := toProduct(hit.Id, doc)
+
+		script := elastic.NewScript(convertPricesScript).Lang("painless").
+			Param("revision", doc.Revision).
+			Param("priceAmount", p.Price.Amount).
+			Param("priceOverrides", toPriceOverrides(p.PriceOverrides))
+		res, err := r.client.Update().Index("catalog").Type("product").Id(hit.Id).Script(script).Do(ctx)
+		if elastic.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return converted, err
+		}
+		if res.Result != "noop" {
+			converted++
+		}
+	}
+	return converted, nil
+}
+
+// UpdateProductRating stores the aggregated review rating on the product so
+// search can filter and sort by it. It leaves the version alone, so rating
+// refreshes never make a seller's edit conflict.
+func (r *elasticRepository) UpdateProductRating(ctx context.Context, productId string, average float64, count int) error {
+	_, err := r.client.Update().Index("catalog").Type("product").Id(productId).Doc(map[string]interface{}{
+		"ratingAverage": average,
+		"ratingCount":   count,
+	}).Do(ctx)
 	return err
 }
 
-func (r *elasticRepository) DeleteProduct(ctx context.Context, productId string) error {
+// UpdateProductDeletedAt moves a product to the trash, or restores it when
+// deletedAt is nil, if it is still at version.
+func (r *elasticRepository) UpdateProductDeletedAt(ctx context.Context, productId string, deletedAt *time.Time, version int64) (int64, error) {
+	return r.updateRevision(ctx, productId, version, map[string]interface{}{
+		"deletedAt": deletedAt,
+	})
+}
+
+// ListDeletedProducts returns the trash of a seller, most recently deleted
+// first.
+func (r *elasticRepository) ListDeletedProducts(ctx context.Context, accountId int, skip, take uint64) ([]models.Product, error) {
+	query := elastic.NewBoolQuery().Filter(
+		elastic.NewTermQuery("accountId", accountId),
+		elastic.NewExistsQuery("deletedAt"),
+	)
+	res, err := r.client.Search().Index("catalog").Type("product").Query(query).
+		Sort("deletedAt", false).From(int(skip)).Size(int(take)).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return hitsToProducts(res), nil
+}
+
+// ListPurgeableProducts returns products deleted before deletedBefore.
+func (r *elasticRepository) ListPurgeableProducts(ctx context.Context, deletedBefore time.Time) ([]models.Product, error) {
+	query := elastic.NewBoolQuery().Filter(elastic.NewRangeQuery("deletedAt").Lte(deletedBefore))
+	res, err := r.client.Search().Index("catalog").Type("product").Query(query).Size(100).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return hitsToProducts(res), nil
+}
+
+// PurgeProduct removes a product for good, together with its reviews and
+// price data.
+func (r *elasticRepository) PurgeProduct(ctx context.Context, productId string) error {
+	for index := range indexMappings {
+		_, err := r.client.DeleteByQuery(index).Query(elastic.NewTermQuery("productId", productId)).Do(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err := r.client.Delete().Index("catalog").Type("product").Id(productId).Do(ctx)
+	if elastic.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// PutReview stores a new review. Review ids are derived from the product and
+// the author, so a second review of the same product fails with
+// ErrAlreadyReviewed.
+func (r *elasticRepository) PutReview(ctx context.Context, review *models.Review) error {
+	review.Id = fmt.Sprintf("%s_%d", review.ProductId, review.AccountId)
+	_, err := r.client.Index().Index("reviews").Type("review").Id(review.Id).OpType("create").Refresh("wait_for").BodyJson(review).Do(ctx)
+	if elastic.IsConflict(err) {
+		return product.ErrAlreadyReviewed
+	}
+	return err
+}
+
+func (r *elasticRepository) GetReview(ctx context.Context, id string) (*models.Review, error) {
+	res, err := r.client.Get().Index("reviews").Type("review").Id(id).Do(ctx)
+	if elastic.IsNotFound(err) || (err == nil && !res.Found) {
+		return nil, product.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	review := models.Review{}
+	if err := json.Unmarshal(*res.Source, &review); err != nil {
+		return nil, err
+	}
+	review.Id = res.Id
+	return &review, nil
+}
+
+func (r *elasticRepository) ListReviews(ctx context.Context, productId string, skip, take uint64) ([]models.Review, error) {
+	res, err := r.client.Search().Index("reviews").Type("review").
+		Query(elastic.NewTermQuery("productId", productId)).
+		Sort("createdAt", false).
+		From(int(skip)).Size(int(take)).
+		Do(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var reviews []models.Review
+	for _, hit := range res.Hits.Hits {
+		review := models.Review{}
+		if err := json.Unmarshal(*hit.Source, &review); err == nil {
+			review.Id = hit.Id
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (r *elasticRepository) UpdateReview(ctx context.Context, review *models.Review) error {
+	_, err := r.client.Index().Index("reviews").Type("review").Id(review.Id).Refresh("wait_for").BodyJson(review).Do(ctx)
+	return err
+}
+
+func (r *elasticRepository) DeleteReview(ctx context.Context, id string) error {
+	_, err := r.client.Delete().Index("reviews").Type("review").Id(id).Refresh("wait_for").Do(ctx)
+	if elastic.IsNotFound(err) {
+		return product.ErrNotFound
+	}
+	return err
+}
+
+// ReviewStats returns the average rating and number of reviews of a product.
+func (r *elasticRepository) ReviewStats(ctx context.Context, productId string) (float64, int, error) {
+	res, err := r.client.Search().Index("reviews").Type("review").
+		Query(elastic.NewTermQuery("productId", productId)).
+		Aggregation("rating", elastic.NewAvgAggregation().Field("rating")).
+		Size(0).
+		Do(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count := int(res.Hits.TotalHits)
+	avg, found := res.Aggregations.Avg("rating")
+	if !found || avg.Value == nil || count == 0 {
+		return 0, 0, nil
+	}
+	return *avg.Value, count, nil
+}
+
+func (r *elasticRepository) PutPriceHistory(ctx context.Context, entry *models.PriceHistoryEntry) error {
+	_, err := r.client.Index().Index("price_history").Type("entry").BodyJson(entry).Do(ctx)
+	return err
+}
+
+// ListPriceHistory returns the price changes of a product, newest first.
+func (r *elasticRepository) ListPriceHistory(ctx context.Context, productId string, skip, take uint64) ([]models.PriceHistoryEntry, error) {
+	res, err := r.client.Search().Index("price_history").Type("entry").
+		Query(elastic.NewTermQuery("productId", productId)).
+		Sort("changedAt", false).
+		From(int(skip)).Size(int(take)).
+		Do(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var entries []models.PriceHistoryEntry
+	for _, hit := range res.Hits.Hits {
+		entry := models.PriceHistoryEntry{}
+		if err := json.Unmarshal(*hit.Source, &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *elasticRepository) PutScheduledPrice(ctx context.Context, change *models.ScheduledPrice) error {
+	res, err := r.client.Index().Index("price_changes").Type("change").Refresh("wait_for").BodyJson(change).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	change.Id = res.Id
+	return nil
+}
+
+func (r *elasticRepository) GetScheduledPrice(ctx context.Context, id string) (*models.ScheduledPrice, error) {
+	res, err := r.client.Get().Index("price_changes").Type("change").Id(id).Do(ctx)
+	if elastic.IsNotFound(err) || (err == nil && !res.Found) {
+		return nil, product.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	change := models.ScheduledPrice{}
+	if err := json.Unmarshal(*res.Source, &change); err != nil {
+		return nil, err
+	}
+	change.Id = res.Id
+	return &change, nil
+}
+
+// ListScheduledPrices returns the pending and active price changes of a
+// product in start order.
+func (r *elasticRepository) ListScheduledPrices(ctx context.Context, productId string) ([]models.ScheduledPrice, error) {
+	query := elastic.NewBoolQuery().Filter(
+		elastic.NewTermQuery("productId", productId),
+		elastic.NewTermsQuery("status", product.PriceChangePending, product.PriceChangeActive),
+	)
+	res, err := r.client.Search().Index("price_changes").Type("change").Query(query).Sort("startAt", true).Size(100).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return hitsToScheduledPrices(res), nil
+}
+
+// ListDueScheduledPrices returns pending changes that should have started and
+// active sales that should have ended by now.
+func (r *elasticRepository) ListDueScheduledPrices(ctx context.Context, now time.Time) ([]models.ScheduledPrice, error) {
+	query := elastic.NewBoolQuery().
+		Should(
+			elastic.NewBoolQuery().Filter(
+				elastic.NewTermQuery("status", product.PriceChangePending),
+				elastic.NewRangeQuery("startAt").Lte(now),
+			),
+			elastic.NewBoolQuery().Filter(
+				elastic.NewTermQuery("status", product.PriceChangeActive),
+				elastic.NewRangeQuery("endAt").Lte(now),
+			),
+		).
+		MinimumNumberShouldMatch(1)
+	res, err := r.client.Search().Index("price_changes").Type("change").Query(query).Sort("startAt", true).Size(100).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return hitsToScheduledPrices(res), nil
+}
+
+func (r *elasticRepository) UpdateScheduledPrice(ctx context.Context, change *models.ScheduledPrice) error {
+	_, err := r.client.Index().Index("price_changes").Type("change").Id(change.Id).Refresh("wait_for").BodyJson(change).Do(ctx)
 	return err
 }
+
+// adjustStockScript changes the stock of a product unless that would take it
+// below zero. Products without stock tracking are left alone; only a refused
+// change is a noop.
+const adjustStockScript = `
+if (ctx._source.stock != null) {
+	if (ctx._source.stock + params.delta < 0) {
+		ctx.op = 'none';
+	} else {
+		ctx._source.stock += params.delta;
+	}
+}`
+
+// AdjustStock adds delta to the stock of a product in a single atomic
+// update. It fails with product.ErrOutOfStock if the stock is too low.
+func (r *elasticRepository) AdjustStock(ctx context.Context, productId string, delta int) error {
+	script := elastic.NewScript(adjustStockScript).Lang("painless").Param("delta", delta)
+	res, err := r.client.Update().Index("catalog").Type("product").Id(productId).Script(script).RetryOnConflict(3).Do(ctx)
+	if elastic.IsNotFound(err) {
+		return product.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if res.Result == "noop" && delta < 0 {
+		return product.ErrOutOfStock
+	}
+	return nil
+}
+
+// PutStockReservation stores a new reservation. It fails with
+// product.ErrVersionConflict if the id is taken.
+func (r *elasticRepository) PutStockReservation(ctx context.Context, reservation *models.StockReservation) error {
+	res, err := r.client.Index().Index("stock_reservations").Type("reservation").Id(reservation.Id).OpType("create").BodyJson(reservation).Do(ctx)
+	if elastic.IsConflict(err) {
+		return product.ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	reservation.Version = res.Version
+	return nil
+}
+
+func (r *elasticRepository) GetStockReservation(ctx context.Context, id string) (*models.StockReservation, error) {
+	res, err := r.client.Get().Index("stock_reservations").Type("reservation").Id(id).Do(ctx)
+	if elastic.IsNotFound(err) || (err == nil && !res.Found) {
+		return nil, product.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := models.StockReservation{}
+	if err := json.Unmarshal(*res.Source, &reservation); err != nil {
+		return nil, err
+	}
+	reservation.Id = res.Id
+	if res.Version != nil {
+		reservation.Version = *res.Version
+	}
+	return &reservation, nil
+}
+
+// UpdateStockReservationStatus changes the status of a reservation if it is
+// still at reservation.Version.
+func (r *elasticRepository) UpdateStockReservationStatus(ctx context.Context, reservation *models.StockReservation, status string) error {
+	res, err := r.client.Update().Index("stock_reservations").Type("reservation").Id(reservation.Id).Version(reservation.Version).Doc(map[string]interface{}{
+		"status": status,
+	}).Do(ctx)
+	if elastic.IsConflict(err) {
+		return product.ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	reservation.Status, reservation.Version = status, int64(res.Version)
+	return nil
+}
+
+func (r *elasticRepository) PutOutboxEntry(ctx context.Context, entry *models.OutboxEntry) error {
+	res, err := r.client.Index().Index("product_outbox").Type("entry").BodyJson(entry).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry.Id = res.Id
+	return nil
+}
+
+// ListOutboxEntries returns the oldest staged product events.
+func (r *elasticRepository) ListOutboxEntries(ctx context.Context, limit int) ([]models.OutboxEntry, error) {
+	res, err := r.client.Search().Index("product_outbox").Type("entry").Sort("sequence", true).Size(limit).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.OutboxEntry
+	for _, hit := range res.Hits.Hits {
+		entry := models.OutboxEntry{}
+		if err := json.Unmarshal(*hit.Source, &entry); err == nil {
+			entry.Id = hit.Id
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// UpdateOutboxEntryAttempts and DeleteOutboxEntry wait for the change to
+// become searchable, so the next relay poll does not see the entry as it was.
+func (r *elasticRepository) UpdateOutboxEntryAttempts(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error {
+	_, err := r.client.Update().Index("product_outbox").Type("entry").Id(id).Refresh("wait_for").Doc(map[string]interface{}{
+		"attempts":      attempts,
+		"lastError":     lastError,
+		"nextAttemptAt": nextAttemptAt,
+	}).Do(ctx)
+	return err
+}
+
+func (r *elasticRepository) DeleteOutboxEntry(ctx context.Context, id string) error {
+	_, err := r.client.Delete().Index("product_outbox").Type("entry").Id(id).Refresh("wait_for").Do(ctx)
+	if elastic.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func hitsToScheduledPrices(res *elastic.SearchResult) []models.ScheduledPrice {
+	var changes []models.ScheduledPrice
+	for _, hit := range res.Hits.Hits {
+		change := models.ScheduledPrice{}
+		if err := json.Unmarshal(*hit.Source, &change); err == nil {
+			change.Id = hit.Id
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func filterQueries(filter models.ProductFilter) []elastic.Query {
+	queries := []elastic.Query{visibleTo(filter.ViewerId), notDeleted()}
+	if filter.MinRating > 0 {
+		queries = append(queries, elastic.NewRangeQuery("ratingAverage").Gte(filter.MinRating))
+	}
+	return queries
+}
+
+// sortBy applies the requested ordering. Products nobody has reviewed yet
+// have no rating and sort last.
+func sortBy(search *elastic.SearchService, filter models.ProductFilter) *elastic.SearchService {
+	if filter.SortBy == product.SortByRating {
+		search = search.SortBy(
+			elastic.NewFieldSort("ratingAverage").Desc().Missing("_last").UnmappedType("float"),
+			elastic.NewFieldSort("ratingCount").Desc().Missing("_last").UnmappedType("integer"),
+		)
+	}
+	return search
+}
+
+// visibleTo matches published products, plus everything owned by viewerId.
+// Documents written before statuses existed have no status and count as
+// published.
+func visibleTo(viewerId int) elastic.Query {
+	query := elastic.NewBoolQuery().
+		Should(
+			elastic.NewMatchQuery("status", product.StatusPublished),
+			elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("status")),
+		).
+		MinimumNumberShouldMatch(1)
+	if viewerId != 0 {
+		query = query.Should(elastic.NewTermQuery("accountId", viewerId))
+	}
+	return query
+}
+
+// hasStatus matches products in any of statuses. Documents without a status
+// are published.
+func hasStatus(statuses []string) elastic.Query {
+	query := elastic.NewBoolQuery().MinimumNumberShouldMatch(1)
+	for _, status := range statuses {
+		query = query.Should(elastic.NewMatchQuery("status", status))
+		if status == product.StatusPublished {
+			query = query.Should(elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("status")))
+		}
+	}
+	return query
+}
+
+// notDeleted leaves out products in the trash. They can still be fetched by
+// id so that past orders keep their product details.
+func notDeleted() elastic.Query {
+	return elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("deletedAt"))
+}
+
+func hitsToProducts(res *elastic.SearchResult) []models.Product {
+	var products []models.Product
+	for _, hit := range res.Hits.Hits {
+		product := models.ProductDocument{}
+		if err := json.Unmarshal(*hit.Source, &product); err == nil {
+			products = append(products, toProduct(hit.Id, product))
+		}
+	}
+	return products
+}
+
+func toProduct(id string, doc models.ProductDocument) models.Product {
+	p := models.Product{
+		Id:          id,
+		Version:     doc.Revision,
+		Name:        doc.Name,
+		Description: doc.Description,
+		AccountId:   doc.AccountId,
+		Images:      doc.Images,
+		Status:      doc.Status,
+		PublishAt:   doc.PublishAt,
+		DeletedAt:   doc.DeletedAt,
+		Stock:       doc.Stock,
+		Sku:         doc.Sku,
+		Category:    doc.Category,
+		TaxCategory: tax.CategoryOf(doc.TaxCategory),
+		WeightGrams: doc.WeightGrams,
+
+		RatingAverage: doc.RatingAverage,
+		RatingCount:   doc.RatingCount,
+	}
+
+	currency := doc.Currency
+	if currency == "" {
+		currency = product.DefaultCurrency
+	}
+	p.Price = storedPrice(doc.PriceAmount, doc.Price, currency)
+	for _, override := range doc.PriceOverrides {
+		p.PriceOverrides = append(p.PriceOverrides, storedPrice(override.Amount, override.Price, override.Currency))
+	}
+	return p
+}
+
+// storedPrice reads a price in minor units, falling back to the decimal
+// price of documents the backfill has not converted yet. Prices are never
+// zero, so a missing amount means a legacy document.
+func storedPrice(amount int64, legacy float64, currency string) money.Money {
+	if amount == 0 && legacy != 0 {
+		return money.FromFloat(legacy, currency)
+	}
+	return money.New(amount, currency)
+}
+
+func toPriceOverrides(prices []money.Money) []models.PriceOverride {
+	var overrides []models.PriceOverride
+	for _, price := range prices {
+		overrides = append(overrides, models.PriceOverride{Currency: price.Currency, Amount: price.Amount})
+	}
+	return overrides
+}
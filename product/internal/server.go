@@ -2,16 +2,24 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"time"
 
+	"github.com/abhiii71/orderStream/pkg/money"
+	moneypb "github.com/abhiii71/orderStream/pkg/money/proto/pb"
+	productErrors "github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/config"
 	"github.com/abhiii71/orderStream/product/models"
 	"github.com/abhiii71/orderStream/product/proto/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"google.golang.org/protobuf/types/known/wrapperspb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type grpcServer struct {
@@ -25,7 +33,8 @@ func ListenGRPC(s Service, port int) error {
 		return err
 	}
 
-	serv := grpc.NewServer()
+	// leave room for image uploads on top of the default 4MB message limit
+	serv := grpc.NewServer(grpc.MaxRecvMsgSize(int(config.MaxImageSize) + 1<<20))
 
 	pb.RegisterProductServiceServer(serv, &grpcServer{
 		UnimplementedProductServiceServer: pb.UnimplementedProductServiceServer{},
@@ -34,30 +43,34 @@ func ListenGRPC(s Service, port int) error {
 	return serv.Serve(lis)
 }
 
-func (s *grpcServer) GetProduct(ctx context.Context, request *wrapperspb.StringValue) (*pb.ProductResponse, error) {
-	product, err := s.service.GetProduct(ctx, request.Value)
+func (s *grpcServer) GetProduct(ctx context.Context, request *pb.GetProductRequest) (*pb.ProductResponse, error) {
+	product, err := s.service.GetProduct(ctx, request.GetId(), int(request.GetViewerAccountId()))
 	if err != nil {
 		return nil, err
 	}
+	s.service.LocalizePrice(ctx, product, request.GetDisplayCurrency())
 
-	return &pb.ProductResponse{Product: &pb.Product{
-		Id:          product.Id,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-	}}, nil
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetViewerAccountId()))}, nil
 }
 
 func (s *grpcServer) GetProducts(ctx context.Context, request *pb.GetProductsRequest) (*pb.ProductsResponse, error) {
 	var res []models.Product
 	var err error
 
+	filter := models.ProductFilter{
+		ViewerId:  int(request.GetViewerAccountId()),
+		MinRating: request.GetMinRating(),
+		SortBy:    request.GetSortBy(),
+
+		DisplayCurrency: request.GetDisplayCurrency(),
+		IncludeHidden:   request.GetIncludeHidden(),
+	}
 	if request.Query != "" {
-		res, err = s.service.SearchProducts(ctx, request.Query, request.Skip, request.Take)
+		res, err = s.service.SearchProducts(ctx, request.Query, request.Skip, request.Take, filter)
 	} else if len(request.Ids) != 0 {
-		res, err = s.service.GetProductsWithIds(ctx, request.Ids)
+		res, err = s.service.GetProductsWithIds(ctx, request.Ids, filter)
 	} else {
-		res, err = s.service.GetProducts(ctx, request.Skip, request.Take)
+		res, err = s.service.GetProducts(ctx, request.Skip, request.Take, filter)
 	}
 	if err != nil {
 		return nil, err
@@ -65,50 +78,189 @@ func (s *grpcServer) GetProducts(ctx context.Context, request *pb.GetProductsReq
 
 	var products []*pb.Product
 	for _, p := range res {
-		products = append(products, &pb.Product{
-			Id:          p.Id,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-		})
+		s.service.LocalizePrice(ctx, &p, filter.DisplayCurrency)
+		products = append(products, productToProto(&p, filter.ViewerId))
+	}
+
+	return &pb.ProductsResponse{Products: products}, nil
+}
 
+func (s *grpcServer) ListProductsByAccount(ctx context.Context, request *pb.ListProductsByAccountRequest) (*pb.ProductsResponse, error) {
+	res, err := s.service.ListProductsByAccount(ctx, int(request.GetAccountId()), request.GetStatuses(), request.GetSkip(), request.GetTake())
+	if err != nil {
+		log.Println(err)
+		return nil, err
 	}
 
+	var products []*pb.Product
+	for _, p := range res {
+		products = append(products, productToProto(&p, int(request.GetAccountId())))
+	}
 	return &pb.ProductsResponse{Products: products}, nil
 }
 
 func (s *grpcServer) PostProduct(ctx context.Context, request *pb.CreateProductRequest) (*pb.ProductResponse, error) {
-	product, err := s.service.PostProduct(ctx, request.Name, request.Description, request.Price, int(request.GetAccountId()))
+	var publishAt *time.Time
+	if request.PublishAt != nil {
+		t := request.PublishAt.AsTime()
+		publishAt = &t
+	}
+
+	product, err := s.service.PostProduct(ctx, request.Name, request.Description, request.GetSku(), request.GetCategory(), request.GetTaxCategory(), request.GetWeightGrams(), money.FromProto(request.GetPrice()), priceOverridesFromProto(request.GetPriceOverrides()), stockFromProto(request.Stock), int(request.GetAccountId()), request.GetStatus(), publishAt)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return &pb.ProductResponse{Product: &pb.Product{
-		Id:          product.Id,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-	}}, nil
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetAccountId()))}, nil
 }
 
 func (s *grpcServer) UpdateProduct(ctx context.Context, request *pb.UpdateProductRequest) (*pb.ProductResponse, error) {
-	product, err := s.service.UpdateProduct(ctx, request.Id, request.Name, request.Description, request.Price, int(request.GetAccountId()))
+	product, err := s.service.UpdateProduct(ctx, request.Id, request.Name, request.Description, request.Sku, request.Category, request.TaxCategory, request.WeightGrams, money.FromProto(request.GetPrice()), priceOverridesFromProto(request.GetPriceOverrides()), stockFromProto(request.Stock), request.GetUntrackStock(), int(request.GetAccountId()), request.GetVersion())
+	if errors.Is(err, productErrors.ErrVersionConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	return &pb.ProductResponse{Product: &pb.Product{
-		Id:          product.Id,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-	}}, nil
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetAccountId()))}, nil
 }
 
 func (s *grpcServer) DeleteProduct(ctx context.Context, request *pb.DeleteProductRequest) (*emptypb.Empty, error) {
 	err := s.service.DeleteProduct(ctx, request.GetProductId(), int(request.GetAccountId()))
+	if errors.Is(err, productErrors.ErrVersionConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcServer) RestoreProduct(ctx context.Context, request *pb.RestoreProductRequest) (*pb.ProductResponse, error) {
+	product, err := s.service.RestoreProduct(ctx, request.GetProductId(), int(request.GetAccountId()))
+	if errors.Is(err, productErrors.ErrVersionConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if errors.Is(err, productErrors.ErrRestoreExpired) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetAccountId()))}, nil
+}
+
+func (s *grpcServer) ListDeletedProducts(ctx context.Context, request *pb.ListDeletedProductsRequest) (*pb.ProductsResponse, error) {
+	res, err := s.service.ListDeletedProducts(ctx, int(request.GetAccountId()), request.GetSkip(), request.GetTake())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var products []*pb.Product
+	for _, p := range res {
+		products = append(products, productToProto(&p, int(request.GetAccountId())))
+	}
+	return &pb.ProductsResponse{Products: products}, nil
+}
+
+func (s *grpcServer) UploadProductImage(ctx context.Context, request *pb.UploadProductImageRequest) (*pb.ProductResponse, error) {
+	product, err := s.service.UploadProductImage(ctx, request.GetProductId(), int(request.GetAccountId()), request.GetContentType(), request.GetData())
+	if errors.Is(err, productErrors.ErrVersionConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetAccountId()))}, nil
+}
+
+func (s *grpcServer) PublishProduct(ctx context.Context, request *pb.PublishProductRequest) (*pb.ProductResponse, error) {
+	var publishAt *time.Time
+	if request.PublishAt != nil {
+		t := request.PublishAt.AsTime()
+		publishAt = &t
+	}
+
+	product, err := s.service.PublishProduct(ctx, request.GetProductId(), int(request.GetAccountId()), publishAt)
+	if errors.Is(err, productErrors.ErrVersionConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if errors.Is(err, productErrors.ErrAlreadyPublished) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetAccountId()))}, nil
+}
+
+func (s *grpcServer) ArchiveProduct(ctx context.Context, request *pb.ArchiveProductRequest) (*pb.ProductResponse, error) {
+	product, err := s.service.ArchiveProduct(ctx, request.GetProductId(), int(request.GetAccountId()))
+	if errors.Is(err, productErrors.ErrVersionConflict) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ProductResponse{Product: productToProto(product, int(request.GetAccountId()))}, nil
+}
+
+func (s *grpcServer) CreateReview(ctx context.Context, request *pb.CreateReviewRequest) (*pb.ReviewResponse, error) {
+	review, err := s.service.CreateReview(ctx, request.GetProductId(), int(request.GetAccountId()), int(request.GetRating()), request.GetTitle(), request.GetBody())
+	if errors.Is(err, productErrors.ErrNotPurchased) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, productErrors.ErrAlreadyReviewed) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ReviewResponse{Review: reviewToProto(review)}, nil
+}
+
+func (s *grpcServer) ListReviews(ctx context.Context, request *pb.ListReviewsRequest) (*pb.ReviewsResponse, error) {
+	res, err := s.service.ListReviews(ctx, request.GetProductId(), request.GetSkip(), request.GetTake())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var reviews []*pb.Review
+	for _, r := range res {
+		reviews = append(reviews, reviewToProto(&r))
+	}
+	return &pb.ReviewsResponse{Reviews: reviews}, nil
+}
+
+func (s *grpcServer) UpdateReview(ctx context.Context, request *pb.UpdateReviewRequest) (*pb.ReviewResponse, error) {
+	review, err := s.service.UpdateReview(ctx, request.GetReviewId(), int(request.GetAccountId()), int(request.GetRating()), request.GetTitle(), request.GetBody())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ReviewResponse{Review: reviewToProto(review)}, nil
+}
+
+func (s *grpcServer) DeleteReview(ctx context.Context, request *pb.DeleteReviewRequest) (*emptypb.Empty, error) {
+	err := s.service.DeleteReview(ctx, request.GetReviewId(), int(request.GetAccountId()))
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -116,3 +268,211 @@ func (s *grpcServer) DeleteProduct(ctx context.Context, request *pb.DeleteProduc
 
 	return &emptypb.Empty{}, nil
 }
+
+func (s *grpcServer) ReplyToReview(ctx context.Context, request *pb.ReplyToReviewRequest) (*pb.ReviewResponse, error) {
+	review, err := s.service.ReplyToReview(ctx, request.GetReviewId(), int(request.GetAccountId()), request.GetBody())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ReviewResponse{Review: reviewToProto(review)}, nil
+}
+
+func (s *grpcServer) GetPriceHistory(ctx context.Context, request *pb.GetPriceHistoryRequest) (*pb.PriceHistoryResponse, error) {
+	res, err := s.service.GetPriceHistory(ctx, request.GetProductId(), request.GetSkip(), request.GetTake())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var entries []*pb.PriceHistoryEntry
+	for _, entry := range res {
+		entries = append(entries, &pb.PriceHistoryEntry{
+			Price:         money.ToProto(entry.Price),
+			PreviousPrice: money.ToProto(entry.PreviousPrice),
+			Reason:        entry.Reason,
+			ChangedAt:     timestamppb.New(entry.ChangedAt),
+		})
+	}
+	return &pb.PriceHistoryResponse{Entries: entries}, nil
+}
+
+func (s *grpcServer) SchedulePriceChange(ctx context.Context, request *pb.SchedulePriceChangeRequest) (*pb.ScheduledPriceResponse, error) {
+	startAt := time.Now().UTC()
+	if request.StartAt != nil {
+		startAt = request.StartAt.AsTime()
+	}
+	var endAt *time.Time
+	if request.EndAt != nil {
+		t := request.EndAt.AsTime()
+		endAt = &t
+	}
+
+	change, err := s.service.SchedulePriceChange(ctx, request.GetProductId(), int(request.GetAccountId()), money.FromProto(request.GetPrice()), startAt, endAt)
+	if errors.Is(err, productErrors.ErrScheduleOverlap) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &pb.ScheduledPriceResponse{Change: scheduledPriceToProto(change)}, nil
+}
+
+func (s *grpcServer) ListScheduledPrices(ctx context.Context, request *pb.ListScheduledPricesRequest) (*pb.ScheduledPricesResponse, error) {
+	res, err := s.service.ListScheduledPrices(ctx, request.GetProductId(), int(request.GetAccountId()))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var changes []*pb.ScheduledPrice
+	for _, change := range res {
+		changes = append(changes, scheduledPriceToProto(&change))
+	}
+	return &pb.ScheduledPricesResponse{Changes: changes}, nil
+}
+
+func (s *grpcServer) CancelPriceChange(ctx context.Context, request *pb.CancelPriceChangeRequest) (*emptypb.Empty, error) {
+	err := s.service.CancelPriceChange(ctx, request.GetChangeId(), int(request.GetAccountId()))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcServer) ReserveStock(ctx context.Context, request *pb.ReserveStockRequest) (*emptypb.Empty, error) {
+	items := make([]models.ReservedItem, len(request.GetItems()))
+	for i, item := range request.GetItems() {
+		items[i] = models.ReservedItem{ProductId: item.GetProductId(), Quantity: int(item.GetQuantity())}
+	}
+
+	err := s.service.ReserveStock(ctx, request.GetReservationId(), items)
+	if errors.Is(err, productErrors.ErrOutOfStock) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, productErrors.ErrInvalidStock) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcServer) ReleaseStock(ctx context.Context, request *pb.ReleaseStockRequest) (*emptypb.Empty, error) {
+	err := s.service.ReleaseStock(ctx, request.GetReservationId())
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func scheduledPriceToProto(c *models.ScheduledPrice) *pb.ScheduledPrice {
+	change := &pb.ScheduledPrice{
+		Id:        c.Id,
+		ProductId: c.ProductId,
+		Price:     money.ToProto(c.Price),
+		StartAt:   timestamppb.New(c.StartAt),
+		Status:    c.Status,
+	}
+	if c.EndAt != nil {
+		change.EndAt = timestamppb.New(*c.EndAt)
+	}
+	return change
+}
+
+func reviewToProto(r *models.Review) *pb.Review {
+	review := &pb.Review{
+		Id:        r.Id,
+		ProductId: r.ProductId,
+		AccountId: int64(r.AccountId),
+		Rating:    int32(r.Rating),
+		Title:     r.Title,
+		Body:      r.Body,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		UpdatedAt: timestamppb.New(r.UpdatedAt),
+	}
+	if r.Reply != nil {
+		review.Reply = &pb.ReviewReply{
+			AccountId: int64(r.Reply.AccountId),
+			Body:      r.Reply.Body,
+			CreatedAt: timestamppb.New(r.Reply.CreatedAt),
+		}
+	}
+	return review
+}
+
+// productToProto converts p for viewerId. Owner-only fields such as stock are
+// left out for everyone but the seller.
+func productToProto(p *models.Product, viewerId int) *pb.Product {
+	product := &pb.Product{
+		Id:          p.Id,
+		Name:        p.Name,
+		Description: p.Description,
+		Sku:         p.Sku,
+		Category:    p.Category,
+		TaxCategory: p.TaxCategory,
+		WeightGrams: p.WeightGrams,
+		Price:       money.ToProto(p.Price),
+		AccountId:   int64(p.AccountId),
+		Status:      p.Status,
+		Version:     p.Version,
+
+		RatingAverage: p.RatingAverage,
+		RatingCount:   int32(p.RatingCount),
+
+		DisplayPrice: money.ToProto(p.DisplayPrice),
+	}
+	if p.DisplayPrice.Currency == "" {
+		product.DisplayPrice = product.Price
+	}
+	for _, override := range p.PriceOverrides {
+		product.PriceOverrides = append(product.PriceOverrides, money.ToProto(override))
+	}
+	if p.PublishAt != nil {
+		product.PublishAt = timestamppb.New(*p.PublishAt)
+	}
+	if p.DeletedAt != nil {
+		product.DeletedAt = timestamppb.New(*p.DeletedAt)
+	}
+	if p.Stock != nil && viewerId != 0 && viewerId == p.AccountId {
+		stock := int64(*p.Stock)
+		product.Stock = &stock
+	}
+
+	for _, image := range p.Images {
+		product.Images = append(product.Images, &pb.ProductImage{
+			Url:          image.URL,
+			ThumbnailUrl: image.ThumbnailURL,
+			ContentType:  image.ContentType,
+			Size:         image.Size,
+			Position:     int32(image.Position),
+		})
+	}
+	return product
+}
+
+func stockFromProto(stock *int64) *int {
+	if stock == nil {
+		return nil
+	}
+	v := int(*stock)
+	return &v
+}
+
+func priceOverridesFromProto(overrides []*moneypb.Money) []money.Money {
+	var res []money.Money
+	for _, override := range overrides {
+		res = append(res, money.FromProto(override))
+	}
+	return res
+}
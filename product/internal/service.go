@@ -1,189 +1,1076 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/IBM/sarama"
+	"github.com/abhiii71/orderStream/pkg/events"
 	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/pkg/tax"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/config"
 	"github.com/abhiii71/orderStream/product/models"
+	"github.com/google/uuid"
 )
 
 type Service interface {
-	GetProducer() sarama.AsyncProducer
-	PostProduct(ctx context.Context, name, description string, price float64, accountId int) (*models.Product, error)
-	GetProduct(ctx context.Context, id string) (*models.Product, error)
-	GetProducts(ctx context.Context, skip, take uint64) ([]models.Product, error)
-	GetProductsWithIds(ctx context.Context, ids []string) ([]models.Product, error)
-	SearchProducts(ctx context.Context, query string, skip, take uint64) ([]models.Product, error)
-	UpdateProduct(ctx context.Context, id, name, description string, price float64, accountId int) (*models.Product, error)
+	PostProduct(ctx context.Context, name, description, sku, category, taxCategory string, weightGrams int64, price money.Money, overrides []money.Money, stock *int, accountId int, status string, publishAt *time.Time) (*models.Product, error)
+	GetProduct(ctx context.Context, id string, viewerId int) (*models.Product, error)
+	GetProducts(ctx context.Context, skip, take uint64, filter models.ProductFilter) ([]models.Product, error)
+	GetProductsWithIds(ctx context.Context, ids []string, filter models.ProductFilter) ([]models.Product, error)
+	ListProductsByAccount(ctx context.Context, accountId int, statuses []string, skip, take uint64) ([]models.Product, error)
+	SearchProducts(ctx context.Context, query string, skip, take uint64, filter models.ProductFilter) ([]models.Product, error)
+	UpdateProduct(ctx context.Context, id, name, description string, sku, category, taxCategory *string, weightGrams *int64, price money.Money, overrides []money.Money, stock *int, untrackStock bool, accountId int, version int64) (*models.Product, error)
 	DeleteProduct(ctx context.Context, productId string, accountId int) error
+	RestoreProduct(ctx context.Context, productId string, accountId int) (*models.Product, error)
+	ListDeletedProducts(ctx context.Context, accountId int, skip, take uint64) ([]models.Product, error)
+	PurgeDeletedProducts(ctx context.Context) error
+	UploadProductImage(ctx context.Context, productId string, accountId int, contentType string, data []byte) (*models.Product, error)
+	PublishProduct(ctx context.Context, productId string, accountId int, publishAt *time.Time) (*models.Product, error)
+	ArchiveProduct(ctx context.Context, productId string, accountId int) (*models.Product, error)
+	PublishDueProducts(ctx context.Context) error
+	CreateReview(ctx context.Context, productId string, accountId, rating int, title, body string) (*models.Review, error)
+	ListReviews(ctx context.Context, productId string, skip, take uint64) ([]models.Review, error)
+	UpdateReview(ctx context.Context, reviewId string, accountId, rating int, title, body string) (*models.Review, error)
+	DeleteReview(ctx context.Context, reviewId string, accountId int) error
+	ReplyToReview(ctx context.Context, reviewId string, accountId int, body string) (*models.Review, error)
+	GetPriceHistory(ctx context.Context, productId string, skip, take uint64) ([]models.PriceHistoryEntry, error)
+	SchedulePriceChange(ctx context.Context, productId string, accountId int, price money.Money, startAt time.Time, endAt *time.Time) (*models.ScheduledPrice, error)
+	ListScheduledPrices(ctx context.Context, productId string, accountId int) ([]models.ScheduledPrice, error)
+	CancelPriceChange(ctx context.Context, changeId string, accountId int) error
+	ApplyDuePriceChanges(ctx context.Context) error
+	LocalizePrice(ctx context.Context, p *models.Product, currency string)
+	ReserveStock(ctx context.Context, reservationId string, items []models.ReservedItem) error
+	ReleaseStock(ctx context.Context, reservationId string) error
 }
 
-type productService struct {
-	repo     Repository
-	producer sarama.AsyncProducer
+// PurchaseVerifier tells whether an account has paid for a product. It is
+// implemented by the order service client.
+type PurchaseVerifier interface {
+	HasPurchased(ctx context.Context, accountId uint64, productId string) (bool, error)
 }
 
-func NewProductService(repository Repository, producer sarama.AsyncProducer) Service {
-	return &productService{repository, producer}
+type productService struct {
+	repo      Repository
+	publisher *kafka.Publisher
+	media     MediaStore
+	purchases PurchaseVerifier
+	rates     RateProvider
 }
 
-func (s *productService) GetProducer() sarama.AsyncProducer {
-	return s.producer
+// NewProductService creates the product service. rates may be nil, in which
+// case prices are only shown in currencies the seller priced them in.
+func NewProductService(repository Repository, publisher *kafka.Publisher, media MediaStore, purchases PurchaseVerifier, rates RateProvider) Service {
+	return &productService{repository, publisher, media, purchases, rates}
 }
 
-func (s *productService) PostProduct(ctx context.Context, name, description string, price float64, accountId int) (*models.Product, error) {
+func (s *productService) PostProduct(ctx context.Context, name, description, sku, category, taxCategory string, weightGrams int64, price money.Money, overrides []money.Money, stock *int, accountId int, status string, publishAt *time.Time) (*models.Product, error) {
+	price, err := validatePrices(price, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if stock != nil && *stock < 0 {
+		return nil, product.ErrInvalidStock
+	}
+	if weightGrams < 0 {
+		return nil, product.ErrInvalidWeight
+	}
+	category, err = normalizeCategory(category)
+	if err != nil {
+		return nil, err
+	}
+	taxCategory = tax.CategoryOf(taxCategory)
+	if !tax.Valid(taxCategory) {
+		return nil, product.ErrInvalidTaxCategory
+	}
+
+	// Products are published right away unless the seller asks for a draft
+	// or schedules publishing for later.
+	now := time.Now().UTC()
+	switch {
+	case status == "" && publishAt != nil && publishAt.After(now):
+		status = product.StatusDraft
+	case status == "" || status == product.StatusPublished:
+		status = product.StatusPublished
+		publishAt = &now
+	case status != product.StatusDraft:
+		return nil, product.ErrInvalidStatus
+	}
+
 	product := models.Product{
+		Id:          uuid.NewString(),
 		Name:        name,
 		Description: description,
+		Sku:         strings.TrimSpace(sku),
+		Category:    category,
+		TaxCategory: taxCategory,
+		WeightGrams: weightGrams,
 		Price:       price,
 		AccountId:   accountId,
+		Status:      status,
+		PublishAt:   publishAt,
+		Stock:       stock,
+
+		PriceOverrides: overrides,
 	}
 
-	err := s.repo.PutProduct(ctx, &product)
+	eventTypes := []string{events.ProductCreated}
+	if product.IsPublished() {
+		eventTypes = append(eventTypes, events.ProductPublished)
+	}
+	err = s.writeWithEvents(ctx, product.Id, nil, func() error {
+		return s.repo.PutProduct(ctx, &product)
+	}, eventTypes...)
 	if err != nil {
 		return nil, err
 	}
-
-	go func() {
-		err := kafka.SendMessageToRecommender(s, models.Event{
-			Type: "product_created",
-			Data: models.EventData{
-				Id:          &product.Id,
-				Name:        &product.Name,
-				Description: &product.Description,
-				Price:       &product.Price,
-				AccountID:   &product.AccountId,
-			},
-		}, "product_events")
-		if err != nil {
-			log.Println("failed to send event to recommendation service: ", err)
-		}
-	}()
 	return &product, nil
 }
 
-func (s *productService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
-	product, err := s.repo.GetProductsByID(ctx, id)
+func (s *productService) GetProduct(ctx context.Context, id string, viewerId int) (*models.Product, error) {
+	p, err := s.repo.GetProductsByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !p.VisibleTo(viewerId) {
+		return nil, product.ErrNotFound
+	}
 
-	go func() {
-		err := kafka.SendMessageToRecommender(s, models.Event{
-			Type: "product_retrieved",
-			Data: models.EventData{
-				Id:        &product.Id,
-				AccountID: &product.AccountId,
-			},
-		}, "interaction_events")
-		if err != nil {
-			log.Println("failed to send event to recommendation service:", err)
-		}
-	}()
+	err = s.publisher.PublishJSON(ctx, interactionEventsTopic, p.Id, models.Event{
+		Type: "product_retrieved",
+		Data: models.EventData{
+			Id:        &p.Id,
+			AccountID: &p.AccountId,
+		},
+	})
+	if err != nil {
+		log.Println("failed to send event to recommendation service:", err)
+	}
 
-	return product, nil
+	return p, nil
 }
 
-func (s *productService) GetProducts(ctx context.Context, skip, take uint64) ([]models.Product, error) {
-	products, err := s.repo.ListProducts(ctx, skip, take)
+func (s *productService) GetProducts(ctx context.Context, skip, take uint64, filter models.ProductFilter) ([]models.Product, error) {
+	products, err := s.repo.ListProducts(ctx, skip, take, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	// Send single products_listed event with all product IDs
-	go func() {
-		productIDs := make([]string, len(products))
-		for i, product := range products {
-			productIDs[i] = product.Id
-		}
-
-		err := kafka.SendMessageToRecommender(s, models.ProductsListedEvent{
-			Type: "products_listed",
-			Data: models.ProductsListedEventData{
-				ProductIDs: productIDs,
-				Count:      len(products),
-			},
-		}, "interaction_events")
-		if err != nil {
-			log.Println("failed to send event to recommendation service:", err)
-		}
-	}()
+	productIDs := make([]string, len(products))
+	for i, product := range products {
+		productIDs[i] = product.Id
+	}
+
+	err = s.publisher.PublishJSON(ctx, interactionEventsTopic, "", models.ProductsListedEvent{
+		Type: "products_listed",
+		Data: models.ProductsListedEventData{
+			ProductIDs: productIDs,
+			Count:      len(products),
+		},
+	})
+	if err != nil {
+		log.Println("failed to send event to recommendation service:", err)
+	}
 
 	return products, nil
 }
 
-func (s *productService) GetProductsWithIds(ctx context.Context, ids []string) ([]models.Product, error) {
-	return s.repo.ListProductsWithIDs(ctx, ids)
+// GetProductsWithIds returns the products with the given ids that the
+// filter's viewer may see. Products in the trash and other sellers' drafts
+// and archived products are left out, unless the filter includes hidden
+// products.
+func (s *productService) GetProductsWithIds(ctx context.Context, ids []string, filter models.ProductFilter) ([]models.Product, error) {
+	products, err := s.repo.ListProductsWithIDs(ctx, ids)
+	if err != nil || filter.IncludeHidden {
+		return products, err
+	}
+
+	visible := products[:0]
+	for _, p := range products {
+		if !p.IsDeleted() && p.VisibleTo(filter.ViewerId) {
+			visible = append(visible, p)
+		}
+	}
+	return visible, nil
 }
 
-func (s *productService) SearchProducts(ctx context.Context, query string, skip, take uint64) ([]models.Product, error) {
-	return s.repo.SearchProducts(ctx, query, skip, take)
+// ListProductsByAccount returns the seller's own catalog, optionally only
+// the products in the given statuses.
+func (s *productService) ListProductsByAccount(ctx context.Context, accountId int, statuses []string, skip, take uint64) ([]models.Product, error) {
+	for _, status := range statuses {
+		if status != product.StatusDraft && status != product.StatusPublished && status != product.StatusArchived {
+			return nil, product.ErrInvalidStatus
+		}
+	}
+	return s.repo.ListProductsByAccount(ctx, accountId, statuses, skip, take)
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id, name, description string, price float64, accountId int) (*models.Product, error) {
-	product, err := s.repo.GetProductsByID(ctx, id)
+func (s *productService) SearchProducts(ctx context.Context, query string, skip, take uint64, filter models.ProductFilter) ([]models.Product, error) {
+	return s.repo.SearchProducts(ctx, query, skip, take, filter)
+}
+
+// UpdateProduct applies an edit made against the given version of the
+// product. If someone else changed the product in the meantime the edit is
+// rejected with ErrVersionConflict instead of overwriting their change.
+// Without stock the current stock is kept, unless untrackStock stops
+// tracking it.
+func (s *productService) UpdateProduct(ctx context.Context, id, name, description string, sku, category, taxCategory *string, weightGrams *int64, price money.Money, overrides []money.Money, stock *int, untrackStock bool, accountId int, version int64) (*models.Product, error) {
+	price, err := validatePrices(price, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if stock != nil && *stock < 0 {
+		return nil, product.ErrInvalidStock
+	}
+
+	current, err := s.repo.GetProductsByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsDeleted() {
+		return nil, product.ErrNotFound
+	}
+	if current.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+	if current.Version != version {
+		return nil, product.ErrVersionConflict
+	}
+
+	if stock == nil && !untrackStock {
+		stock = current.Stock
+	}
+	if sku == nil {
+		sku = &current.Sku
+	}
+	if category == nil {
+		category = &current.Category
+	}
+	normalizedCategory, err := normalizeCategory(*category)
 	if err != nil {
 		return nil, err
 	}
-	if product.AccountId != accountId {
-		return nil, errors.New("unauthorized")
+	if taxCategory == nil {
+		taxCategory = &current.TaxCategory
+	}
+	if !tax.Valid(tax.CategoryOf(*taxCategory)) {
+		return nil, product.ErrInvalidTaxCategory
+	}
+	if weightGrams == nil {
+		weightGrams = &current.WeightGrams
+	}
+	if *weightGrams < 0 {
+		return nil, product.ErrInvalidWeight
 	}
 
 	updateProduct := &models.Product{
 		Id:          id,
 		Name:        name,
 		Description: description,
+		Sku:         strings.TrimSpace(*sku),
+		Category:    normalizedCategory,
+		TaxCategory: tax.CategoryOf(*taxCategory),
+		WeightGrams: *weightGrams,
 		Price:       price,
 		AccountId:   accountId,
+		Images:      current.Images,
+		Status:      current.Status,
+		PublishAt:   current.PublishAt,
+		Version:     version,
+		Stock:       stock,
+
+		RatingAverage:  current.RatingAverage,
+		RatingCount:    current.RatingCount,
+		PriceOverrides: overrides,
+	}
+
+	err = s.writeWithEvents(ctx, id, current, func() error {
+		return s.repo.UpdateProduct(ctx, updateProduct)
+	}, events.ProductUpdated)
+	if err != nil {
+		return nil, err
+	}
+	if price != current.Price {
+		s.recordPrice(ctx, id, price, current.Price, product.PriceReasonManual)
+	}
+
+	return updateProduct, nil
+}
+
+// DeleteProduct moves a product to the trash. It disappears from listings
+// and can no longer be ordered, but past orders can still look it up. The
+// seller may restore it until the purge job removes it for good.
+func (s *productService) DeleteProduct(ctx context.Context, productId string, accountId int) error {
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return err
+	}
+	if p.AccountId != accountId {
+		return product.ErrUnauthorized
+	}
+	if p.IsDeleted() {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	return s.writeWithEvents(ctx, productId, p, func() error {
+		_, err := s.repo.UpdateProductDeletedAt(ctx, productId, &now, p.Version)
+		return err
+	}, events.ProductDeleted)
+}
+
+// RestoreProduct takes a product out of the trash. Published products are
+// announced again so downstream services register them anew.
+func (s *productService) RestoreProduct(ctx context.Context, productId string, accountId int) (*models.Product, error) {
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+	if !p.IsDeleted() {
+		return p, nil
+	}
+	if time.Since(*p.DeletedAt) > config.TrashRetention {
+		return nil, product.ErrRestoreExpired
+	}
+
+	var eventTypes []string
+	if p.IsPublished() {
+		eventTypes = append(eventTypes, events.ProductPublished)
+	}
+
+	var version int64
+	err = s.writeWithEvents(ctx, productId, p, func() (err error) {
+		version, err = s.repo.UpdateProductDeletedAt(ctx, productId, nil, p.Version)
+		return err
+	}, eventTypes...)
+	if err != nil {
+		return nil, err
+	}
+	p.DeletedAt, p.Version = nil, version
+	return p, nil
+}
+
+func (s *productService) ListDeletedProducts(ctx context.Context, accountId int, skip, take uint64) ([]models.Product, error) {
+	return s.repo.ListDeletedProducts(ctx, accountId, skip, take)
+}
+
+// PurgeDeletedProducts permanently removes products that have been in the
+// trash longer than the retention period, along with their images.
+func (s *productService) PurgeDeletedProducts(ctx context.Context) error {
+	products, err := s.repo.ListPurgeableProducts(ctx, time.Now().UTC().Add(-config.TrashRetention))
+	if err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		if err := s.repo.PurgeProduct(ctx, p.Id); err != nil {
+			log.Printf("failed to purge product %s: %v", p.Id, err)
+			continue
+		}
+		for _, image := range p.Images {
+			s.deleteMedia(ctx, image.Key, image.ThumbnailKey)
+		}
+	}
+	return nil
+}
+
+func (s *productService) UploadProductImage(ctx context.Context, productId string, accountId int, contentType string, data []byte) (*models.Product, error) {
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsDeleted() {
+		return nil, product.ErrNotFound
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+
+	contentType, err = ValidateImage(data, contentType, config.MaxImageSize)
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.repo.UpdateProduct(ctx, updateProduct)
+	thumbnail, thumbnailType, err := MakeThumbnail(data, contentType, config.ThumbnailSize, config.MaxImagePixels)
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		err := kafka.SendMessageToRecommender(s, models.Event{
-			Type: "product_updated",
-			Data: models.EventData{
-				Id:          &updateProduct.Id,
-				Name:        &updateProduct.Name,
-				Description: &product.Description,
-				Price:       &updateProduct.Price,
-				AccountID:   &updateProduct.AccountId,
-			},
-		}, "product_events")
+	base := fmt.Sprintf("products/%s/%d", productId, time.Now().UnixNano())
+	image := models.Image{
+		Key:          base + allowedImageTypes[contentType],
+		ThumbnailKey: base + "_thumb" + allowedImageTypes[thumbnailType],
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Position:     len(p.Images),
+	}
+
+	image.URL, err = s.media.Put(ctx, image.Key, contentType, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	image.ThumbnailURL, err = s.media.Put(ctx, image.ThumbnailKey, thumbnailType, bytes.NewReader(thumbnail), int64(len(thumbnail)))
+	if err != nil {
+		s.deleteMedia(ctx, image.Key)
+		return nil, err
+	}
+
+	images := append(p.Images, image)
+	// fails with ErrVersionConflict if another upload got in first
+	version, err := s.repo.UpdateProductImages(ctx, productId, images, p.Version)
+	if err != nil {
+		s.deleteMedia(ctx, image.Key, image.ThumbnailKey)
+		return nil, err
+	}
+
+	p.Images, p.Version = images, version
+	return p, nil
+}
+
+func (s *productService) deleteMedia(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.media.Delete(ctx, key); err != nil {
+			log.Printf("failed to clean up media %s: %v", key, err)
+		}
+	}
+}
+
+func (s *productService) PublishProduct(ctx context.Context, productId string, accountId int, publishAt *time.Time) (*models.Product, error) {
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsDeleted() {
+		return nil, product.ErrNotFound
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+
+	// a future publishAt only schedules the product; the scheduler publishes it
+	now := time.Now().UTC()
+	if publishAt != nil && publishAt.After(now) {
+		if p.Status == product.StatusPublished {
+			return nil, product.ErrAlreadyPublished
+		}
+		version, err := s.repo.UpdateProductStatus(ctx, productId, product.StatusDraft, publishAt, p.Version)
 		if err != nil {
-			log.Println("failed to send to recommendation service:", err)
+			return nil, err
 		}
-	}()
+		p.Status, p.PublishAt, p.Version = product.StatusDraft, publishAt, version
+		return p, nil
+	}
 
-	return updateProduct, nil
+	if p.Status == product.StatusPublished {
+		return p, nil
+	}
+	if err := s.publish(ctx, p, now); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
-func (s *productService) DeleteProduct(ctx context.Context, productId string, accountId int) error {
-	product, err := s.repo.GetProductsByID(ctx, productId)
+func (s *productService) ArchiveProduct(ctx context.Context, productId string, accountId int) (*models.Product, error) {
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsDeleted() {
+		return nil, product.ErrNotFound
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+	if p.Status == product.StatusArchived {
+		return p, nil
+	}
+
+	// drafts were never registered downstream, so there is nothing to retract
+	var eventTypes []string
+	if p.IsPublished() {
+		eventTypes = append(eventTypes, events.ProductArchived)
+	}
+
+	var version int64
+	err = s.writeWithEvents(ctx, productId, p, func() (err error) {
+		version, err = s.repo.UpdateProductStatus(ctx, productId, product.StatusArchived, p.PublishAt, p.Version)
+		return err
+	}, eventTypes...)
+	if err != nil {
+		return nil, err
+	}
+	p.Status, p.Version = product.StatusArchived, version
+	return p, nil
+}
+
+// PublishDueProducts publishes every draft whose scheduled publish time has
+// passed.
+func (s *productService) PublishDueProducts(ctx context.Context) error {
+	now := time.Now().UTC()
+	products, err := s.repo.ListScheduledProducts(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		if err := s.publish(ctx, &products[i], now); err != nil {
+			log.Printf("failed to publish scheduled product %s: %v", products[i].Id, err)
+		}
+	}
+	return nil
+}
+
+func (s *productService) publish(ctx context.Context, p *models.Product, at time.Time) error {
+	var version int64
+	err := s.writeWithEvents(ctx, p.Id, p, func() (err error) {
+		version, err = s.repo.UpdateProductStatus(ctx, p.Id, product.StatusPublished, &at, p.Version)
+		return err
+	}, events.ProductPublished)
+	if err != nil {
+		return err
+	}
+	p.Status, p.PublishAt, p.Version = product.StatusPublished, &at, version
+	return nil
+}
+
+// CreateReview records a review from a verified purchaser: the account must
+// have a paid order containing the product.
+func (s *productService) CreateReview(ctx context.Context, productId string, accountId, rating int, title, body string) (*models.Review, error) {
+	if rating < product.MinRating || rating > product.MaxRating {
+		return nil, product.ErrInvalidRating
+	}
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if !p.VisibleTo(accountId) || p.IsDeleted() {
+		return nil, product.ErrNotFound
+	}
+
+	purchased, err := s.purchases.HasPurchased(ctx, uint64(accountId), productId)
+	if err != nil {
+		return nil, err
+	}
+	if !purchased {
+		return nil, product.ErrNotPurchased
+	}
+
+	now := time.Now().UTC()
+	review := &models.Review{
+		ProductId: productId,
+		AccountId: accountId,
+		Rating:    rating,
+		Title:     title,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.PutReview(ctx, review); err != nil {
+		return nil, err
+	}
+	s.refreshRating(ctx, productId)
+
+	err = s.publisher.PublishJSON(ctx, interactionEventsTopic, review.ProductId, models.ReviewEvent{
+		Type: "review_created",
+		Data: models.ReviewEventData{
+			ReviewId:  review.Id,
+			AccountId: review.AccountId,
+			ProductId: review.ProductId,
+			Rating:    review.Rating,
+		},
+	})
+	if err != nil {
+		log.Println("failed to send event to recommendation service:", err)
+	}
+
+	return review, nil
+}
+
+func (s *productService) ListReviews(ctx context.Context, productId string, skip, take uint64) ([]models.Review, error) {
+	return s.repo.ListReviews(ctx, productId, skip, take)
+}
+
+func (s *productService) UpdateReview(ctx context.Context, reviewId string, accountId, rating int, title, body string) (*models.Review, error) {
+	if rating < product.MinRating || rating > product.MaxRating {
+		return nil, product.ErrInvalidRating
+	}
+	review, err := s.repo.GetReview(ctx, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if review.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+
+	review.Rating, review.Title, review.Body = rating, title, body
+	review.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateReview(ctx, review); err != nil {
+		return nil, err
+	}
+	s.refreshRating(ctx, review.ProductId)
+	return review, nil
+}
+
+func (s *productService) DeleteReview(ctx context.Context, reviewId string, accountId int) error {
+	review, err := s.repo.GetReview(ctx, reviewId)
+	if err != nil {
+		return err
+	}
+	if review.AccountId != accountId {
+		return product.ErrUnauthorized
+	}
+
+	if err := s.repo.DeleteReview(ctx, reviewId); err != nil {
+		return err
+	}
+	s.refreshRating(ctx, review.ProductId)
+	return nil
+}
+
+// ReplyToReview lets the seller of the reviewed product answer it. A new
+// reply replaces the previous one.
+func (s *productService) ReplyToReview(ctx context.Context, reviewId string, accountId int, body string) (*models.Review, error) {
+	review, err := s.repo.GetReview(ctx, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.repo.GetProductsByID(ctx, review.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+
+	review.Reply = &models.SellerReply{
+		AccountId: accountId,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.UpdateReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// refreshRating recomputes the rating summary stored on the product. A
+// failure only leaves the summary stale until the next review change, so it
+// is logged rather than returned.
+func (s *productService) refreshRating(ctx context.Context, productId string) {
+	average, count, err := s.repo.ReviewStats(ctx, productId)
+	if err == nil {
+		err = s.repo.UpdateProductRating(ctx, productId, average, count)
+	}
+	if err != nil {
+		log.Printf("failed to refresh rating of product %s: %v", productId, err)
+	}
+}
+
+func (s *productService) GetPriceHistory(ctx context.Context, productId string, skip, take uint64) ([]models.PriceHistoryEntry, error) {
+	return s.repo.ListPriceHistory(ctx, productId, skip, take)
+}
+
+// SchedulePriceChange plans a new price for a product starting at startAt.
+// With endAt the change is a sale and the previous price is restored when it
+// ends. Changes of the same product may not overlap.
+func (s *productService) SchedulePriceChange(ctx context.Context, productId string, accountId int, price money.Money, startAt time.Time, endAt *time.Time) (*models.ScheduledPrice, error) {
+	if price.Amount <= 0 {
+		return nil, product.ErrInvalidPrice
+	}
+	if endAt != nil && !endAt.After(startAt) {
+		return nil, product.ErrInvalidSchedule
+	}
+
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsDeleted() {
+		return nil, product.ErrNotFound
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+	if price.Currency == "" {
+		price.Currency = p.Price.Currency
+	}
+	if price.Currency != p.Price.Currency {
+		return nil, product.ErrUnsupportedCurrency
+	}
+
+	scheduled, err := s.repo.ListScheduledPrices(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range scheduled {
+		if overlaps(startAt, endAt, other.StartAt, other.EndAt) {
+			return nil, product.ErrScheduleOverlap
+		}
+	}
+
+	change := &models.ScheduledPrice{
+		ProductId: productId,
+		Price:     price,
+		StartAt:   startAt.UTC(),
+		EndAt:     endAt,
+		Status:    product.PriceChangePending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.PutScheduledPrice(ctx, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (s *productService) ListScheduledPrices(ctx context.Context, productId string, accountId int) ([]models.ScheduledPrice, error) {
+	p, err := s.repo.GetProductsByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if p.AccountId != accountId {
+		return nil, product.ErrUnauthorized
+	}
+
+	return s.repo.ListScheduledPrices(ctx, productId)
+}
+
+// CancelPriceChange drops a price change that has not started yet.
+func (s *productService) CancelPriceChange(ctx context.Context, changeId string, accountId int) error {
+	change, err := s.repo.GetScheduledPrice(ctx, changeId)
+	if err != nil {
+		return err
+	}
+	p, err := s.repo.GetProductsByID(ctx, change.ProductId)
+	if err != nil {
+		return err
+	}
+	if p.AccountId != accountId {
+		return product.ErrUnauthorized
+	}
+	if change.Status != product.PriceChangePending {
+		return product.ErrScheduleStarted
+	}
+
+	change.Status = product.PriceChangeCancelled
+	return s.repo.UpdateScheduledPrice(ctx, change)
+}
+
+// ApplyDuePriceChanges starts pending price changes whose start time has
+// passed and ends sales whose end time has passed.
+func (s *productService) ApplyDuePriceChanges(ctx context.Context) error {
+	now := time.Now().UTC()
+	changes, err := s.repo.ListDueScheduledPrices(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range changes {
+		if err := s.applyPriceChange(ctx, &changes[i], now); err != nil {
+			log.Printf("failed to apply price change %s: %v", changes[i].Id, err)
+		}
+	}
+	return nil
+}
+
+func (s *productService) applyPriceChange(ctx context.Context, change *models.ScheduledPrice, now time.Time) error {
+	p, err := s.repo.GetProductsByID(ctx, change.ProductId)
+	if errors.Is(err, product.ErrNotFound) {
+		change.Status = product.PriceChangeCancelled
+		return s.repo.UpdateScheduledPrice(ctx, change)
+	}
 	if err != nil {
 		return err
 	}
-	if product.AccountId != accountId {
-		return errors.New("unauthorized")
+	// a product in the trash keeps its price; the change applies if it is
+	// restored and is cancelled once it is purged
+	if p.IsDeleted() {
+		return nil
 	}
 
-	go func() {
-		err := kafka.SendMessageToRecommender(s, models.Event{
-			Type: "product_deleted",
-			Data: models.EventData{
-				Id: &product.Id,
-			},
-		}, "product_events")
+	switch change.Status {
+	case product.PriceChangePending:
+		// a sale missed entirely, e.g. while the service was down, is skipped
+		// rather than applied after the fact
+		if change.EndAt != nil && !change.EndAt.After(now) {
+			change.Status = product.PriceChangeDone
+			break
+		}
+
+		change.PreviousPrice = p.Price
+		if err := s.setPrice(ctx, p, change.Price, product.PriceReasonScheduled); err != nil {
+			return err
+		}
+		change.Status = product.PriceChangeDone
+		if change.EndAt != nil {
+			change.Status = product.PriceChangeActive
+		}
+
+	case product.PriceChangeActive:
+		// a manual edit during the sale wins over restoring the old price
+		if p.Price == change.Price {
+			if err := s.setPrice(ctx, p, change.PreviousPrice, product.PriceReasonSaleEnded); err != nil {
+				return err
+			}
+		}
+		change.Status = product.PriceChangeDone
+	}
+
+	return s.repo.UpdateScheduledPrice(ctx, change)
+}
+
+// setPrice changes the price of p, records it in the price history and
+// announces it with product_updated so downstream services stay in sync.
+func (s *productService) setPrice(ctx context.Context, p *models.Product, price money.Money, reason string) error {
+	var version int64
+	err := s.writeWithEvents(ctx, p.Id, p, func() (err error) {
+		version, err = s.repo.UpdateProductPrice(ctx, p.Id, price, p.Version)
+		return err
+	}, events.ProductUpdated)
+	if err != nil {
+		return err
+	}
+
+	previous := p.Price
+	p.Price, p.Version = price, version
+	s.recordPrice(ctx, p.Id, price, previous, reason)
+	return nil
+}
+
+func (s *productService) recordPrice(ctx context.Context, productId string, price, previous money.Money, reason string) {
+	err := s.repo.PutPriceHistory(ctx, &models.PriceHistoryEntry{
+		ProductId:     productId,
+		Price:         price,
+		PreviousPrice: previous,
+		Reason:        reason,
+		ChangedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("failed to record price history of product %s: %v", productId, err)
+	}
+}
+
+// LocalizePrice fills in the display price of p in currency. The seller's own
+// price for that currency wins; otherwise the price is converted with the
+// current exchange rate. Without a usable rate the product keeps showing its
+// own price and currency.
+func (s *productService) LocalizePrice(ctx context.Context, p *models.Product, currency string) {
+	p.DisplayPrice = p.Price
+	if currency == "" {
+		return
+	}
+
+	if price, ok := p.PriceIn(currency); ok {
+		p.DisplayPrice = price
+		return
+	}
+	if s.rates == nil {
+		return
+	}
+
+	rate, err := s.rates.Rate(ctx, p.Price.Currency, currency)
+	if err != nil {
+		log.Printf("failed to convert price of product %s to %s: %v", p.Id, currency, err)
+		return
+	}
+	p.DisplayPrice = p.Price.Convert(currency, rate, money.HalfUp)
+}
+
+// normalizeCategory lowercases a category and checks that it is a slug.
+func normalizeCategory(category string) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if len(category) > 64 || strings.HasPrefix(category, "-") || strings.HasSuffix(category, "-") {
+		return "", product.ErrInvalidCategory
+	}
+	for _, r := range category {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return "", product.ErrInvalidCategory
+		}
+	}
+	return category, nil
+}
+
+// validatePrices checks the product price and price overrides and returns the
+// price to store, defaulting its currency to DefaultCurrency.
+func validatePrices(price money.Money, overrides []money.Money) (money.Money, error) {
+	if price.Currency == "" {
+		price.Currency = product.DefaultCurrency
+	}
+	if !slices.Contains(config.SupportedCurrencies, price.Currency) {
+		return money.Money{}, product.ErrUnsupportedCurrency
+	}
+	if price.Amount <= 0 {
+		return money.Money{}, product.ErrInvalidPrice
+	}
+
+	seen := map[string]bool{price.Currency: true}
+	for _, override := range overrides {
+		if !slices.Contains(config.SupportedCurrencies, override.Currency) || seen[override.Currency] {
+			return money.Money{}, product.ErrUnsupportedCurrency
+		}
+		if override.Amount <= 0 {
+			return money.Money{}, product.ErrInvalidPrice
+		}
+		seen[override.Currency] = true
+	}
+	return price, nil
+}
+
+// overlaps reports whether two price change windows intersect. A nil end
+// means the change lasts forever.
+func overlaps(startA time.Time, endA *time.Time, startB time.Time, endB *time.Time) bool {
+	return (endB == nil || startA.Before(*endB)) && (endA == nil || startB.Before(*endA))
+}
+
+// ReserveStock takes stock for the items of an order: all of them or, if one
+// is out of stock, none. Reserving an id that is already reserved does
+// nothing. Products without stock tracking can always be reserved.
+func (s *productService) ReserveStock(ctx context.Context, reservationId string, items []models.ReservedItem) error {
+	_, err := s.repo.GetStockReservation(ctx, reservationId)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, product.ErrNotFound) {
+		return err
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return product.ErrInvalidStock
+		}
+	}
+
+	var taken []models.ReservedItem
+	for _, item := range items {
+		if err := s.repo.AdjustStock(ctx, item.ProductId, -item.Quantity); err != nil {
+			s.returnStock(taken)
+			return err
+		}
+		taken = append(taken, item)
+	}
+
+	err = s.repo.PutStockReservation(ctx, &models.StockReservation{
+		Id:        reservationId,
+		Items:     items,
+		Status:    product.ReservationReserved,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		s.returnStock(taken)
+		if errors.Is(err, product.ErrVersionConflict) {
+			// reserved by a concurrent request
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ReleaseStock puts the stock of a reservation back. Releasing an unknown
+// or released reservation does nothing. The reservation is marked released
+// before the stock is returned, so concurrent releases return it only once.
+func (s *productService) ReleaseStock(ctx context.Context, reservationId string) error {
+	reservation, err := s.repo.GetStockReservation(ctx, reservationId)
+	if errors.Is(err, product.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if reservation.Status == product.ReservationReleased {
+		return nil
+	}
+
+	err = s.repo.UpdateStockReservationStatus(ctx, reservation, product.ReservationReleased)
+	if errors.Is(err, product.ErrVersionConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, item := range reservation.Items {
+		err := s.repo.AdjustStock(ctx, item.ProductId, item.Quantity)
+		if err != nil && !errors.Is(err, product.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("failed to return %d of product %s: %w", item.Quantity, item.ProductId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// returnStock undoes the stock taken by a reservation that failed.
+func (s *productService) returnStock(items []models.ReservedItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, item := range items {
+		if err := s.repo.AdjustStock(ctx, item.ProductId, item.Quantity); err != nil {
+			log.Printf("failed to return %d of product %s: %v", item.Quantity, item.ProductId, err)
+		}
+	}
+}
+
+// StartPublishScheduler periodically publishes scheduled products until ctx
+// is cancelled.
+func StartPublishScheduler(ctx context.Context, s Service, interval time.Duration) {
+	runEvery(ctx, interval, "publish scheduler", s.PublishDueProducts)
+}
+
+// StartPurgeScheduler periodically purges expired products from the trash
+// until ctx is cancelled.
+func StartPurgeScheduler(ctx context.Context, s Service, interval time.Duration) {
+	runEvery(ctx, interval, "purge scheduler", s.PurgeDeletedProducts)
+}
+
+// StartPriceScheduler periodically applies scheduled price changes until ctx
+// is cancelled.
+func StartPriceScheduler(ctx context.Context, s Service, interval time.Duration) {
+	runEvery(ctx, interval, "price scheduler", s.ApplyDuePriceChanges)
+}
+
+// BackfillPriceAmounts converts the catalog prices still stored as decimal
+// numbers to minor units, a batch at a time, until none are left or ctx is
+// cancelled. Products are read with a decimal fallback in the meantime.
+func BackfillPriceAmounts(ctx context.Context, repo Repository) {
+	total := 0
+	for ctx.Err() == nil {
+		converted, err := repo.ConvertLegacyPrices(ctx, 100)
 		if err != nil {
-			log.Println("failed to send event to recommendation service:", err)
+			log.Printf("price backfill error: %v", err)
+			return
+		}
+		if converted == 0 {
+			break
 		}
-	}()
+		total += converted
+	}
+	if total > 0 {
+		log.Printf("price backfill converted %d products", total)
+	}
+}
+
+func runEvery(ctx context.Context, interval time.Duration, name string, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	return s.repo.DeleteProduct(ctx, productId)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Printf("%s error: %v", name, err)
+			}
+		}
+	}
 }
@@ -1,11 +1,10 @@
 package models
 
+// EventData describes a user interaction with a product. Changes to products
+// themselves are published as events.ProductEvent.
 type EventData struct {
-	Id          *string  `json:"product_id,omitempty"`
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	AccountID   *int     `json:"accountID,omitempty"`
+	Id        *string `json:"product_id,omitempty"`
+	AccountID *int    `json:"accountID,omitempty"`
 }
 
 type Event struct {
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEntry records that a product change produces an event. It is stored
+// before the change is written; see productOutbox for how it is published.
+type OutboxEntry struct {
+	Id        string `json:"-"`
+	ProductId string `json:"productId"`
+	Type      string `json:"type"`
+	// Sequence orders the entries of a product.
+	Sequence int64 `json:"sequence"`
+	// Before is the events.ProductSnapshot of the product before the change
+	// in protobuf JSON, or empty for new products.
+	Before   json.RawMessage `json:"before,omitempty"`
+	StagedAt time.Time       `json:"stagedAt"`
+
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"lastError,omitempty"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+}
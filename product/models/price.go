@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+)
+
+// PriceHistoryEntry records one effective change of a product's price.
+type PriceHistoryEntry struct {
+	ProductId     string      `json:"productId"`
+	Price         money.Money `json:"price"`
+	PreviousPrice money.Money `json:"previousPrice"`
+	Reason        string      `json:"reason"`
+	ChangedAt     time.Time   `json:"changedAt"`
+}
+
+// ScheduledPrice is a planned price change. Without EndAt the new price is
+// permanent; with EndAt it is a sale and the previous price comes back once
+// the sale ends.
+type ScheduledPrice struct {
+	Id        string      `json:"id"`
+	ProductId string      `json:"productId"`
+	Price     money.Money `json:"price"`
+	StartAt   time.Time   `json:"startAt"`
+	EndAt     *time.Time  `json:"endAt,omitempty"`
+	Status    string      `json:"status"`
+	// PreviousPrice is the price replaced when the change was applied.
+	PreviousPrice money.Money `json:"previousPrice"`
+	CreatedAt     time.Time   `json:"createdAt"`
+}
@@ -1,15 +1,150 @@
 package models
 
+import (
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+)
+
 type Product struct {
-	Id         string  `json:"id"`
-	Name       string  `json:"name"`
-	Description string  `json:"description"`
-	Price      float64 `json:"price"`
-	AccountId  int     `json:"accountId"`
+	Id          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	AccountId   int         `json:"accountId"`
+	Images      []Image     `json:"images"`
+	Status      string      `json:"status"`
+	PublishAt   *time.Time  `json:"publishAt"`
+	// Version is the revision of the product. Edits made against an older
+	// revision are rejected; rating and stock changes do not count.
+	Version int64 `json:"version"`
+	// DeletedAt is set while the product is in the trash.
+	DeletedAt *time.Time `json:"deletedAt"`
+	// Stock is the number of units available, or nil when the seller does
+	// not track stock. Only the seller gets to see it.
+	Stock *int `json:"stock"`
+	// Sku is the seller's stock keeping unit, empty if they did not set one.
+	Sku string `json:"sku"`
+	// Category is a lowercase slug such as "home-garden", empty if the
+	// seller did not categorize the product.
+	Category string `json:"category"`
+	// TaxCategory is one of the pkg/tax categories, which the order service
+	// charges tax by.
+	TaxCategory string `json:"taxCategory"`
+	// WeightGrams is the shipping weight, 0 if the seller did not set it.
+	WeightGrams int64 `json:"weightGrams"`
+
+	RatingAverage float64 `json:"ratingAverage"`
+	RatingCount   int     `json:"ratingCount"`
+
+	// PriceOverrides are prices set explicitly by the seller for other
+	// currencies; they take precedence over exchange-rate conversion.
+	PriceOverrides []money.Money `json:"priceOverrides"`
+	// DisplayPrice is the price shown to the caller when a display currency
+	// was requested. It is never stored.
+	DisplayPrice money.Money `json:"displayPrice"`
+}
+
+// PriceIn returns the seller's price for currency, if there is one.
+func (p *Product) PriceIn(currency string) (money.Money, bool) {
+	if currency == p.Price.Currency {
+		return p.Price, true
+	}
+	for _, override := range p.PriceOverrides {
+		if override.Currency == currency {
+			return override, true
+		}
+	}
+	return money.Money{}, false
+}
+
+// ProductFilter narrows and orders product listings.
+type ProductFilter struct {
+	// ViewerId is the account browsing; 0 for anonymous callers.
+	ViewerId  int
+	MinRating float64
+	// SortBy is "" for relevance (or index order) and "rating" for the best
+	// rated products first.
+	SortBy string
+	// DisplayCurrency asks for prices converted to this currency; it does not
+	// filter anything out.
+	DisplayCurrency string
+	// IncludeHidden returns products looked up by id whatever their status,
+	// deleted ones included, for services pricing carts and orders; they
+	// check availability themselves. Listings and searches ignore it.
+	IncludeHidden bool
+}
+
+// IsPublished reports whether the product is visible to everyone.
+func (p *Product) IsPublished() bool {
+	return p.Status == "" || p.Status == product.StatusPublished
+}
+
+// IsDeleted reports whether the product is in the trash. Deleted products
+// can no longer be bought but still resolve by id.
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// VisibleTo reports whether accountId may see the product. Owners can always
+// see their own drafts and archived products.
+func (p *Product) VisibleTo(accountId int) bool {
+	return p.IsPublished() || (accountId != 0 && p.AccountId == accountId)
 }
 
+// ProductDocument is the catalog document. Prices are stored as exact minor
+// units of Currency.
 type ProductDocument struct {
-	Name       string  `json:"name"`
-	Description string  `json:"description"`
-	Price      float64 `json:"price"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	PriceAmount int64      `json:"priceAmount"`
+	Currency    string     `json:"currency,omitempty"`
+	AccountId   int        `json:"accountId"`
+	Images      []Image    `json:"images,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	// not omitempty: updates must be able to stop tracking the stock and
+	// clear the SKU and category
+	Stock    *int   `json:"stock"`
+	Sku      string `json:"sku"`
+	Category string `json:"category"`
+	// empty for products stored before tax categories, which are physical
+	TaxCategory string `json:"taxCategory,omitempty"`
+	WeightGrams int64  `json:"weightGrams,omitempty"`
+
+	RatingAverage float64 `json:"ratingAverage,omitempty"`
+	RatingCount   int     `json:"ratingCount,omitempty"`
+
+	// not omitempty: partial updates must be able to clear the overrides
+	PriceOverrides []PriceOverride `json:"priceOverrides"`
+
+	// Revision counts the edits of the product; 0 for products stored
+	// before revisions.
+	Revision int64 `json:"revision,omitempty"`
+
+	// Price is the decimal price of documents stored before minor units.
+	// It is only read until the backfill has converted them.
+	Price float64 `json:"price,omitempty"`
+}
+
+// PriceOverride is a stored price for another currency, in minor units.
+// Price is the decimal price of overrides stored before minor units.
+type PriceOverride struct {
+	Currency string  `json:"currency"`
+	Amount   int64   `json:"amount"`
+	Price    float64 `json:"price,omitempty"`
+}
+
+// Image is an uploaded product picture. Images are kept in upload order and
+// Position is the zero-based index used to sort them.
+type Image struct {
+	Key          string `json:"key"`
+	URL          string `json:"url"`
+	ThumbnailKey string `json:"thumbnailKey"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	ContentType  string `json:"contentType"`
+	Size         int64  `json:"size"`
+	Position     int    `json:"position"`
 }
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type Review struct {
+	Id        string       `json:"id"`
+	ProductId string       `json:"productId"`
+	AccountId int          `json:"accountId"`
+	Rating    int          `json:"rating"`
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	Reply     *SellerReply `json:"reply,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// SellerReply is the product owner's public answer to a review.
+type SellerReply struct {
+	AccountId int       `json:"accountId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ReviewEventData struct {
+	ReviewId  string `json:"review_id"`
+	AccountId int    `json:"user_id"`
+	ProductId string `json:"product_id"`
+	Rating    int    `json:"rating"`
+}
+
+type ReviewEvent struct {
+	Type string          `json:"type"`
+	Data ReviewEventData `json:"data"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StockReservation holds stock for an order until the order is cancelled.
+// Its id is chosen by the order service, so reserving twice is harmless.
+type StockReservation struct {
+	Id        string         `json:"-"`
+	Items     []ReservedItem `json:"items"`
+	Status    string         `json:"status"`
+	CreatedAt time.Time      `json:"createdAt"`
+	// Version guards status changes against concurrent releases.
+	Version int64 `json:"-"`
+}
+
+type ReservedItem struct {
+	ProductId string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
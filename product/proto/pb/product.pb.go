@@ -7,10 +7,11 @@
 package pb
 
 import (
+	pb "github.com/abhiii71/orderStream/pkg/money/proto/pb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
-	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,32 +24,1965 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProductImage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	ThumbnailUrl  string                 `protobuf:"bytes,2,opt,name=thumbnailUrl,proto3" json:"thumbnailUrl,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Position      int32                  `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductImage) Reset() {
+	*x = ProductImage{}
+	mi := &file_product_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductImage) ProtoMessage() {}
+
+func (x *ProductImage) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductImage.ProtoReflect.Descriptor instead.
+func (*ProductImage) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProductImage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ProductImage) GetThumbnailUrl() string {
+	if x != nil {
+		return x.ThumbnailUrl
+	}
+	return ""
+}
+
+func (x *ProductImage) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ProductImage) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ProductImage) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
 type Product struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	AccountId     int64                  `protobuf:"varint,5,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price         *pb.Money              `protobuf:"bytes,16,opt,name=price,proto3" json:"price,omitempty"`
+	AccountId     int64                  `protobuf:"varint,5,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Images        []*ProductImage        `protobuf:"bytes,6,rep,name=images,proto3" json:"images,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	PublishAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=publishAt,proto3" json:"publishAt,omitempty"`
+	Version       int64                  `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	RatingAverage float64                `protobuf:"fixed64,10,opt,name=ratingAverage,proto3" json:"ratingAverage,omitempty"`
+	RatingCount   int32                  `protobuf:"varint,11,opt,name=ratingCount,proto3" json:"ratingCount,omitempty"`
+	// prices the seller set for other currencies
+	PriceOverrides []*pb.Money `protobuf:"bytes,17,rep,name=priceOverrides,proto3" json:"priceOverrides,omitempty"`
+	// price in the requested display currency, or the product's own price
+	// if none was requested or it could not be converted
+	DisplayPrice *pb.Money `protobuf:"bytes,18,opt,name=displayPrice,proto3" json:"displayPrice,omitempty"`
+	// set while the product is in the trash
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	// units in stock; only sent to the seller, and unset when stock is not
+	// tracked
+	Stock *int64 `protobuf:"varint,20,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
+	// seller's stock keeping unit; empty if none was set
+	Sku string `protobuf:"bytes,21,opt,name=sku,proto3" json:"sku,omitempty"`
+	// lowercase category slug, e.g. "home-garden"; empty if uncategorized
+	Category string `protobuf:"bytes,22,opt,name=category,proto3" json:"category,omitempty"`
+	// physical, physical_reduced, digital, e_book, saas, edtech or exempt
+	TaxCategory string `protobuf:"bytes,23,opt,name=taxCategory,proto3" json:"taxCategory,omitempty"`
+	// shipping weight in grams; 0 if not set
+	WeightGrams   int64 `protobuf:"varint,24,opt,name=weightGrams,proto3" json:"weightGrams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Product) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Product) GetImages() []*ProductImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *Product) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Product) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+func (x *Product) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Product) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *Product) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Product) GetPriceOverrides() []*pb.Money {
+	if x != nil {
+		return x.PriceOverrides
+	}
+	return nil
+}
+
+func (x *Product) GetDisplayPrice() *pb.Money {
+	if x != nil {
+		return x.DisplayPrice
+	}
+	return nil
+}
+
+func (x *Product) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Product) GetStock() int64 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
+	return 0
+}
+
+func (x *Product) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *Product) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Product) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+func (x *Product) GetWeightGrams() int64 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
+}
+
+type CreateProductRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Price          *pb.Money              `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	AccountId      int64                  `protobuf:"varint,4,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	PublishAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=publishAt,proto3" json:"publishAt,omitempty"`
+	PriceOverrides []*pb.Money            `protobuf:"bytes,10,rep,name=priceOverrides,proto3" json:"priceOverrides,omitempty"`
+	Stock          *int64                 `protobuf:"varint,11,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
+	Sku            string                 `protobuf:"bytes,12,opt,name=sku,proto3" json:"sku,omitempty"`
+	Category       string                 `protobuf:"bytes,13,opt,name=category,proto3" json:"category,omitempty"`
+	// empty for physical
+	TaxCategory   string `protobuf:"bytes,14,opt,name=taxCategory,proto3" json:"taxCategory,omitempty"`
+	WeightGrams   int64  `protobuf:"varint,15,opt,name=weightGrams,proto3" json:"weightGrams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_product_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *CreateProductRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+func (x *CreateProductRequest) GetPriceOverrides() []*pb.Money {
+	if x != nil {
+		return x.PriceOverrides
+	}
+	return nil
+}
+
+func (x *CreateProductRequest) GetStock() int64 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetWeightGrams() int64 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
+}
+
+type GetProductRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ViewerAccountId int64                  `protobuf:"varint,2,opt,name=viewerAccountId,proto3" json:"viewerAccountId,omitempty"`
+	DisplayCurrency string                 `protobuf:"bytes,3,opt,name=displayCurrency,proto3" json:"displayCurrency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_product_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetProductRequest) GetViewerAccountId() int64 {
+	if x != nil {
+		return x.ViewerAccountId
+	}
+	return 0
+}
+
+func (x *GetProductRequest) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type GetProductsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Skip            uint64                 `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take            uint64                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
+	Ids             []string               `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
+	Query           string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	ViewerAccountId int64                  `protobuf:"varint,5,opt,name=viewerAccountId,proto3" json:"viewerAccountId,omitempty"`
+	MinRating       float64                `protobuf:"fixed64,6,opt,name=minRating,proto3" json:"minRating,omitempty"`
+	// "rating" sorts best rated first; empty keeps relevance order
+	SortBy          string `protobuf:"bytes,7,opt,name=sortBy,proto3" json:"sortBy,omitempty"`
+	DisplayCurrency string `protobuf:"bytes,8,opt,name=displayCurrency,proto3" json:"displayCurrency,omitempty"`
+	// lookups by id from other services: deleted and unpublished products
+	// are returned too
+	IncludeHidden bool `protobuf:"varint,9,opt,name=includeHidden,proto3" json:"includeHidden,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsRequest) Reset() {
+	*x = GetProductsRequest{}
+	mi := &file_product_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsRequest) ProtoMessage() {}
+
+func (x *GetProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetProductsRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *GetProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetViewerAccountId() int64 {
+	if x != nil {
+		return x.ViewerAccountId
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetMinRating() float64 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetIncludeHidden() bool {
+	if x != nil {
+		return x.IncludeHidden
+	}
+	return false
+}
+
+type ListProductsByAccountRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId int64                  `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	// only products in these statuses; empty lists all of them
+	Statuses      []string `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Skip          uint64   `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64   `protobuf:"varint,4,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsByAccountRequest) Reset() {
+	*x = ListProductsByAccountRequest{}
+	mi := &file_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsByAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsByAccountRequest) ProtoMessage() {}
+
+func (x *ListProductsByAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsByAccountRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsByAccountRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListProductsByAccountRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ListProductsByAccountRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListProductsByAccountRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListProductsByAccountRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type UpdateProductRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price       *pb.Money              `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	AccountId   int64                  `protobuf:"varint,5,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	// version of the product the edit was based on; a stale version is
+	// rejected with ABORTED
+	Version        int64       `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	PriceOverrides []*pb.Money `protobuf:"bytes,10,rep,name=priceOverrides,proto3" json:"priceOverrides,omitempty"`
+	// unset keeps the current stock
+	Stock *int64 `protobuf:"varint,11,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
+	// unset keeps the current SKU
+	Sku *string `protobuf:"bytes,12,opt,name=sku,proto3,oneof" json:"sku,omitempty"`
+	// unset keeps the current category
+	Category *string `protobuf:"bytes,13,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	// unset keeps the current tax category
+	TaxCategory *string `protobuf:"bytes,14,opt,name=taxCategory,proto3,oneof" json:"taxCategory,omitempty"`
+	// unset keeps the current weight
+	WeightGrams *int64 `protobuf:"varint,15,opt,name=weightGrams,proto3,oneof" json:"weightGrams,omitempty"`
+	// stops tracking the stock when stock is unset; the product can then
+	// always be bought
+	UntrackStock  bool `protobuf:"varint,16,opt,name=untrackStock,proto3" json:"untrackStock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_product_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetPriceOverrides() []*pb.Money {
+	if x != nil {
+		return x.PriceOverrides
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetStock() int64 {
+	if x != nil && x.Stock != nil {
+		return *x.Stock
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetSku() string {
+	if x != nil && x.Sku != nil {
+		return *x.Sku
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetTaxCategory() string {
+	if x != nil && x.TaxCategory != nil {
+		return *x.TaxCategory
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetWeightGrams() int64 {
+	if x != nil && x.WeightGrams != nil {
+		return *x.WeightGrams
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetUntrackStock() bool {
+	if x != nil {
+		return x.UntrackStock
+	}
+	return false
+}
+
+type DeleteProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductRequest) Reset() {
+	*x = DeleteProductRequest{}
+	mi := &file_product_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductRequest) ProtoMessage() {}
+
+func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *DeleteProductRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type RestoreProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreProductRequest) Reset() {
+	*x = RestoreProductRequest{}
+	mi := &file_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreProductRequest) ProtoMessage() {}
+
+func (x *RestoreProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreProductRequest.ProtoReflect.Descriptor instead.
+func (*RestoreProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestoreProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RestoreProductRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type ListDeletedProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     int64                  `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeletedProductsRequest) Reset() {
+	*x = ListDeletedProductsRequest{}
+	mi := &file_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeletedProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedProductsRequest) ProtoMessage() {}
+
+func (x *ListDeletedProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeletedProductsRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDeletedProductsRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ListDeletedProductsRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListDeletedProductsRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type PublishProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	PublishAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=publishAt,proto3" json:"publishAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishProductRequest) Reset() {
+	*x = PublishProductRequest{}
+	mi := &file_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishProductRequest) ProtoMessage() {}
+
+func (x *PublishProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishProductRequest.ProtoReflect.Descriptor instead.
+func (*PublishProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PublishProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PublishProductRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *PublishProductRequest) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+type ArchiveProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveProductRequest) Reset() {
+	*x = ArchiveProductRequest{}
+	mi := &file_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveProductRequest) ProtoMessage() {}
+
+func (x *ArchiveProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveProductRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ArchiveProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ArchiveProductRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type UploadProductImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadProductImageRequest) Reset() {
+	*x = UploadProductImageRequest{}
+	mi := &file_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadProductImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadProductImageRequest) ProtoMessage() {}
+
+func (x *UploadProductImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadProductImageRequest.ProtoReflect.Descriptor instead.
+func (*UploadProductImageRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UploadProductImageRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *UploadProductImageRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UploadProductImageRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadProductImageRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ReviewReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     int64                  `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewReply) Reset() {
+	*x = ReviewReply{}
+	mi := &file_product_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewReply) ProtoMessage() {}
+
+func (x *ReviewReply) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewReply.ProtoReflect.Descriptor instead.
+func (*ReviewReply) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReviewReply) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ReviewReply) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *ReviewReply) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Review struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Rating        int32                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Reply         *ReviewReply           `protobuf:"bytes,7,opt,name=reply,proto3" json:"reply,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_product_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Review) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Review) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Review) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Review) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Review) GetReply() *ReviewReply {
+	if x != nil {
+		return x.Reply
+	}
+	return nil
+}
+
+func (x *Review) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Review) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Rating        int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewRequest) Reset() {
+	*x = CreateReviewRequest{}
+	mi := &file_product_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewRequest) ProtoMessage() {}
+
+func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
+func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateReviewRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *CreateReviewRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *CreateReviewRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type ListReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRequest) Reset() {
+	*x = ListReviewsRequest{}
+	mi := &file_product_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRequest) ProtoMessage() {}
+
+func (x *ListReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListReviewsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type UpdateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=reviewId,proto3" json:"reviewId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Rating        int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReviewRequest) Reset() {
+	*x = UpdateReviewRequest{}
+	mi := &file_product_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReviewRequest) ProtoMessage() {}
+
+func (x *UpdateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReviewRequest.ProtoReflect.Descriptor instead.
+func (*UpdateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *UpdateReviewRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UpdateReviewRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *UpdateReviewRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateReviewRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type DeleteReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=reviewId,proto3" json:"reviewId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReviewRequest) Reset() {
+	*x = DeleteReviewRequest{}
+	mi := &file_product_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewRequest) ProtoMessage() {}
+
+func (x *DeleteReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReviewRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *DeleteReviewRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type ReplyToReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=reviewId,proto3" json:"reviewId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyToReviewRequest) Reset() {
+	*x = ReplyToReviewRequest{}
+	mi := &file_product_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyToReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyToReviewRequest) ProtoMessage() {}
+
+func (x *ReplyToReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyToReviewRequest.ProtoReflect.Descriptor instead.
+func (*ReplyToReviewRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReplyToReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ReplyToReviewRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ReplyToReviewRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type ReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewResponse) Reset() {
+	*x = ReviewResponse{}
+	mi := &file_product_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewResponse) ProtoMessage() {}
+
+func (x *ReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewResponse.ProtoReflect.Descriptor instead.
+func (*ReviewResponse) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewsResponse) Reset() {
+	*x = ReviewsResponse{}
+	mi := &file_product_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewsResponse) ProtoMessage() {}
+
+func (x *ReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReviewsResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+type PriceHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         *pb.Money              `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	PreviousPrice *pb.Money              `protobuf:"bytes,6,opt,name=previousPrice,proto3" json:"previousPrice,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=changedAt,proto3" json:"changedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceHistoryEntry) Reset() {
+	*x = PriceHistoryEntry{}
+	mi := &file_product_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistoryEntry) ProtoMessage() {}
+
+func (x *PriceHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistoryEntry.ProtoReflect.Descriptor instead.
+func (*PriceHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PriceHistoryEntry) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *PriceHistoryEntry) GetPreviousPrice() *pb.Money {
+	if x != nil {
+		return x.PreviousPrice
+	}
+	return nil
+}
+
+func (x *PriceHistoryEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PriceHistoryEntry) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type ScheduledPrice struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	Price     *pb.Money              `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	StartAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=startAt,proto3" json:"startAt,omitempty"`
+	// unset for permanent changes; set for sales
+	EndAt         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=endAt,proto3" json:"endAt,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledPrice) Reset() {
+	*x = ScheduledPrice{}
+	mi := &file_product_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPrice) ProtoMessage() {}
+
+func (x *ScheduledPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPrice.ProtoReflect.Descriptor instead.
+func (*ScheduledPrice) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ScheduledPrice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledPrice) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ScheduledPrice) GetPrice() *pb.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ScheduledPrice) GetStartAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartAt
+	}
+	return nil
+}
+
+func (x *ScheduledPrice) GetEndAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndAt
+	}
+	return nil
+}
+
+func (x *ScheduledPrice) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetPriceHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceHistoryRequest) Reset() {
+	*x = GetPriceHistoryRequest{}
+	mi := &file_product_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceHistoryRequest) ProtoMessage() {}
+
+func (x *GetPriceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetPriceHistoryRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type SchedulePriceChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Price         *pb.Money              `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	StartAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=startAt,proto3" json:"startAt,omitempty"`
+	EndAt         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=endAt,proto3" json:"endAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) Reset() {
-	*x = Product{}
-	mi := &file_product_proto_msgTypes[0]
+func (x *SchedulePriceChangeRequest) Reset() {
+	*x = SchedulePriceChangeRequest{}
+	mi := &file_product_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Product) String() string {
+func (x *SchedulePriceChangeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Product) ProtoMessage() {}
+func (*SchedulePriceChangeRequest) ProtoMessage() {}
 
-func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[0]
+func (x *SchedulePriceChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,71 +1993,69 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Product.ProtoReflect.Descriptor instead.
-func (*Product) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use SchedulePriceChangeRequest.ProtoReflect.Descriptor instead.
+func (*SchedulePriceChangeRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *Product) GetId() string {
+func (x *SchedulePriceChangeRequest) GetProductId() string {
 	if x != nil {
-		return x.Id
+		return x.ProductId
 	}
 	return ""
 }
 
-func (x *Product) GetName() string {
+func (x *SchedulePriceChangeRequest) GetAccountId() int64 {
 	if x != nil {
-		return x.Name
+		return x.AccountId
 	}
-	return ""
+	return 0
 }
 
-func (x *Product) GetDescription() string {
+func (x *SchedulePriceChangeRequest) GetPrice() *pb.Money {
 	if x != nil {
-		return x.Description
+		return x.Price
 	}
-	return ""
+	return nil
 }
 
-func (x *Product) GetPrice() float64 {
+func (x *SchedulePriceChangeRequest) GetStartAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Price
+		return x.StartAt
 	}
-	return 0
+	return nil
 }
 
-func (x *Product) GetAccountId() int64 {
+func (x *SchedulePriceChangeRequest) GetEndAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.AccountId
+		return x.EndAt
 	}
-	return 0
+	return nil
 }
 
-type CreateProductRequest struct {
+type ListScheduledPricesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
-	AccountId     int64                  `protobuf:"varint,4,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateProductRequest) Reset() {
-	*x = CreateProductRequest{}
-	mi := &file_product_proto_msgTypes[1]
+func (x *ListScheduledPricesRequest) Reset() {
+	*x = ListScheduledPricesRequest{}
+	mi := &file_product_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateProductRequest) String() string {
+func (x *ListScheduledPricesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateProductRequest) ProtoMessage() {}
+func (*ListScheduledPricesRequest) ProtoMessage() {}
 
-func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[1]
+func (x *ListScheduledPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -134,64 +2066,100 @@ func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
-func (*CreateProductRequest) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ListScheduledPricesRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledPricesRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *CreateProductRequest) GetName() string {
+func (x *ListScheduledPricesRequest) GetProductId() string {
 	if x != nil {
-		return x.Name
+		return x.ProductId
 	}
 	return ""
 }
 
-func (x *CreateProductRequest) GetDescription() string {
+func (x *ListScheduledPricesRequest) GetAccountId() int64 {
 	if x != nil {
-		return x.Description
+		return x.AccountId
 	}
-	return ""
+	return 0
+}
+
+type CancelPriceChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChangeId      string                 `protobuf:"bytes,1,opt,name=changeId,proto3" json:"changeId,omitempty"`
+	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelPriceChangeRequest) Reset() {
+	*x = CancelPriceChangeRequest{}
+	mi := &file_product_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelPriceChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *CreateProductRequest) GetPrice() float64 {
+func (*CancelPriceChangeRequest) ProtoMessage() {}
+
+func (x *CancelPriceChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[27]
 	if x != nil {
-		return x.Price
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *CreateProductRequest) GetAccountId() int64 {
+// Deprecated: Use CancelPriceChangeRequest.ProtoReflect.Descriptor instead.
+func (*CancelPriceChangeRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CancelPriceChangeRequest) GetChangeId() string {
+	if x != nil {
+		return x.ChangeId
+	}
+	return ""
+}
+
+func (x *CancelPriceChangeRequest) GetAccountId() int64 {
 	if x != nil {
 		return x.AccountId
 	}
 	return 0
 }
 
-type GetProductsRequest struct {
+type ReservedItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Skip          uint64                 `protobuf:"varint,1,opt,name=skip,proto3" json:"skip,omitempty"`
-	Take          uint64                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
-	Ids           []string               `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
-	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductsRequest) Reset() {
-	*x = GetProductsRequest{}
-	mi := &file_product_proto_msgTypes[2]
+func (x *ReservedItem) Reset() {
+	*x = ReservedItem{}
+	mi := &file_product_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductsRequest) String() string {
+func (x *ReservedItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductsRequest) ProtoMessage() {}
+func (*ReservedItem) ProtoMessage() {}
 
-func (x *GetProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[2]
+func (x *ReservedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -202,65 +2170,99 @@ func (x *GetProductsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductsRequest.ProtoReflect.Descriptor instead.
-func (*GetProductsRequest) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ReservedItem.ProtoReflect.Descriptor instead.
+func (*ReservedItem) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *GetProductsRequest) GetSkip() uint64 {
+func (x *ReservedItem) GetProductId() string {
 	if x != nil {
-		return x.Skip
+		return x.ProductId
 	}
-	return 0
+	return ""
 }
 
-func (x *GetProductsRequest) GetTake() uint64 {
+func (x *ReservedItem) GetQuantity() int32 {
 	if x != nil {
-		return x.Take
+		return x.Quantity
 	}
 	return 0
 }
 
-func (x *GetProductsRequest) GetIds() []string {
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservationId,proto3" json:"reservationId,omitempty"`
+	Items         []*ReservedItem        `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_product_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[29]
 	if x != nil {
-		return x.Ids
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *GetProductsRequest) GetQuery() string {
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReserveStockRequest) GetReservationId() string {
 	if x != nil {
-		return x.Query
+		return x.ReservationId
 	}
 	return ""
 }
 
-type UpdateProductRequest struct {
+func (x *ReserveStockRequest) GetItems() []*ReservedItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ReleaseStockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	AccountId     int64                  `protobuf:"varint,5,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservationId,proto3" json:"reservationId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateProductRequest) Reset() {
-	*x = UpdateProductRequest{}
-	mi := &file_product_proto_msgTypes[3]
+func (x *ReleaseStockRequest) Reset() {
+	*x = ReleaseStockRequest{}
+	mi := &file_product_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateProductRequest) String() string {
+func (x *ReleaseStockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateProductRequest) ProtoMessage() {}
+func (*ReleaseStockRequest) ProtoMessage() {}
 
-func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[3]
+func (x *ReleaseStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -271,69 +2273,84 @@ func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
-func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ReleaseStockRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseStockRequest) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *UpdateProductRequest) GetId() string {
+func (x *ReleaseStockRequest) GetReservationId() string {
 	if x != nil {
-		return x.Id
+		return x.ReservationId
 	}
 	return ""
 }
 
-func (x *UpdateProductRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+type PriceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*PriceHistoryEntry   `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateProductRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+func (x *PriceHistoryResponse) Reset() {
+	*x = PriceHistoryResponse{}
+	mi := &file_product_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *UpdateProductRequest) GetPrice() float64 {
+func (*PriceHistoryResponse) ProtoMessage() {}
+
+func (x *PriceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[31]
 	if x != nil {
-		return x.Price
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *UpdateProductRequest) GetAccountId() int64 {
+// Deprecated: Use PriceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*PriceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PriceHistoryResponse) GetEntries() []*PriceHistoryEntry {
 	if x != nil {
-		return x.AccountId
+		return x.Entries
 	}
-	return 0
+	return nil
 }
 
-type DeleteProductRequest struct {
+type ScheduledPriceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
-	AccountId     int64                  `protobuf:"varint,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Change        *ScheduledPrice        `protobuf:"bytes,1,opt,name=change,proto3" json:"change,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteProductRequest) Reset() {
-	*x = DeleteProductRequest{}
-	mi := &file_product_proto_msgTypes[4]
+func (x *ScheduledPriceResponse) Reset() {
+	*x = ScheduledPriceResponse{}
+	mi := &file_product_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteProductRequest) String() string {
+func (x *ScheduledPriceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteProductRequest) ProtoMessage() {}
+func (*ScheduledPriceResponse) ProtoMessage() {}
 
-func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[4]
+func (x *ScheduledPriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -344,23 +2361,60 @@ func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
-func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ScheduledPriceResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledPriceResponse) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *DeleteProductRequest) GetProductId() string {
+func (x *ScheduledPriceResponse) GetChange() *ScheduledPrice {
 	if x != nil {
-		return x.ProductId
+		return x.Change
 	}
-	return ""
+	return nil
 }
 
-func (x *DeleteProductRequest) GetAccountId() int64 {
+type ScheduledPricesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*ScheduledPrice      `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledPricesResponse) Reset() {
+	*x = ScheduledPricesResponse{}
+	mi := &file_product_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledPricesResponse) ProtoMessage() {}
+
+func (x *ScheduledPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_product_proto_msgTypes[33]
 	if x != nil {
-		return x.AccountId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledPricesResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledPricesResponse) Descriptor() ([]byte, []int) {
+	return file_product_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ScheduledPricesResponse) GetChanges() []*ScheduledPrice {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
 }
 
 type ProductResponse struct {
@@ -372,7 +2426,7 @@ type ProductResponse struct {
 
 func (x *ProductResponse) Reset() {
 	*x = ProductResponse{}
-	mi := &file_product_proto_msgTypes[5]
+	mi := &file_product_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -384,7 +2438,7 @@ func (x *ProductResponse) String() string {
 func (*ProductResponse) ProtoMessage() {}
 
 func (x *ProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[5]
+	mi := &file_product_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -397,7 +2451,7 @@ func (x *ProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
 func (*ProductResponse) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{5}
+	return file_product_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ProductResponse) GetProduct() *Product {
@@ -416,7 +2470,7 @@ type ProductsResponse struct {
 
 func (x *ProductsResponse) Reset() {
 	*x = ProductsResponse{}
-	mi := &file_product_proto_msgTypes[6]
+	mi := &file_product_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -428,7 +2482,7 @@ func (x *ProductsResponse) String() string {
 func (*ProductsResponse) ProtoMessage() {}
 
 func (x *ProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_product_proto_msgTypes[6]
+	mi := &file_product_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -441,7 +2495,7 @@ func (x *ProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductsResponse.ProtoReflect.Descriptor instead.
 func (*ProductsResponse) Descriptor() ([]byte, []int) {
-	return file_product_proto_rawDescGZIP(), []int{6}
+	return file_product_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *ProductsResponse) GetProducts() []*Product {
@@ -455,43 +2509,224 @@ var File_product_proto protoreflect.FileDescriptor
 
 const file_product_proto_rawDesc = "" +
 	"\n" +
-	"\rproduct.proto\x12\x02pb\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x83\x01\n" +
+	"\rproduct.proto\x12\x02pb\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\vmoney.proto\"\x96\x01\n" +
+	"\fProductImage\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\"\n" +
+	"\fthumbnailUrl\x18\x02 \x01(\tR\fthumbnailUrl\x12 \n" +
+	"\vcontentType\x18\x03 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\x05R\bposition\"\xc6\x05\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1c\n" +
-	"\taccountId\x18\x05 \x01(\x03R\taccountId\"\x80\x01\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\x10 \x01(\v2\f.money.MoneyR\x05price\x12\x1c\n" +
+	"\taccountId\x18\x05 \x01(\x03R\taccountId\x12(\n" +
+	"\x06images\x18\x06 \x03(\v2\x10.pb.ProductImageR\x06images\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x128\n" +
+	"\tpublishAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x12$\n" +
+	"\rratingAverage\x18\n" +
+	" \x01(\x01R\rratingAverage\x12 \n" +
+	"\vratingCount\x18\v \x01(\x05R\vratingCount\x124\n" +
+	"\x0epriceOverrides\x18\x11 \x03(\v2\f.money.MoneyR\x0epriceOverrides\x120\n" +
+	"\fdisplayPrice\x18\x12 \x01(\v2\f.money.MoneyR\fdisplayPrice\x128\n" +
+	"\tdeletedAt\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x19\n" +
+	"\x05stock\x18\x14 \x01(\x03H\x00R\x05stock\x88\x01\x01\x12\x10\n" +
+	"\x03sku\x18\x15 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bcategory\x18\x16 \x01(\tR\bcategory\x12 \n" +
+	"\vtaxCategory\x18\x17 \x01(\tR\vtaxCategory\x12 \n" +
+	"\vweightGrams\x18\x18 \x01(\x03R\vweightGramsB\b\n" +
+	"\x06_stockJ\x04\b\x04\x10\x05J\x04\b\f\x10\rJ\x04\b\r\x10\x0eJ\x04\b\x0e\x10\x0fJ\x04\b\x0f\x10\x10\"\xbf\x03\n" +
 	"\x14CreateProductRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1c\n" +
-	"\taccountId\x18\x04 \x01(\x03R\taccountId\"d\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\t \x01(\v2\f.money.MoneyR\x05price\x12\x1c\n" +
+	"\taccountId\x18\x04 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x128\n" +
+	"\tpublishAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x124\n" +
+	"\x0epriceOverrides\x18\n" +
+	" \x03(\v2\f.money.MoneyR\x0epriceOverrides\x12\x19\n" +
+	"\x05stock\x18\v \x01(\x03H\x00R\x05stock\x88\x01\x01\x12\x10\n" +
+	"\x03sku\x18\f \x01(\tR\x03sku\x12\x1a\n" +
+	"\bcategory\x18\r \x01(\tR\bcategory\x12 \n" +
+	"\vtaxCategory\x18\x0e \x01(\tR\vtaxCategory\x12 \n" +
+	"\vweightGrams\x18\x0f \x01(\x03R\vweightGramsB\b\n" +
+	"\x06_stockJ\x04\b\x03\x10\x04J\x04\b\a\x10\bJ\x04\b\b\x10\t\"w\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\x0fviewerAccountId\x18\x02 \x01(\x03R\x0fviewerAccountId\x12(\n" +
+	"\x0fdisplayCurrency\x18\x03 \x01(\tR\x0fdisplayCurrency\"\x94\x02\n" +
 	"\x12GetProductsRequest\x12\x12\n" +
 	"\x04skip\x18\x01 \x01(\x04R\x04skip\x12\x12\n" +
 	"\x04take\x18\x02 \x01(\x04R\x04take\x12\x10\n" +
 	"\x03ids\x18\x03 \x03(\tR\x03ids\x12\x14\n" +
-	"\x05query\x18\x04 \x01(\tR\x05query\"\x90\x01\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12(\n" +
+	"\x0fviewerAccountId\x18\x05 \x01(\x03R\x0fviewerAccountId\x12\x1c\n" +
+	"\tminRating\x18\x06 \x01(\x01R\tminRating\x12\x16\n" +
+	"\x06sortBy\x18\a \x01(\tR\x06sortBy\x12(\n" +
+	"\x0fdisplayCurrency\x18\b \x01(\tR\x0fdisplayCurrency\x12$\n" +
+	"\rincludeHidden\x18\t \x01(\bR\rincludeHidden\"\x80\x01\n" +
+	"\x1cListProductsByAccountRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x03R\taccountId\x12\x1a\n" +
+	"\bstatuses\x18\x02 \x03(\tR\bstatuses\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x04 \x01(\x04R\x04take\"\x84\x04\n" +
 	"\x14UpdateProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1c\n" +
-	"\taccountId\x18\x05 \x01(\x03R\taccountId\"R\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\"\n" +
+	"\x05price\x18\t \x01(\v2\f.money.MoneyR\x05price\x12\x1c\n" +
+	"\taccountId\x18\x05 \x01(\x03R\taccountId\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x124\n" +
+	"\x0epriceOverrides\x18\n" +
+	" \x03(\v2\f.money.MoneyR\x0epriceOverrides\x12\x19\n" +
+	"\x05stock\x18\v \x01(\x03H\x00R\x05stock\x88\x01\x01\x12\x15\n" +
+	"\x03sku\x18\f \x01(\tH\x01R\x03sku\x88\x01\x01\x12\x1f\n" +
+	"\bcategory\x18\r \x01(\tH\x02R\bcategory\x88\x01\x01\x12%\n" +
+	"\vtaxCategory\x18\x0e \x01(\tH\x03R\vtaxCategory\x88\x01\x01\x12%\n" +
+	"\vweightGrams\x18\x0f \x01(\x03H\x04R\vweightGrams\x88\x01\x01\x12\"\n" +
+	"\funtrackStock\x18\x10 \x01(\bR\funtrackStockB\b\n" +
+	"\x06_stockB\x06\n" +
+	"\x04_skuB\v\n" +
+	"\t_categoryB\x0e\n" +
+	"\f_taxCategoryB\x0e\n" +
+	"\f_weightGramsJ\x04\b\x04\x10\x05J\x04\b\a\x10\bJ\x04\b\b\x10\t\"R\n" +
 	"\x14DeleteProductRequest\x12\x1c\n" +
 	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
-	"\taccountId\x18\x02 \x01(\x03R\taccountId\"8\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"S\n" +
+	"\x15RestoreProductRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"b\n" +
+	"\x1aListDeletedProductsRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x03R\taccountId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"\x8d\x01\n" +
+	"\x15PublishProductRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\x128\n" +
+	"\tpublishAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\"S\n" +
+	"\x15ArchiveProductRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"\x8d\x01\n" +
+	"\x19UploadProductImageRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\x12 \n" +
+	"\vcontentType\x18\x03 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\"y\n" +
+	"\vReviewReply\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\x03R\taccountId\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x128\n" +
+	"\tcreatedAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb1\x02\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tproductId\x18\x02 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x03 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x05R\x06rating\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\tR\x04body\x12%\n" +
+	"\x05reply\x18\a \x01(\v2\x0f.pb.ReviewReplyR\x05reply\x128\n" +
+	"\tcreatedAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x128\n" +
+	"\tupdatedAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x93\x01\n" +
+	"\x13CreateReviewRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\"Z\n" +
+	"\x12ListReviewsRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"\x91\x01\n" +
+	"\x13UpdateReviewRequest\x12\x1a\n" +
+	"\breviewId\x18\x01 \x01(\tR\breviewId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\"O\n" +
+	"\x13DeleteReviewRequest\x12\x1a\n" +
+	"\breviewId\x18\x01 \x01(\tR\breviewId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"d\n" +
+	"\x14ReplyToReviewRequest\x12\x1a\n" +
+	"\breviewId\x18\x01 \x01(\tR\breviewId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\"4\n" +
+	"\x0eReviewResponse\x12\"\n" +
+	"\x06review\x18\x01 \x01(\v2\n" +
+	".pb.ReviewR\x06review\"7\n" +
+	"\x0fReviewsResponse\x12$\n" +
+	"\areviews\x18\x01 \x03(\v2\n" +
+	".pb.ReviewR\areviews\"\xc9\x01\n" +
+	"\x11PriceHistoryEntry\x12\"\n" +
+	"\x05price\x18\x05 \x01(\v2\f.money.MoneyR\x05price\x122\n" +
+	"\rpreviousPrice\x18\x06 \x01(\v2\f.money.MoneyR\rpreviousPrice\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x128\n" +
+	"\tchangedAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAtJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03\"\xe8\x01\n" +
+	"\x0eScheduledPrice\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tproductId\x18\x02 \x01(\tR\tproductId\x12\"\n" +
+	"\x05price\x18\a \x01(\v2\f.money.MoneyR\x05price\x124\n" +
+	"\astartAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\astartAt\x120\n" +
+	"\x05endAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05endAt\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06statusJ\x04\b\x03\x10\x04\"^\n" +
+	"\x16GetPriceHistoryRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"\xea\x01\n" +
+	"\x1aSchedulePriceChangeRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\x12\"\n" +
+	"\x05price\x18\x06 \x01(\v2\f.money.MoneyR\x05price\x124\n" +
+	"\astartAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\astartAt\x120\n" +
+	"\x05endAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05endAtJ\x04\b\x03\x10\x04\"X\n" +
+	"\x1aListScheduledPricesRequest\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"T\n" +
+	"\x18CancelPriceChangeRequest\x12\x1a\n" +
+	"\bchangeId\x18\x01 \x01(\tR\bchangeId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\x03R\taccountId\"H\n" +
+	"\fReservedItem\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"c\n" +
+	"\x13ReserveStockRequest\x12$\n" +
+	"\rreservationId\x18\x01 \x01(\tR\rreservationId\x12&\n" +
+	"\x05items\x18\x02 \x03(\v2\x10.pb.ReservedItemR\x05items\";\n" +
+	"\x13ReleaseStockRequest\x12$\n" +
+	"\rreservationId\x18\x01 \x01(\tR\rreservationId\"G\n" +
+	"\x14PriceHistoryResponse\x12/\n" +
+	"\aentries\x18\x01 \x03(\v2\x15.pb.PriceHistoryEntryR\aentries\"D\n" +
+	"\x16ScheduledPriceResponse\x12*\n" +
+	"\x06change\x18\x01 \x01(\v2\x12.pb.ScheduledPriceR\x06change\"G\n" +
+	"\x17ScheduledPricesResponse\x12,\n" +
+	"\achanges\x18\x01 \x03(\v2\x12.pb.ScheduledPriceR\achanges\"8\n" +
 	"\x0fProductResponse\x12%\n" +
 	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\";\n" +
 	"\x10ProductsResponse\x12'\n" +
-	"\bproducts\x18\x01 \x03(\v2\v.pb.ProductR\bproducts2\xd9\x02\n" +
+	"\bproducts\x18\x01 \x03(\v2\v.pb.ProductR\bproducts2\x95\f\n" +
 	"\x0eProductService\x12>\n" +
-	"\vPostProduct\x12\x18.pb.CreateProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12A\n" +
+	"\vPostProduct\x12\x18.pb.CreateProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12:\n" +
 	"\n" +
-	"GetProduct\x12\x1c.google.protobuf.StringValue\x1a\x13.pb.ProductResponse\"\x00\x12=\n" +
-	"\vGetProducts\x12\x16.pb.GetProductsRequest\x1a\x14.pb.ProductsResponse\"\x00\x12@\n" +
+	"GetProduct\x12\x15.pb.GetProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12=\n" +
+	"\vGetProducts\x12\x16.pb.GetProductsRequest\x1a\x14.pb.ProductsResponse\"\x00\x12Q\n" +
+	"\x15ListProductsByAccount\x12 .pb.ListProductsByAccountRequest\x1a\x14.pb.ProductsResponse\"\x00\x12@\n" +
 	"\rUpdateProduct\x12\x18.pb.UpdateProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12C\n" +
-	"\rDeleteProduct\x12\x18.pb.DeleteProductRequest\x1a\x16.google.protobuf.Empty\"\x00B\x06Z\x04./pbb\x06proto3"
+	"\rDeleteProduct\x12\x18.pb.DeleteProductRequest\x1a\x16.google.protobuf.Empty\"\x00\x12B\n" +
+	"\x0eRestoreProduct\x12\x19.pb.RestoreProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12M\n" +
+	"\x13ListDeletedProducts\x12\x1e.pb.ListDeletedProductsRequest\x1a\x14.pb.ProductsResponse\"\x00\x12J\n" +
+	"\x12UploadProductImage\x12\x1d.pb.UploadProductImageRequest\x1a\x13.pb.ProductResponse\"\x00\x12B\n" +
+	"\x0ePublishProduct\x12\x19.pb.PublishProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12B\n" +
+	"\x0eArchiveProduct\x12\x19.pb.ArchiveProductRequest\x1a\x13.pb.ProductResponse\"\x00\x12=\n" +
+	"\fCreateReview\x12\x17.pb.CreateReviewRequest\x1a\x12.pb.ReviewResponse\"\x00\x12<\n" +
+	"\vListReviews\x12\x16.pb.ListReviewsRequest\x1a\x13.pb.ReviewsResponse\"\x00\x12=\n" +
+	"\fUpdateReview\x12\x17.pb.UpdateReviewRequest\x1a\x12.pb.ReviewResponse\"\x00\x12A\n" +
+	"\fDeleteReview\x12\x17.pb.DeleteReviewRequest\x1a\x16.google.protobuf.Empty\"\x00\x12?\n" +
+	"\rReplyToReview\x12\x18.pb.ReplyToReviewRequest\x1a\x12.pb.ReviewResponse\"\x00\x12I\n" +
+	"\x0fGetPriceHistory\x12\x1a.pb.GetPriceHistoryRequest\x1a\x18.pb.PriceHistoryResponse\"\x00\x12S\n" +
+	"\x13SchedulePriceChange\x12\x1e.pb.SchedulePriceChangeRequest\x1a\x1a.pb.ScheduledPriceResponse\"\x00\x12T\n" +
+	"\x13ListScheduledPrices\x12\x1e.pb.ListScheduledPricesRequest\x1a\x1b.pb.ScheduledPricesResponse\"\x00\x12K\n" +
+	"\x11CancelPriceChange\x12\x1c.pb.CancelPriceChangeRequest\x1a\x16.google.protobuf.Empty\"\x00\x12A\n" +
+	"\fReserveStock\x12\x17.pb.ReserveStockRequest\x1a\x16.google.protobuf.Empty\"\x00\x12A\n" +
+	"\fReleaseStock\x12\x17.pb.ReleaseStockRequest\x1a\x16.google.protobuf.Empty\"\x00B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_product_proto_rawDescOnce sync.Once
@@ -505,36 +2740,131 @@ func file_product_proto_rawDescGZIP() []byte {
 	return file_product_proto_rawDescData
 }
 
-var file_product_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_product_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_product_proto_goTypes = []any{
-	(*Product)(nil),                // 0: pb.Product
-	(*CreateProductRequest)(nil),   // 1: pb.CreateProductRequest
-	(*GetProductsRequest)(nil),     // 2: pb.GetProductsRequest
-	(*UpdateProductRequest)(nil),   // 3: pb.UpdateProductRequest
-	(*DeleteProductRequest)(nil),   // 4: pb.DeleteProductRequest
-	(*ProductResponse)(nil),        // 5: pb.ProductResponse
-	(*ProductsResponse)(nil),       // 6: pb.ProductsResponse
-	(*wrapperspb.StringValue)(nil), // 7: google.protobuf.StringValue
-	(*emptypb.Empty)(nil),          // 8: google.protobuf.Empty
+	(*ProductImage)(nil),                 // 0: pb.ProductImage
+	(*Product)(nil),                      // 1: pb.Product
+	(*CreateProductRequest)(nil),         // 2: pb.CreateProductRequest
+	(*GetProductRequest)(nil),            // 3: pb.GetProductRequest
+	(*GetProductsRequest)(nil),           // 4: pb.GetProductsRequest
+	(*ListProductsByAccountRequest)(nil), // 5: pb.ListProductsByAccountRequest
+	(*UpdateProductRequest)(nil),         // 6: pb.UpdateProductRequest
+	(*DeleteProductRequest)(nil),         // 7: pb.DeleteProductRequest
+	(*RestoreProductRequest)(nil),        // 8: pb.RestoreProductRequest
+	(*ListDeletedProductsRequest)(nil),   // 9: pb.ListDeletedProductsRequest
+	(*PublishProductRequest)(nil),        // 10: pb.PublishProductRequest
+	(*ArchiveProductRequest)(nil),        // 11: pb.ArchiveProductRequest
+	(*UploadProductImageRequest)(nil),    // 12: pb.UploadProductImageRequest
+	(*ReviewReply)(nil),                  // 13: pb.ReviewReply
+	(*Review)(nil),                       // 14: pb.Review
+	(*CreateReviewRequest)(nil),          // 15: pb.CreateReviewRequest
+	(*ListReviewsRequest)(nil),           // 16: pb.ListReviewsRequest
+	(*UpdateReviewRequest)(nil),          // 17: pb.UpdateReviewRequest
+	(*DeleteReviewRequest)(nil),          // 18: pb.DeleteReviewRequest
+	(*ReplyToReviewRequest)(nil),         // 19: pb.ReplyToReviewRequest
+	(*ReviewResponse)(nil),               // 20: pb.ReviewResponse
+	(*ReviewsResponse)(nil),              // 21: pb.ReviewsResponse
+	(*PriceHistoryEntry)(nil),            // 22: pb.PriceHistoryEntry
+	(*ScheduledPrice)(nil),               // 23: pb.ScheduledPrice
+	(*GetPriceHistoryRequest)(nil),       // 24: pb.GetPriceHistoryRequest
+	(*SchedulePriceChangeRequest)(nil),   // 25: pb.SchedulePriceChangeRequest
+	(*ListScheduledPricesRequest)(nil),   // 26: pb.ListScheduledPricesRequest
+	(*CancelPriceChangeRequest)(nil),     // 27: pb.CancelPriceChangeRequest
+	(*ReservedItem)(nil),                 // 28: pb.ReservedItem
+	(*ReserveStockRequest)(nil),          // 29: pb.ReserveStockRequest
+	(*ReleaseStockRequest)(nil),          // 30: pb.ReleaseStockRequest
+	(*PriceHistoryResponse)(nil),         // 31: pb.PriceHistoryResponse
+	(*ScheduledPriceResponse)(nil),       // 32: pb.ScheduledPriceResponse
+	(*ScheduledPricesResponse)(nil),      // 33: pb.ScheduledPricesResponse
+	(*ProductResponse)(nil),              // 34: pb.ProductResponse
+	(*ProductsResponse)(nil),             // 35: pb.ProductsResponse
+	(*pb.Money)(nil),                     // 36: money.Money
+	(*timestamppb.Timestamp)(nil),        // 37: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                // 38: google.protobuf.Empty
 }
 var file_product_proto_depIdxs = []int32{
-	0, // 0: pb.ProductResponse.product:type_name -> pb.Product
-	0, // 1: pb.ProductsResponse.products:type_name -> pb.Product
-	1, // 2: pb.ProductService.PostProduct:input_type -> pb.CreateProductRequest
-	7, // 3: pb.ProductService.GetProduct:input_type -> google.protobuf.StringValue
-	2, // 4: pb.ProductService.GetProducts:input_type -> pb.GetProductsRequest
-	3, // 5: pb.ProductService.UpdateProduct:input_type -> pb.UpdateProductRequest
-	4, // 6: pb.ProductService.DeleteProduct:input_type -> pb.DeleteProductRequest
-	5, // 7: pb.ProductService.PostProduct:output_type -> pb.ProductResponse
-	5, // 8: pb.ProductService.GetProduct:output_type -> pb.ProductResponse
-	6, // 9: pb.ProductService.GetProducts:output_type -> pb.ProductsResponse
-	5, // 10: pb.ProductService.UpdateProduct:output_type -> pb.ProductResponse
-	8, // 11: pb.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	36, // 0: pb.Product.price:type_name -> money.Money
+	0,  // 1: pb.Product.images:type_name -> pb.ProductImage
+	37, // 2: pb.Product.publishAt:type_name -> google.protobuf.Timestamp
+	36, // 3: pb.Product.priceOverrides:type_name -> money.Money
+	36, // 4: pb.Product.displayPrice:type_name -> money.Money
+	37, // 5: pb.Product.deletedAt:type_name -> google.protobuf.Timestamp
+	36, // 6: pb.CreateProductRequest.price:type_name -> money.Money
+	37, // 7: pb.CreateProductRequest.publishAt:type_name -> google.protobuf.Timestamp
+	36, // 8: pb.CreateProductRequest.priceOverrides:type_name -> money.Money
+	36, // 9: pb.UpdateProductRequest.price:type_name -> money.Money
+	36, // 10: pb.UpdateProductRequest.priceOverrides:type_name -> money.Money
+	37, // 11: pb.PublishProductRequest.publishAt:type_name -> google.protobuf.Timestamp
+	37, // 12: pb.ReviewReply.createdAt:type_name -> google.protobuf.Timestamp
+	13, // 13: pb.Review.reply:type_name -> pb.ReviewReply
+	37, // 14: pb.Review.createdAt:type_name -> google.protobuf.Timestamp
+	37, // 15: pb.Review.updatedAt:type_name -> google.protobuf.Timestamp
+	14, // 16: pb.ReviewResponse.review:type_name -> pb.Review
+	14, // 17: pb.ReviewsResponse.reviews:type_name -> pb.Review
+	36, // 18: pb.PriceHistoryEntry.price:type_name -> money.Money
+	36, // 19: pb.PriceHistoryEntry.previousPrice:type_name -> money.Money
+	37, // 20: pb.PriceHistoryEntry.changedAt:type_name -> google.protobuf.Timestamp
+	36, // 21: pb.ScheduledPrice.price:type_name -> money.Money
+	37, // 22: pb.ScheduledPrice.startAt:type_name -> google.protobuf.Timestamp
+	37, // 23: pb.ScheduledPrice.endAt:type_name -> google.protobuf.Timestamp
+	36, // 24: pb.SchedulePriceChangeRequest.price:type_name -> money.Money
+	37, // 25: pb.SchedulePriceChangeRequest.startAt:type_name -> google.protobuf.Timestamp
+	37, // 26: pb.SchedulePriceChangeRequest.endAt:type_name -> google.protobuf.Timestamp
+	28, // 27: pb.ReserveStockRequest.items:type_name -> pb.ReservedItem
+	22, // 28: pb.PriceHistoryResponse.entries:type_name -> pb.PriceHistoryEntry
+	23, // 29: pb.ScheduledPriceResponse.change:type_name -> pb.ScheduledPrice
+	23, // 30: pb.ScheduledPricesResponse.changes:type_name -> pb.ScheduledPrice
+	1,  // 31: pb.ProductResponse.product:type_name -> pb.Product
+	1,  // 32: pb.ProductsResponse.products:type_name -> pb.Product
+	2,  // 33: pb.ProductService.PostProduct:input_type -> pb.CreateProductRequest
+	3,  // 34: pb.ProductService.GetProduct:input_type -> pb.GetProductRequest
+	4,  // 35: pb.ProductService.GetProducts:input_type -> pb.GetProductsRequest
+	5,  // 36: pb.ProductService.ListProductsByAccount:input_type -> pb.ListProductsByAccountRequest
+	6,  // 37: pb.ProductService.UpdateProduct:input_type -> pb.UpdateProductRequest
+	7,  // 38: pb.ProductService.DeleteProduct:input_type -> pb.DeleteProductRequest
+	8,  // 39: pb.ProductService.RestoreProduct:input_type -> pb.RestoreProductRequest
+	9,  // 40: pb.ProductService.ListDeletedProducts:input_type -> pb.ListDeletedProductsRequest
+	12, // 41: pb.ProductService.UploadProductImage:input_type -> pb.UploadProductImageRequest
+	10, // 42: pb.ProductService.PublishProduct:input_type -> pb.PublishProductRequest
+	11, // 43: pb.ProductService.ArchiveProduct:input_type -> pb.ArchiveProductRequest
+	15, // 44: pb.ProductService.CreateReview:input_type -> pb.CreateReviewRequest
+	16, // 45: pb.ProductService.ListReviews:input_type -> pb.ListReviewsRequest
+	17, // 46: pb.ProductService.UpdateReview:input_type -> pb.UpdateReviewRequest
+	18, // 47: pb.ProductService.DeleteReview:input_type -> pb.DeleteReviewRequest
+	19, // 48: pb.ProductService.ReplyToReview:input_type -> pb.ReplyToReviewRequest
+	24, // 49: pb.ProductService.GetPriceHistory:input_type -> pb.GetPriceHistoryRequest
+	25, // 50: pb.ProductService.SchedulePriceChange:input_type -> pb.SchedulePriceChangeRequest
+	26, // 51: pb.ProductService.ListScheduledPrices:input_type -> pb.ListScheduledPricesRequest
+	27, // 52: pb.ProductService.CancelPriceChange:input_type -> pb.CancelPriceChangeRequest
+	29, // 53: pb.ProductService.ReserveStock:input_type -> pb.ReserveStockRequest
+	30, // 54: pb.ProductService.ReleaseStock:input_type -> pb.ReleaseStockRequest
+	34, // 55: pb.ProductService.PostProduct:output_type -> pb.ProductResponse
+	34, // 56: pb.ProductService.GetProduct:output_type -> pb.ProductResponse
+	35, // 57: pb.ProductService.GetProducts:output_type -> pb.ProductsResponse
+	35, // 58: pb.ProductService.ListProductsByAccount:output_type -> pb.ProductsResponse
+	34, // 59: pb.ProductService.UpdateProduct:output_type -> pb.ProductResponse
+	38, // 60: pb.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
+	34, // 61: pb.ProductService.RestoreProduct:output_type -> pb.ProductResponse
+	35, // 62: pb.ProductService.ListDeletedProducts:output_type -> pb.ProductsResponse
+	34, // 63: pb.ProductService.UploadProductImage:output_type -> pb.ProductResponse
+	34, // 64: pb.ProductService.PublishProduct:output_type -> pb.ProductResponse
+	34, // 65: pb.ProductService.ArchiveProduct:output_type -> pb.ProductResponse
+	20, // 66: pb.ProductService.CreateReview:output_type -> pb.ReviewResponse
+	21, // 67: pb.ProductService.ListReviews:output_type -> pb.ReviewsResponse
+	20, // 68: pb.ProductService.UpdateReview:output_type -> pb.ReviewResponse
+	38, // 69: pb.ProductService.DeleteReview:output_type -> google.protobuf.Empty
+	20, // 70: pb.ProductService.ReplyToReview:output_type -> pb.ReviewResponse
+	31, // 71: pb.ProductService.GetPriceHistory:output_type -> pb.PriceHistoryResponse
+	32, // 72: pb.ProductService.SchedulePriceChange:output_type -> pb.ScheduledPriceResponse
+	33, // 73: pb.ProductService.ListScheduledPrices:output_type -> pb.ScheduledPricesResponse
+	38, // 74: pb.ProductService.CancelPriceChange:output_type -> google.protobuf.Empty
+	38, // 75: pb.ProductService.ReserveStock:output_type -> google.protobuf.Empty
+	38, // 76: pb.ProductService.ReleaseStock:output_type -> google.protobuf.Empty
+	55, // [55:77] is the sub-list for method output_type
+	33, // [33:55] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_product_proto_init() }
@@ -542,13 +2872,16 @@ func file_product_proto_init() {
 	if File_product_proto != nil {
 		return
 	}
+	file_product_proto_msgTypes[1].OneofWrappers = []any{}
+	file_product_proto_msgTypes[2].OneofWrappers = []any{}
+	file_product_proto_msgTypes[6].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_product_proto_rawDesc), len(file_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestValidateImage(t *testing.T) {
+	data := encodePNG(t, 4, 4)
+
+	cases := []struct {
+		name     string
+		data     []byte
+		declared string
+		maxSize  int64
+		want     error
+	}{
+		{"declared type", data, "image/png", 1 << 20, nil},
+		{"undeclared type", data, "application/octet-stream", 1 << 20, nil},
+		{"too large", data, "image/png", int64(len(data)) - 1, product.ErrImageTooLarge},
+		{"not an image", []byte("<html><body>hi</body></html>"), "image/png", 1 << 20, product.ErrUnsupportedImage},
+		{"wrong declared type", data, "image/jpeg", 1 << 20, product.ErrContentTypeMismatch},
+	}
+	for _, c := range cases {
+		contentType, err := internal.ValidateImage(c.data, c.declared, c.maxSize)
+		if !errors.Is(err, c.want) {
+			t.Errorf("%s: ValidateImage error = %v, want %v", c.name, err, c.want)
+			continue
+		}
+		if err == nil && contentType != "image/png" {
+			t.Errorf("%s: ValidateImage = %q, want image/png", c.name, contentType)
+		}
+	}
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	thumbnail, contentType, err := internal.MakeThumbnail(encodePNG(t, 300, 150), "image/png", 100, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "image/png" {
+		t.Errorf("thumbnail type = %q, want image/png", contentType)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumbnail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("thumbnail is %dx%d, want 100x50", cfg.Width, cfg.Height)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 80)), nil); err != nil {
+		t.Fatal(err)
+	}
+	thumbnail, contentType, err = internal.MakeThumbnail(buf.Bytes(), "image/jpeg", 20, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail)); err != nil || contentType != "image/jpeg" || cfg.Width != 10 || cfg.Height != 20 {
+		t.Errorf("jpeg thumbnail is %dx%d %q (%v), want 10x20 image/jpeg", cfg.Width, cfg.Height, contentType, err)
+	}
+}
+
+func TestMakeThumbnailRejectsHugeImages(t *testing.T) {
+	// a small file claiming 100000x100000 pixels must not be decoded
+	header := encodePNG(t, 1, 1)[:33]
+	binary.BigEndian.PutUint32(header[16:], 100000)
+	binary.BigEndian.PutUint32(header[20:], 100000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+
+	if _, _, err := internal.MakeThumbnail(header, "image/png", 100, 40_000_000); !errors.Is(err, product.ErrImageDimensions) {
+		t.Errorf("MakeThumbnail error = %v, want ErrImageDimensions", err)
+	}
+	if _, _, err := internal.MakeThumbnail(encodePNG(t, 20, 20), "image/png", 100, 399); !errors.Is(err, product.ErrImageDimensions) {
+		t.Errorf("MakeThumbnail error = %v, want ErrImageDimensions", err)
+	}
+}
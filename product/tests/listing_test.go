@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/client"
+	"github.com/abhiii71/orderStream/product/internal"
+	"github.com/abhiii71/orderStream/product/models"
+)
+
+// sellerCatalog returns products of the seller in every status, one of them
+// in the trash, and a product of another seller.
+func sellerCatalog() []models.Product {
+	var products []models.Product
+	for _, p := range []struct{ id, name, status string }{
+		{"cup", "Cup", product.StatusPublished},
+		{"bowl", "Bowl", product.StatusDraft},
+		{"plate", "Plate", product.StatusArchived},
+		{"jug", "Jug", product.StatusPublished},
+	} {
+		stock := 5
+		products = append(products, models.Product{
+			Id:        p.id,
+			Name:      p.name,
+			Price:     money.New(1200, "USD"),
+			AccountId: sellerId,
+			Status:    p.status,
+			Stock:     &stock,
+		})
+	}
+	products = append(products, deletedProduct("mug", time.Hour))
+
+	other := publishedProduct()
+	other.Id, other.Name, other.AccountId = "vase", "Vase", sellerId+1
+	return append(products, other)
+}
+
+func productIds(products []models.Product) []string {
+	var ids []string
+	for _, p := range products {
+		ids = append(ids, p.Id)
+	}
+	return ids
+}
+
+func TestListProductsByAccount(t *testing.T) {
+	service := internal.NewProductService(newMemoryRepository(sellerCatalog()...), nil, nil, nil, nil)
+
+	tests := []struct {
+		name       string
+		statuses   []string
+		skip, take uint64
+		want       []string
+	}{
+		{"all statuses", nil, 0, 10, []string{"bowl", "cup", "jug", "plate"}},
+		{"drafts", []string{product.StatusDraft}, 0, 10, []string{"bowl"}},
+		{"published and archived", []string{product.StatusPublished, product.StatusArchived}, 0, 10, []string{"cup", "jug", "plate"}},
+		{"second page", nil, 2, 2, []string{"jug", "plate"}},
+		{"past the end", nil, 4, 2, nil},
+	}
+	for _, tt := range tests {
+		products, err := service.ListProductsByAccount(context.Background(), sellerId, tt.statuses, tt.skip, tt.take)
+		if err != nil {
+			t.Errorf("%s: ListProductsByAccount error = %v", tt.name, err)
+			continue
+		}
+		if got := productIds(products); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ListProductsByAccount = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := service.ListProductsByAccount(context.Background(), sellerId, []string{"deleted"}, 0, 10); !errors.Is(err, product.ErrInvalidStatus) {
+		t.Errorf("ListProductsByAccount with an unknown status error = %v, want ErrInvalidStatus", err)
+	}
+}
+
+func TestStockMustNotBeNegative(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	negative, zero := -1, 0
+	_, err := service.PostProduct(context.Background(), "Mug", "", "", "", "", 0, money.New(1200, "USD"), nil, &negative, sellerId, product.StatusDraft, nil)
+	if !errors.Is(err, product.ErrInvalidStock) {
+		t.Errorf("PostProduct error = %v, want ErrInvalidStock", err)
+	}
+	_, err = service.UpdateProduct(context.Background(), "mug", "Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, &negative, false, sellerId, 3)
+	if !errors.Is(err, product.ErrInvalidStock) {
+		t.Errorf("UpdateProduct error = %v, want ErrInvalidStock", err)
+	}
+
+	updated, err := service.UpdateProduct(context.Background(), "mug", "Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, &zero, false, sellerId, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Stock == nil || *updated.Stock != 0 {
+		t.Errorf("stock = %v, want the product sold out", updated.Stock)
+	}
+}
+
+func TestUpdateProductKeepsOrStopsTrackingStock(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	stock := 4
+	kept, err := service.UpdateProduct(context.Background(), "mug", "Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, &stock, false, sellerId, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err = service.UpdateProduct(context.Background(), "mug", "Big Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, nil, false, sellerId, kept.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.Stock == nil || *kept.Stock != 4 {
+		t.Errorf("stock = %v, want 4 kept", kept.Stock)
+	}
+
+	untracked, err := service.UpdateProduct(context.Background(), "mug", "Big Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, nil, true, sellerId, kept.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := repo.GetProductsByID(context.Background(), "mug"); untracked.Stock != nil || p.Stock != nil {
+		t.Errorf("stock = %v, want it no longer tracked", p.Stock)
+	}
+}
+
+func TestStockIsOnlyShownToTheSeller(t *testing.T) {
+	repo := newMemoryRepository(sellerCatalog()...)
+	products := dialProducts(t, internal.NewProductService(repo, newPublisher(t, 2), nil, nil, nil))
+
+	listed, err := products.ListProductsByAccount(context.Background(), sellerId, nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range listed {
+		if p.Stock == nil || *p.Stock != 5 {
+			t.Errorf("seller's listing: stock of %s = %v, want 5", p.Id, p.Stock)
+		}
+	}
+
+	for _, viewerId := range []int64{sellerId, sellerId + 1} {
+		p, err := products.GetProduct(context.Background(), "cup", viewerId, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shown := p.Stock != nil; shown != (viewerId == sellerId) {
+			t.Errorf("viewer %d: stock shown = %t", viewerId, shown)
+		}
+	}
+}
+
+// dialProducts serves service over gRPC and returns a client of it.
+func dialProducts(t *testing.T, service internal.Service) *client.Client {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+	go internal.ListenGRPC(service, port)
+
+	// wait for the server to listen, RPCs fail fast otherwise
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+	}
+
+	products, err := client.NewClient(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(products.Close)
+	return products
+}
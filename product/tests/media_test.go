@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abhiii71/orderStream/product/internal"
+)
+
+// fakeS3 is a tiny MinIO-style stand-in: it keeps objects in memory and checks
+// that requests carry SigV4 headers for the expected access key.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+			http.Error(w, "AccessDenied", http.StatusForbidden)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3MediaStore(t *testing.T) {
+	backend := newFakeS3()
+	srv := httptest.NewServer(backend)
+	defer srv.Close()
+
+	store := internal.NewS3MediaStore(srv.URL, "us-east-1", "media", "minio", "minio123", "")
+	ctx := context.Background()
+
+	url, err := store.Put(ctx, "products/p1/1.png", "image/png", strings.NewReader("png-bytes"), 9)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if url != srv.URL+"/media/products/p1/1.png" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != "png-bytes" || res.Header.Get("Content-Type") != "image/png" {
+		t.Fatalf("unexpected object %q (%s)", body, res.Header.Get("Content-Type"))
+	}
+
+	if err := store.Delete(ctx, "products/p1/1.png"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := backend.objects["/media/products/p1/1.png"]; ok {
+		t.Fatal("object still present after delete")
+	}
+}
+
+func TestS3MediaStoreRejectedCredentials(t *testing.T) {
+	srv := httptest.NewServer(newFakeS3())
+	defer srv.Close()
+
+	store := internal.NewS3MediaStore(srv.URL, "us-east-1", "media", "someone-else", "secret", "")
+	if _, err := store.Put(context.Background(), "a.png", "image/png", strings.NewReader("x"), 1); err == nil {
+		t.Fatal("expected an error for rejected credentials")
+	}
+}
+
+func TestLocalMediaStore(t *testing.T) {
+	root := t.TempDir()
+	store, err := internal.NewLocalMediaStore(root, "http://cdn.local/media/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	url, err := store.Put(ctx, "products/p1/1.jpg", "image/jpeg", strings.NewReader("jpeg-bytes"), 10)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if url != "http://cdn.local/media/products/p1/1.jpg" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "products", "p1", "1.jpg"))
+	if err != nil || string(data) != "jpeg-bytes" {
+		t.Fatalf("unexpected file contents %q: %v", data, err)
+	}
+
+	if _, err := store.Put(ctx, "../escape.jpg", "image/jpeg", strings.NewReader("x"), 1); err == nil {
+		t.Fatal("expected keys outside the media root to be rejected")
+	}
+
+	if err := store.Delete(ctx, "products/p1/1.jpg"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "products", "p1", "1.jpg")); !os.IsNotExist(err) {
+		t.Fatal("file still present after delete")
+	}
+}
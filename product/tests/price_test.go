@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+)
+
+func TestSchedulePriceChangeRejectsInvalidChanges(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	start := time.Now().Add(time.Hour)
+	end := start.Add(24 * time.Hour)
+	if _, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(900, "USD"), start, &end); err != nil {
+		t.Fatal(err)
+	}
+
+	during := start.Add(time.Hour)
+	tests := []struct {
+		name      string
+		accountId int
+		price     money.Money
+		startAt   time.Time
+		endAt     *time.Time
+		want      error
+	}{
+		{"ends before it starts", sellerId, money.New(900, "USD"), end.Add(time.Hour), &end, product.ErrInvalidSchedule},
+		{"starts during the sale", sellerId, money.New(1000, "USD"), during, nil, product.ErrScheduleOverlap},
+		{"ends during the sale", sellerId, money.New(1000, "USD"), start.Add(-time.Hour), &during, product.ErrScheduleOverlap},
+		{"other seller", sellerId + 1, money.New(1000, "USD"), end, nil, product.ErrUnauthorized},
+		{"other currency", sellerId, money.New(1000, "EUR"), end, nil, product.ErrUnsupportedCurrency},
+		{"free", sellerId, money.New(0, "USD"), end, nil, product.ErrInvalidPrice},
+	}
+	for _, tt := range tests {
+		_, err := service.SchedulePriceChange(context.Background(), "mug", tt.accountId, tt.price, tt.startAt, tt.endAt)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: SchedulePriceChange error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// changes may follow each other back to back
+	if _, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(1000, "USD"), end, nil); err != nil {
+		t.Errorf("change starting when the sale ends: %v", err)
+	}
+}
+
+func TestSalesApplyAndRestoreThePrice(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	end := time.Now().Add(time.Hour)
+	sale, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(900, ""), time.Now().Add(-time.Minute), &end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sale.Price != money.New(900, "USD") {
+		t.Errorf("price = %v, want the product's currency filled in", sale.Price)
+	}
+
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertPrice(t, repo, "sale started", money.New(900, "USD"))
+	if change, _ := repo.GetScheduledPrice(context.Background(), sale.Id); change.Status != product.PriceChangeActive {
+		t.Errorf("status = %q, want the sale active", change.Status)
+	}
+
+	endSale(repo, sale.Id)
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertPrice(t, repo, "sale ended", money.New(1200, "USD"))
+	if change, _ := repo.GetScheduledPrice(context.Background(), sale.Id); change.Status != product.PriceChangeDone {
+		t.Errorf("status = %q, want the sale done", change.Status)
+	}
+
+	history, err := service.GetPriceHistory(context.Background(), "mug", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reasons []string
+	for _, entry := range history {
+		reasons = append(reasons, entry.Reason)
+	}
+	if want := []string{product.PriceReasonSaleEnded, product.PriceReasonScheduled}; !slices.Equal(reasons, want) {
+		t.Fatalf("history reasons = %v, want %v", reasons, want)
+	}
+	if history[1].PreviousPrice != money.New(1200, "USD") || history[1].Price != money.New(900, "USD") {
+		t.Errorf("sale entry = %+v, want 12.00 USD to 9.00 USD", history[1])
+	}
+}
+
+func TestSaleEndKeepsManualPrices(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	end := time.Now().Add(time.Hour)
+	sale, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(900, "USD"), time.Now().Add(-time.Minute), &end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := repo.GetProductsByID(context.Background(), "mug")
+	if _, err := service.UpdateProduct(context.Background(), "mug", p.Name, p.Description, nil, nil, nil, nil, money.New(1500, "USD"), nil, nil, false, sellerId, p.Version); err != nil {
+		t.Fatal(err)
+	}
+
+	endSale(repo, sale.Id)
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertPrice(t, repo, "sale ended after a manual edit", money.New(1500, "USD"))
+
+	history, _ := service.GetPriceHistory(context.Background(), "mug", 0, 10)
+	if len(history) != 2 || history[0].Reason != product.PriceReasonManual {
+		t.Errorf("history = %+v, want the sale and the manual edit", history)
+	}
+}
+
+func TestCancelPriceChange(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	later, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(1000, "USD"), time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.CancelPriceChange(context.Background(), later.Id, sellerId+1); !errors.Is(err, product.ErrUnauthorized) {
+		t.Errorf("CancelPriceChange by another seller error = %v, want ErrUnauthorized", err)
+	}
+	if err := service.CancelPriceChange(context.Background(), later.Id, sellerId); err != nil {
+		t.Fatal(err)
+	}
+	if scheduled, _ := service.ListScheduledPrices(context.Background(), "mug", sellerId); len(scheduled) != 0 {
+		t.Errorf("scheduled = %+v, want the cancelled change gone", scheduled)
+	}
+
+	now, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(1000, "USD"), time.Now().Add(-time.Minute), nil)
+	if err != nil {
+		t.Fatalf("scheduling over a cancelled change: %v", err)
+	}
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.CancelPriceChange(context.Background(), now.Id, sellerId); !errors.Is(err, product.ErrScheduleStarted) {
+		t.Errorf("CancelPriceChange of an applied change error = %v, want ErrScheduleStarted", err)
+	}
+	assertPrice(t, repo, "applied change", money.New(1000, "USD"))
+}
+
+func TestProductsMustNotBeFree(t *testing.T) {
+	service := internal.NewProductService(newMemoryRepository(publishedProduct()), nil, nil, nil, nil)
+
+	_, err := service.PostProduct(context.Background(), "Mug", "", "", "", "", 0, money.New(0, "USD"), nil, nil, sellerId, product.StatusDraft, nil)
+	if !errors.Is(err, product.ErrInvalidPrice) {
+		t.Errorf("PostProduct error = %v, want ErrInvalidPrice", err)
+	}
+	_, err = service.UpdateProduct(context.Background(), "mug", "Mug", "", nil, nil, nil, nil, money.New(0, "USD"), nil, nil, false, sellerId, 3)
+	if !errors.Is(err, product.ErrInvalidPrice) {
+		t.Errorf("UpdateProduct error = %v, want ErrInvalidPrice", err)
+	}
+}
+
+func TestPriceChangesWaitForDeletedProducts(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	change, err := service.SchedulePriceChange(context.Background(), "mug", sellerId, money.New(900, "USD"), time.Now().Add(-time.Minute), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteProduct(context.Background(), "mug", sellerId); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertPrice(t, repo, "deleted product", money.New(1200, "USD"))
+	if pending, _ := repo.GetScheduledPrice(context.Background(), change.Id); pending.Status != product.PriceChangePending {
+		t.Errorf("status = %q, want the change pending", pending.Status)
+	}
+
+	if _, err := service.RestoreProduct(context.Background(), "mug", sellerId); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertPrice(t, repo, "restored product", money.New(900, "USD"))
+}
+
+// endSale moves the end of a sale into the past.
+func endSale(repo *memoryRepository, id string) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	change := repo.changes[id]
+	ended := time.Now().Add(-time.Second)
+	change.EndAt = &ended
+	repo.changes[id] = change
+}
+
+func assertPrice(t *testing.T, repo *memoryRepository, name string, want money.Money) {
+	t.Helper()
+
+	if p, _ := repo.GetProductsByID(context.Background(), "mug"); p.Price != want {
+		t.Errorf("%s: price = %v, want %v", name, p.Price, want)
+	}
+}
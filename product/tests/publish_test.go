@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+	"github.com/abhiii71/orderStream/product/models"
+)
+
+func TestPublishLaterRejectsPublishedProducts(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	later := time.Now().Add(time.Hour)
+	if _, err := service.PublishProduct(context.Background(), "mug", sellerId, &later); !errors.Is(err, product.ErrAlreadyPublished) {
+		t.Fatalf("PublishProduct error = %v, want ErrAlreadyPublished", err)
+	}
+	if p, _ := repo.GetProductsByID(context.Background(), "mug"); p.Status != product.StatusPublished || p.PublishAt != nil {
+		t.Errorf("status = %q, publishAt = %v, want the product left published", p.Status, p.PublishAt)
+	}
+}
+
+func TestPublishLaterSchedulesDrafts(t *testing.T) {
+	draft := publishedProduct()
+	draft.Status = product.StatusDraft
+	repo := newMemoryRepository(draft)
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	later := time.Now().Add(time.Hour)
+	scheduled, err := service.PublishProduct(context.Background(), "mug", sellerId, &later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scheduled.Status != product.StatusDraft || scheduled.PublishAt == nil || !scheduled.PublishAt.Equal(later) {
+		t.Errorf("status = %q, publishAt = %v, want a draft scheduled for %v", scheduled.Status, scheduled.PublishAt, later)
+	}
+	if len(repo.staged()) != 0 {
+		t.Error("scheduling staged an event")
+	}
+}
+
+func TestGetProductsWithIdsHidesUnpublishedProducts(t *testing.T) {
+	published := publishedProduct()
+	draft := publishedProduct()
+	draft.Id, draft.Status = "draft", product.StatusDraft
+	archived := publishedProduct()
+	archived.Id, archived.Status = "archived", product.StatusArchived
+	deleted := publishedProduct()
+	deletedAt := time.Now()
+	deleted.Id, deleted.DeletedAt = "deleted", &deletedAt
+	service := internal.NewProductService(newMemoryRepository(published, draft, archived, deleted), nil, nil, nil, nil)
+
+	ids := []string{"mug", "draft", "archived", "deleted", "missing"}
+	tests := []struct {
+		name   string
+		filter models.ProductFilter
+		want   []string
+	}{
+		{"anonymous", models.ProductFilter{}, []string{"mug"}},
+		{"other account", models.ProductFilter{ViewerId: sellerId + 1}, []string{"mug"}},
+		{"owner", models.ProductFilter{ViewerId: sellerId}, []string{"mug", "draft", "archived"}},
+		{"other service", models.ProductFilter{IncludeHidden: true}, []string{"mug", "draft", "archived", "deleted"}},
+	}
+	for _, tt := range tests {
+		products, err := service.GetProductsWithIds(context.Background(), ids, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range products {
+			got = append(got, p.Id)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: GetProductsWithIds = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+)
+
+const ratesJSON = `{"base": "USD", "rates": {"EUR": 0.9, "INR": 83}}`
+
+func assertRate(t *testing.T, rates internal.RateProvider, from, to string, want float64) {
+	t.Helper()
+
+	got, err := rates.Rate(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Rate(%s, %s): %v", from, to, err)
+	}
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("Rate(%s, %s) = %v, want %v", from, to, got, want)
+	}
+}
+
+func TestStaticRateProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(ratesJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := internal.NewStaticRateProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertRate(t, rates, "USD", "USD", 1)
+	assertRate(t, rates, "USD", "EUR", 0.9)
+	assertRate(t, rates, "EUR", "USD", 1/0.9)
+	assertRate(t, rates, "EUR", "INR", 83/0.9)
+
+	if _, err := rates.Rate(context.Background(), "USD", "GBP"); !errors.Is(err, product.ErrNoExchangeRate) {
+		t.Fatalf("expected ErrNoExchangeRate, got %v", err)
+	}
+}
+
+func TestHTTPRateProviderCachesAndKeepsStaleRates(t *testing.T) {
+	var requests, failing atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(ratesJSON))
+	}))
+	defer server.Close()
+
+	rates := internal.NewHTTPRateProvider(server.URL, time.Hour)
+	assertRate(t, rates, "USD", "INR", 83)
+	assertRate(t, rates, "USD", "EUR", 0.9)
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("expected rates to be fetched once, got %d requests", n)
+	}
+
+	// a failed refresh falls back to the last rates instead of failing
+	expired := internal.NewHTTPRateProvider(server.URL, time.Nanosecond)
+	assertRate(t, expired, "USD", "EUR", 0.9)
+	failing.Store(1)
+	time.Sleep(time.Millisecond)
+	assertRate(t, expired, "USD", "EUR", 0.9)
+}
@@ -0,0 +1,361 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+	"github.com/abhiii71/orderStream/product/models"
+)
+
+// memoryRepository keeps products in memory and checks versions on writes
+// like the Elasticsearch repository does. Methods the tests do not need are
+// left to the embedded nil Repository and panic if called.
+type memoryRepository struct {
+	internal.Repository
+
+	mu       sync.Mutex
+	products map[string]models.Product
+	outbox   map[string]models.OutboxEntry
+	reviews  map[string]models.Review
+	history  []models.PriceHistoryEntry
+	changes  map[string]models.ScheduledPrice
+	nextId   int
+	// beforeWrite runs before every versioned write, standing in for a
+	// concurrent request.
+	beforeWrite func(r *memoryRepository)
+}
+
+func newMemoryRepository(products ...models.Product) *memoryRepository {
+	r := &memoryRepository{
+		products: map[string]models.Product{},
+		outbox:   map[string]models.OutboxEntry{},
+		reviews:  map[string]models.Review{},
+		changes:  map[string]models.ScheduledPrice{},
+	}
+	for _, p := range products {
+		r.products[p.Id] = p
+	}
+	return r
+}
+
+func (r *memoryRepository) GetProductsByID(_ context.Context, id string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[id]
+	if !ok {
+		return nil, product.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *memoryRepository) ListProductsWithIDs(_ context.Context, ids []string) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var products []models.Product
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+// write applies change to a product that is still at version.
+func (r *memoryRepository) write(id string, version int64, change func(p *models.Product)) (int64, error) {
+	if r.beforeWrite != nil {
+		r.beforeWrite(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[id]
+	if !ok {
+		return 0, product.ErrNotFound
+	}
+	if p.Version != version {
+		return 0, product.ErrVersionConflict
+	}
+	change(&p)
+	p.Version++
+	r.products[id] = p
+	return p.Version, nil
+}
+
+func (r *memoryRepository) UpdateProduct(_ context.Context, updated *models.Product) error {
+	version, err := r.write(updated.Id, updated.Version, func(p *models.Product) {
+		p.Name, p.Description, p.Price, p.Stock = updated.Name, updated.Description, updated.Price, updated.Stock
+	})
+	if err != nil {
+		return err
+	}
+	updated.Version = version
+	return nil
+}
+
+func (r *memoryRepository) UpdateProductPrice(_ context.Context, id string, price money.Money, version int64) (int64, error) {
+	return r.write(id, version, func(p *models.Product) {
+		p.Price = price
+	})
+}
+
+func (r *memoryRepository) UpdateProductStatus(_ context.Context, id, status string, publishAt *time.Time, version int64) (int64, error) {
+	return r.write(id, version, func(p *models.Product) {
+		p.Status, p.PublishAt = status, publishAt
+	})
+}
+
+func (r *memoryRepository) UpdateProductDeletedAt(_ context.Context, id string, deletedAt *time.Time, version int64) (int64, error) {
+	return r.write(id, version, func(p *models.Product) {
+		p.DeletedAt = deletedAt
+	})
+}
+
+// ListProductsByAccount returns the products of a seller outside the trash,
+// ordered by name.
+func (r *memoryRepository) ListProductsByAccount(_ context.Context, accountId int, statuses []string, skip, take uint64) ([]models.Product, error) {
+	products := r.listProducts(func(p models.Product) bool {
+		return p.AccountId == accountId && !p.IsDeleted() &&
+			(len(statuses) == 0 || slices.Contains(statuses, p.Status))
+	})
+	slices.SortFunc(products, func(a, b models.Product) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return page(products, skip, take), nil
+}
+
+func (r *memoryRepository) ListDeletedProducts(_ context.Context, accountId int, skip, take uint64) ([]models.Product, error) {
+	products := r.listProducts(func(p models.Product) bool {
+		return p.AccountId == accountId && p.IsDeleted()
+	})
+	slices.SortFunc(products, func(a, b models.Product) int {
+		return b.DeletedAt.Compare(*a.DeletedAt)
+	})
+	return page(products, skip, take), nil
+}
+
+func (r *memoryRepository) ListPurgeableProducts(_ context.Context, deletedBefore time.Time) ([]models.Product, error) {
+	return r.listProducts(func(p models.Product) bool {
+		return p.IsDeleted() && !p.DeletedAt.After(deletedBefore)
+	}), nil
+}
+
+func (r *memoryRepository) listProducts(match func(p models.Product) bool) []models.Product {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var products []models.Product
+	for _, p := range r.products {
+		if match(p) {
+			products = append(products, p)
+		}
+	}
+	slices.SortFunc(products, func(a, b models.Product) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return products
+}
+
+// PurgeProduct removes a product together with its reviews.
+func (r *memoryRepository) PurgeProduct(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.products, id)
+	for reviewId, review := range r.reviews {
+		if review.ProductId == id {
+			delete(r.reviews, reviewId)
+		}
+	}
+	return nil
+}
+
+// touch stands in for an edit made by someone else.
+func (r *memoryRepository) touch(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := r.products[id]
+	p.Version++
+	r.products[id] = p
+}
+
+func (r *memoryRepository) PutOutboxEntry(_ context.Context, entry *models.OutboxEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextId++
+	entry.Id = strconv.Itoa(r.nextId)
+	r.outbox[entry.Id] = *entry
+	return nil
+}
+
+func (r *memoryRepository) DeleteOutboxEntry(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.outbox, id)
+	return nil
+}
+
+func (r *memoryRepository) staged() []models.OutboxEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []models.OutboxEntry
+	for _, entry := range r.outbox {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (r *memoryRepository) UpdateProductRating(_ context.Context, productId string, average float64, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.products[productId]
+	if !ok {
+		return product.ErrNotFound
+	}
+	p.RatingAverage, p.RatingCount = average, count
+	r.products[productId] = p
+	return nil
+}
+
+// PutReview keys reviews by product and author like the Elasticsearch
+// repository, so a second review of a product fails.
+func (r *memoryRepository) PutReview(_ context.Context, review *models.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	review.Id = review.ProductId + "_" + strconv.Itoa(review.AccountId)
+	if _, ok := r.reviews[review.Id]; ok {
+		return product.ErrAlreadyReviewed
+	}
+	r.reviews[review.Id] = *review
+	return nil
+}
+
+func (r *memoryRepository) ReviewStats(_ context.Context, productId string) (float64, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sum, count := 0, 0
+	for _, review := range r.reviews {
+		if review.ProductId == productId {
+			sum += review.Rating
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return float64(sum) / float64(count), count, nil
+}
+
+func (r *memoryRepository) PutPriceHistory(_ context.Context, entry *models.PriceHistoryEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history = append(r.history, *entry)
+	return nil
+}
+
+// ListPriceHistory returns the newest entries first.
+func (r *memoryRepository) ListPriceHistory(_ context.Context, productId string, skip, take uint64) ([]models.PriceHistoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []models.PriceHistoryEntry
+	for _, entry := range slices.Backward(r.history) {
+		if entry.ProductId == productId {
+			entries = append(entries, entry)
+		}
+	}
+	return page(entries, skip, take), nil
+}
+
+func (r *memoryRepository) PutScheduledPrice(_ context.Context, change *models.ScheduledPrice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextId++
+	change.Id = strconv.Itoa(r.nextId)
+	r.changes[change.Id] = *change
+	return nil
+}
+
+func (r *memoryRepository) GetScheduledPrice(_ context.Context, id string) (*models.ScheduledPrice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change, ok := r.changes[id]
+	if !ok {
+		return nil, product.ErrNotFound
+	}
+	return &change, nil
+}
+
+// ListScheduledPrices returns the pending and active changes of a product.
+func (r *memoryRepository) ListScheduledPrices(_ context.Context, productId string) ([]models.ScheduledPrice, error) {
+	return r.listChanges(func(change models.ScheduledPrice) bool {
+		return change.ProductId == productId &&
+			(change.Status == product.PriceChangePending || change.Status == product.PriceChangeActive)
+	}), nil
+}
+
+func (r *memoryRepository) ListDueScheduledPrices(_ context.Context, now time.Time) ([]models.ScheduledPrice, error) {
+	return r.listChanges(func(change models.ScheduledPrice) bool {
+		switch change.Status {
+		case product.PriceChangePending:
+			return !change.StartAt.After(now)
+		case product.PriceChangeActive:
+			return change.EndAt != nil && !change.EndAt.After(now)
+		}
+		return false
+	}), nil
+}
+
+func (r *memoryRepository) listChanges(match func(change models.ScheduledPrice) bool) []models.ScheduledPrice {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var changes []models.ScheduledPrice
+	for _, change := range r.changes {
+		if match(change) {
+			changes = append(changes, change)
+		}
+	}
+	slices.SortFunc(changes, func(a, b models.ScheduledPrice) int {
+		return a.StartAt.Compare(b.StartAt)
+	})
+	return changes
+}
+
+func (r *memoryRepository) UpdateScheduledPrice(_ context.Context, change *models.ScheduledPrice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.changes[change.Id]; !ok {
+		return product.ErrNotFound
+	}
+	r.changes[change.Id] = *change
+	return nil
+}
+
+func page[T any](items []T, skip, take uint64) []T {
+	if skip >= uint64(len(items)) {
+		return nil
+	}
+	items = items[skip:]
+	return items[:min(take, uint64(len(items)))]
+}
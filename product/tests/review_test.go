@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama/mocks"
+	"github.com/abhiii71/orderStream/pkg/kafka"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+)
+
+const buyerId = 11
+
+// fakePurchases answers HasPurchased from the accounts that bought
+// something.
+type fakePurchases map[uint64]bool
+
+func (f fakePurchases) HasPurchased(_ context.Context, accountId uint64, _ string) (bool, error) {
+	return f[accountId], nil
+}
+
+// newPublisher returns a publisher that expects exactly events messages.
+// The expectations are checked when the test ends.
+func newPublisher(t *testing.T, events int) *kafka.Publisher {
+	t.Helper()
+
+	producer := mocks.NewAsyncProducer(t, mocks.NewTestConfig())
+	for range events {
+		producer.ExpectInputAndSucceed()
+	}
+	publisher := kafka.NewPublisherFromProducer(producer, kafka.PublisherConfig{})
+	t.Cleanup(func() {
+		if err := publisher.Close(context.Background()); err != nil {
+			t.Error(err)
+		}
+	})
+	return publisher
+}
+
+func TestCreateReviewRequiresAPurchase(t *testing.T) {
+	draft := publishedProduct()
+	draft.Id, draft.Status = "draft", product.StatusDraft
+	repo := newMemoryRepository(publishedProduct(), draft)
+	service := internal.NewProductService(repo, newPublisher(t, 0), nil, fakePurchases{buyerId: true}, nil)
+
+	tests := []struct {
+		name      string
+		productId string
+		accountId int
+		rating    int
+		want      error
+	}{
+		{"not purchased", "mug", buyerId + 1, 5, product.ErrNotPurchased},
+		{"rating too low", "mug", buyerId, product.MinRating - 1, product.ErrInvalidRating},
+		{"rating too high", "mug", buyerId, product.MaxRating + 1, product.ErrInvalidRating},
+		{"unpublished product", "draft", buyerId, 5, product.ErrNotFound},
+	}
+	for _, tt := range tests {
+		_, err := service.CreateReview(context.Background(), tt.productId, tt.accountId, tt.rating, "Nice", "Holds coffee.")
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: CreateReview error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if len(repo.reviews) != 0 {
+		t.Errorf("reviews = %v, want none stored", repo.reviews)
+	}
+}
+
+func TestCreateReviewRecordsVerifiedPurchases(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, newPublisher(t, 1), nil, fakePurchases{buyerId: true}, nil)
+
+	review, err := service.CreateReview(context.Background(), "mug", buyerId, 4, "Nice", "Holds coffee.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Id == "" || review.AccountId != buyerId || review.Rating != 4 {
+		t.Errorf("review = %+v, want a stored 4 star review by the buyer", review)
+	}
+	if p, _ := repo.GetProductsByID(context.Background(), "mug"); p.RatingAverage != 4 || p.RatingCount != 1 {
+		t.Errorf("rating = %v over %d reviews, want 4 over 1", p.RatingAverage, p.RatingCount)
+	}
+
+	if _, err := service.CreateReview(context.Background(), "mug", buyerId, 1, "Broke", "Cracked."); !errors.Is(err, product.ErrAlreadyReviewed) {
+		t.Errorf("second review error = %v, want ErrAlreadyReviewed", err)
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abhiii71/orderStream/pkg/events"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/config"
+	"github.com/abhiii71/orderStream/product/internal"
+	"github.com/abhiii71/orderStream/product/models"
+)
+
+// fakeMedia records the keys deleted from it.
+type fakeMedia struct {
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (m *fakeMedia) Put(context.Context, string, string, io.Reader, int64) (string, error) {
+	return "", errors.New("not supported")
+}
+
+func (m *fakeMedia) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deleted = append(m.deleted, key)
+	return nil
+}
+
+// deletedProduct returns a product of the seller that was moved to the
+// trash the given time ago.
+func deletedProduct(id string, ago time.Duration) models.Product {
+	p := publishedProduct()
+	deletedAt := time.Now().UTC().Add(-ago)
+	p.Id, p.DeletedAt = id, &deletedAt
+	return p
+}
+
+func TestDeleteProductMovesItToTheTrash(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	for range 2 {
+		if err := service.DeleteProduct(context.Background(), "mug", sellerId); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if staged := repo.staged(); len(staged) != 1 || staged[0].Type != events.ProductDeleted {
+		t.Errorf("staged = %+v, want one product_deleted event", staged)
+	}
+
+	deleted, err := service.ListDeletedProducts(context.Background(), sellerId, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].Id != "mug" {
+		t.Errorf("ListDeletedProducts = %+v, want the mug", deleted)
+	}
+	if others, _ := service.ListDeletedProducts(context.Background(), sellerId+1, 0, 10); len(others) != 0 {
+		t.Errorf("another seller's trash = %+v, want it empty", others)
+	}
+
+	// the product is kept for past orders
+	if p, err := repo.GetProductsByID(context.Background(), "mug"); err != nil || !p.IsDeleted() {
+		t.Errorf("GetProductsByID = %+v, %v, want the deleted product", p, err)
+	}
+}
+
+func TestRestoreProduct(t *testing.T) {
+	draft := deletedProduct("draft", time.Hour)
+	draft.Status = product.StatusDraft
+	repo := newMemoryRepository(deletedProduct("mug", time.Hour), draft)
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	if _, err := service.RestoreProduct(context.Background(), "mug", sellerId+1); !errors.Is(err, product.ErrUnauthorized) {
+		t.Errorf("RestoreProduct by another seller error = %v, want ErrUnauthorized", err)
+	}
+
+	restored, err := service.RestoreProduct(context.Background(), "mug", sellerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.IsDeleted() || restored.Version != 4 {
+		t.Errorf("restored = %+v, want it out of the trash at version 4", restored)
+	}
+	if staged := repo.staged(); len(staged) != 1 || staged[0].Type != events.ProductPublished {
+		t.Errorf("staged = %+v, want the product published again", staged)
+	}
+
+	if _, err := service.RestoreProduct(context.Background(), "draft", sellerId); err != nil {
+		t.Fatal(err)
+	}
+	if staged := repo.staged(); len(staged) != 1 {
+		t.Errorf("staged = %+v, want no event for a restored draft", staged)
+	}
+}
+
+func TestRestoreProductAfterRetentionFails(t *testing.T) {
+	repo := newMemoryRepository(deletedProduct("mug", config.TrashRetention+time.Hour))
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	if _, err := service.RestoreProduct(context.Background(), "mug", sellerId); !errors.Is(err, product.ErrRestoreExpired) {
+		t.Fatalf("RestoreProduct error = %v, want ErrRestoreExpired", err)
+	}
+	if p, _ := repo.GetProductsByID(context.Background(), "mug"); !p.IsDeleted() {
+		t.Error("expired product was restored")
+	}
+}
+
+func TestPurgeDeletedProducts(t *testing.T) {
+	expired := deletedProduct("expired", config.TrashRetention+time.Hour)
+	expired.Images = []models.Image{{Key: "products/expired/1.png", ThumbnailKey: "products/expired/1_thumb.png"}}
+	repo := newMemoryRepository(expired, deletedProduct("recent", time.Hour), publishedProduct())
+	repo.reviews["expired_11"] = models.Review{Id: "expired_11", ProductId: "expired", AccountId: buyerId, Rating: 5}
+	media := &fakeMedia{}
+	service := internal.NewProductService(repo, nil, media, nil, nil)
+
+	if err := service.PurgeDeletedProducts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var left []string
+	for id := range repo.products {
+		left = append(left, id)
+	}
+	slices.Sort(left)
+	if want := []string{"mug", "recent"}; !slices.Equal(left, want) {
+		t.Errorf("products = %v, want %v", left, want)
+	}
+	if len(repo.reviews) != 0 {
+		t.Errorf("reviews = %v, want the purged product's reviews removed", repo.reviews)
+	}
+	if want := []string{"products/expired/1.png", "products/expired/1_thumb.png"}; !slices.Equal(media.deleted, want) {
+		t.Errorf("deleted media = %v, want %v", media.deleted, want)
+	}
+}
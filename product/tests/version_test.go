@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abhiii71/orderStream/pkg/money"
+	"github.com/abhiii71/orderStream/product"
+	"github.com/abhiii71/orderStream/product/internal"
+	"github.com/abhiii71/orderStream/product/models"
+)
+
+const sellerId = 7
+
+func publishedProduct() models.Product {
+	return models.Product{
+		Id:        "mug",
+		Name:      "Mug",
+		Price:     money.New(1200, "USD"),
+		AccountId: sellerId,
+		Status:    product.StatusPublished,
+		Version:   3,
+	}
+}
+
+func TestUpdateProductRejectsStaleVersion(t *testing.T) {
+	repo := newMemoryRepository(publishedProduct())
+	service := internal.NewProductService(repo, nil, nil, nil, nil)
+
+	_, err := service.UpdateProduct(context.Background(), "mug", "Big Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, nil, false, sellerId, 2)
+	if !errors.Is(err, product.ErrVersionConflict) {
+		t.Fatalf("UpdateProduct error = %v, want ErrVersionConflict", err)
+	}
+	if p, _ := repo.GetProductsByID(context.Background(), "mug"); p.Name != "Mug" {
+		t.Errorf("name = %q, want the stale edit dropped", p.Name)
+	}
+
+	updated, err := service.UpdateProduct(context.Background(), "mug", "Big Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, nil, false, sellerId, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Version != 4 {
+		t.Errorf("version = %d, want 4", updated.Version)
+	}
+}
+
+func TestUpdateProductRejectsOtherSellers(t *testing.T) {
+	service := internal.NewProductService(newMemoryRepository(publishedProduct()), nil, nil, nil, nil)
+
+	_, err := service.UpdateProduct(context.Background(), "mug", "Big Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, nil, false, sellerId+1, 3)
+	if !errors.Is(err, product.ErrUnauthorized) {
+		t.Fatalf("UpdateProduct error = %v, want ErrUnauthorized", err)
+	}
+	if err := service.DeleteProduct(context.Background(), "mug", sellerId+1); !errors.Is(err, product.ErrUnauthorized) {
+		t.Fatalf("DeleteProduct error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestWritesLosingARaceConflict(t *testing.T) {
+	cases := []struct {
+		name  string
+		write func(s internal.Service) error
+	}{
+		{"update", func(s internal.Service) error {
+			_, err := s.UpdateProduct(context.Background(), "mug", "Big Mug", "", nil, nil, nil, nil, money.New(1200, "USD"), nil, nil, false, sellerId, 3)
+			return err
+		}},
+		{"archive", func(s internal.Service) error {
+			_, err := s.ArchiveProduct(context.Background(), "mug", sellerId)
+			return err
+		}},
+		{"delete", func(s internal.Service) error {
+			return s.DeleteProduct(context.Background(), "mug", sellerId)
+		}},
+	}
+	for _, c := range cases {
+		repo := newMemoryRepository(publishedProduct())
+		// someone else edits the product between the read and the write
+		repo.beforeWrite = func(r *memoryRepository) {
+			r.touch("mug")
+			r.beforeWrite = nil
+		}
+
+		err := c.write(internal.NewProductService(repo, nil, nil, nil, nil))
+		if !errors.Is(err, product.ErrVersionConflict) {
+			t.Errorf("%s: error = %v, want ErrVersionConflict", c.name, err)
+		}
+		if p, _ := repo.GetProductsByID(context.Background(), "mug"); p.Version != 4 || p.Name != "Mug" || p.Status != product.StatusPublished || p.DeletedAt != nil {
+			t.Errorf("%s: product = %+v, want only the concurrent edit", c.name, p)
+		}
+		if staged := repo.staged(); len(staged) != 0 {
+			t.Errorf("%s: %d events left staged for a write that failed", c.name, len(staged))
+		}
+	}
+}
@@ -5,33 +5,51 @@ from app.db.session import ReplicaSession
 from app.db.models import Product, Interaction
 from config.settings import PRODUCT_API, KAFKA_SERVER
 
+# currencies without minor units; every other price is in hundredths
+ZERO_DECIMAL_CURRENCIES = {"JPY", "KRW", "VND"}
+
+
+def to_price(money):
+    # protobuf JSON encodes int64 as a string
+    amount = int(money.get("amount", 0))
+    if money.get("currency") in ZERO_DECIMAL_CURRENCIES:
+        return float(amount)
+    return amount / 100
+
+
 def sync_products():
     consumer = KafkaConsumer("product_events", bootstrap_servers=KAFKA_SERVER)
     for message in consumer:
         event = json.loads(message.value)
+        if event.get("schemaVersion", 0) > 1:
+            print(f"Skipping product event with unsupported schema version {event['schemaVersion']}")
+            continue
+
         with ReplicaSession() as session:
-            if event["type"] in ["product_created", "product_updated"]:
-                product_data = event["data"]
-                print(f"Product data: {product_data}")
-                print(f"Processing product event: {event['type']} for product ID: {product_data['product_id']}")
-                product = session.query(Product).filter_by(id=product_data["product_id"]).first()
+            # only published products are recommendable
+            if event["type"] in ["product_published", "product_updated"]:
+                product_data = event["after"]
+                if product_data.get("status", "published") != "published":
+                    continue
+                print(f"Processing product event: {event['type']} for product ID: {event['productId']}")
+                product = session.query(Product).filter_by(id=event["productId"]).first()
                 if product:
                     product.name = product_data["name"]
-                    product.description = product_data["description"]
-                    product.price = product_data["price"]
-                    product.account_id = product_data["account_id"]
-                else:
+                    product.description = product_data.get("description", "")
+                    product.price = to_price(product_data["price"])
+                    product.account_id = int(product_data["accountId"])
+                elif event["type"] == "product_published":
                     product = Product(
-                        id=product_data["product_id"],
+                        id=event["productId"],
                         name=product_data["name"],
-                        description=product_data["description"],
-                        price=product_data["price"],
-                        account_id=product_data["accountID"]
+                        description=product_data.get("description", ""),
+                        price=to_price(product_data["price"]),
+                        account_id=int(product_data["accountId"])
                     )
                     session.add(product)
                 session.commit()
-            elif event["type"] == "product_deleted":
-                product = session.query(Product).filter_by(id=event["data"]["product_id"]).first()
+            elif event["type"] in ["product_archived", "product_deleted"]:
+                product = session.query(Product).filter_by(id=event["productId"]).first()
                 if product:
                     session.delete(product)
                     session.commit()